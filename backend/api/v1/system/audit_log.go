@@ -0,0 +1,145 @@
+package system
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"k-admin-system/global"
+	"k-admin-system/model/common"
+	"k-admin-system/model/system"
+	systemService "k-admin-system/service/system"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// AuditLogApi 审计日志API
+type AuditLogApi struct{}
+
+// AuditLogFilterRequest 审计日志过滤参数（查询与导出共用）
+type AuditLogFilterRequest struct {
+	UserID    *uint      `form:"userId"`
+	Method    string     `form:"method" binding:"omitempty,oneof=GET POST PUT PATCH DELETE get post put patch delete"`
+	StartTime *time.Time `form:"startTime" time_format:"2006-01-02T15:04:05Z07:00"`
+	EndTime   *time.Time `form:"endTime" time_format:"2006-01-02T15:04:05Z07:00"`
+	Path      string     `form:"path"`
+}
+
+// GetAuditLogListRequest 查询审计日志请求
+type GetAuditLogListRequest struct {
+	Page     int `form:"page" binding:"required,min=1"`
+	PageSize int `form:"pageSize" binding:"required,min=1,max=100"`
+	AuditLogFilterRequest
+}
+
+// validate 校验时间范围
+func (r *AuditLogFilterRequest) validate() error {
+	if r.StartTime != nil && r.EndTime != nil && r.StartTime.After(*r.EndTime) {
+		return errors.New("startTime must be before endTime")
+	}
+	return nil
+}
+
+// toFilter 转换为服务层查询条件
+func (r *AuditLogFilterRequest) toFilter() systemService.AuditLogFilter {
+	return systemService.AuditLogFilter{
+		UserID:       r.UserID,
+		Method:       strings.ToUpper(r.Method),
+		StartTime:    r.StartTime,
+		EndTime:      r.EndTime,
+		PathContains: r.Path,
+	}
+}
+
+// GetAuditLogList godoc
+// @Summary 查询审计日志
+// @Description 分页查询审计日志，支持按用户、HTTP方法、时间范围和路径过滤
+// @Tags 审计日志
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param page query int true "页码" minimum(1)
+// @Param pageSize query int true "每页数量" minimum(1) maximum(100)
+// @Param userId query int false "用户ID"
+// @Param method query string false "HTTP方法"
+// @Param startTime query string false "开始时间（RFC3339）"
+// @Param endTime query string false "结束时间（RFC3339）"
+// @Param path query string false "路径（模糊搜索）"
+// @Success 200 {object} common.Response{data=common.PagedResponse[system.SysAuditLog]} "获取成功"
+// @Failure 200 {object} common.Response "获取失败"
+// @Router /api/v1/system/audit-log [get]
+func (a *AuditLogApi) GetAuditLogList(c *gin.Context) {
+	var req GetAuditLogListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		common.Fail(c, "invalid request parameters: "+err.Error())
+		return
+	}
+
+	if err := req.validate(); err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+
+	auditLogService := systemService.AuditLogService{}
+	logs, total, err := auditLogService.GetLogs(req.toFilter(), req.Page, req.PageSize)
+	if err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+
+	common.OkWithData(c, common.PagedResponse[system.SysAuditLog]{
+		List:     logs,
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	})
+}
+
+// ExportAuditLogs godoc
+// @Summary 导出审计日志
+// @Description 按与查询接口相同的过滤条件，以NDJSON格式（每行一条JSON记录）流式导出审计日志，便于接入日志采集管道
+// @Tags 审计日志
+// @Accept json
+// @Produce application/x-ndjson
+// @Security Bearer
+// @Param userId query int false "用户ID"
+// @Param method query string false "HTTP方法"
+// @Param startTime query string false "开始时间（RFC3339）"
+// @Param endTime query string false "结束时间（RFC3339）"
+// @Param path query string false "路径（模糊搜索）"
+// @Success 200 {file} file "NDJSON数据"
+// @Failure 200 {object} common.Response "导出失败"
+// @Router /api/v1/system/audit-log/export [get]
+func (a *AuditLogApi) ExportAuditLogs(c *gin.Context) {
+	var req AuditLogFilterRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		common.Fail(c, "invalid request parameters: "+err.Error())
+		return
+	}
+
+	if err := req.validate(); err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+
+	c.Header("Content-Type", "application/x-ndjson")
+	c.Header("Content-Disposition", "attachment; filename=audit-logs.ndjson")
+
+	auditLogService := systemService.AuditLogService{}
+	if _, err := auditLogService.ExportLogs(req.toFilter(), c.Writer); err != nil {
+		// 尚未写出数据时仍可返回统一的错误响应，否则只能中断输出并记录日志
+		if !c.Writer.Written() {
+			c.Writer.Header().Del("Content-Type")
+			c.Writer.Header().Del("Content-Disposition")
+			common.Fail(c, err.Error())
+			return
+		}
+		global.Logger.Error("Failed to export audit logs", zap.Error(err))
+		c.Abort()
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
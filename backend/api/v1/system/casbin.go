@@ -0,0 +1,119 @@
+package system
+
+import (
+	"bytes"
+	"net/http"
+
+	"k-admin-system/model/common"
+	systemService "k-admin-system/service/system"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CasbinApi Casbin策略管理API
+type CasbinApi struct{}
+
+// ExportPoliciesRequest 导出策略请求
+type ExportPoliciesRequest struct {
+	RoleKey string `form:"roleKey"`
+}
+
+// ImportPoliciesResponse 导入策略响应
+type ImportPoliciesResponse struct {
+	Imported int `json:"imported"`
+}
+
+// RemoveOrphanedPoliciesResponse 清理孤立策略响应
+type RemoveOrphanedPoliciesResponse struct {
+	Removed  int                          `json:"removed"`
+	Policies []systemService.CasbinPolicy `json:"policies"`
+}
+
+// ImportPolicies godoc
+// @Summary 导入Casbin策略
+// @Description 从CSV文件批量导入策略，每行格式为 role_key,path,method，已存在的策略会被跳过
+// @Tags 权限管理
+// @Accept multipart/form-data
+// @Produce json
+// @Security Bearer
+// @Param file formData file true "CSV文件"
+// @Success 200 {object} common.Response{data=ImportPoliciesResponse} "导入成功"
+// @Failure 200 {object} common.Response "导入失败"
+// @Router /api/v1/system/casbin/import [post]
+func (a *CasbinApi) ImportPolicies(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		common.Fail(c, "invalid request parameters: "+err.Error())
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		common.Fail(c, "failed to open uploaded file: "+err.Error())
+		return
+	}
+	defer file.Close()
+
+	casbinService := systemService.CasbinService{}
+	imported, err := casbinService.ImportPoliciesFromCSV(file)
+	if err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+
+	common.OkWithData(c, ImportPoliciesResponse{Imported: imported})
+}
+
+// ExportPolicies godoc
+// @Summary 导出Casbin策略
+// @Description 将策略导出为CSV文件，可按角色过滤
+// @Tags 权限管理
+// @Accept json
+// @Produce text/csv
+// @Security Bearer
+// @Param roleKey query string false "角色标识，为空时导出全部策略"
+// @Success 200 {file} file "CSV文件"
+// @Failure 200 {object} common.Response "导出失败"
+// @Router /api/v1/system/casbin/export [get]
+func (a *CasbinApi) ExportPolicies(c *gin.Context) {
+	var req ExportPoliciesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		common.Fail(c, "invalid request parameters: "+err.Error())
+		return
+	}
+
+	// 先写入缓冲区，导出失败时仍可返回统一的错误响应
+	var buf bytes.Buffer
+	casbinService := systemService.CasbinService{}
+	if err := casbinService.ExportPoliciesToCSV(req.RoleKey, &buf); err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+
+	c.Header("Content-Disposition", "attachment; filename=policies.csv")
+	c.Data(http.StatusOK, "text/csv", buf.Bytes())
+}
+
+// RemoveOrphanedPolicies godoc
+// @Summary 清理孤立的Casbin策略
+// @Description 删除所属角色已不存在的Casbin策略，返回被删除的策略列表
+// @Tags 权限管理
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} common.Response{data=RemoveOrphanedPoliciesResponse} "清理成功"
+// @Failure 200 {object} common.Response "清理失败"
+// @Router /api/v1/system/casbin/orphaned [delete]
+func (a *CasbinApi) RemoveOrphanedPolicies(c *gin.Context) {
+	roleService := systemService.RoleService{}
+	policies, err := roleService.RemoveOrphanedPolicies()
+	if err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+
+	common.OkWithData(c, RemoveOrphanedPoliciesResponse{
+		Removed:  len(policies),
+		Policies: policies,
+	})
+}
@@ -2,6 +2,7 @@ package system
 
 import (
 	"k-admin-system/model/common"
+	"k-admin-system/model/system"
 	systemService "k-admin-system/service/system"
 
 	"github.com/gin-gonic/gin"
@@ -11,15 +12,17 @@ type DashboardApi struct{}
 
 // DashboardStatsResponse 仪表盘统计数据响应
 type DashboardStatsResponse struct {
-	UserCount   int64 `json:"userCount"`
-	RoleCount   int64 `json:"roleCount"`
-	MenuCount   int64 `json:"menuCount"`
-	ConfigCount int64 `json:"configCount"`
+	UserCount   int64                    `json:"userCount"`
+	RoleCount   int64                    `json:"roleCount"`
+	MenuCount   int64                    `json:"menuCount"`
+	ConfigCount int64                    `json:"configCount"`
+	RoleStats   []systemService.RoleStat `json:"roleStats"`
+	RecentUsers []system.SysUser         `json:"recentUsers"`
 }
 
 // GetDashboardStats godoc
 // @Summary 获取仪表盘统计数据
-// @Description 获取系统各模块的统计数据
+// @Description 获取系统各模块的统计数据、每个角色的用户数和菜单数，以及最近注册的用户
 // @Tags 仪表盘
 // @Accept json
 // @Produce json
@@ -35,5 +38,10 @@ func (a *DashboardApi) GetDashboardStats(c *gin.Context) {
 		return
 	}
 
+	// 用户信息脱敏并转换时区
+	for i := range stats.RecentUsers {
+		stats.RecentUsers[i] = stats.RecentUsers[i].Sanitize().InTimezone(c.GetString("timezone"))
+	}
+
 	common.OkWithData(c, stats)
 }
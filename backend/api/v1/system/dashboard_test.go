@@ -0,0 +1,49 @@
+package system
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"k-admin-system/global"
+	"k-admin-system/model/system"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestDashboardApi_RecentUsersAreMasked(t *testing.T) {
+	setupTestEnv(t)
+	role := &system.SysRole{RoleName: "Editor", RoleKey: "editor", Status: true}
+	if err := global.DB.Create(role).Error; err != nil {
+		t.Fatalf("failed to create role: %v", err)
+	}
+	user := &system.SysUser{Username: "alice", Password: "secret-hash", Email: "alice@example.com", Phone: "13812345678", RoleID: role.ID, Active: true}
+	if err := global.DB.Create(user).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	dashboardApi := DashboardApi{}
+	r := gin.New()
+	r.GET("/dashboard/stats", dashboardApi.GetDashboardStats)
+
+	var stats struct {
+		RecentUsers []map[string]interface{} `json:"recentUsers"`
+	}
+	if resp := doJSON(t, r, http.MethodGet, "/dashboard/stats", nil, &stats); resp.Code != 0 {
+		t.Fatalf("GetDashboardStats response = %+v", resp)
+	}
+	if len(stats.RecentUsers) != 1 {
+		t.Fatalf("recentUsers = %v, want one user", stats.RecentUsers)
+	}
+
+	recent := stats.RecentUsers[0]
+	if _, ok := recent["password"]; ok {
+		t.Error("recentUsers exposes the password")
+	}
+	if email, _ := recent["email"].(string); email == user.Email || !strings.Contains(email, "*") {
+		t.Errorf("recentUsers email = %q, want masked", email)
+	}
+	if phone, _ := recent["phone"].(string); phone == user.Phone {
+		t.Errorf("recentUsers phone = %q, want masked", phone)
+	}
+}
@@ -0,0 +1,92 @@
+package system
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"k-admin-system/config"
+	"k-admin-system/global"
+	"k-admin-system/model/common"
+	"k-admin-system/model/system"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// setupTestEnv 使用内存SQLite初始化全局依赖
+// 操作日志异步写入，可能在测试结束后才执行，因此不恢复 global.DB 和 global.Logger
+func setupTestEnv(t *testing.T) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	// 内存数据库只存在于单个连接中
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get sql.DB: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	if err := db.AutoMigrate(&system.SysRole{}, &system.SysMenu{}, &system.SysUser{}, &system.SysOperationLog{}); err != nil {
+		t.Fatalf("failed to migrate tables: %v", err)
+	}
+
+	prevConfig := global.Config
+	global.DB = db
+	global.Logger = zap.NewNop()
+	global.Config = &config.Config{}
+	t.Cleanup(func() {
+		global.Config = prevConfig
+	})
+}
+
+// doJSON 发送JSON请求并解析统一响应，data 非空时将响应数据解码到 data
+func doJSON(t *testing.T, r http.Handler, method, path string, body interface{}, data interface{}) common.Response {
+	t.Helper()
+	_, resp := doJSONWithStatus(t, r, method, path, body, data)
+	return resp
+}
+
+// doJSONWithStatus 与 doJSON 相同，同时返回HTTP状态码
+func doJSONWithStatus(t *testing.T, r http.Handler, method, path string, body interface{}, data interface{}) (int, common.Response) {
+	t.Helper()
+
+	var reader *bytes.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("failed to marshal request: %v", err)
+		}
+		reader = bytes.NewReader(payload)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var resp struct {
+		common.Response
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("%s %s: failed to decode response %q: %v", method, path, w.Body.String(), err)
+	}
+	if data != nil && len(resp.Data) > 0 {
+		if err := json.Unmarshal(resp.Data, data); err != nil {
+			t.Fatalf("%s %s: failed to decode data: %v", method, path, err)
+		}
+	}
+	return w.Code, resp.Response
+}
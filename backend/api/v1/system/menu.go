@@ -1,6 +1,8 @@
 package system
 
 import (
+	"bytes"
+	"net/http"
 	"strconv"
 
 	"k-admin-system/global"
@@ -37,11 +39,27 @@ type UpdateMenuRequest struct {
 	BtnPerms  []string        `json:"btnPerms"`
 }
 
+// ReorderMenusRequest 重排菜单请求
+type ReorderMenusRequest struct {
+	ParentID   uint   `json:"parentId"`
+	OrderedIDs []uint `json:"orderedIds" binding:"required,min=1"`
+}
+
 // GetMenuTreeRequest 获取菜单树请求
 type GetMenuTreeRequest struct {
 	RoleID uint `form:"roleId"`
 }
 
+// ExportMenuTreeRequest 导出菜单树请求
+type ExportMenuTreeRequest struct {
+	Format string `form:"format" binding:"omitempty,oneof=json yaml"`
+}
+
+// ImportMenusResponse 导入菜单响应
+type ImportMenusResponse struct {
+	Imported int `json:"imported"`
+}
+
 // CreateMenu godoc
 // @Summary 创建菜单
 // @Description 创建新菜单
@@ -117,6 +135,33 @@ func (a *MenuApi) UpdateMenu(c *gin.Context) {
 	common.OkWithData(c, menu)
 }
 
+// ReorderMenus godoc
+// @Summary 重排菜单
+// @Description 按给定ID顺序重排同一父菜单下的子菜单，需包含该父菜单下的全部子菜单
+// @Tags 菜单管理
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body ReorderMenusRequest true "重排菜单请求"
+// @Success 200 {object} common.Response "重排成功"
+// @Failure 200 {object} common.Response "重排失败"
+// @Router /api/v1/menu/reorder [put]
+func (a *MenuApi) ReorderMenus(c *gin.Context) {
+	var req ReorderMenusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.Fail(c, "invalid request parameters: "+err.Error())
+		return
+	}
+
+	menuService := systemService.MenuService{}
+	if err := menuService.SortMenusByParent(req.ParentID, req.OrderedIDs); err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+
+	common.OkWithDetailed(c, nil, "menus reordered successfully")
+}
+
 // DeleteMenu godoc
 // @Summary 删除菜单
 // @Description 删除菜单（不能删除有子菜单的菜单）
@@ -145,6 +190,34 @@ func (a *MenuApi) DeleteMenu(c *gin.Context) {
 	common.OkWithDetailed(c, nil, "menu deleted successfully")
 }
 
+// RestoreMenu godoc
+// @Summary 恢复菜单
+// @Description 恢复已删除的菜单（同一父菜单下不能存在相同路径的菜单）
+// @Tags 菜单管理
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "菜单ID"
+// @Success 200 {object} common.Response "恢复成功"
+// @Failure 200 {object} common.Response "恢复失败"
+// @Router /api/v1/menu/{id}/restore [post]
+func (a *MenuApi) RestoreMenu(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		common.Fail(c, "invalid menu ID")
+		return
+	}
+
+	menuService := systemService.MenuService{}
+	if err := menuService.RestoreMenu(uint(id)); err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+
+	common.OkWithDetailed(c, nil, "menu restored successfully")
+}
+
 // GetMenu godoc
 // @Summary 获取菜单详情
 // @Description 根据ID获取菜单详细信息
@@ -174,6 +247,67 @@ func (a *MenuApi) GetMenu(c *gin.Context) {
 	common.OkWithData(c, menu)
 }
 
+// GetMenuByPath godoc
+// @Summary 根据路由路径获取菜单
+// @Description 根据前端路由路径获取菜单信息（图标、标题、缓存等元数据）
+// @Tags 菜单管理
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param path query string true "路由路径" example(/system/user)
+// @Success 200 {object} common.Response{data=system.SysMenu} "获取成功"
+// @Failure 200 {object} common.Response "获取失败"
+// @Router /api/v1/menu/by-path [get]
+func (a *MenuApi) GetMenuByPath(c *gin.Context) {
+	path := c.Query("path")
+	if path == "" {
+		common.Fail(c, "invalid request parameters: path is required")
+		return
+	}
+
+	menuService := systemService.MenuService{}
+	menu, err := menuService.GetMenuByPath(path)
+	if err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+
+	common.OkWithData(c, menu)
+}
+
+// GetMenuUsers godoc
+// @Summary 获取可访问菜单的用户
+// @Description 获取角色已分配该菜单的所有用户，用于下线菜单前评估影响范围
+// @Tags 菜单管理
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "菜单ID"
+// @Success 200 {object} common.Response{data=[]system.SysUser} "获取成功"
+// @Failure 200 {object} common.Response "获取失败"
+// @Router /api/v1/menu/{id}/users [get]
+func (a *MenuApi) GetMenuUsers(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		common.Fail(c, "invalid menu ID")
+		return
+	}
+
+	userService := systemService.UserService{}
+	users, err := userService.GetUsersByMenuAccess(uint(id))
+	if err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+
+	for i := range users {
+		users[i] = users[i].Sanitize().InTimezone(c.GetString("timezone"))
+	}
+
+	common.OkWithData(c, users)
+}
+
 // GetAllMenus godoc
 // @Summary 获取所有菜单
 // @Description 获取所有菜单列表（不构建树结构）
@@ -195,6 +329,34 @@ func (a *MenuApi) GetAllMenus(c *gin.Context) {
 	common.OkWithData(c, menus)
 }
 
+// GetMenusByComponent godoc
+// @Summary 按组件路径查询菜单
+// @Description 查询组件路径包含指定字符串的菜单，用于确认哪些菜单引用了某个前端组件
+// @Tags 菜单管理
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param component query string true "组件路径（模糊匹配）"
+// @Success 200 {object} common.Response{data=[]system.SysMenu} "获取成功"
+// @Failure 200 {object} common.Response "获取失败"
+// @Router /api/v1/menu/by-component [get]
+func (a *MenuApi) GetMenusByComponent(c *gin.Context) {
+	component := c.Query("component")
+	if component == "" {
+		common.Fail(c, "invalid request parameters: component is required")
+		return
+	}
+
+	menuService := systemService.MenuService{}
+	menus, err := menuService.GetMenusByComponent(component)
+	if err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+
+	common.OkWithData(c, menus)
+}
+
 // GetMenuTree godoc
 // @Summary 获取菜单树
 // @Description 获取菜单树结构，可根据角色过滤
@@ -227,3 +389,62 @@ func (a *MenuApi) GetMenuTree(c *gin.Context) {
 
 	common.OkWithData(c, tree)
 }
+
+// ExportMenuTree godoc
+// @Summary 导出菜单树
+// @Description 导出完整菜单树为JSON或YAML文件，便于纳入版本控制
+// @Tags 菜单管理
+// @Accept json
+// @Produce json,application/x-yaml
+// @Security Bearer
+// @Param format query string false "导出格式（json或yaml，默认json）"
+// @Success 200 {file} file "导出文件"
+// @Failure 200 {object} common.Response "导出失败"
+// @Router /api/v1/menu/export [get]
+func (a *MenuApi) ExportMenuTree(c *gin.Context) {
+	var req ExportMenuTreeRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		common.Fail(c, "invalid request parameters: "+err.Error())
+		return
+	}
+	if req.Format == "" {
+		req.Format = "json"
+	}
+
+	// 先写入缓冲区，导出失败时仍可返回统一的错误响应
+	var buf bytes.Buffer
+	menuService := systemService.MenuService{}
+	if err := menuService.ExportMenuTree(&buf, req.Format); err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+
+	contentType := "application/json"
+	if req.Format == "yaml" {
+		contentType = "application/x-yaml"
+	}
+	c.Header("Content-Disposition", "attachment; filename=menus."+req.Format)
+	c.Data(http.StatusOK, contentType, buf.Bytes())
+}
+
+// ImportMenus godoc
+// @Summary 导入菜单树
+// @Description 从YAML导入菜单树，按路由路径匹配已有菜单进行更新，不存在则创建
+// @Tags 菜单管理
+// @Accept application/x-yaml
+// @Produce json
+// @Security Bearer
+// @Param request body string true "YAML格式的菜单树"
+// @Success 200 {object} common.Response{data=ImportMenusResponse} "导入成功"
+// @Failure 200 {object} common.Response "导入失败"
+// @Router /api/v1/menu/import [post]
+func (a *MenuApi) ImportMenus(c *gin.Context) {
+	menuService := systemService.MenuService{}
+	imported, err := menuService.ImportMenusFromYAML(c.Request.Body)
+	if err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+
+	common.OkWithData(c, ImportMenusResponse{Imported: imported})
+}
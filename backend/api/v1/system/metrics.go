@@ -0,0 +1,49 @@
+package system
+
+import (
+	"k-admin-system/model/common"
+	systemService "k-admin-system/service/system"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MetricsApi 请求指标API
+type MetricsApi struct{}
+
+// GetLatencyRequest 查询路由耗时百分位请求
+type GetLatencyRequest struct {
+	Path       string  `form:"path" binding:"required"`
+	Percentile float64 `form:"percentile" binding:"omitempty,gt=0,lte=100"`
+}
+
+// GetLatency godoc
+// @Summary 查询路由耗时百分位
+// @Description 根据最近24小时的请求耗时样本计算指定路由的百分位耗时（需开启 metrics.enabled）
+// @Tags 系统监控
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param path query string true "路由模式（如 /api/v1/user/list）"
+// @Param percentile query number false "百分位（0-100]" default(95)
+// @Success 200 {object} common.Response{data=systemService.LatencyStat} "获取成功"
+// @Failure 200 {object} common.Response "获取失败"
+// @Router /api/v1/system/metrics/latency [get]
+func (a *MetricsApi) GetLatency(c *gin.Context) {
+	var req GetLatencyRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		common.Fail(c, "invalid request parameters: "+err.Error())
+		return
+	}
+	if req.Percentile == 0 {
+		req.Percentile = 95
+	}
+
+	metricsService := systemService.MetricsService{}
+	stat, err := metricsService.GetLatencyPercentile(req.Path, req.Percentile)
+	if err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+
+	common.OkWithData(c, stat)
+}
@@ -0,0 +1,114 @@
+package system
+
+import (
+	"net/http"
+	"strconv"
+
+	"k-admin-system/model/common"
+	systemService "k-admin-system/service/system"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationApi 站内通知API
+type NotificationApi struct{}
+
+// BroadcastNotificationRequest 广播通知请求
+type BroadcastNotificationRequest struct {
+	Title   string `json:"title" binding:"required,max=200"`
+	Content string `json:"content" binding:"required"`
+}
+
+// Broadcast godoc
+// @Summary 广播系统通知
+// @Description 向所有启用的用户发送站内通知
+// @Tags 站内通知
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param data body BroadcastNotificationRequest true "通知内容"
+// @Success 200 {object} common.Response{data=system.SysNotification} "发送成功"
+// @Failure 200 {object} common.Response "发送失败"
+// @Router /api/v1/system/notification/broadcast [post]
+func (a *NotificationApi) Broadcast(c *gin.Context) {
+	defer trackOperation(c, "notification", "broadcast")()
+
+	var req BroadcastNotificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.Fail(c, "invalid request parameters: "+err.Error())
+		return
+	}
+
+	notificationService := systemService.NotificationService{}
+	notification, err := notificationService.Broadcast(req.Title, req.Content, c.GetUint("userId"))
+	if err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+
+	common.OkWithDetailed(c, notification, "notification broadcast successfully")
+}
+
+// GetUnreadNotifications godoc
+// @Summary 获取未读通知
+// @Description 获取当前用户的未读站内通知，按发布时间倒序
+// @Tags 站内通知
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} common.Response{data=[]system.SysNotification} "获取成功"
+// @Failure 200 {object} common.Response "获取失败"
+// @Router /api/v1/user/notifications [get]
+func (a *NotificationApi) GetUnreadNotifications(c *gin.Context) {
+	userID, exists := c.Get("userId")
+	if !exists {
+		common.Fail(c, "user not authenticated")
+		return
+	}
+
+	notificationService := systemService.NotificationService{}
+	notifications, err := notificationService.GetUnreadNotifications(userID.(uint))
+	if err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+
+	common.OkWithData(c, notifications)
+}
+
+// MarkAsRead godoc
+// @Summary 标记通知已读
+// @Description 将当前用户的一条通知标记为已读
+// @Tags 站内通知
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "通知ID"
+// @Success 200 {object} common.Response "标记成功"
+// @Failure 200 {object} common.Response "标记失败"
+// @Router /api/v1/user/notification/{id}/read [patch]
+func (a *NotificationApi) MarkAsRead(c *gin.Context) {
+	userID, exists := c.Get("userId")
+	if !exists {
+		common.Fail(c, "user not authenticated")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		common.FailWithStatus(c, http.StatusBadRequest, "invalid notification ID")
+		return
+	}
+
+	notificationService := systemService.NotificationService{}
+	if err := notificationService.MarkAsRead(userID.(uint), uint(id)); err != nil {
+		if err.Error() == "notification not found" {
+			common.FailWithStatus(c, http.StatusNotFound, err.Error())
+			return
+		}
+		common.Fail(c, err.Error())
+		return
+	}
+
+	common.OkWithDetailed(c, nil, "notification marked as read")
+}
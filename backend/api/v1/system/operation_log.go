@@ -0,0 +1,92 @@
+package system
+
+import (
+	"time"
+
+	"k-admin-system/model/common"
+	"k-admin-system/model/system"
+	systemService "k-admin-system/service/system"
+	"k-admin-system/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OperationLogApi 操作日志API
+type OperationLogApi struct{}
+
+// GetOperationLogListRequest 查询操作日志请求
+type GetOperationLogListRequest struct {
+	Page      int        `form:"page" binding:"required,min=1"`
+	PageSize  int        `form:"pageSize" binding:"required,min=1,max=100"`
+	UserID    *uint      `form:"userId"`
+	Module    string     `form:"module"`
+	Action    string     `form:"action"`
+	Success   *bool      `form:"success"`
+	StartTime *time.Time `form:"startTime" time_format:"2006-01-02T15:04:05Z07:00"`
+	EndTime   *time.Time `form:"endTime" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// trackOperation 记录处理函数的操作日志，在处理函数开头以 defer trackOperation(c, module, action)() 调用
+// 处理函数通过 common.Fail 系列函数返回失败时，最后一条错误作为操作失败原因
+func trackOperation(c *gin.Context, module, action string) func() {
+	start := time.Now()
+	return func() {
+		var err error
+		if last := c.Errors.Last(); last != nil {
+			err = last.Err
+		}
+		utils.RecordOperation(c, module, action, time.Since(start), err)
+	}
+}
+
+// GetOperationLogList godoc
+// @Summary 查询操作日志
+// @Description 分页查询操作日志，支持按用户、模块、操作、结果和时间范围过滤
+// @Tags 操作日志
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param page query int true "页码" minimum(1)
+// @Param pageSize query int true "每页数量" minimum(1) maximum(100)
+// @Param userId query int false "用户ID"
+// @Param module query string false "模块（如 user、role）"
+// @Param action query string false "操作（如 create、delete）"
+// @Param success query bool false "是否成功"
+// @Param startTime query string false "开始时间（RFC3339）"
+// @Param endTime query string false "结束时间（RFC3339）"
+// @Success 200 {object} common.Response{data=common.PagedResponse[system.SysOperationLog]} "获取成功"
+// @Failure 200 {object} common.Response "获取失败"
+// @Router /api/v1/system/operation-log [get]
+func (a *OperationLogApi) GetOperationLogList(c *gin.Context) {
+	var req GetOperationLogListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		common.Fail(c, "invalid request parameters: "+err.Error())
+		return
+	}
+
+	if req.StartTime != nil && req.EndTime != nil && req.StartTime.After(*req.EndTime) {
+		common.Fail(c, "startTime must be before endTime")
+		return
+	}
+
+	operationLogService := systemService.OperationLogService{}
+	logs, total, err := operationLogService.GetLogs(systemService.OperationLogFilter{
+		UserID:    req.UserID,
+		Module:    req.Module,
+		Action:    req.Action,
+		Success:   req.Success,
+		StartTime: req.StartTime,
+		EndTime:   req.EndTime,
+	}, req.Page, req.PageSize)
+	if err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+
+	common.OkWithData(c, common.PagedResponse[system.SysOperationLog]{
+		List:     logs,
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	})
+}
@@ -0,0 +1,102 @@
+package system
+
+import (
+	"strconv"
+
+	"k-admin-system/model/common"
+	"k-admin-system/model/system"
+	systemService "k-admin-system/service/system"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RateLimitOverrideApi struct{}
+
+// SetRateLimitOverrideRequest 设置用户限流覆盖请求
+type SetRateLimitOverrideRequest struct {
+	UserID        uint `json:"userId" binding:"required"`
+	Requests      int  `json:"requests" binding:"required,min=1"`
+	WindowSeconds int  `json:"windowSeconds" binding:"required,min=1"`
+}
+
+// GetOverrideList godoc
+// @Summary 获取用户限流覆盖列表
+// @Description 获取所有用户级限流覆盖配置
+// @Tags 限流管理
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} common.Response{data=[]system.SysRateLimitOverride} "获取成功"
+// @Failure 200 {object} common.Response "获取失败"
+// @Router /api/v1/tools/db-inspector/override [get]
+func (a *RateLimitOverrideApi) GetOverrideList(c *gin.Context) {
+	rateLimitService := systemService.RateLimitOverrideService{}
+	overrides, err := rateLimitService.GetOverrideList()
+	if err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+
+	common.OkWithData(c, overrides)
+}
+
+// SetOverride godoc
+// @Summary 设置用户限流覆盖
+// @Description 为指定用户设置独立的限流参数（已存在则更新）
+// @Tags 限流管理
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body SetRateLimitOverrideRequest true "设置用户限流覆盖请求"
+// @Success 200 {object} common.Response{data=system.SysRateLimitOverride} "设置成功"
+// @Failure 200 {object} common.Response "设置失败"
+// @Router /api/v1/tools/db-inspector/override [post]
+func (a *RateLimitOverrideApi) SetOverride(c *gin.Context) {
+	var req SetRateLimitOverrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.Fail(c, "invalid request parameters: "+err.Error())
+		return
+	}
+
+	override := &system.SysRateLimitOverride{
+		UserID:        req.UserID,
+		Requests:      req.Requests,
+		WindowSeconds: req.WindowSeconds,
+	}
+
+	rateLimitService := systemService.RateLimitOverrideService{}
+	if err := rateLimitService.SetOverride(override); err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+
+	common.OkWithData(c, override)
+}
+
+// DeleteOverride godoc
+// @Summary 删除用户限流覆盖
+// @Description 删除指定用户的限流覆盖配置，恢复使用全局配置
+// @Tags 限流管理
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param userId path int true "用户ID"
+// @Success 200 {object} common.Response "删除成功"
+// @Failure 200 {object} common.Response "删除失败"
+// @Router /api/v1/tools/db-inspector/override/{userId} [delete]
+func (a *RateLimitOverrideApi) DeleteOverride(c *gin.Context) {
+	userIDStr := c.Param("userId")
+	userID, err := strconv.ParseUint(userIDStr, 10, 32)
+	if err != nil {
+		common.Fail(c, "invalid user ID")
+		return
+	}
+
+	rateLimitService := systemService.RateLimitOverrideService{}
+	if err := rateLimitService.DeleteOverride(uint(userID)); err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+
+	common.OkWithDetailed(c, nil, "rate limit override deleted successfully")
+}
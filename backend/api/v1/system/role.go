@@ -1,6 +1,7 @@
 package system
 
 import (
+	"net/http"
 	"strconv"
 
 	"k-admin-system/model/common"
@@ -14,35 +15,45 @@ type RoleApi struct{}
 
 // CreateRoleRequest 创建角色请求
 type CreateRoleRequest struct {
-	RoleName  string `json:"roleName" binding:"required"`
-	RoleKey   string `json:"roleKey" binding:"required"`
-	DataScope string `json:"dataScope"`
-	Sort      int    `json:"sort"`
-	Status    bool   `json:"status"`
-	Remark    string `json:"remark"`
+	RoleName          string `json:"roleName" binding:"required"`
+	RoleKey           string `json:"roleKey" binding:"required"`
+	DataScope         string `json:"dataScope"`
+	Sort              int    `json:"sort"`
+	Status            bool   `json:"status"`
+	Remark            string `json:"remark"`
+	DBInspectorFilter string `json:"dbInspectorFilter"` // 数据库检查器行级过滤，JSON格式 {"表名": "列 运算符 值 [AND ...]"}
+}
+
+// CreateRoleFullRequest 创建角色并分配菜单和API权限请求
+type CreateRoleFullRequest struct {
+	CreateRoleRequest
+	MenuIDs  []uint     `json:"menuIds"`
+	Policies [][]string `json:"policies" binding:"dive,len=2"` // [[path, method], ...]
 }
 
 // UpdateRoleRequest 更新角色请求
 type UpdateRoleRequest struct {
-	ID        uint   `json:"id" binding:"required"`
-	RoleName  string `json:"roleName" binding:"required"`
-	RoleKey   string `json:"roleKey" binding:"required"`
-	DataScope string `json:"dataScope"`
-	Sort      int    `json:"sort"`
-	Status    bool   `json:"status"`
-	Remark    string `json:"remark"`
+	ID                uint   `json:"id" binding:"required"`
+	RoleName          string `json:"roleName" binding:"required"`
+	RoleKey           string `json:"roleKey" binding:"required"`
+	DataScope         string `json:"dataScope"`
+	Sort              int    `json:"sort"`
+	Status            bool   `json:"status"`
+	Remark            string `json:"remark"`
+	DBInspectorFilter string `json:"dbInspectorFilter"` // 数据库检查器行级过滤，JSON格式 {"表名": "列 运算符 值 [AND ...]"}
 }
 
 // GetRoleListRequest 获取角色列表请求
 type GetRoleListRequest struct {
-	Page     int `form:"page" binding:"required,min=1"`
-	PageSize int `form:"pageSize" binding:"required,min=1,max=100"`
+	Page      int    `form:"page" binding:"required,min=1"`
+	PageSize  int    `form:"pageSize" binding:"required,min=1,max=100"`
+	SortBy    string `form:"sortBy" binding:"omitempty,oneof=sort createdAt created_at roleName role_name"`
+	SortOrder string `form:"sortOrder" binding:"omitempty,oneof=asc desc"`
 }
 
-// GetRoleListResponse 获取角色列表响应
-type GetRoleListResponse struct {
-	List  []system.SysRole `json:"list"`
-	Total int64            `json:"total"`
+// PatchRoleRemarkRequest 更新角色备注请求
+type PatchRoleRemarkRequest struct {
+	Remark string `json:"remark" binding:"max=255"`
 }
 
 // AssignMenusRequest 分配菜单权限请求
@@ -51,6 +62,11 @@ type AssignMenusRequest struct {
 	MenuIDs []uint `json:"menuIds"`
 }
 
+// BulkAssignMenusRequest 批量分配菜单权限请求
+type BulkAssignMenusRequest struct {
+	Assignments []systemService.RoleMenuAssignment `json:"assignments" binding:"required,min=1,dive"`
+}
+
 // AssignAPIsRequest 分配API权限请求
 type AssignAPIsRequest struct {
 	RoleID   uint       `json:"roleId" binding:"required"`
@@ -69,6 +85,8 @@ type AssignAPIsRequest struct {
 // @Failure 200 {object} common.Response "创建失败"
 // @Router /api/v1/role [post]
 func (a *RoleApi) CreateRole(c *gin.Context) {
+	defer trackOperation(c, "role", "create")()
+
 	var req CreateRoleRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		common.Fail(c, "invalid request parameters: "+err.Error())
@@ -76,12 +94,13 @@ func (a *RoleApi) CreateRole(c *gin.Context) {
 	}
 
 	role := &system.SysRole{
-		RoleName:  req.RoleName,
-		RoleKey:   req.RoleKey,
-		DataScope: req.DataScope,
-		Sort:      req.Sort,
-		Status:    req.Status,
-		Remark:    req.Remark,
+		RoleName:          req.RoleName,
+		RoleKey:           req.RoleKey,
+		DataScope:         req.DataScope,
+		Sort:              req.Sort,
+		Status:            req.Status,
+		Remark:            req.Remark,
+		DBInspectorFilter: req.DBInspectorFilter,
 	}
 
 	roleService := systemService.RoleService{}
@@ -93,6 +112,46 @@ func (a *RoleApi) CreateRole(c *gin.Context) {
 	common.OkWithData(c, role)
 }
 
+// CreateRoleFull godoc
+// @Summary 创建角色并分配权限
+// @Description 在一次操作中创建角色、分配菜单并写入API权限策略，任一步骤失败则全部撤销
+// @Tags 角色管理
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body CreateRoleFullRequest true "创建角色请求"
+// @Success 200 {object} common.Response{data=system.SysRole} "创建成功"
+// @Failure 400 {object} common.Response "参数错误"
+// @Failure 200 {object} common.Response "创建失败"
+// @Router /api/v1/role/create-full [post]
+func (a *RoleApi) CreateRoleFull(c *gin.Context) {
+	defer trackOperation(c, "role", "create_full")()
+
+	var req CreateRoleFullRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.FailWithStatus(c, http.StatusBadRequest, "invalid request parameters: "+err.Error())
+		return
+	}
+
+	role := &system.SysRole{
+		RoleName:          req.RoleName,
+		RoleKey:           req.RoleKey,
+		DataScope:         req.DataScope,
+		Sort:              req.Sort,
+		Status:            req.Status,
+		Remark:            req.Remark,
+		DBInspectorFilter: req.DBInspectorFilter,
+	}
+
+	roleService := systemService.RoleService{}
+	if err := roleService.CreateRoleWithMenusAndPolicies(role, req.MenuIDs, req.Policies); err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+
+	common.OkWithData(c, role)
+}
+
 // UpdateRole godoc
 // @Summary 更新角色
 // @Description 更新角色信息
@@ -105,6 +164,8 @@ func (a *RoleApi) CreateRole(c *gin.Context) {
 // @Failure 200 {object} common.Response "更新失败"
 // @Router /api/v1/role [put]
 func (a *RoleApi) UpdateRole(c *gin.Context) {
+	defer trackOperation(c, "role", "update")()
+
 	var req UpdateRoleRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		common.Fail(c, "invalid request parameters: "+err.Error())
@@ -112,12 +173,13 @@ func (a *RoleApi) UpdateRole(c *gin.Context) {
 	}
 
 	role := &system.SysRole{
-		RoleName:  req.RoleName,
-		RoleKey:   req.RoleKey,
-		DataScope: req.DataScope,
-		Sort:      req.Sort,
-		Status:    req.Status,
-		Remark:    req.Remark,
+		RoleName:          req.RoleName,
+		RoleKey:           req.RoleKey,
+		DataScope:         req.DataScope,
+		Sort:              req.Sort,
+		Status:            req.Status,
+		Remark:            req.Remark,
+		DBInspectorFilter: req.DBInspectorFilter,
 	}
 	role.ID = req.ID
 
@@ -130,18 +192,59 @@ func (a *RoleApi) UpdateRole(c *gin.Context) {
 	common.OkWithData(c, role)
 }
 
+// PatchRoleRemark godoc
+// @Summary 更新角色备注
+// @Description 仅更新角色备注，其他字段保持不变
+// @Tags 角色管理
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "角色ID"
+// @Param request body PatchRoleRemarkRequest true "更新角色备注请求"
+// @Success 200 {object} common.Response "更新成功"
+// @Failure 400 {object} common.Response "参数错误"
+// @Failure 200 {object} common.Response "更新失败"
+// @Router /api/v1/role/{id}/remark [patch]
+func (a *RoleApi) PatchRoleRemark(c *gin.Context) {
+	defer trackOperation(c, "role", "update_remark")()
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		common.FailWithStatus(c, http.StatusBadRequest, "invalid role ID")
+		return
+	}
+
+	var req PatchRoleRemarkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.FailWithStatus(c, http.StatusBadRequest, "invalid request parameters: "+err.Error())
+		return
+	}
+
+	roleService := systemService.RoleService{}
+	if err := roleService.UpdateRemark(uint(id), req.Remark); err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+
+	common.OkWithDetailed(c, nil, "role remark updated successfully")
+}
+
 // DeleteRole godoc
 // @Summary 删除角色
-// @Description 删除角色（不能删除有关联用户的角色）
+// @Description 删除角色。未指定 reassignTo 时不能删除有关联用户的角色；指定时先将关联用户迁移到该角色再删除
 // @Tags 角色管理
 // @Accept json
 // @Produce json
 // @Security Bearer
 // @Param id path int true "角色ID"
+// @Param reassignTo query int false "替代角色ID（必须存在且启用）"
 // @Success 200 {object} common.Response "删除成功"
 // @Failure 200 {object} common.Response "删除失败"
 // @Router /api/v1/role/{id} [delete]
 func (a *RoleApi) DeleteRole(c *gin.Context) {
+	defer trackOperation(c, "role", "delete")()
+
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
@@ -150,6 +253,22 @@ func (a *RoleApi) DeleteRole(c *gin.Context) {
 	}
 
 	roleService := systemService.RoleService{}
+
+	// 指定了替代角色时，先迁移关联用户再删除
+	if reassignTo := c.Query("reassignTo"); reassignTo != "" {
+		replacementID, err := strconv.ParseUint(reassignTo, 10, 32)
+		if err != nil {
+			common.Fail(c, "invalid replacement role ID")
+			return
+		}
+		if err := roleService.DeleteRoleWithReassignment(uint(id), uint(replacementID)); err != nil {
+			common.Fail(c, err.Error())
+			return
+		}
+		common.OkWithDetailed(c, nil, "role deleted successfully")
+		return
+	}
+
 	if err := roleService.DeleteRole(uint(id)); err != nil {
 		common.Fail(c, err.Error())
 		return
@@ -170,6 +289,8 @@ func (a *RoleApi) DeleteRole(c *gin.Context) {
 // @Failure 200 {object} common.Response "获取失败"
 // @Router /api/v1/role/{id} [get]
 func (a *RoleApi) GetRole(c *gin.Context) {
+	defer trackOperation(c, "role", "get")()
+
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
@@ -196,10 +317,14 @@ func (a *RoleApi) GetRole(c *gin.Context) {
 // @Security Bearer
 // @Param page query int true "页码" minimum(1)
 // @Param pageSize query int true "每页数量" minimum(1) maximum(100)
-// @Success 200 {object} common.Response{data=GetRoleListResponse} "获取成功"
+// @Param sortBy query string false "排序字段" Enums(sort, createdAt, created_at, roleName, role_name)
+// @Param sortOrder query string false "排序方向" Enums(asc, desc)
+// @Success 200 {object} common.Response{data=common.PagedResponse[system.SysRole]} "获取成功"
 // @Failure 200 {object} common.Response "获取失败"
 // @Router /api/v1/role/list [get]
 func (a *RoleApi) GetRoleList(c *gin.Context) {
+	defer trackOperation(c, "role", "list")()
+
 	var req GetRoleListRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
 		common.Fail(c, "invalid request parameters: "+err.Error())
@@ -207,15 +332,17 @@ func (a *RoleApi) GetRoleList(c *gin.Context) {
 	}
 
 	roleService := systemService.RoleService{}
-	roles, total, err := roleService.GetRoleList(req.Page, req.PageSize)
+	roles, total, err := roleService.GetRoleList(req.Page, req.PageSize, req.SortBy, req.SortOrder)
 	if err != nil {
 		common.Fail(c, err.Error())
 		return
 	}
 
-	common.OkWithData(c, GetRoleListResponse{
-		List:  roles,
-		Total: total,
+	common.OkWithData(c, common.PagedResponse[system.SysRole]{
+		List:     roles,
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
 	})
 }
 
@@ -231,6 +358,8 @@ func (a *RoleApi) GetRoleList(c *gin.Context) {
 // @Failure 200 {object} common.Response "分配失败"
 // @Router /api/v1/role/assign-menus [post]
 func (a *RoleApi) AssignMenus(c *gin.Context) {
+	defer trackOperation(c, "role", "assign_menus")()
+
 	var req AssignMenusRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		common.Fail(c, "invalid request parameters: "+err.Error())
@@ -246,6 +375,36 @@ func (a *RoleApi) AssignMenus(c *gin.Context) {
 	common.OkWithDetailed(c, nil, "menus assigned successfully")
 }
 
+// BulkAssignMenus godoc
+// @Summary 批量分配菜单权限
+// @Description 在单个事务中为多个角色分配菜单权限，任一角色失败则全部回滚
+// @Tags 角色管理
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body BulkAssignMenusRequest true "批量分配菜单权限请求"
+// @Success 200 {object} common.Response "分配成功"
+// @Failure 400 {object} common.Response "参数错误"
+// @Failure 200 {object} common.Response "分配失败"
+// @Router /api/v1/role/bulk-assign-menus [post]
+func (a *RoleApi) BulkAssignMenus(c *gin.Context) {
+	defer trackOperation(c, "role", "bulk_assign_menus")()
+
+	var req BulkAssignMenusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.FailWithStatus(c, http.StatusBadRequest, "invalid request parameters: "+err.Error())
+		return
+	}
+
+	roleService := systemService.RoleService{}
+	if err := roleService.BulkAssignMenus(req.Assignments); err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+
+	common.OkWithDetailed(c, nil, "menus assigned successfully")
+}
+
 // GetRoleMenus godoc
 // @Summary 获取角色菜单权限
 // @Description 获取角色已分配的菜单ID列表
@@ -258,6 +417,8 @@ func (a *RoleApi) AssignMenus(c *gin.Context) {
 // @Failure 200 {object} common.Response "获取失败"
 // @Router /api/v1/role/{id}/menus [get]
 func (a *RoleApi) GetRoleMenus(c *gin.Context) {
+	defer trackOperation(c, "role", "get_menus")()
+
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
@@ -275,6 +436,37 @@ func (a *RoleApi) GetRoleMenus(c *gin.Context) {
 	common.OkWithData(c, menuIDs)
 }
 
+// GetRoleMenuTree godoc
+// @Summary 获取角色菜单树
+// @Description 获取角色已分配菜单的树形结构
+// @Tags 角色管理
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "角色ID"
+// @Success 200 {object} common.Response{data=[]system.SysMenu} "获取成功"
+// @Failure 200 {object} common.Response "获取失败"
+// @Router /api/v1/role/{id}/menu-tree [get]
+func (a *RoleApi) GetRoleMenuTree(c *gin.Context) {
+	defer trackOperation(c, "role", "get_menu_tree")()
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		common.Fail(c, "invalid role ID")
+		return
+	}
+
+	roleService := systemService.RoleService{}
+	tree, err := roleService.GetRoleMenuTree(uint(id))
+	if err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+
+	common.OkWithData(c, tree)
+}
+
 // AssignAPIs godoc
 // @Summary 分配API权限
 // @Description 为角色分配API权限（通过Casbin策略）
@@ -287,6 +479,8 @@ func (a *RoleApi) GetRoleMenus(c *gin.Context) {
 // @Failure 200 {object} common.Response "分配失败"
 // @Router /api/v1/role/assign-apis [post]
 func (a *RoleApi) AssignAPIs(c *gin.Context) {
+	defer trackOperation(c, "role", "assign_apis")()
+
 	var req AssignAPIsRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		common.Fail(c, "invalid request parameters: "+err.Error())
@@ -304,16 +498,18 @@ func (a *RoleApi) AssignAPIs(c *gin.Context) {
 
 // GetRoleAPIs godoc
 // @Summary 获取角色API权限
-// @Description 获取角色已分配的API权限列表
+// @Description 获取角色已分配的API权限列表，description 为对应路由的处理函数名
 // @Tags 角色管理
 // @Accept json
 // @Produce json
 // @Security Bearer
 // @Param id path int true "角色ID"
-// @Success 200 {object} common.Response{data=[][]string} "获取成功"
+// @Success 200 {object} common.Response{data=[]systemService.CasbinPolicyDTO} "获取成功"
 // @Failure 200 {object} common.Response "获取失败"
 // @Router /api/v1/role/{id}/apis [get]
 func (a *RoleApi) GetRoleAPIs(c *gin.Context) {
+	defer trackOperation(c, "role", "get_apis")()
+
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
@@ -322,7 +518,7 @@ func (a *RoleApi) GetRoleAPIs(c *gin.Context) {
 	}
 
 	roleService := systemService.RoleService{}
-	policies, err := roleService.GetRoleAPIs(uint(id))
+	policies, err := roleService.GetRoleCasbinPolicies(uint(id))
 	if err != nil {
 		common.Fail(c, err.Error())
 		return
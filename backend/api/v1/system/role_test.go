@@ -0,0 +1,312 @@
+package system
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k-admin-system/core"
+	"k-admin-system/global"
+	"k-admin-system/model/common"
+	"k-admin-system/model/system"
+	systemService "k-admin-system/service/system"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// newRoleRouter 注册角色CRUD路由（不含认证和鉴权中间件）
+func newRoleRouter() *gin.Engine {
+	roleApi := RoleApi{}
+	r := gin.New()
+	r.POST("/role", roleApi.CreateRole)
+	r.PUT("/role", roleApi.UpdateRole)
+	r.DELETE("/role/:id", roleApi.DeleteRole)
+	r.GET("/role/:id", roleApi.GetRole)
+	r.GET("/role/list", roleApi.GetRoleList)
+	return r
+}
+
+func TestRoleApi_CRUD(t *testing.T) {
+	setupTestEnv(t)
+	r := newRoleRouter()
+
+	// 创建
+	var created system.SysRole
+	resp := doJSON(t, r, http.MethodPost, "/role", CreateRoleRequest{RoleName: "Editor", RoleKey: "editor", Status: true}, &created)
+	if resp.Code != 0 || created.ID == 0 {
+		t.Fatalf("CreateRole response = %+v, role = %+v", resp, created)
+	}
+
+	// 重复的角色键
+	if resp := doJSON(t, r, http.MethodPost, "/role", CreateRoleRequest{RoleName: "Editor 2", RoleKey: "editor"}, nil); resp.Code == 0 {
+		t.Error("CreateRole with a duplicate role key succeeded")
+	}
+
+	// 参数校验
+	if resp := doJSON(t, r, http.MethodPost, "/role", map[string]string{"roleName": "No Key"}, nil); resp.Code == 0 {
+		t.Error("CreateRole without roleKey succeeded")
+	}
+
+	// 查询
+	var fetched system.SysRole
+	resp = doJSON(t, r, http.MethodGet, fmt.Sprintf("/role/%d", created.ID), nil, &fetched)
+	if resp.Code != 0 || fetched.RoleKey != "editor" {
+		t.Fatalf("GetRole response = %+v, role = %+v", resp, fetched)
+	}
+
+	// 更新
+	var updated system.SysRole
+	resp = doJSON(t, r, http.MethodPut, "/role", UpdateRoleRequest{
+		ID: created.ID, RoleName: "Senior Editor", RoleKey: "editor", Status: true, Remark: "updated",
+	}, &updated)
+	if resp.Code != 0 || updated.RoleName != "Senior Editor" {
+		t.Fatalf("UpdateRole response = %+v, role = %+v", resp, updated)
+	}
+
+	// 列表
+	var page common.PagedResponse[system.SysRole]
+	resp = doJSON(t, r, http.MethodGet, "/role/list?page=1&pageSize=10", nil, &page)
+	if resp.Code != 0 || page.Total != 1 || len(page.List) != 1 || page.List[0].RoleName != "Senior Editor" {
+		t.Fatalf("GetRoleList response = %+v, page = %+v", resp, page)
+	}
+
+	// 删除
+	if resp := doJSON(t, r, http.MethodDelete, fmt.Sprintf("/role/%d", created.ID), nil, nil); resp.Code != 0 {
+		t.Fatalf("DeleteRole response = %+v", resp)
+	}
+	if resp := doJSON(t, r, http.MethodGet, fmt.Sprintf("/role/%d", created.ID), nil, nil); resp.Code == 0 {
+		t.Error("GetRole after delete succeeded")
+	}
+}
+
+func TestRoleApi_DeleteRoleWithUsers(t *testing.T) {
+	setupTestEnv(t)
+	r := newRoleRouter()
+
+	role := system.SysRole{RoleName: "Viewer", RoleKey: "viewer", Status: true}
+	if err := global.DB.Create(&role).Error; err != nil {
+		t.Fatalf("failed to create role: %v", err)
+	}
+	if err := global.DB.Create(&system.SysUser{Username: "viewer", Password: "x", RoleID: role.ID, Active: true}).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	if resp := doJSON(t, r, http.MethodDelete, fmt.Sprintf("/role/%d", role.ID), nil, nil); resp.Code == 0 {
+		t.Error("DeleteRole for a role with users succeeded")
+	}
+	if resp := doJSON(t, r, http.MethodDelete, "/role/abc", nil, nil); resp.Code == 0 {
+		t.Error("DeleteRole with an invalid ID succeeded")
+	}
+}
+
+func TestRoleApi_GetRoleAPIsDescribesRoutes(t *testing.T) {
+	setupTestEnv(t)
+
+	enforcer, err := core.InitCasbin()
+	if err != nil {
+		t.Fatalf("failed to init casbin: %v", err)
+	}
+	prevEnforcer, prevRoutes := global.CasbinEnforcer, global.Routes
+	global.CasbinEnforcer = enforcer
+	t.Cleanup(func() {
+		global.CasbinEnforcer, global.Routes = prevEnforcer, prevRoutes
+	})
+
+	role := &system.SysRole{RoleName: "Editor", RoleKey: "editor", Status: true}
+	if err := global.DB.Create(role).Error; err != nil {
+		t.Fatalf("failed to create role: %v", err)
+	}
+	policies := [][]string{
+		{"editor", "/api/v1/user/list", "GET"},
+		{"editor", "/api/v1/user/:id", "GET"},
+		{"editor", "/api/v1/unknown", "GET"},
+	}
+	if _, err := enforcer.AddPolicies(policies); err != nil {
+		t.Fatalf("AddPolicies() error = %v", err)
+	}
+
+	userApi := UserApi{}
+	roleApi := RoleApi{}
+	r := gin.New()
+	r.GET("/api/v1/user/list", userApi.GetUserList)
+	r.GET("/api/v1/user/:id", userApi.GetUser)
+	r.GET("/api/v1/role/:id/apis", roleApi.GetRoleAPIs)
+	global.Routes = r.Routes()
+
+	var result []systemService.CasbinPolicyDTO
+	if resp := doJSON(t, r, http.MethodGet, fmt.Sprintf("/api/v1/role/%d/apis", role.ID), nil, &result); resp.Code != 0 {
+		t.Fatalf("GetRoleAPIs response = %+v", resp)
+	}
+
+	descriptions := map[string]string{}
+	for _, policy := range result {
+		descriptions[policy.Method+" "+policy.Path] = policy.Description
+	}
+	want := map[string]string{
+		"GET /api/v1/user/list": "UserApi.GetUserList",
+		"GET /api/v1/user/:id":  "UserApi.GetUser",
+		"GET /api/v1/unknown":   "",
+	}
+	for key, description := range want {
+		if got, ok := descriptions[key]; !ok || got != description {
+			t.Errorf("description of %s = %q, want %q", key, got, description)
+		}
+	}
+}
+
+func TestRoleApi_PatchRoleRemarkOnlyChangesRemark(t *testing.T) {
+	setupTestEnv(t)
+	roleApi := RoleApi{}
+	r := gin.New()
+	r.PATCH("/role/:id/remark", roleApi.PatchRoleRemark)
+
+	role := system.SysRole{
+		RoleName: "Editor", RoleKey: "editor", DataScope: "dept", Sort: 3, Status: false,
+		Remark: "old remark", DBInspectorFilter: `{"orders": "status = 'paid'"}`,
+	}
+	if err := global.DB.Create(&role).Error; err != nil {
+		t.Fatalf("failed to create role: %v", err)
+	}
+	var before system.SysRole
+	if err := global.DB.First(&before, role.ID).Error; err != nil {
+		t.Fatalf("failed to load role: %v", err)
+	}
+
+	for _, remark := range []string{"new remark", ""} {
+		if resp := doJSON(t, r, http.MethodPatch, fmt.Sprintf("/role/%d/remark", role.ID), PatchRoleRemarkRequest{Remark: remark}, nil); resp.Code != 0 {
+			t.Fatalf("PatchRoleRemark response = %+v", resp)
+		}
+		var after system.SysRole
+		if err := global.DB.First(&after, role.ID).Error; err != nil {
+			t.Fatalf("failed to load role: %v", err)
+		}
+		if after.Remark != remark {
+			t.Errorf("remark = %q, want %q", after.Remark, remark)
+		}
+		// 除备注和更新时间外的字段保持不变
+		want := before
+		want.Remark, want.UpdatedAt = after.Remark, after.UpdatedAt
+		if !reflect.DeepEqual(after, want) {
+			t.Errorf("role after patch = %+v, want %+v", after, want)
+		}
+	}
+
+	if resp := doJSON(t, r, http.MethodPatch, fmt.Sprintf("/role/%d/remark", role.ID+1), PatchRoleRemarkRequest{Remark: "x"}, nil); resp.Code == 0 {
+		t.Error("PatchRoleRemark of a missing role succeeded")
+	}
+}
+
+func TestRoleApi_BadInputReturns400(t *testing.T) {
+	setupTestEnv(t)
+	roleApi := RoleApi{}
+	r := gin.New()
+	r.POST("/role/create-full", roleApi.CreateRoleFull)
+	r.PATCH("/role/:id/remark", roleApi.PatchRoleRemark)
+	r.POST("/role/bulk-assign-menus", roleApi.BulkAssignMenus)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   interface{}
+	}{
+		{"create-full without role key", http.MethodPost, "/role/create-full", map[string]string{"roleName": "Editor"}},
+		{"create-full with a malformed policy", http.MethodPost, "/role/create-full", map[string]interface{}{
+			"roleName": "Editor", "roleKey": "editor", "policies": [][]string{{"/api/v1/user/list"}},
+		}},
+		{"remark with an invalid role ID", http.MethodPatch, "/role/abc/remark", PatchRoleRemarkRequest{Remark: "x"}},
+		{"remark too long", http.MethodPatch, "/role/1/remark", PatchRoleRemarkRequest{Remark: strings.Repeat("x", 256)}},
+		{"bulk assign without assignments", http.MethodPost, "/role/bulk-assign-menus", BulkAssignMenusRequest{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, resp := doJSONWithStatus(t, r, tt.method, tt.path, tt.body, nil)
+			if status != http.StatusBadRequest || resp.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, code = %d, want 400/400 (%s)", status, resp.Code, resp.Msg)
+			}
+		})
+	}
+}
+
+func TestRoleApi_CreateRoleFullRollsBackOnFailure(t *testing.T) {
+	setupTestEnv(t)
+	enforcer, err := core.InitCasbin()
+	if err != nil {
+		t.Fatalf("failed to init casbin: %v", err)
+	}
+	prevEnforcer := global.CasbinEnforcer
+	global.CasbinEnforcer = enforcer
+	core.InvalidateCasbinCache()
+	t.Cleanup(func() {
+		global.CasbinEnforcer = prevEnforcer
+		core.InvalidateCasbinCache()
+	})
+
+	menu := system.SysMenu{Path: "/system", Name: "System"}
+	if err := global.DB.Create(&menu).Error; err != nil {
+		t.Fatalf("failed to create menu: %v", err)
+	}
+
+	roleApi := RoleApi{}
+	r := gin.New()
+	r.POST("/role/create-full", roleApi.CreateRoleFull)
+
+	tests := []struct {
+		name      string
+		menuIDs   []uint
+		policies  [][]string
+		failTable string // 写入该表时注入错误
+	}{
+		{"invalid policy method", []uint{menu.ID}, [][]string{{"/api/v1/user/list", "FETCH"}}, ""},
+		{"missing menu", []uint{menu.ID, menu.ID + 100}, [][]string{{"/api/v1/user/list", "GET"}}, ""},
+		{"menu assignment fails", []uint{menu.ID}, [][]string{{"/api/v1/user/list", "GET"}}, "sys_role_menus"},
+		{"policy write fails", []uint{menu.ID}, [][]string{{"/api/v1/user/list", "GET"}}, "sys_casbin_rules"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.failTable != "" {
+				if err := global.DB.Callback().Create().Before("gorm:create").Register("test:fail_create", func(db *gorm.DB) {
+					if db.Statement.Table == tt.failTable {
+						db.AddError(errors.New("insert failed"))
+					}
+				}); err != nil {
+					t.Fatalf("failed to register callback: %v", err)
+				}
+				t.Cleanup(func() {
+					_ = global.DB.Callback().Create().Remove("test:fail_create")
+				})
+			}
+
+			req := CreateRoleFullRequest{
+				CreateRoleRequest: CreateRoleRequest{RoleName: "Editor", RoleKey: "editor", Status: true},
+				MenuIDs:           tt.menuIDs,
+				Policies:          tt.policies,
+			}
+			if resp := doJSON(t, r, http.MethodPost, "/role/create-full", req, nil); resp.Code == 0 {
+				t.Fatal("CreateRoleFull succeeded, want failure")
+			}
+
+			var roles int64
+			global.DB.Unscoped().Model(&system.SysRole{}).Where("role_key = ?", "editor").Count(&roles)
+			if roles != 0 {
+				t.Errorf("expected no role to remain, got %d", roles)
+			}
+			var assignments int64
+			global.DB.Table("sys_role_menus").Count(&assignments)
+			if assignments != 0 {
+				t.Errorf("expected no menu assignments to remain, got %d", assignments)
+			}
+			policies, err := enforcer.GetFilteredPolicy(0, "editor")
+			if err != nil {
+				t.Fatalf("failed to get policies: %v", err)
+			}
+			if len(policies) != 0 {
+				t.Errorf("expected no policies to remain, got %v", policies)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package system
+
+import (
+	"k-admin-system/model/common"
+	"k-admin-system/model/system"
+	systemService "k-admin-system/service/system"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SysConfigApi 系统动态配置API
+type SysConfigApi struct{}
+
+// SetSysConfigRequest 设置动态配置请求
+type SetSysConfigRequest struct {
+	Value       string `json:"value"`
+	Category    string `json:"category" binding:"max=50"`
+	Description string `json:"description" binding:"max=255"`
+}
+
+// GetConfigList godoc
+// @Summary 获取动态配置列表
+// @Description 获取系统动态配置，可按分类过滤
+// @Tags 系统配置
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param category query string false "配置分类"
+// @Success 200 {object} common.Response{data=[]system.SysConfig} "获取成功"
+// @Failure 200 {object} common.Response "获取失败"
+// @Router /api/v1/system/config [get]
+func (a *SysConfigApi) GetConfigList(c *gin.Context) {
+	sysConfigService := systemService.SysConfigService{}
+	configs, err := sysConfigService.GetConfigList(c.Query("category"))
+	if err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+
+	common.OkWithData(c, configs)
+}
+
+// SetConfig godoc
+// @Summary 设置动态配置
+// @Description 创建或更新指定键的配置值，修改立即生效
+// @Tags 系统配置
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param key path string true "配置键"
+// @Param request body SetSysConfigRequest true "设置动态配置请求"
+// @Success 200 {object} common.Response{data=system.SysConfig} "设置成功"
+// @Failure 200 {object} common.Response "设置失败"
+// @Router /api/v1/system/config/{key} [put]
+func (a *SysConfigApi) SetConfig(c *gin.Context) {
+	key := c.Param("key")
+	if key == "" || len(key) > 100 {
+		common.Fail(c, "invalid request parameters: key must be 1-100 characters")
+		return
+	}
+
+	var req SetSysConfigRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.Fail(c, "invalid request parameters: "+err.Error())
+		return
+	}
+
+	config := &system.SysConfig{
+		Key:         key,
+		Value:       req.Value,
+		Category:    req.Category,
+		Description: req.Description,
+	}
+
+	sysConfigService := systemService.SysConfigService{}
+	if err := sysConfigService.Set(config); err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+
+	common.OkWithData(c, config)
+}
@@ -0,0 +1,91 @@
+package system
+
+import (
+	"runtime"
+	"time"
+
+	"k-admin-system/model/common"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SysInfoApi 服务器信息API
+// 版本与构建信息在 main.go 中通过 -ldflags 注入
+type SysInfoApi struct {
+	Version   string
+	GitCommit string
+	BuildTime string
+	StartTime time.Time
+}
+
+// SysInfoResponse 服务器信息响应
+type SysInfoResponse struct {
+	Version       string `json:"version"`
+	GoVersion     string `json:"goVersion"`
+	UptimeSeconds int64  `json:"uptimeSeconds"`
+	GitCommit     string `json:"gitCommit"`
+	BuildTime     string `json:"buildTime"`
+}
+
+// SysInfoV2Response 服务器信息响应（v2）
+// 构建信息与运行时信息分组返回，并附带启动时间
+type SysInfoV2Response struct {
+	Build   SysBuildInfo   `json:"build"`
+	Runtime SysRuntimeInfo `json:"runtime"`
+}
+
+// SysBuildInfo 构建信息
+type SysBuildInfo struct {
+	Version   string `json:"version"`
+	GitCommit string `json:"gitCommit"`
+	BuildTime string `json:"buildTime"`
+}
+
+// SysRuntimeInfo 运行时信息
+type SysRuntimeInfo struct {
+	GoVersion     string    `json:"goVersion"`
+	StartTime     time.Time `json:"startTime"`
+	UptimeSeconds int64     `json:"uptimeSeconds"`
+}
+
+// GetSysInfo godoc
+// @Summary 获取服务器信息
+// @Description 获取服务器版本、Go版本、运行时长和构建信息（无需认证）
+// @Tags System
+// @Accept json
+// @Produce json
+// @Success 200 {object} common.Response{data=SysInfoResponse}
+// @Router /api/v1/system/info [get]
+func (a *SysInfoApi) GetSysInfo(c *gin.Context) {
+	common.OkWithData(c, SysInfoResponse{
+		Version:       a.Version,
+		GoVersion:     runtime.Version(),
+		UptimeSeconds: int64(time.Since(a.StartTime).Seconds()),
+		GitCommit:     a.GitCommit,
+		BuildTime:     a.BuildTime,
+	})
+}
+
+// GetSysInfoV2 godoc
+// @Summary 获取服务器信息（v2）
+// @Description 通过 Accept: application/vnd.kadmin.v2+json 请求，构建信息与运行时信息分组返回（无需认证）
+// @Tags System
+// @Accept json
+// @Produce json
+// @Param Accept header string true "application/vnd.kadmin.v2+json"
+// @Success 200 {object} common.Response{data=SysInfoV2Response}
+// @Router /api/v1/system/info [get]
+func (a *SysInfoApi) GetSysInfoV2(c *gin.Context) {
+	common.OkWithData(c, SysInfoV2Response{
+		Build: SysBuildInfo{
+			Version:   a.Version,
+			GitCommit: a.GitCommit,
+			BuildTime: a.BuildTime,
+		},
+		Runtime: SysRuntimeInfo{
+			GoVersion:     runtime.Version(),
+			StartTime:     a.StartTime,
+			UptimeSeconds: int64(time.Since(a.StartTime).Seconds()),
+		},
+	})
+}
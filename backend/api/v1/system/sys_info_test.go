@@ -0,0 +1,44 @@
+package system
+
+import (
+	"net/http"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestSysInfoApi_GetSysInfo(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	api := &SysInfoApi{Version: "v1.2.3", GitCommit: "abc1234", BuildTime: "2026-10-01T00:00:00Z", StartTime: time.Now().Add(-90 * time.Second)}
+	r := gin.New()
+	r.GET("/system/info", api.GetSysInfo)
+	r.GET("/system/info/v2", api.GetSysInfoV2)
+
+	var info SysInfoResponse
+	if resp := doJSON(t, r, http.MethodGet, "/system/info", nil, &info); resp.Code != 0 {
+		t.Fatalf("GetSysInfo response = %+v", resp)
+	}
+	if !strings.HasPrefix(info.GoVersion, "go") || info.GoVersion != runtime.Version() {
+		t.Errorf("goVersion = %q, want %q", info.GoVersion, runtime.Version())
+	}
+	if info.Version != "v1.2.3" || info.GitCommit != "abc1234" || info.BuildTime != "2026-10-01T00:00:00Z" {
+		t.Errorf("build info = %+v", info)
+	}
+	if info.UptimeSeconds < 90 {
+		t.Errorf("uptimeSeconds = %d, want at least 90", info.UptimeSeconds)
+	}
+
+	var infoV2 SysInfoV2Response
+	if resp := doJSON(t, r, http.MethodGet, "/system/info/v2", nil, &infoV2); resp.Code != 0 {
+		t.Fatalf("GetSysInfoV2 response = %+v", resp)
+	}
+	if !strings.HasPrefix(infoV2.Runtime.GoVersion, "go") {
+		t.Errorf("runtime.goVersion = %q, want a go prefix", infoV2.Runtime.GoVersion)
+	}
+	if infoV2.Build.Version != "v1.2.3" || !infoV2.Runtime.StartTime.Equal(api.StartTime) {
+		t.Errorf("v2 info = %+v", infoV2)
+	}
+}
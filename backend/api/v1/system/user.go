@@ -1,11 +1,16 @@
 package system
 
 import (
+	"errors"
+	"net/http"
 	"strconv"
+	"time"
 
+	"k-admin-system/global"
 	"k-admin-system/model/common"
 	"k-admin-system/model/system"
 	systemService "k-admin-system/service/system"
+	"k-admin-system/utils"
 
 	"github.com/gin-gonic/gin"
 )
@@ -16,6 +21,7 @@ type UserApi struct{}
 type LoginRequest struct {
 	Username string `json:"username" binding:"required"`
 	Password string `json:"password" binding:"required"`
+	TOTPCode string `json:"totpCode"` // 已启用两步验证的用户必填
 }
 
 // LoginResponse 登录响应
@@ -31,7 +37,7 @@ type CreateUserRequest struct {
 	Password  string `json:"password" binding:"required"`
 	Nickname  string `json:"nickname"`
 	HeaderImg string `json:"headerImg"`
-	Phone     string `json:"phone"`
+	Phone     string `json:"phone" binding:"omitempty,e164"`
 	Email     string `json:"email"`
 	RoleID    uint   `json:"roleId" binding:"required"`
 	Active    bool   `json:"active"`
@@ -44,7 +50,7 @@ type UpdateUserRequest struct {
 	Password  string `json:"password"` // 可选，如果提供则更新密码
 	Nickname  string `json:"nickname"`
 	HeaderImg string `json:"headerImg"`
-	Phone     string `json:"phone"`
+	Phone     string `json:"phone"` // 可能为脱敏后的原值，还原后在服务层校验E.164格式
 	Email     string `json:"email"`
 	RoleID    uint   `json:"roleId" binding:"required"`
 	Active    bool   `json:"active"`
@@ -56,10 +62,40 @@ type ChangePasswordRequest struct {
 	NewPassword string `json:"newPassword" binding:"required"`
 }
 
+// ChangeExpiredPasswordRequest 修改过期密码请求（无需登录）
+type ChangeExpiredPasswordRequest struct {
+	Username    string `json:"username" binding:"required"`
+	OldPassword string `json:"oldPassword" binding:"required"`
+	NewPassword string `json:"newPassword" binding:"required"`
+	TOTPCode    string `json:"totpCode"` // 已启用两步验证的用户必填
+}
+
 // ResetPasswordRequest 重置密码请求
 type ResetPasswordRequest struct {
 	UserID      uint   `json:"userId" binding:"required"`
-	NewPassword string `json:"newPassword" binding:"required"`
+	NewPassword string `json:"newPassword"` // 可选，为空时自动生成随机密码
+}
+
+// ResetPasswordResponse 重置密码响应
+type ResetPasswordResponse struct {
+	GeneratedPassword string `json:"generatedPassword,omitempty"` // 仅在自动生成密码时返回
+}
+
+// BatchToggleStatusRequest 批量切换状态请求
+type BatchToggleStatusRequest struct {
+	IDs    []uint `json:"ids" binding:"required,min=1,max=500"`
+	Active bool   `json:"active"`
+}
+
+// MergeUsersRequest 合并重复账号请求
+type MergeUsersRequest struct {
+	PrimaryID   uint `json:"primaryId" binding:"required"`
+	DuplicateID uint `json:"duplicateId" binding:"required"`
+}
+
+// BatchToggleStatusResponse 批量切换状态响应
+type BatchToggleStatusResponse struct {
+	Affected int64 `json:"affected"`
 }
 
 // ToggleStatusRequest 切换状态请求
@@ -80,10 +116,69 @@ type GetUserListRequest struct {
 	Active   *bool  `form:"active"` // 使用指针以区分未设置和false
 }
 
-// GetUserListResponse 获取用户列表响应
-type GetUserListResponse struct {
-	List  []system.SysUser `json:"list"`
-	Total int64            `json:"total"`
+// SearchUsersRequest 用户快速搜索请求
+type SearchUsersRequest struct {
+	Q     string `form:"q" binding:"required"`
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=50"`
+}
+
+// GetUsersCreatedRequest 按创建时间查询用户请求
+type GetUsersCreatedRequest struct {
+	Start string `form:"start" binding:"required"`
+	End   string `form:"end" binding:"required"`
+}
+
+// GetDormantUsersRequest 按最后登录时间查询用户请求
+type GetDormantUsersRequest struct {
+	Start  string `form:"start" binding:"required"`
+	End    string `form:"end" binding:"required"`
+	Active *bool  `form:"active"`
+}
+
+// CleanupInactiveUsersRequest 清理不活跃用户请求
+type CleanupInactiveUsersRequest struct {
+	InactiveDays int `form:"inactiveDays" binding:"required,min=1"`
+}
+
+// GetUserActivityRequest 获取用户活动概要请求
+type GetUserActivityRequest struct {
+	Days int `form:"days" binding:"omitempty,min=1,max=365"`
+}
+
+// SetupMFAResponse 两步验证设置响应
+type SetupMFAResponse struct {
+	Secret    string `json:"secret"`
+	QRCodeURL string `json:"qrCodeUrl"` // data URI格式的二维码图片
+}
+
+// VerifyMFARequest 两步验证校验请求
+type VerifyMFARequest struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
+// CreateAPIKeyRequest 生成API密钥请求
+type CreateAPIKeyRequest struct {
+	Name          string `json:"name" binding:"required,max=100"`
+	ExpiresInDays int    `json:"expiresInDays" binding:"omitempty,min=1"` // 为空表示永不过期
+}
+
+// CreateAPIKeyResponse 生成API密钥响应
+type CreateAPIKeyResponse struct {
+	Key    string            `json:"key"` // 原始密钥，仅在生成时返回一次
+	APIKey *system.SysAPIKey `json:"apiKey"`
+}
+
+// codePasswordExpired 密码已过期时登录接口返回的业务错误码
+const codePasswordExpired = 4003
+
+// PasswordExpiredResponse 密码过期响应
+type PasswordExpiredResponse struct {
+	RequirePasswordChange bool `json:"requirePasswordChange"`
+}
+
+// CleanupInactiveUsersResponse 清理不活跃用户响应
+type CleanupInactiveUsersResponse struct {
+	DeletedCount int64 `json:"deletedCount"`
 }
 
 // Login godoc
@@ -94,26 +189,35 @@ type GetUserListResponse struct {
 // @Produce json
 // @Param request body LoginRequest true "登录请求"
 // @Success 200 {object} common.Response{data=LoginResponse} "登录成功"
-// @Failure 200 {object} common.Response "登录失败"
+// @Failure 200 {object} common.Response{data=PasswordExpiredResponse} "登录失败；密码过期时 code 为 4003，需调用 /api/v1/user/change-expired-password 修改密码"
 // @Router /api/v1/user/login [post]
 func (a *UserApi) Login(c *gin.Context) {
+	defer trackOperation(c, "user", "login")()
+
 	var req LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		common.Fail(c, "invalid request parameters: "+err.Error())
+		common.FailWithStatus(c, http.StatusBadRequest, "invalid request parameters: "+err.Error())
 		return
 	}
 
 	userService := systemService.UserService{}
-	accessToken, refreshToken, user, err := userService.Login(req.Username, req.Password)
+	fingerprint := utils.ComputeFingerprint(c.GetHeader("User-Agent"), c.GetHeader("Accept-Language"))
+	accessToken, refreshToken, user, err := userService.Login(req.Username, req.Password, req.TOTPCode, fingerprint, c.ClientIP(), c.Request.UserAgent())
 	if err != nil {
+		if errors.Is(err, systemService.ErrPasswordExpired) {
+			// 客户端根据 requirePasswordChange 引导用户修改密码
+			common.FailWithCodeDetailed(c, codePasswordExpired, PasswordExpiredResponse{RequirePasswordChange: true}, err.Error())
+			return
+		}
 		common.Fail(c, err.Error())
 		return
 	}
 
+	sanitized := user.Sanitize().InTimezone(c.GetString("timezone"))
 	common.OkWithData(c, LoginResponse{
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
-		User:         user,
+		User:         &sanitized,
 	})
 }
 
@@ -129,9 +233,11 @@ func (a *UserApi) Login(c *gin.Context) {
 // @Failure 200 {object} common.Response "创建失败"
 // @Router /api/v1/user [post]
 func (a *UserApi) CreateUser(c *gin.Context) {
+	defer trackOperation(c, "user", "create")()
+
 	var req CreateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		common.Fail(c, "invalid request parameters: "+err.Error())
+		common.FailWithStatus(c, http.StatusBadRequest, "invalid request parameters: "+err.Error())
 		return
 	}
 
@@ -152,7 +258,7 @@ func (a *UserApi) CreateUser(c *gin.Context) {
 		return
 	}
 
-	common.OkWithData(c, user)
+	common.OkWithData(c, user.Sanitize().InTimezone(c.GetString("timezone")))
 }
 
 // UpdateUser godoc
@@ -167,9 +273,11 @@ func (a *UserApi) CreateUser(c *gin.Context) {
 // @Failure 200 {object} common.Response "更新失败"
 // @Router /api/v1/user [put]
 func (a *UserApi) UpdateUser(c *gin.Context) {
+	defer trackOperation(c, "user", "update")()
+
 	var req UpdateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		common.Fail(c, "invalid request parameters: "+err.Error())
+		common.FailWithStatus(c, http.StatusBadRequest, "invalid request parameters: "+err.Error())
 		return
 	}
 
@@ -191,7 +299,7 @@ func (a *UserApi) UpdateUser(c *gin.Context) {
 		return
 	}
 
-	common.OkWithData(c, user)
+	common.OkWithData(c, user.Sanitize().InTimezone(c.GetString("timezone")))
 }
 
 // DeleteUser godoc
@@ -206,10 +314,12 @@ func (a *UserApi) UpdateUser(c *gin.Context) {
 // @Failure 200 {object} common.Response "删除失败"
 // @Router /api/v1/user/{id} [delete]
 func (a *UserApi) DeleteUser(c *gin.Context) {
+	defer trackOperation(c, "user", "delete")()
+
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		common.Fail(c, "invalid user ID")
+		common.FailWithStatus(c, http.StatusBadRequest, "invalid user ID")
 		return
 	}
 
@@ -232,23 +342,31 @@ func (a *UserApi) DeleteUser(c *gin.Context) {
 // @Param id path int true "用户ID"
 // @Success 200 {object} common.Response{data=system.SysUser} "获取成功"
 // @Failure 200 {object} common.Response "获取失败"
+// @Failure 400 {object} common.Response "用户ID无效"
+// @Failure 404 {object} common.Response "用户不存在"
 // @Router /api/v1/user/{id} [get]
 func (a *UserApi) GetUser(c *gin.Context) {
+	defer trackOperation(c, "user", "get")()
+
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		common.Fail(c, "invalid user ID")
+		common.FailWithStatus(c, http.StatusBadRequest, "invalid user ID")
 		return
 	}
 
 	userService := systemService.UserService{}
 	user, err := userService.GetUserByID(uint(id))
 	if err != nil {
+		if err.Error() == "user not found" {
+			common.FailWithStatus(c, http.StatusNotFound, err.Error())
+			return
+		}
 		common.Fail(c, err.Error())
 		return
 	}
 
-	common.OkWithData(c, user)
+	common.OkWithData(c, user.Sanitize().InTimezone(c.GetString("timezone")))
 }
 
 // GetUserList godoc
@@ -266,13 +384,15 @@ func (a *UserApi) GetUser(c *gin.Context) {
 // @Param email query string false "邮箱（模糊搜索）"
 // @Param roleId query int false "角色ID"
 // @Param active query bool false "是否激活"
-// @Success 200 {object} common.Response{data=GetUserListResponse} "获取成功"
+// @Success 200 {object} common.Response{data=common.PagedResponse[system.SysUser]} "获取成功"
 // @Failure 200 {object} common.Response "获取失败"
 // @Router /api/v1/user/list [get]
 func (a *UserApi) GetUserList(c *gin.Context) {
+	defer trackOperation(c, "user", "list")()
+
 	var req GetUserListRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
-		common.Fail(c, "invalid request parameters: "+err.Error())
+		common.FailWithStatus(c, http.StatusBadRequest, "invalid request parameters: "+err.Error())
 		return
 	}
 
@@ -304,9 +424,16 @@ func (a *UserApi) GetUserList(c *gin.Context) {
 		return
 	}
 
-	common.OkWithData(c, GetUserListResponse{
-		List:  users,
-		Total: total,
+	// 响应前对用户信息脱敏
+	for i := range users {
+		users[i] = users[i].Sanitize().InTimezone(c.GetString("timezone"))
+	}
+
+	common.OkWithData(c, common.PagedResponse[system.SysUser]{
+		List:     users,
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
 	})
 }
 
@@ -322,9 +449,11 @@ func (a *UserApi) GetUserList(c *gin.Context) {
 // @Failure 200 {object} common.Response "修改失败"
 // @Router /api/v1/user/change-password [post]
 func (a *UserApi) ChangePassword(c *gin.Context) {
+	defer trackOperation(c, "user", "change_password")()
+
 	var req ChangePasswordRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		common.Fail(c, "invalid request parameters: "+err.Error())
+		common.FailWithStatus(c, http.StatusBadRequest, "invalid request parameters: "+err.Error())
 		return
 	}
 
@@ -344,31 +473,78 @@ func (a *UserApi) ChangePassword(c *gin.Context) {
 	common.OkWithDetailed(c, nil, "password changed successfully")
 }
 
+// ChangeExpiredPassword godoc
+// @Summary 修改过期密码
+// @Description 密码过期后无法登录，凭用户名和旧密码（已启用两步验证时还需TOTP验证码）修改密码，修改后重新登录
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Param request body ChangeExpiredPasswordRequest true "修改过期密码请求"
+// @Success 200 {object} common.Response "修改成功"
+// @Failure 200 {object} common.Response "修改失败"
+// @Router /api/v1/user/change-expired-password [post]
+func (a *UserApi) ChangeExpiredPassword(c *gin.Context) {
+	defer trackOperation(c, "user", "change_expired_password")()
+
+	var req ChangeExpiredPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.FailWithStatus(c, http.StatusBadRequest, "invalid request parameters: "+err.Error())
+		return
+	}
+
+	userService := systemService.UserService{}
+	if err := userService.ChangeExpiredPassword(req.Username, req.OldPassword, req.TOTPCode, req.NewPassword); err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+
+	common.OkWithDetailed(c, nil, "password changed successfully")
+}
+
 // ResetPassword godoc
 // @Summary 重置密码
-// @Description 管理员重置用户密码（不需要验证旧密码）
+// @Description 管理员重置用户密码（不需要验证旧密码），newPassword为空时自动生成随机密码并返回
 // @Tags 用户管理
 // @Accept json
 // @Produce json
 // @Security Bearer
 // @Param request body ResetPasswordRequest true "重置密码请求"
-// @Success 200 {object} common.Response "重置成功"
+// @Success 200 {object} common.Response{data=ResetPasswordResponse} "重置成功"
 // @Failure 200 {object} common.Response "重置失败"
 // @Router /api/v1/user/reset-password [post]
 func (a *UserApi) ResetPassword(c *gin.Context) {
+	defer trackOperation(c, "user", "reset_password")()
+
 	var req ResetPasswordRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		common.Fail(c, "invalid request parameters: "+err.Error())
+		common.FailWithStatus(c, http.StatusBadRequest, "invalid request parameters: "+err.Error())
 		return
 	}
 
+	// 未提供新密码时，按安全配置自动生成随机密码
+	var resp ResetPasswordResponse
+	if req.NewPassword == "" {
+		securityConfig := global.Config.Security
+		generated, err := utils.GeneratePassword(securityConfig.PasswordLength, utils.PasswordOptions{
+			MinUpper:  securityConfig.PasswordMinUpper,
+			MinDigit:  securityConfig.PasswordMinDigit,
+			MinSymbol: securityConfig.PasswordMinSymbol,
+		})
+		if err != nil {
+			common.Fail(c, "failed to generate password: "+err.Error())
+			return
+		}
+		req.NewPassword = generated
+		resp.GeneratedPassword = generated
+	}
+
 	userService := systemService.UserService{}
 	if err := userService.ResetPassword(req.UserID, req.NewPassword); err != nil {
 		common.Fail(c, err.Error())
 		return
 	}
 
-	common.OkWithDetailed(c, nil, "password reset successfully")
+	common.OkWithDetailed(c, resp, "password reset successfully")
 }
 
 // ToggleStatus godoc
@@ -383,9 +559,11 @@ func (a *UserApi) ResetPassword(c *gin.Context) {
 // @Failure 200 {object} common.Response "操作失败"
 // @Router /api/v1/user/toggle-status [post]
 func (a *UserApi) ToggleStatus(c *gin.Context) {
+	defer trackOperation(c, "user", "toggle_status")()
+
 	var req ToggleStatusRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		common.Fail(c, "invalid request parameters: "+err.Error())
+		common.FailWithStatus(c, http.StatusBadRequest, "invalid request parameters: "+err.Error())
 		return
 	}
 
@@ -397,3 +575,497 @@ func (a *UserApi) ToggleStatus(c *gin.Context) {
 
 	common.OkWithDetailed(c, nil, "user status updated successfully")
 }
+
+// BatchToggleStatus godoc
+// @Summary 批量切换用户状态
+// @Description 批量启用或禁用用户账户，返回受影响的用户数量
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body BatchToggleStatusRequest true "批量切换状态请求"
+// @Success 200 {object} common.Response{data=BatchToggleStatusResponse} "操作成功"
+// @Failure 200 {object} common.Response "操作失败"
+// @Router /api/v1/user/batch-status [post]
+func (a *UserApi) BatchToggleStatus(c *gin.Context) {
+	defer trackOperation(c, "user", "batch_toggle_status")()
+
+	var req BatchToggleStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.FailWithStatus(c, http.StatusBadRequest, "invalid request parameters: "+err.Error())
+		return
+	}
+
+	userService := systemService.UserService{}
+	affected, err := userService.BatchToggleStatus(req.IDs, req.Active)
+	if err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+
+	common.OkWithDetailed(c, BatchToggleStatusResponse{Affected: affected}, "user status updated successfully")
+}
+
+// MergeUsers godoc
+// @Summary 合并重复账号
+// @Description 撤销重复账号的登录会话和API密钥并软删除该账号，记录一条合并审计事件；重复账号不能是管理员或当前用户
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body MergeUsersRequest true "合并账号请求"
+// @Success 200 {object} common.Response "合并成功"
+// @Failure 400 {object} common.Response "参数错误"
+// @Failure 200 {object} common.Response "合并失败"
+// @Router /api/v1/user/merge [post]
+func (a *UserApi) MergeUsers(c *gin.Context) {
+	defer trackOperation(c, "user", "merge")()
+
+	var req MergeUsersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.FailWithStatus(c, http.StatusBadRequest, "invalid request parameters: "+err.Error())
+		return
+	}
+
+	userService := systemService.UserService{}
+	if err := userService.MergeDuplicateAccounts(req.PrimaryID, req.DuplicateID, c.GetUint("userId")); err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+
+	common.OkWithDetailed(c, nil, "users merged successfully")
+}
+
+// GetUserRoles godoc
+// @Summary 获取用户角色
+// @Description 获取用户拥有的角色列表
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "用户ID"
+// @Success 200 {object} common.Response{data=[]system.SysRole} "获取成功"
+// @Failure 200 {object} common.Response "获取失败"
+// @Router /api/v1/user/{id}/roles [get]
+func (a *UserApi) GetUserRoles(c *gin.Context) {
+	defer trackOperation(c, "user", "get_roles")()
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		common.FailWithStatus(c, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	roleService := systemService.RoleService{}
+	roles, err := roleService.GetRolesByUserID(uint(id))
+	if err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+
+	common.OkWithData(c, roles)
+}
+
+// GetUserActivity godoc
+// @Summary 获取用户活动概要
+// @Description 基于审计日志统计用户在最近N天内的请求总数、请求方法分布、最常访问路径和最后活动时间
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "用户ID"
+// @Param days query int false "统计天数（默认30）" minimum(1) maximum(365)
+// @Success 200 {object} common.Response{data=systemService.ActivitySummary} "获取成功"
+// @Failure 200 {object} common.Response "获取失败"
+// @Router /api/v1/user/{id}/activity [get]
+func (a *UserApi) GetUserActivity(c *gin.Context) {
+	defer trackOperation(c, "user", "get_activity")()
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		common.FailWithStatus(c, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	var req GetUserActivityRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		common.FailWithStatus(c, http.StatusBadRequest, "invalid request parameters: "+err.Error())
+		return
+	}
+	if req.Days == 0 {
+		req.Days = 30
+	}
+
+	userService := systemService.UserService{}
+	summary, err := userService.GetUserActivitySummary(uint(id), time.Duration(req.Days)*24*time.Hour)
+	if err != nil {
+		if err.Error() == "user not found" {
+			common.FailWithStatus(c, http.StatusNotFound, err.Error())
+			return
+		}
+		common.Fail(c, err.Error())
+		return
+	}
+
+	common.OkWithData(c, summary)
+}
+
+// GetUserSessions godoc
+// @Summary 获取用户登录会话
+// @Description 获取用户当前未过期的登录会话列表，按创建时间倒序
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "用户ID"
+// @Success 200 {object} common.Response{data=[]system.SysUserSession} "获取成功"
+// @Failure 200 {object} common.Response "获取失败"
+// @Router /api/v1/user/{id}/sessions [get]
+func (a *UserApi) GetUserSessions(c *gin.Context) {
+	defer trackOperation(c, "user", "get_sessions")()
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		common.FailWithStatus(c, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	sessionService := systemService.SessionService{}
+	sessions, err := sessionService.GetUserSessions(uint(id))
+	if err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+
+	common.OkWithData(c, sessions)
+}
+
+// RevokeSession godoc
+// @Summary 撤销登录会话
+// @Description 将会话的访问令牌和刷新令牌加入黑名单并删除会话记录
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param sessionID path int true "会话ID"
+// @Success 200 {object} common.Response "撤销成功"
+// @Failure 200 {object} common.Response "撤销失败"
+// @Router /api/v1/user/session/{sessionID} [delete]
+func (a *UserApi) RevokeSession(c *gin.Context) {
+	defer trackOperation(c, "user", "revoke_session")()
+
+	idStr := c.Param("sessionID")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		common.FailWithStatus(c, http.StatusBadRequest, "invalid session ID")
+		return
+	}
+
+	sessionService := systemService.SessionService{}
+	if err := sessionService.RevokeSession(uint(id)); err != nil {
+		if err.Error() == "session not found" {
+			common.FailWithStatus(c, http.StatusNotFound, err.Error())
+			return
+		}
+		common.Fail(c, err.Error())
+		return
+	}
+
+	common.OkWithDetailed(c, nil, "session revoked successfully")
+}
+
+// SetupMFA godoc
+// @Summary 设置两步验证
+// @Description 为当前用户生成TOTP密钥和二维码，需调用校验接口验证后才会启用
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} common.Response{data=SetupMFAResponse} "设置成功"
+// @Failure 200 {object} common.Response "设置失败"
+// @Router /api/v1/user/mfa/setup [post]
+func (a *UserApi) SetupMFA(c *gin.Context) {
+	defer trackOperation(c, "user", "setup_mfa")()
+
+	userID, exists := c.Get("userId")
+	if !exists {
+		common.Fail(c, "user not authenticated")
+		return
+	}
+
+	userService := systemService.UserService{}
+	secret, qrCodeURL, err := userService.SetupMFA(userID.(uint))
+	if err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+
+	common.OkWithData(c, SetupMFAResponse{
+		Secret:    secret,
+		QRCodeURL: qrCodeURL,
+	})
+}
+
+// CreateAPIKey godoc
+// @Summary 生成API密钥
+// @Description 为当前用户生成API密钥，用于服务间调用时通过 X-API-Key 请求头代替JWT令牌认证。原始密钥仅在响应中返回一次
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body CreateAPIKeyRequest true "生成API密钥请求"
+// @Success 200 {object} common.Response{data=CreateAPIKeyResponse} "生成成功"
+// @Failure 200 {object} common.Response "生成失败"
+// @Router /api/v1/user/api-key [post]
+func (a *UserApi) CreateAPIKey(c *gin.Context) {
+	defer trackOperation(c, "user", "create_api_key")()
+
+	userID, exists := c.Get("userId")
+	if !exists {
+		common.Fail(c, "user not authenticated")
+		return
+	}
+
+	var req CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.FailWithStatus(c, http.StatusBadRequest, "invalid request parameters: "+err.Error())
+		return
+	}
+
+	var expiresAt *time.Time
+	if req.ExpiresInDays > 0 {
+		t := time.Now().Add(time.Duration(req.ExpiresInDays) * 24 * time.Hour)
+		expiresAt = &t
+	}
+
+	apiKeyService := systemService.APIKeyService{}
+	key, apiKey, err := apiKeyService.CreateAPIKey(userID.(uint), req.Name, expiresAt)
+	if err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+
+	common.OkWithData(c, CreateAPIKeyResponse{
+		Key:    key,
+		APIKey: apiKey,
+	})
+}
+
+// VerifyMFA godoc
+// @Summary 校验两步验证码
+// @Description 使用TOTP验证码校验当前用户的密钥，首次校验通过后启用两步验证
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body VerifyMFARequest true "校验请求"
+// @Success 200 {object} common.Response "校验成功"
+// @Failure 200 {object} common.Response "校验失败"
+// @Router /api/v1/user/mfa/verify [post]
+func (a *UserApi) VerifyMFA(c *gin.Context) {
+	defer trackOperation(c, "user", "verify_mfa")()
+
+	var req VerifyMFARequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.FailWithStatus(c, http.StatusBadRequest, "invalid request parameters: "+err.Error())
+		return
+	}
+
+	userID, exists := c.Get("userId")
+	if !exists {
+		common.Fail(c, "user not authenticated")
+		return
+	}
+
+	userService := systemService.UserService{}
+	if err := userService.VerifyMFA(userID.(uint), req.Code); err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+
+	common.OkWithDetailed(c, nil, "MFA verified successfully")
+}
+
+// CleanupInactiveUsers godoc
+// @Summary 清理不活跃用户
+// @Description 软删除超过指定天数未登录的用户以及从未登录过的用户（超级管理员除外）
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param inactiveDays query int true "未登录天数" minimum(1)
+// @Success 200 {object} common.Response{data=CleanupInactiveUsersResponse} "清理成功"
+// @Failure 200 {object} common.Response "清理失败"
+// @Router /api/v1/user/cleanup [delete]
+func (a *UserApi) CleanupInactiveUsers(c *gin.Context) {
+	defer trackOperation(c, "user", "cleanup_inactive")()
+
+	var req CleanupInactiveUsersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		common.FailWithStatus(c, http.StatusBadRequest, "invalid request parameters: "+err.Error())
+		return
+	}
+
+	userService := systemService.UserService{}
+	deletedCount, err := userService.CleanupInactiveUsers(time.Duration(req.InactiveDays) * 24 * time.Hour)
+	if err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+
+	common.OkWithData(c, CleanupInactiveUsersResponse{
+		DeletedCount: deletedCount,
+	})
+}
+
+// SearchUsers godoc
+// @Summary 快速搜索用户
+// @Description 按用户名或昵称前缀搜索用户，用于搜索框输入联想，仅返回ID、用户名、昵称和头像
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param q query string true "搜索关键字（前缀匹配）"
+// @Param limit query int false "返回数量上限，默认10" minimum(1) maximum(50)
+// @Success 200 {object} common.Response{data=[]system.SysUser} "搜索成功"
+// @Failure 200 {object} common.Response "搜索失败"
+// @Router /api/v1/user/search [get]
+func (a *UserApi) SearchUsers(c *gin.Context) {
+	defer trackOperation(c, "user", "search")()
+
+	var req SearchUsersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		common.FailWithStatus(c, http.StatusBadRequest, "invalid request parameters: "+err.Error())
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+
+	userService := systemService.UserService{}
+	users, err := userService.SearchUsers(req.Q, req.Limit)
+	if err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+
+	common.OkWithData(c, users)
+}
+
+// GetUsersCreated godoc
+// @Summary 按创建时间查询用户
+// @Description 获取指定时间范围内创建的用户（包含起止时间），时间格式为RFC3339或日期（YYYY-MM-DD，结束日期包含当天）
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param start query string true "开始时间"
+// @Param end query string true "结束时间"
+// @Success 200 {object} common.Response{data=[]system.SysUser} "获取成功"
+// @Failure 200 {object} common.Response "获取失败"
+// @Router /api/v1/user/created [get]
+func (a *UserApi) GetUsersCreated(c *gin.Context) {
+	defer trackOperation(c, "user", "list_created")()
+
+	var req GetUsersCreatedRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		common.FailWithStatus(c, http.StatusBadRequest, "invalid request parameters: "+err.Error())
+		return
+	}
+
+	start, _, err := parseReportTime(req.Start)
+	if err != nil {
+		common.FailWithStatus(c, http.StatusBadRequest, "invalid start time: "+err.Error())
+		return
+	}
+	end, dateOnly, err := parseReportTime(req.End)
+	if err != nil {
+		common.FailWithStatus(c, http.StatusBadRequest, "invalid end time: "+err.Error())
+		return
+	}
+	if dateOnly {
+		// 仅指定日期时包含结束日期当天
+		end = end.Add(24*time.Hour - time.Nanosecond)
+	}
+
+	userService := systemService.UserService{}
+	users, err := userService.GetUsersCreatedBetween(start, end)
+	if err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+
+	for i := range users {
+		users[i] = users[i].Sanitize().InTimezone(c.GetString("timezone"))
+	}
+
+	common.OkWithData(c, users)
+}
+
+// GetDormantUsers godoc
+// @Summary 按最后登录时间查询用户
+// @Description 获取最后登录时间在指定范围内的用户（包含起止时间），用于休眠账号排查。时间格式为RFC3339或日期（YYYY-MM-DD，结束日期包含当天），从未登录的用户不在结果中
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param start query string true "开始时间"
+// @Param end query string true "结束时间"
+// @Param active query bool false "按启用状态过滤"
+// @Success 200 {object} common.Response{data=[]system.SysUser} "获取成功"
+// @Failure 400 {object} common.Response "参数错误"
+// @Failure 200 {object} common.Response "获取失败"
+// @Router /api/v1/user/dormant [get]
+func (a *UserApi) GetDormantUsers(c *gin.Context) {
+	defer trackOperation(c, "user", "list_dormant")()
+
+	var req GetDormantUsersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		common.FailWithStatus(c, http.StatusBadRequest, "invalid request parameters: "+err.Error())
+		return
+	}
+
+	start, _, err := parseReportTime(req.Start)
+	if err != nil {
+		common.FailWithStatus(c, http.StatusBadRequest, "invalid start time: "+err.Error())
+		return
+	}
+	end, dateOnly, err := parseReportTime(req.End)
+	if err != nil {
+		common.FailWithStatus(c, http.StatusBadRequest, "invalid end time: "+err.Error())
+		return
+	}
+	if dateOnly {
+		// 仅指定日期时包含结束日期当天
+		end = end.Add(24*time.Hour - time.Nanosecond)
+	}
+
+	userService := systemService.UserService{}
+	users, err := userService.GetUsersByLastLoginRange(start, end, req.Active)
+	if err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+
+	for i := range users {
+		users[i] = users[i].Sanitize().InTimezone(c.GetString("timezone"))
+	}
+
+	common.OkWithData(c, users)
+}
+
+// parseReportTime 解析RFC3339时间，或按本地时区解析YYYY-MM-DD日期
+// 第二个返回值表示输入是否为仅日期格式
+func parseReportTime(value string) (time.Time, bool, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, false, nil
+	}
+	t, err := time.ParseInLocation(time.DateOnly, value, time.Local)
+	if err != nil {
+		return time.Time{}, false, errors.New("expected RFC3339 or YYYY-MM-DD format")
+	}
+	return t, true, nil
+}
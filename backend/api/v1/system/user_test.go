@@ -0,0 +1,167 @@
+package system
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"k-admin-system/global"
+	"k-admin-system/middleware"
+	"k-admin-system/model/system"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestUserApi_GetUserStatusCodes(t *testing.T) {
+	setupTestEnv(t)
+	userApi := UserApi{}
+	r := gin.New()
+	r.GET("/user/:id", userApi.GetUser)
+	r.POST("/login", userApi.Login)
+
+	user := &system.SysUser{Username: "alice", Password: "hashed", Active: true}
+	if err := global.DB.Create(user).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       interface{}
+		wantStatus int
+		wantCode   int
+	}{
+		{"existing user", http.MethodGet, fmt.Sprintf("/user/%d", user.ID), nil, http.StatusOK, 0},
+		{"invalid id", http.MethodGet, "/user/abc", nil, http.StatusBadRequest, http.StatusBadRequest},
+		{"missing user", http.MethodGet, fmt.Sprintf("/user/%d", user.ID+1), nil, http.StatusNotFound, http.StatusNotFound},
+		{"invalid login body", http.MethodPost, "/login", map[string]string{"username": "alice"}, http.StatusBadRequest, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, resp := doJSONWithStatus(t, r, tt.method, tt.path, tt.body, nil)
+			if status != tt.wantStatus || resp.Code != tt.wantCode {
+				t.Errorf("status = %d, code = %d, want %d/%d (msg %q)", status, resp.Code, tt.wantStatus, tt.wantCode, resp.Msg)
+			}
+		})
+	}
+}
+
+func TestUserApi_BatchToggleStatus(t *testing.T) {
+	setupTestEnv(t)
+	userApi := UserApi{}
+	r := gin.New()
+	r.POST("/user/batch-status", userApi.BatchToggleStatus)
+
+	editor := system.SysRole{RoleName: "Editor", RoleKey: "editor", Status: true}
+	admin := system.SysRole{RoleName: "Admin", RoleKey: "admin", Status: true}
+	for _, role := range []*system.SysRole{&editor, &admin} {
+		if err := global.DB.Create(role).Error; err != nil {
+			t.Fatalf("failed to create role: %v", err)
+		}
+	}
+	createUser := func(name string, roleID uint) system.SysUser {
+		user := system.SysUser{Username: name, Password: "hashed", RoleID: roleID, Active: true}
+		if err := global.DB.Create(&user).Error; err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+		return user
+	}
+
+	var ids []uint
+	for i := 0; i < 10; i++ {
+		ids = append(ids, createUser(fmt.Sprintf("user%02d", i), editor.ID).ID)
+	}
+	untouched := createUser("untouched", editor.ID)
+	root := createUser("root", admin.ID)
+
+	var result BatchToggleStatusResponse
+	resp := doJSON(t, r, http.MethodPost, "/user/batch-status", BatchToggleStatusRequest{IDs: ids, Active: false}, &result)
+	if resp.Code != 0 || result.Affected != 10 {
+		t.Fatalf("BatchToggleStatus response = %+v, affected = %d, want 10", resp, result.Affected)
+	}
+	for _, id := range ids {
+		var user system.SysUser
+		if err := global.DB.First(&user, id).Error; err != nil {
+			t.Fatalf("failed to load user %d: %v", id, err)
+		}
+		if user.Active {
+			t.Errorf("user %d is still active", id)
+		}
+	}
+	var stored system.SysUser
+	if err := global.DB.First(&stored, untouched.ID).Error; err != nil || !stored.Active {
+		t.Errorf("user outside the batch: active = %v, err = %v; want still active", stored.Active, err)
+	}
+
+	// 包含超级管理员时整批失败，不修改任何用户
+	resp = doJSON(t, r, http.MethodPost, "/user/batch-status", BatchToggleStatusRequest{IDs: []uint{untouched.ID, root.ID}, Active: false}, nil)
+	if resp.Code == 0 {
+		t.Fatal("BatchToggleStatus disabled the super administrator")
+	}
+	if err := global.DB.First(&stored, untouched.ID).Error; err != nil || !stored.Active {
+		t.Errorf("user in a rejected batch: active = %v, err = %v; want still active", stored.Active, err)
+	}
+
+	if status, _ := doJSONWithStatus(t, r, http.MethodPost, "/user/batch-status", BatchToggleStatusRequest{Active: true}, nil); status != http.StatusBadRequest {
+		t.Errorf("BatchToggleStatus without IDs: status = %d, want 400", status)
+	}
+}
+
+func TestUserApi_GetUserInClientTimezone(t *testing.T) {
+	setupTestEnv(t)
+	global.Config.Timezone.Default = "UTC"
+	userApi := UserApi{}
+	r := gin.New()
+	r.Use(middleware.Logger())
+	r.GET("/user/:id", userApi.GetUser)
+
+	createdAt := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+	lastLogin := time.Date(2026, 1, 16, 3, 30, 0, 0, time.UTC)
+	user := &system.SysUser{Username: "alice", Password: "hashed", Active: true, LastLoginAt: &lastLogin}
+	user.CreatedAt = createdAt
+	if err := global.DB.Create(user).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		timezone      string
+		wantCreatedAt string
+		wantLastLogin string
+	}{
+		{"eastern time", "America/New_York", "2026-01-15T07:00:00-05:00", "2026-01-15T22:30:00-05:00"},
+		{"default utc", "", "2026-01-15T12:00:00Z", "2026-01-16T03:30:00Z"},
+		{"invalid timezone falls back to default", "Mars/Olympus", "2026-01-15T12:00:00Z", "2026-01-16T03:30:00Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/user/%d", user.ID), nil)
+			if tt.timezone != "" {
+				req.Header.Set("X-Timezone", tt.timezone)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			var resp struct {
+				Code int `json:"code"`
+				Data struct {
+					CreatedAt   string `json:"createdAt"`
+					LastLoginAt string `json:"lastLoginAt"`
+				} `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.Code != 0 {
+				t.Fatalf("GetUser response = %s", w.Body.String())
+			}
+			if resp.Data.CreatedAt != tt.wantCreatedAt || resp.Data.LastLoginAt != tt.wantLastLogin {
+				t.Errorf("createdAt = %s, lastLoginAt = %s, want %s and %s", resp.Data.CreatedAt, resp.Data.LastLoginAt, tt.wantCreatedAt, tt.wantLastLogin)
+			}
+		})
+	}
+}
@@ -1,6 +1,10 @@
 package tools
 
 import (
+	"net/http"
+	"strconv"
+	"strings"
+
 	"k-admin-system/model/common"
 	"k-admin-system/service/tools"
 
@@ -11,6 +15,35 @@ type CodeGeneratorAPI struct {
 	Service *tools.CodeGeneratorService
 }
 
+// PreviewFromTableRequest 基于已有表预览代码的查询参数
+type PreviewFromTableRequest struct {
+	StructName   string `form:"struct_name"`
+	PackageName  string `form:"package_name"`
+	ModulePath   string `form:"module_path"`
+	FrontendPath string `form:"frontend_path"`
+	tools.GenerateOptions
+}
+
+// GetTables 获取可用于代码生成的表列表
+// @Summary 获取可用于代码生成的表列表
+// @Description 获取当前数据库中的业务表，系统表（sys_*、casbin_rule）和迁移表会被过滤
+// @Tags Code Generator
+// @Accept json
+// @Produce json
+// @Success 200 {object} common.Response{data=[]string} "成功"
+// @Failure 500 {object} common.Response "失败"
+// @Security ApiKeyAuth
+// @Router /tools/gen/tables [get]
+func (api *CodeGeneratorAPI) GetTables(c *gin.Context) {
+	tables, err := api.Service.ListUserTables()
+	if err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+
+	common.OkWithData(c, tables)
+}
+
 // GetTableMetadata 获取表元数据
 // @Summary 获取表元数据
 // @Description 获取指定表的元数据信息，包括列名、类型、约束等
@@ -59,41 +92,33 @@ func (api *CodeGeneratorAPI) GetTableMetadata(c *gin.Context) {
 // @Produce json
 // @Param config body tools.GenerateConfig true "生成配置"
 // @Success 200 {object} common.Response{data=map[string]string} "成功，返回生成的文件路径列表"
-// @Failure 400 {object} common.Response "参数错误"
-// @Failure 500 {object} common.Response "失败"
+// @Failure 400 {object} common.Response{data=[]tools.ValidationError} "请求格式错误或配置校验失败（code 为 400），不会写入任何文件"
+// @Failure 500 {object} common.Response "生成或写入文件失败（code 为 500）"
 // @Security ApiKeyAuth
 // @Router /tools/gen/generate [post]
 func (api *CodeGeneratorAPI) GenerateCode(c *gin.Context) {
 	var config tools.GenerateConfig
 	if err := c.ShouldBindJSON(&config); err != nil {
-		common.Fail(c, "invalid request: "+err.Error())
+		common.FailWithStatus(c, http.StatusBadRequest, "invalid request: "+err.Error())
 		return
 	}
 
-	// Validate required fields
-	if config.TableName == "" {
-		common.Fail(c, "table_name is required")
-		return
-	}
-	if config.StructName == "" {
-		common.Fail(c, "struct_name is required")
-		return
-	}
-	if config.PackageName == "" {
-		common.Fail(c, "package_name is required")
+	// Validate config before generating or writing anything
+	if errs := api.Service.ValidateConfig(config); len(errs) > 0 {
+		common.FailWithStatusDetailed(c, http.StatusBadRequest, errs, "invalid generate config")
 		return
 	}
 
 	// Generate code
 	files, err := api.Service.GenerateCode(config)
 	if err != nil {
-		common.Fail(c, err.Error())
+		common.FailWithStatus(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	// Write files to disk
-	if err := api.Service.WriteGeneratedCode(files); err != nil {
-		common.Fail(c, "failed to write files: "+err.Error())
+	if err := api.Service.WriteGeneratedCode(config, files, c.GetString("username")); err != nil {
+		common.FailWithStatus(c, http.StatusInternalServerError, "failed to write files: "+err.Error())
 		return
 	}
 
@@ -109,6 +134,53 @@ func (api *CodeGeneratorAPI) GenerateCode(c *gin.Context) {
 	})
 }
 
+// GetGenerationHistory 获取代码生成历史
+// @Summary 获取代码生成历史
+// @Description 获取代码生成写入记录，按生成时间倒序
+// @Tags Code Generator
+// @Accept json
+// @Produce json
+// @Success 200 {object} common.Response{data=[]tools.GenerationRecord} "成功"
+// @Failure 500 {object} common.Response "失败"
+// @Security ApiKeyAuth
+// @Router /tools/gen/history [get]
+func (api *CodeGeneratorAPI) GetGenerationHistory(c *gin.Context) {
+	records, err := api.Service.GetGenerationHistory()
+	if err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+
+	common.OkWithData(c, records)
+}
+
+// DeleteGenerationRecord 删除代码生成历史记录
+// @Summary 删除代码生成历史记录
+// @Description 删除指定的代码生成历史记录，已生成的文件不会被删除
+// @Tags Code Generator
+// @Accept json
+// @Produce json
+// @Param id path int true "记录ID"
+// @Success 200 {object} common.Response "成功"
+// @Failure 400 {object} common.Response "参数错误"
+// @Failure 500 {object} common.Response "失败"
+// @Security ApiKeyAuth
+// @Router /tools/gen/history/{id} [delete]
+func (api *CodeGeneratorAPI) DeleteGenerationRecord(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		common.Fail(c, "invalid record ID")
+		return
+	}
+
+	if err := api.Service.DeleteGenerationRecord(uint(id)); err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+
+	common.OkWithDetailed(c, nil, "generation record deleted successfully")
+}
+
 // PreviewCode 预览代码
 // @Summary 预览生成的代码
 // @Description 根据配置生成代码预览，不写入文件
@@ -152,6 +224,72 @@ func (api *CodeGeneratorAPI) PreviewCode(c *gin.Context) {
 	common.OkWithData(c, files)
 }
 
+// PreviewFromTable 基于已有表预览代码
+// @Summary 基于已有表预览生成的代码
+// @Description 读取已有表的元数据并生成代码预览，不写入文件。未指定任何 generate_* 参数时生成全部文件
+// @Tags Code Generator
+// @Accept json
+// @Produce json
+// @Param tableName path string true "表名"
+// @Param struct_name query string false "结构体名称，默认由表名转换"
+// @Param package_name query string false "包名，默认 system"
+// @Param module_path query string false "Go 模块路径"
+// @Param frontend_path query string false "前端源码路径"
+// @Success 200 {object} common.Response{data=map[string]string} "成功，返回文件路径和内容的映射"
+// @Failure 400 {object} common.Response "参数错误"
+// @Failure 500 {object} common.Response "失败"
+// @Security ApiKeyAuth
+// @Router /tools/gen/preview/{tableName} [get]
+func (api *CodeGeneratorAPI) PreviewFromTable(c *gin.Context) {
+	tableName := c.Param("tableName")
+	if tableName == "" {
+		common.Fail(c, "table name is required")
+		return
+	}
+
+	var req PreviewFromTableRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		common.Fail(c, "invalid request: "+err.Error())
+		return
+	}
+
+	// No generate_* flags given: generate everything
+	options := req.GenerateOptions
+	if !hasGenerateOptionParams(c) {
+		options = tools.AllGenerateOptions()
+	}
+
+	metadata, err := api.Service.GetTableMetadata(tableName)
+	if err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+
+	files, err := api.Service.GenerateFromExistingTable(metadata, tools.GenerateConfig{
+		StructName:   req.StructName,
+		PackageName:  req.PackageName,
+		ModulePath:   req.ModulePath,
+		FrontendPath: req.FrontendPath,
+		Options:      options,
+	})
+	if err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+
+	common.OkWithData(c, files)
+}
+
+// hasGenerateOptionParams reports whether any generate_* query parameter was provided
+func hasGenerateOptionParams(c *gin.Context) bool {
+	for key := range c.Request.URL.Query() {
+		if strings.HasPrefix(key, "generate_") {
+			return true
+		}
+	}
+	return false
+}
+
 // CreateTable 创建表
 // @Summary 创建数据库表
 // @Description 根据字段定义创建新的数据库表
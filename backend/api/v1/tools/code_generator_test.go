@@ -0,0 +1,209 @@
+package tools
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k-admin-system/model/common"
+	"k-admin-system/model/system"
+	"k-admin-system/service/tools"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// setupGenerateTest 以临时目录作为输出根目录，复制迁移模板并返回注册了生成接口的路由
+func setupGenerateTest(t *testing.T) (*gin.Engine, *gorm.DB, string) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	template, err := os.ReadFile("../../../resource/template/backend/migration.tpl")
+	if err != nil {
+		t.Fatalf("failed to read template: %v", err)
+	}
+	root := t.TempDir()
+	templateDir := filepath.Join(root, "backend/resource/template/backend")
+	if err := os.MkdirAll(templateDir, 0755); err != nil {
+		t.Fatalf("failed to create template directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(templateDir, "migration.tpl"), template, 0644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+	t.Chdir(root)
+
+	db, err := gorm.Open(sqlite.Open(filepath.Join(root, "test.db")), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	if err := db.AutoMigrate(&system.SysCodeGenHistory{}); err != nil {
+		t.Fatalf("failed to migrate tables: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	})
+
+	api := &CodeGeneratorAPI{Service: tools.NewCodeGeneratorService(db)}
+	r := gin.New()
+	r.POST("/tools/gen/generate", api.GenerateCode)
+	return r, db, root
+}
+
+// postGenerate 提交生成配置，返回HTTP状态码和响应
+func postGenerate(t *testing.T, r http.Handler, config tools.GenerateConfig) (int, common.Response) {
+	t.Helper()
+	payload, err := json.Marshal(config)
+	if err != nil {
+		t.Fatalf("failed to marshal config: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/tools/gen/generate", bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var resp common.Response
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response %q: %v", w.Body.String(), err)
+	}
+	return w.Code, resp
+}
+
+func TestGenerateCode_WritesFiles(t *testing.T) {
+	r, db, root := setupGenerateTest(t)
+
+	status, resp := postGenerate(t, r, tools.GenerateConfig{
+		TableName:   "orders",
+		StructName:  "Order",
+		PackageName: "demo",
+		Fields:      []tools.FieldConfig{{ColumnName: "title", FieldName: "Title", FieldType: "string"}},
+		Options:     tools.GenerateOptions{GenerateMigrationSQL: true},
+	})
+	if status != http.StatusOK || resp.Code != 0 {
+		t.Fatalf("GenerateCode() = %d %+v, want success", status, resp)
+	}
+	if _, err := os.Stat(filepath.Join(root, "backend/migrations/create_orders.sql")); err != nil {
+		t.Errorf("migration file was not written: %v", err)
+	}
+	var history int64
+	db.Model(&system.SysCodeGenHistory{}).Count(&history)
+	if history != 1 {
+		t.Errorf("generation history rows = %d, want 1", history)
+	}
+}
+
+func TestGenerateCode_InvalidConfigWritesNothing(t *testing.T) {
+	r, _, root := setupGenerateTest(t)
+
+	status, resp := postGenerate(t, r, tools.GenerateConfig{
+		TableName:   "orders",
+		StructName:  "Order",
+		PackageName: "demo",
+		Fields:      []tools.FieldConfig{{ColumnName: "title; DROP TABLE users", FieldName: "Title", FieldType: "string"}},
+		Options:     tools.GenerateOptions{GenerateMigrationSQL: true},
+	})
+	if status != http.StatusBadRequest || resp.Code != http.StatusBadRequest {
+		t.Fatalf("GenerateCode() = %d (code %d), want 400", status, resp.Code)
+	}
+	errs, ok := resp.Data.([]interface{})
+	if !ok || len(errs) != 1 {
+		t.Errorf("GenerateCode() data = %v, want one validation error", resp.Data)
+	}
+	if _, err := os.Stat(filepath.Join(root, "backend/migrations")); !os.IsNotExist(err) {
+		t.Errorf("files were written for an invalid config")
+	}
+}
+
+// setupPreviewTest 在内存SQLite中创建 categories 和 products 表，切换到仓库根目录以读取模板，返回注册了预览接口的路由
+func setupPreviewTest(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	t.Chdir("../../../..")
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get sql.DB: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	for _, stmt := range []string{
+		`CREATE TABLE categories (id INTEGER PRIMARY KEY AUTOINCREMENT, name VARCHAR(100) NOT NULL)`,
+		`CREATE TABLE products (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name VARCHAR(100) NOT NULL,
+			price DECIMAL(10,2),
+			category_id INTEGER REFERENCES categories(id)
+		)`,
+	} {
+		if err := db.Exec(stmt).Error; err != nil {
+			t.Fatalf("failed to execute %q: %v", stmt, err)
+		}
+	}
+
+	api := &CodeGeneratorAPI{Service: tools.NewCodeGeneratorService(db)}
+	r := gin.New()
+	r.GET("/tools/gen/preview/:tableName", api.PreviewFromTable)
+	return r
+}
+
+func TestPreviewFromTable_SQLite(t *testing.T) {
+	r := setupPreviewTest(t)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tools/gen/preview/products?struct_name=Product&package_name=demo&generate_model=true&generate_migration_sql=true", nil))
+
+	var resp struct {
+		common.Response
+		Data map[string]string `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response %q: %v", w.Body.String(), err)
+	}
+	if w.Code != http.StatusOK || resp.Code != 0 {
+		t.Fatalf("PreviewFromTable() = %d %+v, want success", w.Code, resp.Response)
+	}
+
+	model, ok := resp.Data["backend/model/demo/product.go"]
+	if !ok {
+		t.Fatalf("preview is missing the model file, got keys %v", mapKeys(resp.Data))
+	}
+	for _, want := range []string{"type Product struct", "Name", "Price", "foreignKey:CategoryId"} {
+		if !strings.Contains(model, want) {
+			t.Errorf("model file does not contain %q:\n%s", want, model)
+		}
+	}
+	if _, ok := resp.Data["backend/migrations/create_products.sql"]; !ok {
+		t.Errorf("preview is missing the migration file, got keys %v", mapKeys(resp.Data))
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tools/gen/preview/missing?generate_model=true", nil))
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response %q: %v", w.Body.String(), err)
+	}
+	if resp.Code == 0 {
+		t.Error("PreviewFromTable() of a missing table succeeded")
+	}
+}
+
+// mapKeys 返回 map 的键，用于失败信息
+func mapKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -1,11 +1,15 @@
 package tools
 
 import (
+	"net/http"
+	"strconv"
+
+	"k-admin-system/global"
 	"k-admin-system/model/common"
 	"k-admin-system/service/tools"
-	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
 )
 
 type DBInspectorAPI struct {
@@ -58,9 +62,235 @@ func (api *DBInspectorAPI) GetTableSchema(c *gin.Context) {
 	common.OkWithData(c, schema)
 }
 
+// GetForeignKeys 获取表外键
+// @Summary 获取表外键信息
+// @Description 获取指定表的外键约束，包括列名、引用表和引用列
+// @Tags DB Inspector
+// @Accept json
+// @Produce json
+// @Param tableName path string true "表名"
+// @Success 200 {object} common.Response{data=[]tools.ForeignKeyInfo} "成功"
+// @Failure 400 {object} common.Response "参数错误"
+// @Failure 500 {object} common.Response "失败"
+// @Security ApiKeyAuth
+// @Router /tools/db/tables/{tableName}/foreign-keys [get]
+func (api *DBInspectorAPI) GetForeignKeys(c *gin.Context) {
+	tableName := c.Param("tableName")
+	if tableName == "" {
+		common.Fail(c, "table name is required")
+		return
+	}
+
+	foreignKeys, err := api.service.GetForeignKeys(tableName)
+	if err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+	common.OkWithData(c, foreignKeys)
+}
+
+// GetTableIndexes 获取表索引
+// @Summary 获取表索引信息
+// @Description 获取指定表的索引，包括索引名、索引列、是否唯一和索引类型
+// @Tags DB Inspector
+// @Accept json
+// @Produce json
+// @Param tableName path string true "表名"
+// @Success 200 {object} common.Response{data=[]tools.IndexInfo} "成功"
+// @Failure 400 {object} common.Response "参数错误"
+// @Failure 500 {object} common.Response "失败"
+// @Security ApiKeyAuth
+// @Router /tools/db/tables/{tableName}/indexes [get]
+func (api *DBInspectorAPI) GetTableIndexes(c *gin.Context) {
+	tableName := c.Param("tableName")
+	if tableName == "" {
+		common.Fail(c, "table name is required")
+		return
+	}
+
+	indexes, err := api.service.GetTableIndexes(tableName)
+	if err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+	common.OkWithData(c, indexes)
+}
+
+// GetSampleRows 获取表的随机样本数据
+// @Summary 获取表的随机样本数据
+// @Description 随机返回指定表中最多 n 行数据，用于了解表的数据形态
+// @Tags DB Inspector
+// @Accept json
+// @Produce json
+// @Param tableName path string true "表名"
+// @Param n query int false "样本行数，最大100" default(10)
+// @Success 200 {object} common.Response{data=[]map[string]interface{}} "成功"
+// @Failure 400 {object} common.Response "参数错误"
+// @Failure 500 {object} common.Response "失败"
+// @Security ApiKeyAuth
+// @Router /tools/db/tables/{tableName}/sample [get]
+func (api *DBInspectorAPI) GetSampleRows(c *gin.Context) {
+	tableName := c.Param("tableName")
+	if tableName == "" {
+		common.Fail(c, "table name is required")
+		return
+	}
+
+	n, err := strconv.Atoi(c.DefaultQuery("n", "10"))
+	if err != nil {
+		common.Fail(c, "invalid request: n must be an integer")
+		return
+	}
+
+	rows, err := api.service.GetSampleRows(tableName, n, c.GetUint("roleId"))
+	if err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+	common.OkWithData(c, rows)
+}
+
+// CountNullValues 统计列的NULL值数量
+// @Summary 统计列的NULL值数量
+// @Description 统计指定表中某列为NULL的行数，用于数据质量检查
+// @Tags DB Inspector
+// @Accept json
+// @Produce json
+// @Param tableName path string true "表名"
+// @Param columnName path string true "列名"
+// @Success 200 {object} common.Response{data=map[string]interface{}} "成功"
+// @Failure 400 {object} common.Response "参数错误"
+// @Failure 500 {object} common.Response "失败"
+// @Security ApiKeyAuth
+// @Router /tools/db/tables/{tableName}/columns/{columnName}/nulls [get]
+func (api *DBInspectorAPI) CountNullValues(c *gin.Context) {
+	tableName := c.Param("tableName")
+	columnName := c.Param("columnName")
+	if tableName == "" || columnName == "" {
+		common.Fail(c, "table name and column name are required")
+		return
+	}
+
+	count, err := api.service.CountNullValues(tableName, columnName, c.GetUint("roleId"))
+	if err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+	common.OkWithData(c, map[string]interface{}{
+		"column":    columnName,
+		"nullCount": count,
+	})
+}
+
+// GetDistinctValues 获取列的去重值
+// @Summary 获取列的去重值
+// @Description 返回指定列按值排序的去重值，用于筛选条件的自动补全
+// @Tags DB Inspector
+// @Accept json
+// @Produce json
+// @Param tableName path string true "表名"
+// @Param columnName path string true "列名"
+// @Param limit query int false "返回数量，最大200" default(50)
+// @Success 200 {object} common.Response{data=[]interface{}} "成功"
+// @Failure 400 {object} common.Response "参数错误"
+// @Failure 500 {object} common.Response "失败"
+// @Security ApiKeyAuth
+// @Router /tools/db/tables/{tableName}/columns/{columnName}/distinct [get]
+func (api *DBInspectorAPI) GetDistinctValues(c *gin.Context) {
+	tableName := c.Param("tableName")
+	columnName := c.Param("columnName")
+	if tableName == "" || columnName == "" {
+		common.Fail(c, "table name and column name are required")
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil {
+		common.Fail(c, "invalid request: limit must be an integer")
+		return
+	}
+
+	values, err := api.service.GetDistinctValues(tableName, columnName, limit, c.GetUint("roleId"))
+	if err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+	common.OkWithData(c, values)
+}
+
+// BackupTable 备份表
+// @Summary 导出单表SQL备份
+// @Description 导出指定表的 CREATE TABLE 语句和全部数据的 INSERT 语句，以附件形式下载
+// @Tags DB Inspector
+// @Produce plain
+// @Param tableName path string true "表名"
+// @Success 200 {file} file "SQL备份文件"
+// @Failure 400 {object} common.Response "参数错误"
+// @Failure 500 {object} common.Response "失败"
+// @Security ApiKeyAuth
+// @Router /tools/db/tables/{tableName}/backup [get]
+func (api *DBInspectorAPI) BackupTable(c *gin.Context) {
+	tableName := c.Param("tableName")
+	if tableName == "" {
+		common.Fail(c, "table name is required")
+		return
+	}
+
+	c.Header("Content-Type", "application/sql")
+	c.Header("Content-Disposition", "attachment; filename="+tableName+".sql")
+
+	if err := api.service.BackupTableToSQL(tableName, c.Writer, c.GetUint("roleId")); err != nil {
+		// 尚未写出数据时仍可返回统一的错误响应，否则只能中断输出并记录日志
+		if !c.Writer.Written() {
+			c.Writer.Header().Del("Content-Type")
+			c.Writer.Header().Del("Content-Disposition")
+			common.Fail(c, err.Error())
+			return
+		}
+		global.Logger.Error("Failed to back up table", zap.String("table", tableName), zap.Error(err))
+		c.Abort()
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// GenerateERDiagram 生成ER图
+// @Summary 生成ER图
+// @Description 根据表结构和外键生成 Mermaid erDiagram 语法
+// @Tags DB Inspector
+// @Accept json
+// @Produce json
+// @Param request body map[string]interface{} true "表名列表" example({"tables":["users","orders"]})
+// @Success 200 {object} common.Response{data=map[string]string} "成功"
+// @Failure 400 {object} common.Response "参数错误"
+// @Failure 500 {object} common.Response "失败"
+// @Security ApiKeyAuth
+// @Router /tools/db/erd [post]
+func (api *DBInspectorAPI) GenerateERDiagram(c *gin.Context) {
+	var req struct {
+		Tables []string `json:"tables" binding:"required,min=1"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.Fail(c, "invalid request: "+err.Error())
+		return
+	}
+
+	diagram, err := api.service.GenerateERDiagram(req.Tables)
+	if err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+
+	common.OkWithData(c, map[string]interface{}{
+		"diagram": diagram,
+	})
+}
+
 // GetTableData 获取表数据
 // @Summary 获取表数据
-// @Description 分页获取指定表的数据记录
+// @Description 分页获取指定表的数据记录，当前用户角色配置了该表的行级过滤时只返回满足条件的行
 // @Tags DB Inspector
 // @Accept json
 // @Produce json
@@ -89,17 +319,18 @@ func (api *DBInspectorAPI) GetTableData(c *gin.Context) {
 		pageSize = 10
 	}
 
-	data, total, err := api.service.GetTableData(tableName, page, pageSize)
+	// 按当前用户角色的行级过滤配置过滤数据
+	data, total, err := api.service.GetTableData(tableName, page, pageSize, c.GetUint("roleId"))
 	if err != nil {
 		common.Fail(c, err.Error())
 		return
 	}
 
-	common.OkWithData(c, map[string]interface{}{
-		"list":     data,
-		"total":    total,
-		"page":     page,
-		"pageSize": pageSize,
+	common.OkWithData(c, common.PagedResponse[map[string]interface{}]{
+		List:     data,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
 	})
 }
 
@@ -131,7 +362,7 @@ func (api *DBInspectorAPI) ExecuteSQL(c *gin.Context) {
 	// 这里应该从JWT claims中获取用户角色，检查是否为超级管理员
 	// 如果不是超级管理员且SQL包含危险操作，应该拒绝
 
-	result, err := api.service.ExecuteSQL(req.SQL, req.ReadOnly)
+	result, err := api.service.ExecuteSQL(req.SQL, req.ReadOnly, c.GetUint("roleId"))
 	if err != nil {
 		common.Fail(c, err.Error())
 		return
@@ -140,6 +371,38 @@ func (api *DBInspectorAPI) ExecuteSQL(c *gin.Context) {
 	common.OkWithData(c, result)
 }
 
+// RunExplain 查看执行计划
+// @Summary 查看SQL执行计划
+// @Description 返回SQL语句的EXPLAIN结果，verbose为true时在MySQL上使用EXPLAIN ANALYZE（会实际执行语句，仅允许只读语句）
+// @Tags DB Inspector
+// @Accept json
+// @Produce json
+// @Param request body map[string]interface{} true "SQL请求" example({"sql":"SELECT * FROM users WHERE username = 'admin'","verbose":false})
+// @Success 200 {object} common.Response{data=[]map[string]interface{}} "成功"
+// @Failure 400 {object} common.Response "参数错误"
+// @Failure 500 {object} common.Response "失败"
+// @Security ApiKeyAuth
+// @Router /tools/db/explain [post]
+func (api *DBInspectorAPI) RunExplain(c *gin.Context) {
+	var req struct {
+		SQL     string `json:"sql" binding:"required"`
+		Verbose bool   `json:"verbose"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.Fail(c, "invalid request: "+err.Error())
+		return
+	}
+
+	rows, err := api.service.RunExplain(req.SQL, req.Verbose, c.GetUint("roleId"))
+	if err != nil {
+		common.Fail(c, err.Error())
+		return
+	}
+
+	common.OkWithData(c, rows)
+}
+
 // CreateRecord 创建记录
 // @Summary 创建表记录
 // @Description 在指定表中创建新记录
@@ -166,7 +429,7 @@ func (api *DBInspectorAPI) CreateRecord(c *gin.Context) {
 		return
 	}
 
-	if err := api.service.CreateRecord(tableName, data); err != nil {
+	if err := api.service.CreateRecord(tableName, data, c.GetUint("roleId")); err != nil {
 		common.Fail(c, err.Error())
 		return
 	}
@@ -208,7 +471,7 @@ func (api *DBInspectorAPI) UpdateRecord(c *gin.Context) {
 		return
 	}
 
-	if err := api.service.UpdateRecord(tableName, id, data); err != nil {
+	if err := api.service.UpdateRecord(tableName, id, data, c.GetUint("roleId")); err != nil {
 		common.Fail(c, err.Error())
 		return
 	}
@@ -243,7 +506,7 @@ func (api *DBInspectorAPI) DeleteRecord(c *gin.Context) {
 		return
 	}
 
-	if err := api.service.DeleteRecord(tableName, id); err != nil {
+	if err := api.service.DeleteRecord(tableName, id, c.GetUint("roleId")); err != nil {
 		common.Fail(c, err.Error())
 		return
 	}
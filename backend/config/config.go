@@ -1,8 +1,15 @@
 package config
 
 import (
+	"context"
 	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -16,75 +23,150 @@ type Config struct {
 	Logger    LoggerConfig    `mapstructure:"logger"`
 	CORS      CORSConfig      `mapstructure:"cors"`
 	RateLimit RateLimitConfig `mapstructure:"rate_limit"`
+	Security  SecurityConfig  `mapstructure:"security"`
+	Secrets   SecretsConfig   `mapstructure:"secrets"`
+	Tracing   TracingConfig   `mapstructure:"tracing"`
+	Timezone  TimezoneConfig  `mapstructure:"timezone"`
+	Casbin    CasbinConfig    `mapstructure:"casbin"`
+	Metrics   MetricsConfig   `mapstructure:"metrics"`
 }
 
 // ServerConfig holds server-related configuration
 type ServerConfig struct {
-	Port string `mapstructure:"port"`
-	Mode string `mapstructure:"mode"` // debug, release, test
+	Port                string   `mapstructure:"port"`
+	Mode                string   `mapstructure:"mode"`                   // debug, release, test
+	MaxRequestBodyBytes int64    `mapstructure:"max_request_body_bytes"` // request body size limit
+	TrustedProxies      []string `mapstructure:"trusted_proxies"`        // proxy IPs or CIDR ranges whose X-Forwarded-For is trusted for the client IP
+}
+
+// SecretsConfig holds configuration for resolving sm:// secret references
+type SecretsConfig struct {
+	Region string `mapstructure:"region"` // AWS region, defaults to the AWS SDK default region
 }
 
 // DatabaseConfig holds database connection configuration
 type DatabaseConfig struct {
-	Host         string `mapstructure:"host"`
-	Port         int    `mapstructure:"port"`
-	Name         string `mapstructure:"name"`
-	Username     string `mapstructure:"username"`
-	Password     string `mapstructure:"password"`
-	MaxIdleConns int    `mapstructure:"max_idle_conns"`
-	MaxOpenConns int    `mapstructure:"max_open_conns"`
+	Host            string `mapstructure:"host"`
+	Port            int    `mapstructure:"port"`
+	Name            string `mapstructure:"name"`
+	Username        string `mapstructure:"username"`
+	Password        string `mapstructure:"password"`
+	MaxIdleConns    int    `mapstructure:"max_idle_conns"`
+	MaxOpenConns    int    `mapstructure:"max_open_conns"`
+	MaxRetries      int    `mapstructure:"max_retries"`        // connection attempts on startup
+	ConnMaxLifetime int    `mapstructure:"conn_max_lifetime"`  // seconds a connection may be reused
+	ConnMaxIdleTime int    `mapstructure:"conn_max_idle_time"` // seconds a connection may stay idle
 }
 
 // JWTConfig holds JWT token configuration
 type JWTConfig struct {
-	Secret            string `mapstructure:"secret"`
-	AccessExpiration  int    `mapstructure:"access_expiration"`  // in minutes
-	RefreshExpiration int    `mapstructure:"refresh_expiration"` // in days
+	Secret             string `mapstructure:"secret"`
+	AccessExpiration   int    `mapstructure:"access_expiration"`   // in minutes
+	RefreshExpiration  int    `mapstructure:"refresh_expiration"`  // in days
+	FingerprintEnabled bool   `mapstructure:"fingerprint_enabled"` // bind tokens to User-Agent + Accept-Language
+	ClockSkewSeconds   int    `mapstructure:"clock_skew_seconds"`  // leeway for exp/nbf checks to tolerate clock drift between services
 }
 
 // RedisConfig holds Redis connection configuration
 type RedisConfig struct {
-	Host     string `mapstructure:"host"`
-	Port     int    `mapstructure:"port"`
-	Password string `mapstructure:"password"`
-	DB       int    `mapstructure:"db"`
+	Host        string `mapstructure:"host"`
+	Port        int    `mapstructure:"port"`
+	Password    string `mapstructure:"password"`
+	DB          int    `mapstructure:"db"`
+	MaxRetries  int    `mapstructure:"max_retries"`  // connection attempts on startup
+	DialTimeout int    `mapstructure:"dial_timeout"` // seconds to wait when dialing a new connection
 }
 
 // LoggerConfig holds logging configuration
 type LoggerConfig struct {
-	Level      string `mapstructure:"level"`       // debug, info, warn, error, fatal
-	Path       string `mapstructure:"path"`        // log file path
-	MaxSize    int    `mapstructure:"max_size"`    // megabytes
-	MaxAge     int    `mapstructure:"max_age"`     // days
-	MaxBackups int    `mapstructure:"max_backups"` // number of backups
-	Compress   bool   `mapstructure:"compress"`    // compress rotated files
+	Level         string `mapstructure:"level"`          // debug, info, warn, error, fatal
+	Path          string `mapstructure:"path"`           // log file path
+	MaxSize       int    `mapstructure:"max_size"`       // megabytes
+	MaxAge        int    `mapstructure:"max_age"`        // days
+	MaxBackups    int    `mapstructure:"max_backups"`    // number of backups
+	Compress      bool   `mapstructure:"compress"`       // compress rotated files
+	ConsoleOutput bool   `mapstructure:"console_output"` // also log to stdout in release mode
 }
 
 // CORSConfig holds CORS configuration
 type CORSConfig struct {
-	AllowOrigins     []string `mapstructure:"allow_origins"`
-	AllowMethods     []string `mapstructure:"allow_methods"`
-	AllowHeaders     []string `mapstructure:"allow_headers"`
-	ExposeHeaders    []string `mapstructure:"expose_headers"`
-	AllowCredentials bool     `mapstructure:"allow_credentials"`
-	MaxAge           int      `mapstructure:"max_age"` // in seconds
+	AllowOrigins       []string `mapstructure:"allow_origins"`
+	AllowOriginRegexes []string `mapstructure:"allow_origin_regexes"` // checked when Origin matches no allow_origins entry; each pattern must match the whole Origin
+	AllowMethods       []string `mapstructure:"allow_methods"`
+	AllowHeaders       []string `mapstructure:"allow_headers"`
+	ExposeHeaders      []string `mapstructure:"expose_headers"`
+	AllowCredentials   bool     `mapstructure:"allow_credentials"`
+	MaxAge             int      `mapstructure:"max_age"` // in seconds
 }
 
 // RateLimitConfig holds rate limiting configuration
 type RateLimitConfig struct {
-	Enabled  bool   `mapstructure:"enabled"`  // enable/disable rate limiting
-	Requests int    `mapstructure:"requests"` // number of requests allowed
-	Window   int    `mapstructure:"window"`   // time window in seconds
-	KeyFunc  string `mapstructure:"key_func"` // "ip" or "user" - how to identify clients
+	Enabled   bool     `mapstructure:"enabled"`   // enable/disable rate limiting
+	Requests  int      `mapstructure:"requests"`  // number of requests allowed
+	Window    int      `mapstructure:"window"`    // time window in seconds
+	KeyFunc   string   `mapstructure:"key_func"`  // "ip" or "user" - how to identify clients
+	Whitelist []string `mapstructure:"whitelist"` // IPs or CIDR ranges that bypass rate limiting
+}
+
+// TracingConfig holds OpenTelemetry tracing configuration
+type TracingConfig struct {
+	OTLPEndpoint string `mapstructure:"otlp_endpoint"` // OTLP/HTTP collector URL, empty disables tracing
+	ServiceName  string `mapstructure:"service_name"`  // service name reported with spans
+}
+
+// TimezoneConfig holds the timezone used for response timestamps
+type TimezoneConfig struct {
+	Default string `mapstructure:"default"` // IANA name, e.g. "UTC" or "Asia/Shanghai"; overridable per request via X-Timezone
+}
+
+// CasbinConfig holds Casbin authorization configuration
+type CasbinConfig struct {
+	SuperuserRoleKey string `mapstructure:"superuser_role_key"` // role key that bypasses policy enforcement, empty disables the bypass
+}
+
+// MetricsConfig holds request metrics configuration
+type MetricsConfig struct {
+	Enabled bool `mapstructure:"enabled"` // record per-route latency in Redis for percentile queries
+}
+
+// SecurityConfig holds security-related configuration
+type SecurityConfig struct {
+	PasswordLength    int    `mapstructure:"password_length"`     // length of generated passwords
+	PasswordMinUpper  int    `mapstructure:"password_min_upper"`  // min uppercase letters in generated passwords
+	PasswordMinDigit  int    `mapstructure:"password_min_digit"`  // min digits in generated passwords
+	PasswordMinSymbol int    `mapstructure:"password_min_symbol"` // min symbols in generated passwords
+	CSP               string `mapstructure:"csp"`                 // Content-Security-Policy header value, empty to disable
+
+	PasswordHashAlgorithm string       `mapstructure:"password_hash_algorithm"` // "bcrypt" (default) or "argon2id"
+	Argon2Params          Argon2Config `mapstructure:"argon2_params"`           // used when password_hash_algorithm is argon2id
+
+	MaxFailedAttempts   int `mapstructure:"max_failed_attempts"`   // consecutive failed logins before the account is locked
+	LockDuration        int `mapstructure:"lock_duration"`         // account lock duration in minutes
+	MinPasswordStrength int `mapstructure:"min_password_strength"` // minimum utils.PasswordStrength score (1-4) for new passwords
+	PasswordMaxAgeDays  int `mapstructure:"password_max_age_days"` // days before a password expires and must be changed (0 disables expiry)
+}
+
+// Argon2Config holds argon2id password hashing parameters
+type Argon2Config struct {
+	Time    uint32 `mapstructure:"time"`    // number of passes over memory
+	Memory  uint32 `mapstructure:"memory"`  // memory in KiB
+	Threads uint8  `mapstructure:"threads"` // degree of parallelism
 }
 
 // LoadConfig loads configuration from file and environment variables
-// Supports YAML and JSON formats
+// Supports YAML, JSON and TOML formats (viper decodes TOML with go-toml/v2)
 // Environment variables take precedence over file configuration
 func LoadConfig(configPath string) (*Config, error) {
+	return LoadConfigWithSecrets(configPath, nil)
+}
+
+// LoadConfigWithSecrets loads configuration like LoadConfig and resolves every value
+// starting with sm:// through sm. When sm is nil and such values exist,
+// an AWS Secrets Manager backend is created from the secrets section
+func LoadConfigWithSecrets(configPath string, sm SecretsManager) (*Config, error) {
 	v := viper.New()
 
-	// Set config file path
+	// Set config file path; the format (YAML, JSON or TOML) is detected from the extension
 	if configPath != "" {
 		v.SetConfigFile(configPath)
 	} else {
@@ -100,6 +182,12 @@ func LoadConfig(configPath string) (*Config, error) {
 	v.SetEnvPrefix("KADMIN")
 	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 
+	// Defaults for settings where zero is a meaningful value, so an explicit 0 is kept
+	v.SetDefault("jwt.clock_skew_seconds", 30)
+	v.SetDefault("security.password_min_upper", 1)
+	v.SetDefault("security.password_min_digit", 1)
+	v.SetDefault("security.password_min_symbol", 1)
+
 	// Read config file
 	if err := v.ReadInConfig(); err != nil {
 		// Config file is optional if all required values are in env vars
@@ -114,6 +202,20 @@ func LoadConfig(configPath string) (*Config, error) {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
+	// Resolve secret references
+	if hasSecretRefs(&config) {
+		if sm == nil {
+			awsSM, err := NewAWSSecretsManager(context.Background(), config.Secrets.Region)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create secrets manager: %w", err)
+			}
+			sm = awsSM
+		}
+		if err := resolveSecrets(&config, sm); err != nil {
+			return nil, err
+		}
+	}
+
 	// Validate required fields
 	if err := validateConfig(&config); err != nil {
 		return nil, fmt.Errorf("config validation failed: %w", err)
@@ -128,9 +230,18 @@ func validateConfig(config *Config) error {
 	if config.Server.Port == "" {
 		return fmt.Errorf("server.port is required")
 	}
+	if err := validateServerPort(config.Server.Port); err != nil {
+		return err
+	}
 	if config.Server.Mode == "" {
 		config.Server.Mode = "debug" // default mode
 	}
+	if config.Server.MaxRequestBodyBytes == 0 {
+		config.Server.MaxRequestBodyBytes = 10 << 20 // default 10 MB
+	}
+	if config.Server.TrustedProxies == nil {
+		config.Server.TrustedProxies = []string{"127.0.0.1"}
+	}
 	if config.Server.Mode != "debug" && config.Server.Mode != "release" && config.Server.Mode != "test" {
 		return fmt.Errorf("server.mode must be one of: debug, release, test")
 	}
@@ -142,6 +253,9 @@ func validateConfig(config *Config) error {
 	if config.Database.Port == 0 {
 		return fmt.Errorf("database.port is required")
 	}
+	if !isValidPort(config.Database.Port) {
+		return fmt.Errorf("database.port must be between 1 and 65535, got %d", config.Database.Port)
+	}
 	if config.Database.Name == "" {
 		return fmt.Errorf("database.name is required")
 	}
@@ -157,6 +271,15 @@ func validateConfig(config *Config) error {
 	if config.Database.MaxOpenConns == 0 {
 		config.Database.MaxOpenConns = 100
 	}
+	if config.Database.MaxRetries == 0 {
+		config.Database.MaxRetries = 5
+	}
+	if config.Database.ConnMaxLifetime == 0 {
+		config.Database.ConnMaxLifetime = 3600 // default 1 hour
+	}
+	if config.Database.ConnMaxIdleTime == 0 {
+		config.Database.ConnMaxIdleTime = 600 // default 10 minutes
+	}
 
 	// Validate JWT config
 	if config.JWT.Secret == "" {
@@ -168,6 +291,10 @@ func validateConfig(config *Config) error {
 	if config.JWT.RefreshExpiration == 0 {
 		config.JWT.RefreshExpiration = 7 // default 7 days
 	}
+	// jwt.clock_skew_seconds defaults to 30 in LoadConfigWithSecrets; 0 disables the leeway
+	if config.JWT.ClockSkewSeconds < 0 {
+		return fmt.Errorf("jwt.clock_skew_seconds must not be negative")
+	}
 
 	// Validate Redis config
 	if config.Redis.Host == "" {
@@ -176,7 +303,16 @@ func validateConfig(config *Config) error {
 	if config.Redis.Port == 0 {
 		return fmt.Errorf("redis.port is required")
 	}
+	if !isValidPort(config.Redis.Port) {
+		return fmt.Errorf("redis.port must be between 1 and 65535, got %d", config.Redis.Port)
+	}
 	// Password and DB can have default values
+	if config.Redis.MaxRetries == 0 {
+		config.Redis.MaxRetries = 5
+	}
+	if config.Redis.DialTimeout == 0 {
+		config.Redis.DialTimeout = 5 // default 5 seconds
+	}
 
 	// Validate Logger config
 	if config.Logger.Level == "" {
@@ -189,6 +325,9 @@ func validateConfig(config *Config) error {
 	if config.Logger.Path == "" {
 		config.Logger.Path = "./logs/app.log" // default path
 	}
+	if err := ensureLogDir(config.Logger.Path); err != nil {
+		return err
+	}
 	// Set default log rotation values if not specified
 	if config.Logger.MaxSize == 0 {
 		config.Logger.MaxSize = 100 // 100MB
@@ -201,9 +340,14 @@ func validateConfig(config *Config) error {
 	}
 
 	// Validate CORS config - set defaults if not specified
-	if len(config.CORS.AllowOrigins) == 0 {
+	if len(config.CORS.AllowOrigins) == 0 && len(config.CORS.AllowOriginRegexes) == 0 {
 		config.CORS.AllowOrigins = []string{"*"} // default allow all origins
 	}
+	for _, pattern := range config.CORS.AllowOriginRegexes {
+		if _, err := regexp.Compile(`^(?:` + pattern + `)$`); err != nil {
+			return fmt.Errorf("cors.allow_origin_regexes contains invalid pattern %q: %w", pattern, err)
+		}
+	}
 	if len(config.CORS.AllowMethods) == 0 {
 		config.CORS.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"}
 	}
@@ -227,6 +371,105 @@ func validateConfig(config *Config) error {
 	if config.RateLimit.KeyFunc != "ip" && config.RateLimit.KeyFunc != "user" {
 		return fmt.Errorf("rate_limit.key_func must be one of: ip, user")
 	}
+	for _, entry := range config.RateLimit.Whitelist {
+		if !isValidIPOrCIDR(entry) {
+			return fmt.Errorf("rate_limit.whitelist entry %q must be an IP address or CIDR range", entry)
+		}
+	}
+
+	// Validate Security config - set defaults if not specified
+	if config.Security.PasswordLength == 0 {
+		config.Security.PasswordLength = 16 // default 16 characters
+	}
+	// Minimum character counts default to 1 in LoadConfig; an explicit 0 disables the requirement
+	if config.Security.PasswordMinUpper < 0 || config.Security.PasswordMinDigit < 0 || config.Security.PasswordMinSymbol < 0 {
+		return fmt.Errorf("security.password_min_upper, password_min_digit and password_min_symbol must not be negative")
+	}
+	if config.Security.PasswordMinUpper+config.Security.PasswordMinDigit+config.Security.PasswordMinSymbol > config.Security.PasswordLength {
+		return fmt.Errorf("security.password_length must be at least the sum of the minimum character counts")
+	}
+	if config.Security.PasswordHashAlgorithm == "" {
+		config.Security.PasswordHashAlgorithm = "bcrypt"
+	}
+	if config.Security.PasswordHashAlgorithm != "bcrypt" && config.Security.PasswordHashAlgorithm != "argon2id" {
+		return fmt.Errorf("security.password_hash_algorithm must be one of: bcrypt, argon2id")
+	}
+	if config.Security.Argon2Params.Time == 0 {
+		config.Security.Argon2Params.Time = 1
+	}
+	if config.Security.Argon2Params.Memory == 0 {
+		config.Security.Argon2Params.Memory = 64 * 1024 // 64 MiB
+	}
+	if config.Security.Argon2Params.Threads == 0 {
+		config.Security.Argon2Params.Threads = 4
+	}
+	if config.Security.MaxFailedAttempts == 0 {
+		config.Security.MaxFailedAttempts = 5
+	}
+	if config.Security.LockDuration == 0 {
+		config.Security.LockDuration = 15 // default 15 minutes
+	}
+	if config.Security.MinPasswordStrength == 0 {
+		config.Security.MinPasswordStrength = 3
+	}
+	if config.Security.MinPasswordStrength < 1 || config.Security.MinPasswordStrength > 4 {
+		return fmt.Errorf("security.min_password_strength must be between 1 and 4")
+	}
+	if config.Security.PasswordMaxAgeDays < 0 {
+		return fmt.Errorf("security.password_max_age_days must not be negative")
+	}
+
+	// Set default tracing service name
+	if config.Tracing.ServiceName == "" {
+		config.Tracing.ServiceName = "k-admin-system"
+	}
 
+	// Validate Timezone config
+	if config.Timezone.Default == "" {
+		config.Timezone.Default = "UTC"
+	}
+	if _, err := time.LoadLocation(config.Timezone.Default); err != nil {
+		return fmt.Errorf("timezone.default %q is not a valid IANA timezone: %w", config.Timezone.Default, err)
+	}
+
+	return nil
+}
+
+// serverPortPattern matches the Gin listen address format ":<port>"
+var serverPortPattern = regexp.MustCompile(`^:\d{1,5}$`)
+
+// validateServerPort checks that port has the form ":<port>" with a port number in 1-65535
+func validateServerPort(port string) error {
+	if !serverPortPattern.MatchString(port) {
+		return fmt.Errorf("server.port must be in the form \":<port>\" (e.g. \":8080\"), got %q", port)
+	}
+	number, _ := strconv.Atoi(port[1:])
+	if !isValidPort(number) {
+		return fmt.Errorf("server.port must be between 1 and 65535, got %q", port)
+	}
 	return nil
 }
+
+// ensureLogDir creates the directory of the log file path if it does not exist,
+// so that an unwritable logger.path is reported at startup rather than by InitLogger
+func ensureLogDir(path string) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("logger.path directory %q cannot be created: %w", dir, err)
+	}
+	return nil
+}
+
+// isValidPort reports whether port is in the valid TCP port range
+func isValidPort(port int) bool {
+	return port >= 1 && port <= 65535
+}
+
+// isValidIPOrCIDR reports whether s is an IP address or a CIDR range
+func isValidIPOrCIDR(s string) bool {
+	if net.ParseIP(s) != nil {
+		return true
+	}
+	_, _, err := net.ParseCIDR(s)
+	return err == nil
+}
@@ -0,0 +1,244 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeConfigFile 在临时目录中写入配置文件，日志路径指向同一临时目录
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	dir := t.TempDir()
+	content = strings.ReplaceAll(content, "{{logPath}}", filepath.ToSlash(filepath.Join(dir, "logs", "app.log")))
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return path
+}
+
+// minimalYAML 满足校验所需的最小YAML配置
+const minimalYAML = `
+server:
+  port: ":8080"
+database:
+  host: localhost
+  port: 3306
+  name: k_admin
+  username: root
+redis:
+  host: localhost
+  port: 6379
+logger:
+  path: "{{logPath}}"
+jwt:
+  secret: test-secret
+`
+
+// tomlConfig 在 minimalYAML 基础上增加了限流配置的TOML配置
+const tomlConfig = `
+[server]
+port = ":8080"
+
+[database]
+host = "localhost"
+port = 3306
+name = "k_admin"
+username = "root"
+
+[redis]
+host = "localhost"
+port = 6379
+
+[logger]
+path = "{{logPath}}"
+
+[jwt]
+secret = "test-secret"
+
+[rate_limit]
+enabled = true
+requests = 100
+window = 60
+whitelist = ["127.0.0.1", "10.0.0.0/8"]
+`
+
+// tomlEquivalentYAML 与 tomlConfig 内容相同的YAML配置
+const tomlEquivalentYAML = minimalYAML + `
+rate_limit:
+  enabled: true
+  requests: 100
+  window: 60
+  whitelist:
+    - "127.0.0.1"
+    - "10.0.0.0/8"
+`
+
+func TestLoadConfig_TOML(t *testing.T) {
+	cfg, err := LoadConfig(writeConfigFile(t, "config.toml", tomlConfig))
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	if cfg.Server.Port != ":8080" || cfg.Database.Port != 3306 || cfg.Database.Name != "k_admin" || cfg.JWT.Secret != "test-secret" {
+		t.Errorf("LoadConfig() scalar values = %+v %+v %+v", cfg.Server, cfg.Database, cfg.JWT)
+	}
+	if want := []string{"127.0.0.1", "10.0.0.0/8"}; !reflect.DeepEqual(cfg.RateLimit.Whitelist, want) {
+		t.Errorf("rate_limit.whitelist = %v, want %v", cfg.RateLimit.Whitelist, want)
+	}
+	if !cfg.RateLimit.Enabled || cfg.RateLimit.Requests != 100 || cfg.RateLimit.Window != 60 {
+		t.Errorf("rate_limit = %+v, want enabled with 100 requests per 60s", cfg.RateLimit)
+	}
+	// 未配置的项与YAML一样使用默认值
+	if cfg.JWT.ClockSkewSeconds != 30 {
+		t.Errorf("unset jwt.clock_skew_seconds = %d, want default 30", cfg.JWT.ClockSkewSeconds)
+	}
+}
+
+func TestLoadConfig_TOMLMatchesYAML(t *testing.T) {
+	tomlCfg, err := LoadConfig(writeConfigFile(t, "config.toml", tomlConfig))
+	if err != nil {
+		t.Fatalf("LoadConfig(toml) error = %v", err)
+	}
+	yamlCfg, err := LoadConfig(writeConfigFile(t, "config.yaml", tomlEquivalentYAML))
+	if err != nil {
+		t.Fatalf("LoadConfig(yaml) error = %v", err)
+	}
+
+	// 日志路径位于各自的临时目录中
+	tomlCfg.Logger.Path, yamlCfg.Logger.Path = "", ""
+	if !reflect.DeepEqual(tomlCfg, yamlCfg) {
+		t.Errorf("TOML config = %+v\nwant the equivalent YAML config %+v", tomlCfg, yamlCfg)
+	}
+}
+
+func TestLoadConfig_InvalidTOML(t *testing.T) {
+	if _, err := LoadConfig(writeConfigFile(t, "config.toml", "[server\nport = \":8080\"\n")); err == nil {
+		t.Error("LoadConfig() accepted malformed TOML")
+	}
+}
+
+func TestLoadConfig_ClockSkewDefault(t *testing.T) {
+	cfg, err := LoadConfig(writeConfigFile(t, "config.yaml", minimalYAML))
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if cfg.JWT.ClockSkewSeconds != 30 {
+		t.Errorf("unset jwt.clock_skew_seconds = %d, want default 30", cfg.JWT.ClockSkewSeconds)
+	}
+}
+
+func TestLoadConfig_ExplicitZeroClockSkew(t *testing.T) {
+	cfg, err := LoadConfig(writeConfigFile(t, "config.yaml", minimalYAML+"  clock_skew_seconds: 0\n"))
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if cfg.JWT.ClockSkewSeconds != 0 {
+		t.Errorf("explicit jwt.clock_skew_seconds: 0 loaded as %d", cfg.JWT.ClockSkewSeconds)
+	}
+}
+
+func TestLoadConfig_PasswordPolicyDefaults(t *testing.T) {
+	cfg, err := LoadConfig(writeConfigFile(t, "config.yaml", minimalYAML))
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if cfg.Security.PasswordMinUpper != 1 || cfg.Security.PasswordMinDigit != 1 || cfg.Security.PasswordMinSymbol != 1 {
+		t.Errorf("unset password minimums = %+v, want 1 each", cfg.Security)
+	}
+}
+
+func TestLoadConfig_ExplicitZeroPasswordPolicy(t *testing.T) {
+	content := minimalYAML + "security:\n  password_min_upper: 0\n  password_min_digit: 0\n  password_min_symbol: 0\n"
+	cfg, err := LoadConfig(writeConfigFile(t, "config.yaml", content))
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if cfg.Security.PasswordMinUpper != 0 || cfg.Security.PasswordMinDigit != 0 || cfg.Security.PasswordMinSymbol != 0 {
+		t.Errorf("explicit zero password minimums loaded as %+v", cfg.Security)
+	}
+}
+
+func TestLoadConfig_NegativePasswordPolicy(t *testing.T) {
+	content := minimalYAML + "security:\n  password_min_digit: -1\n"
+	if _, err := LoadConfig(writeConfigFile(t, "config.yaml", content)); err == nil {
+		t.Error("LoadConfig() accepted a negative security.password_min_digit")
+	}
+}
+
+func TestLoadConfig_PortValidation(t *testing.T) {
+	tests := []struct {
+		field   string
+		line    string // minimalYAML 中被替换的行
+		value   string
+		wantErr bool
+	}{
+		{"server.port", `  port: ":8080"`, `":1"`, false},
+		{"server.port", `  port: ":8080"`, `":65535"`, false},
+		{"server.port", `  port: ":8080"`, `":0"`, true},
+		{"server.port", `  port: ":8080"`, `":65536"`, true},
+		{"server.port", `  port: ":8080"`, `"8080"`, true},
+		{"server.port", `  port: ":8080"`, `":http"`, true},
+		{"database.port", "  port: 3306", "1", false},
+		{"database.port", "  port: 3306", "65535", false},
+		{"database.port", "  port: 3306", "0", true},
+		{"database.port", "  port: 3306", "-1", true},
+		{"database.port", "  port: 3306", "65536", true},
+		{"redis.port", "  port: 6379", "1", false},
+		{"redis.port", "  port: 6379", "65535", false},
+		{"redis.port", "  port: 6379", "0", true},
+		{"redis.port", "  port: 6379", "-1", true},
+		{"redis.port", "  port: 6379", "70000", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.field+"="+tt.value, func(t *testing.T) {
+			content := strings.Replace(minimalYAML, tt.line+"\n", "  port: "+tt.value+"\n", 1)
+			_, err := LoadConfig(writeConfigFile(t, "config.yaml", content))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), tt.field) {
+				t.Errorf("LoadConfig() error = %v, want it to name %s", err, tt.field)
+			}
+		})
+	}
+}
+
+func TestValidateConfig_LoggerPathCreation(t *testing.T) {
+	dir := t.TempDir()
+	blocker := filepath.Join(dir, "not-a-dir")
+	if err := os.WriteFile(blocker, nil, 0644); err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		logPath string
+		wantErr bool
+	}{
+		{"nested directories are created", filepath.Join(dir, "a", "b", "c", "app.log"), false},
+		{"existing directory", filepath.Join(dir, "app.log"), false},
+		{"parent is a regular file", filepath.Join(blocker, "logs", "app.log"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := strings.Replace(minimalYAML, `"{{logPath}}"`, `"`+filepath.ToSlash(tt.logPath)+`"`, 1)
+			_, err := LoadConfig(writeConfigFile(t, "config.yaml", content))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !strings.Contains(err.Error(), "logger.path") {
+					t.Errorf("LoadConfig() error = %v, want it to name logger.path", err)
+				}
+				return
+			}
+			if info, err := os.Stat(filepath.Dir(tt.logPath)); err != nil || !info.IsDir() {
+				t.Errorf("log directory %s was not created: %v", filepath.Dir(tt.logPath), err)
+			}
+		})
+	}
+}
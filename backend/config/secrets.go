@@ -0,0 +1,93 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// secretRefPrefix marks a config value that must be resolved from a secrets manager,
+// e.g. password: "sm://prod/k-admin/db-password"
+const secretRefPrefix = "sm://"
+
+// SecretsManager resolves secret references in configuration values
+type SecretsManager interface {
+	GetSecret(key string) (string, error)
+}
+
+// MockSecretsManager is an in-memory SecretsManager for tests and local development
+type MockSecretsManager struct {
+	Secrets map[string]string
+}
+
+// GetSecret returns the secret stored under key
+func (m *MockSecretsManager) GetSecret(key string) (string, error) {
+	value, ok := m.Secrets[key]
+	if !ok {
+		return "", fmt.Errorf("secret %q not found", key)
+	}
+	return value, nil
+}
+
+// hasSecretRefs reports whether any string field in config starts with sm://
+func hasSecretRefs(config *Config) bool {
+	found := false
+	walkStringFields(reflect.ValueOf(config).Elem(), "", func(_ string, field reflect.Value) error {
+		if strings.HasPrefix(field.String(), secretRefPrefix) {
+			found = true
+		}
+		return nil
+	})
+	return found
+}
+
+// resolveSecrets replaces every sm://<key> string field in config with the secret value
+func resolveSecrets(config *Config, sm SecretsManager) error {
+	return walkStringFields(reflect.ValueOf(config).Elem(), "", func(path string, field reflect.Value) error {
+		value := field.String()
+		if !strings.HasPrefix(value, secretRefPrefix) {
+			return nil
+		}
+
+		secret, err := sm.GetSecret(strings.TrimPrefix(value, secretRefPrefix))
+		if err != nil {
+			return fmt.Errorf("failed to resolve secret for %s: %w", path, err)
+		}
+		field.SetString(secret)
+		return nil
+	})
+}
+
+// walkStringFields calls fn for every settable string field (including string slice elements) in v
+func walkStringFields(v reflect.Value, path string, fn func(path string, field reflect.Value) error) error {
+	switch v.Kind() {
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			if !t.Field(i).IsExported() {
+				continue
+			}
+			name := t.Field(i).Tag.Get("mapstructure")
+			if name == "" {
+				name = t.Field(i).Name
+			}
+			if path != "" {
+				name = path + "." + name
+			}
+			if err := walkStringFields(v.Field(i), name, fn); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			if err := walkStringFields(v.Index(i), fmt.Sprintf("%s[%d]", path, i), fn); err != nil {
+				return err
+			}
+		}
+	case reflect.String:
+		if v.CanSet() {
+			return fn(path, v)
+		}
+	}
+	return nil
+}
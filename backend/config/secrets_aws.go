@@ -0,0 +1,47 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+)
+
+// AWSSecretsManager resolves secrets from AWS Secrets Manager
+type AWSSecretsManager struct {
+	client *secretsmanager.Client
+}
+
+// NewAWSSecretsManager creates an AWS Secrets Manager backend using the default
+// credential chain (env vars, shared config, IAM role). region overrides the default region when set
+func NewAWSSecretsManager(ctx context.Context, region string) (*AWSSecretsManager, error) {
+	var opts []func(*awsconfig.LoadOptions) error
+	if region != "" {
+		opts = append(opts, awsconfig.WithRegion(region))
+	}
+
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	return &AWSSecretsManager{client: secretsmanager.NewFromConfig(awsCfg)}, nil
+}
+
+// GetSecret returns the string value of the secret identified by key (name or ARN)
+func (m *AWSSecretsManager) GetSecret(key string) (string, error) {
+	output, err := m.client.GetSecretValue(context.Background(), &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(key),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get secret %q: %w", key, err)
+	}
+	if output.SecretString == nil {
+		return "", errors.New("secret " + key + " has no string value")
+	}
+
+	return *output.SecretString, nil
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+// secretsYAML 数据库密码和CORS来源引用密钥的配置
+var secretsYAML = strings.Replace(minimalYAML, "  username: root\n", "  username: root\n  password: \"sm://prod/k-admin/db-password\"\n", 1) + `cors:
+  allow_origins:
+    - "sm://prod/k-admin/frontend-origin"
+`
+
+func TestLoadConfigWithSecrets_ResolvesThroughMock(t *testing.T) {
+	sm := &MockSecretsManager{Secrets: map[string]string{
+		"prod/k-admin/db-password":     "s3cr3t",
+		"prod/k-admin/frontend-origin": "https://admin.example.com",
+	}}
+
+	cfg, err := LoadConfigWithSecrets(writeConfigFile(t, "config.yaml", secretsYAML), sm)
+	if err != nil {
+		t.Fatalf("LoadConfigWithSecrets() error = %v", err)
+	}
+	if cfg.Database.Password != "s3cr3t" {
+		t.Errorf("database.password = %q, want the resolved secret", cfg.Database.Password)
+	}
+	if len(cfg.CORS.AllowOrigins) != 1 || cfg.CORS.AllowOrigins[0] != "https://admin.example.com" {
+		t.Errorf("cors.allow_origins = %v, want the resolved secret", cfg.CORS.AllowOrigins)
+	}
+}
+
+func TestLoadConfigWithSecrets_MissingSecret(t *testing.T) {
+	sm := &MockSecretsManager{Secrets: map[string]string{
+		"prod/k-admin/frontend-origin": "https://admin.example.com",
+	}}
+
+	_, err := LoadConfigWithSecrets(writeConfigFile(t, "config.yaml", secretsYAML), sm)
+	if err == nil || !strings.Contains(err.Error(), "database.password") {
+		t.Errorf("LoadConfigWithSecrets() error = %v, want an error naming database.password", err)
+	}
+}
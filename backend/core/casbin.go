@@ -1,18 +1,25 @@
 package core
 
 import (
+	"strings"
+	"sync"
+
 	"k-admin-system/global"
 	"k-admin-system/model/system"
+	"k-admin-system/resource"
 
 	"github.com/casbin/casbin/v3"
+	"github.com/casbin/casbin/v3/model"
 	gormadapter "github.com/casbin/gorm-adapter/v3"
 	"go.uber.org/zap"
 )
 
 // InitCasbin 初始化Casbin enforcer
-// 使用Gorm adapter连接数据库，加载RBAC模型配置
+// 使用Gorm adapter连接数据库，策略持久化在sys_casbin_rules表中，重启后仍然有效
+// RBAC模型配置通过 go:embed 从 resource/model.conf 加载
 func InitCasbin() (*casbin.Enforcer, error) {
 	// 创建Gorm adapter，使用sys_casbin_rules表
+	// adapter 默认开启自动迁移，会在表不存在时自动创建
 	adapter, err := gormadapter.NewAdapterByDBWithCustomTable(
 		global.DB,
 		&system.SysCasbinRule{},
@@ -23,8 +30,14 @@ func InitCasbin() (*casbin.Enforcer, error) {
 		return nil, err
 	}
 
-	// 加载Casbin模型配置文件
-	enforcer, err := casbin.NewEnforcer("config/casbin_model.conf", adapter)
+	// 加载内嵌的Casbin模型配置
+	m, err := model.NewModelFromString(resource.CasbinModel)
+	if err != nil {
+		global.Logger.Error("Failed to load Casbin model", zap.Error(err))
+		return nil, err
+	}
+
+	enforcer, err := casbin.NewEnforcer(m, adapter)
 	if err != nil {
 		global.Logger.Error("Failed to create Casbin enforcer", zap.Error(err))
 		return nil, err
@@ -40,3 +53,59 @@ func InitCasbin() (*casbin.Enforcer, error) {
 	global.Logger.Info("Casbin enforcer initialized successfully")
 	return enforcer, nil
 }
+
+// casbinCacheMaxEntries 权限决策缓存的最大条目数，超出后整体清空，避免大量不同路径导致内存无限增长
+const casbinCacheMaxEntries = 10000
+
+// casbinCache 权限决策缓存
+// enforcer 的策略本身已常驻内存，但每次 Enforce 都要对全部策略执行 keyMatch2 匹配，
+// 缓存 (角色, 路径, 方法) 的决策结果可避免重复匹配。策略变更后必须调用 InvalidateCasbinCache
+// casbinCacheGen 为缓存代数，每次清空缓存时递增。
+// Enforce 开始前读取代数，结束后代数未变才写入缓存，避免策略变更前开始的检查写回旧决策
+var (
+	casbinCacheMu  sync.RWMutex
+	casbinCache    = make(map[string]bool)
+	casbinCacheGen uint64
+)
+
+// casbinEnforce 执行实际的权限匹配，测试中可替换
+var casbinEnforce = func(sub, obj, act string) (bool, error) {
+	return global.CasbinEnforcer.Enforce(sub, obj, act)
+}
+
+// CasbinEnforce 带决策缓存的权限检查
+func CasbinEnforce(sub, obj, act string) (bool, error) {
+	key := strings.Join([]string{sub, obj, act}, "\x00")
+
+	casbinCacheMu.RLock()
+	allowed, ok := casbinCache[key]
+	gen := casbinCacheGen
+	casbinCacheMu.RUnlock()
+	if ok {
+		return allowed, nil
+	}
+
+	allowed, err := casbinEnforce(sub, obj, act)
+	if err != nil {
+		return false, err
+	}
+
+	casbinCacheMu.Lock()
+	if gen == casbinCacheGen {
+		if len(casbinCache) >= casbinCacheMaxEntries {
+			casbinCache = make(map[string]bool)
+		}
+		casbinCache[key] = allowed
+	}
+	casbinCacheMu.Unlock()
+
+	return allowed, nil
+}
+
+// InvalidateCasbinCache 清空权限决策缓存，在增删策略后调用
+func InvalidateCasbinCache() {
+	casbinCacheMu.Lock()
+	casbinCache = make(map[string]bool)
+	casbinCacheGen++
+	casbinCacheMu.Unlock()
+}
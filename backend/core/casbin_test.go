@@ -0,0 +1,152 @@
+package core
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"k-admin-system/global"
+)
+
+const (
+	benchmarkPolicyCount     = 100
+	benchmarkConcurrentCalls = 1000
+)
+
+// setupBenchmarkCasbin 初始化包含 benchmarkPolicyCount 条策略的 enforcer
+func setupBenchmarkCasbin(b *testing.B) {
+	b.Helper()
+	setupTestCasbin(b, setupTestDB(b))
+
+	policies := make([][]string, 0, benchmarkPolicyCount)
+	for i := 0; i < benchmarkPolicyCount; i++ {
+		policies = append(policies, []string{"editor", fmt.Sprintf("/api/v1/resource%d/:id", i), http.MethodGet})
+	}
+	if _, err := global.CasbinEnforcer.AddPolicies(policies); err != nil {
+		b.Fatalf("failed to add policies: %v", err)
+	}
+	InvalidateCasbinCache()
+}
+
+// runConcurrentEnforce 并发执行 benchmarkConcurrentCalls 次权限检查
+func runConcurrentEnforce(b *testing.B, enforce func(sub, obj, act string) (bool, error)) {
+	var wg sync.WaitGroup
+	wg.Add(benchmarkConcurrentCalls)
+	for i := 0; i < benchmarkConcurrentCalls; i++ {
+		go func(i int) {
+			defer wg.Done()
+			path := fmt.Sprintf("/api/v1/resource%d/%d", i%benchmarkPolicyCount, i%10)
+			if _, err := enforce("editor", path, http.MethodGet); err != nil {
+				b.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func BenchmarkCasbinEnforce_Cached(b *testing.B) {
+	setupBenchmarkCasbin(b)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		runConcurrentEnforce(b, CasbinEnforce)
+	}
+}
+
+func BenchmarkCasbinEnforce_Uncached(b *testing.B) {
+	setupBenchmarkCasbin(b)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		runConcurrentEnforce(b, func(sub, obj, act string) (bool, error) {
+			return global.CasbinEnforcer.Enforce(sub, obj, act)
+		})
+	}
+}
+
+func TestCasbinEnforce_InvalidatesOnWrite(t *testing.T) {
+	setupTestCasbin(t, setupTestDB(t))
+
+	allowed, err := CasbinEnforce("editor", "/api/v1/post/1", http.MethodGet)
+	if err != nil {
+		t.Fatalf("CasbinEnforce() error = %v", err)
+	}
+	if allowed {
+		t.Fatal("CasbinEnforce() = true before policy was added")
+	}
+
+	if _, err := global.CasbinEnforcer.AddPolicy("editor", "/api/v1/post/:id", http.MethodGet); err != nil {
+		t.Fatalf("AddPolicy() error = %v", err)
+	}
+	// 未清空缓存时仍返回缓存的决策
+	if allowed, _ := CasbinEnforce("editor", "/api/v1/post/1", http.MethodGet); allowed {
+		t.Error("CasbinEnforce() bypassed the cache before invalidation")
+	}
+
+	InvalidateCasbinCache()
+	allowed, err = CasbinEnforce("editor", "/api/v1/post/1", http.MethodGet)
+	if err != nil {
+		t.Fatalf("CasbinEnforce() error = %v", err)
+	}
+	if !allowed {
+		t.Error("CasbinEnforce() = false after policy was added and cache invalidated")
+	}
+}
+
+func TestCasbinEnforce_DoesNotCacheDecisionsFromBeforeInvalidation(t *testing.T) {
+	setupTestCasbin(t, setupTestDB(t))
+	if _, err := global.CasbinEnforcer.AddPolicy("editor", "/api/v1/post/:id", http.MethodDelete); err != nil {
+		t.Fatalf("AddPolicy() error = %v", err)
+	}
+	InvalidateCasbinCache()
+
+	// 检查读取旧策略得到允许后、写入缓存前，策略被撤销并清空缓存
+	prevEnforce := casbinEnforce
+	t.Cleanup(func() { casbinEnforce = prevEnforce })
+	casbinEnforce = func(sub, obj, act string) (bool, error) {
+		allowed, err := prevEnforce(sub, obj, act)
+		if _, err := global.CasbinEnforcer.RemovePolicy("editor", "/api/v1/post/:id", http.MethodDelete); err != nil {
+			t.Errorf("RemovePolicy() error = %v", err)
+		}
+		InvalidateCasbinCache()
+		return allowed, err
+	}
+	if allowed, err := CasbinEnforce("editor", "/api/v1/post/1", http.MethodDelete); err != nil || !allowed {
+		t.Fatalf("CasbinEnforce() = %v, %v; want the in-flight allow decision", allowed, err)
+	}
+
+	casbinEnforce = prevEnforce
+	allowed, err := CasbinEnforce("editor", "/api/v1/post/1", http.MethodDelete)
+	if err != nil {
+		t.Fatalf("CasbinEnforce() error = %v", err)
+	}
+	if allowed {
+		t.Error("CasbinEnforce() returned a stale allow decision cached after the policy was revoked")
+	}
+}
+
+func TestInitCasbin_PoliciesSurviveRestart(t *testing.T) {
+	setupTestCasbin(t, setupTestDB(t))
+	if _, err := global.CasbinEnforcer.AddPolicy("editor", "/api/v1/post/:id", http.MethodPut); err != nil {
+		t.Fatalf("AddPolicy() error = %v", err)
+	}
+	if _, err := global.CasbinEnforcer.AddGroupingPolicy("alice", "editor"); err != nil {
+		t.Fatalf("AddGroupingPolicy() error = %v", err)
+	}
+
+	// 重新创建 enforcer，模拟服务重启后从数据库加载策略
+	enforcer, err := InitCasbin()
+	if err != nil {
+		t.Fatalf("InitCasbin() error = %v", err)
+	}
+	for _, sub := range []string{"editor", "alice"} {
+		allowed, err := enforcer.Enforce(sub, "/api/v1/post/7", http.MethodPut)
+		if err != nil {
+			t.Fatalf("Enforce() error = %v", err)
+		}
+		if !allowed {
+			t.Errorf("Enforce(%s) = false after recreating the enforcer", sub)
+		}
+	}
+}
@@ -28,8 +28,32 @@ func InitDB(cfg *config.Config, log *zap.Logger) (*gorm.DB, error) {
 	// Configure Gorm logger
 	gormLogger := newGormLogger(log, cfg)
 
-	// Open database connection
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
+	// Open database connection, retrying while the database is not ready yet
+	db, err := connectWithRetry("Database", cfg.Database.MaxRetries, log, func() (*gorm.DB, error) {
+		return openDB(dsn, cfg, gormLogger)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("Database connected successfully",
+		zap.String("host", cfg.Database.Host),
+		zap.Int("port", cfg.Database.Port),
+		zap.String("database", cfg.Database.Name),
+		zap.Int("max_idle_conns", cfg.Database.MaxIdleConns),
+		zap.Int("max_open_conns", cfg.Database.MaxOpenConns),
+	)
+
+	return db, nil
+}
+
+// dbDialector builds the Gorm dialector for a DSN
+// Tests replace it to simulate connection failures without a MySQL server
+var dbDialector = mysql.Open
+
+// openDB opens a database connection, configures the connection pool and verifies it with a ping
+func openDB(dsn string, cfg *config.Config, gormLogger logger.Interface) (*gorm.DB, error) {
+	db, err := gorm.Open(dbDialector(dsn), &gorm.Config{
 		Logger: gormLogger,
 		NowFunc: func() time.Time {
 			return time.Now().Local()
@@ -48,24 +72,66 @@ func InitDB(cfg *config.Config, log *zap.Logger) (*gorm.DB, error) {
 	// Configure connection pool
 	sqlDB.SetMaxIdleConns(cfg.Database.MaxIdleConns)
 	sqlDB.SetMaxOpenConns(cfg.Database.MaxOpenConns)
-	sqlDB.SetConnMaxLifetime(time.Hour)
+	sqlDB.SetConnMaxLifetime(time.Duration(cfg.Database.ConnMaxLifetime) * time.Second)
+	sqlDB.SetConnMaxIdleTime(time.Duration(cfg.Database.ConnMaxIdleTime) * time.Second)
 
 	// Test connection
 	if err := sqlDB.Ping(); err != nil {
+		sqlDB.Close()
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	log.Info("Database connected successfully",
-		zap.String("host", cfg.Database.Host),
-		zap.Int("port", cfg.Database.Port),
-		zap.String("database", cfg.Database.Name),
-		zap.Int("max_idle_conns", cfg.Database.MaxIdleConns),
-		zap.Int("max_open_conns", cfg.Database.MaxOpenConns),
-	)
-
 	return db, nil
 }
 
+var (
+	// retryInitialBackoff is the wait time after the first failed attempt
+	retryInitialBackoff = time.Second
+	// retryMaxBackoff caps the exponential backoff between attempts
+	retryMaxBackoff = 30 * time.Second
+)
+
+// connectWithRetry calls connect up to maxRetries times with exponential backoff
+// (1s, 2s, 4s, ... capped at 30s) and returns the last error once all attempts fail.
+// name identifies the backing service (e.g. "Database", "Redis") in log messages
+func connectWithRetry[T any](name string, maxRetries int, log *zap.Logger, connect func() (T, error)) (T, error) {
+	if maxRetries < 1 {
+		maxRetries = 1
+	}
+
+	start := time.Now()
+	backoff := retryInitialBackoff
+	var zero T
+	var lastErr error
+
+	for attempt := 1; attempt <= maxRetries; attempt++ {
+		conn, err := connect()
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+
+		log.Warn(name+" connection attempt failed",
+			zap.Int("attempt", attempt),
+			zap.Int("max_retries", maxRetries),
+			zap.Duration("elapsed", time.Since(start)),
+			zap.Error(err),
+		)
+
+		if attempt == maxRetries {
+			break
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+		if backoff > retryMaxBackoff {
+			backoff = retryMaxBackoff
+		}
+	}
+
+	return zero, lastErr
+}
+
 // gormLogger is a custom logger that integrates Gorm with Zap
 type gormLogger struct {
 	zapLogger         *zap.Logger
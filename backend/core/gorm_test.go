@@ -0,0 +1,114 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"k-admin-system/config"
+
+	"github.com/glebarez/sqlite"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// failingDialector 初始化时总是失败的 dialector，模拟数据库尚未就绪
+type failingDialector struct {
+	gorm.Dialector
+}
+
+func (failingDialector) Initialize(*gorm.DB) error {
+	return errors.New("connection refused")
+}
+
+// mockDBDialector 替换 dbDialector：前 failures 次连接失败，之后连接到内存SQLite，返回已尝试次数的指针
+func mockDBDialector(t *testing.T, failures int) *int {
+	t.Helper()
+	attempts := 0
+	prevDialector, prevBackoff := dbDialector, retryInitialBackoff
+	dbDialector = func(string) gorm.Dialector {
+		attempts++
+		if attempts <= failures {
+			return failingDialector{sqlite.Open(":memory:")}
+		}
+		return sqlite.Open(":memory:")
+	}
+	retryInitialBackoff = 10 * time.Millisecond
+	t.Cleanup(func() {
+		dbDialector, retryInitialBackoff = prevDialector, prevBackoff
+	})
+	return &attempts
+}
+
+func TestInitDB_RetryOnFailure(t *testing.T) {
+	attempts := mockDBDialector(t, 2)
+
+	db, err := InitDB(&config.Config{Database: config.DatabaseConfig{MaxRetries: 5, MaxOpenConns: 1}}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("InitDB() error = %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get sql.DB: %v", err)
+	}
+	defer sqlDB.Close()
+
+	if *attempts != 3 {
+		t.Errorf("connection attempts = %d, want 3", *attempts)
+	}
+	if err := db.Exec("SELECT 1").Error; err != nil {
+		t.Errorf("database is not usable: %v", err)
+	}
+}
+
+func TestInitDB_GivesUpAfterMaxRetries(t *testing.T) {
+	attempts := mockDBDialector(t, 10)
+
+	if _, err := InitDB(&config.Config{Database: config.DatabaseConfig{MaxRetries: 2}}, zap.NewNop()); err == nil {
+		t.Fatal("InitDB() succeeded although every connection attempt failed")
+	}
+	if *attempts != 2 {
+		t.Errorf("connection attempts = %d, want 2", *attempts)
+	}
+}
+
+func TestInitDB_ConnectionPoolSettings(t *testing.T) {
+	mockDBDialector(t, 0)
+
+	db, err := InitDB(&config.Config{Database: config.DatabaseConfig{
+		MaxRetries: 1, MaxOpenConns: 3, MaxIdleConns: 1, ConnMaxLifetime: 3600, ConnMaxIdleTime: 600,
+	}}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("InitDB() error = %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get sql.DB: %v", err)
+	}
+	defer sqlDB.Close()
+
+	if got := sqlDB.Stats().MaxOpenConnections; got != 3 {
+		t.Errorf("MaxOpenConnections = %d, want 3", got)
+	}
+
+	// 同时占用3个连接后释放，空闲连接池只保留1个
+	conns := make([]*sql.Conn, 0, 3)
+	for i := 0; i < 3; i++ {
+		conn, err := sqlDB.Conn(context.Background())
+		if err != nil {
+			t.Fatalf("failed to acquire connection %d: %v", i+1, err)
+		}
+		conns = append(conns, conn)
+	}
+	if stats := sqlDB.Stats(); stats.OpenConnections != 3 || stats.InUse != 3 {
+		t.Errorf("stats while holding connections = %+v, want 3 open and in use", stats)
+	}
+	for _, conn := range conns {
+		_ = conn.Close()
+	}
+	if stats := sqlDB.Stats(); stats.Idle != 1 || stats.MaxIdleClosed != 2 {
+		t.Errorf("stats after release = %+v, want 1 idle and 2 closed for exceeding max idle", stats)
+	}
+}
@@ -0,0 +1,55 @@
+package core
+
+import (
+	"testing"
+
+	"k-admin-system/global"
+
+	"github.com/glebarez/sqlite"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// setupTestDB 使用空的内存SQLite初始化 global.DB，测试结束后恢复原值
+func setupTestDB(t testing.TB) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	// 内存数据库只存在于单个连接中
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get sql.DB: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	prevDB, prevLogger, prevEnforcer := global.DB, global.Logger, global.CasbinEnforcer
+	global.DB = db
+	global.Logger = zap.NewNop()
+	global.CasbinEnforcer = nil
+	t.Cleanup(func() {
+		_ = sqlDB.Close()
+		global.DB, global.Logger, global.CasbinEnforcer = prevDB, prevLogger, prevEnforcer
+		InvalidateCasbinCache()
+	})
+
+	return db
+}
+
+// setupTestCasbin 创建全部迁移表并基于测试数据库初始化 Casbin enforcer
+func setupTestCasbin(t testing.TB, db *gorm.DB) {
+	t.Helper()
+
+	if err := db.AutoMigrate(migrationModels()...); err != nil {
+		t.Fatalf("failed to migrate tables: %v", err)
+	}
+	enforcer, err := InitCasbin()
+	if err != nil {
+		t.Fatalf("failed to init casbin: %v", err)
+	}
+	global.CasbinEnforcer = enforcer
+	InvalidateCasbinCache()
+}
@@ -1,6 +1,14 @@
 package core
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
 	"k-admin-system/global"
 	"k-admin-system/model/system"
 	"k-admin-system/utils"
@@ -9,15 +17,33 @@ import (
 	"gorm.io/gorm"
 )
 
+// migrationVersion 当前表结构版本，修改迁移模型时同步更新
+const migrationVersion = "v1.1.0_sessions_api_keys_configs"
+
+// migrationModels 需要自动迁移的模型
+// 注意顺序：先创建被引用的表，再创建引用它们的表
+func migrationModels() []interface{} {
+	return []interface{}{
+		&system.SysRole{},              // 先创建角色表
+		&system.SysMenu{},              // 再创建菜单表
+		&system.SysUser{},              // 最后创建用户表（依赖角色表）
+		&system.SysCasbinRule{},        // Casbin 规则表
+		&system.SysCasbinSeed{},        // 已写入的 Casbin 种子策略表
+		&system.SysRateLimitOverride{}, // 用户级限流覆盖表
+		&system.SysAuditLog{},          // 审计日志表
+		&system.SysOperationLog{},      // 操作日志表
+		&system.SysCodeGenHistory{},    // 代码生成历史表
+		&system.SysUserSession{},       // 用户登录会话表
+		&system.SysAPIKey{},            // API密钥表
+		&system.SysNotification{},      // 站内通知表
+		&system.SysUserNotification{},  // 用户通知接收表
+		&system.SysConfig{},            // 系统动态配置表
+	}
+}
+
 // RegisterTables 注册需要自动迁移的表
 func RegisterTables(db *gorm.DB) error {
-	// 注册系统模型 - 注意顺序：先创建被引用的表，再创建引用它们的表
-	err := db.AutoMigrate(
-		&system.SysRole{},       // 先创建角色表
-		&system.SysMenu{},       // 再创建菜单表
-		&system.SysUser{},       // 最后创建用户表（依赖角色表）
-		&system.SysCasbinRule{}, // Casbin 规则表
-	)
+	err := db.AutoMigrate(migrationModels()...)
 	if err != nil {
 		global.Logger.Error("Failed to migrate tables", zap.Error(err))
 		return err
@@ -28,8 +54,9 @@ func RegisterTables(db *gorm.DB) error {
 }
 
 // InitializeData 初始化默认数据
-func InitializeData() error {
-	if global.DB == nil {
+// db 可以是事务，由调用方决定失败时是否回滚；Casbin 策略通过独立的适配器连接写入，不在此处处理
+func InitializeData(db *gorm.DB) error {
+	if db == nil {
 		global.Logger.Error("Database connection is nil, cannot initialize data")
 		return gorm.ErrInvalidDB
 	}
@@ -38,24 +65,24 @@ func InitializeData() error {
 
 	// 检查是否已有管理员角色
 	var roleCount int64
-	if err := global.DB.Model(&system.SysRole{}).Count(&roleCount).Error; err != nil {
+	if err := db.Model(&system.SysRole{}).Count(&roleCount).Error; err != nil {
 		global.Logger.Error("Failed to count roles", zap.Error(err))
 		return err
 	}
 
 	if roleCount > 0 {
-		global.Logger.Info("Roles already exist, checking menu associations and Casbin policies...")
+		global.Logger.Info("Roles already exist, checking menu associations...")
 
 		// 检查管理员角色的菜单关联
 		var adminRole system.SysRole
-		if err := global.DB.Where("role_key = ?", "admin").First(&adminRole).Error; err != nil {
+		if err := db.Where("role_key = ?", "admin").First(&adminRole).Error; err != nil {
 			global.Logger.Error("Failed to find admin role", zap.Error(err))
 			return err
 		}
 
 		// 检查并修复菜单关联
 		var totalMenuCount int64
-		if err := global.DB.Model(&system.SysMenu{}).Count(&totalMenuCount).Error; err != nil {
+		if err := db.Model(&system.SysMenu{}).Count(&totalMenuCount).Error; err != nil {
 			global.Logger.Error("Failed to count total menus", zap.Error(err))
 			return err
 		}
@@ -63,19 +90,19 @@ func InitializeData() error {
 
 		if totalMenuCount == 0 {
 			global.Logger.Warn("No menus in database, creating default menus...")
-			if err := createDefaultMenus(&adminRole); err != nil {
+			if err := createDefaultMenus(db, &adminRole); err != nil {
 				return err
 			}
 		} else {
-			menuCount := global.DB.Model(&adminRole).Association("Menus").Count()
+			menuCount := db.Model(&adminRole).Association("Menus").Count()
 			if menuCount == 0 {
 				global.Logger.Warn("Admin role has no menu associations, fixing...")
 				var allMenus []system.SysMenu
-				if err := global.DB.Find(&allMenus).Error; err != nil {
+				if err := db.Find(&allMenus).Error; err != nil {
 					global.Logger.Error("Failed to find menus", zap.Error(err))
 					return err
 				}
-				if err := global.DB.Model(&adminRole).Association("Menus").Append(allMenus); err != nil {
+				if err := db.Model(&adminRole).Association("Menus").Append(allMenus); err != nil {
 					global.Logger.Error("Failed to associate menus with admin role", zap.Error(err))
 					return err
 				}
@@ -85,11 +112,6 @@ func InitializeData() error {
 			}
 		}
 
-		// 检查并添加 Casbin 策略
-		if err := ensureAdminCasbinPolicies(); err != nil {
-			return err
-		}
-
 		return nil
 	}
 
@@ -104,7 +126,7 @@ func InitializeData() error {
 		Status:    true,
 		Remark:    "系统默认超级管理员角色",
 	}
-	if err := global.DB.Create(adminRole).Error; err != nil {
+	if err := db.Create(adminRole).Error; err != nil {
 		global.Logger.Error("Failed to create admin role", zap.Error(err))
 		return err
 	}
@@ -124,19 +146,14 @@ func InitializeData() error {
 		RoleID:   adminRole.ID,
 		Active:   true,
 	}
-	if err := global.DB.Create(adminUser).Error; err != nil {
+	if err := db.Create(adminUser).Error; err != nil {
 		global.Logger.Error("Failed to create admin user", zap.Error(err))
 		return err
 	}
 	global.Logger.Info("Admin user created", zap.Uint("userId", adminUser.ID))
 
 	// 创建默认菜单
-	if err := createDefaultMenus(adminRole); err != nil {
-		return err
-	}
-
-	// 添加 admin 角色的 Casbin 策略
-	if err := ensureAdminCasbinPolicies(); err != nil {
+	if err := createDefaultMenus(db, adminRole); err != nil {
 		return err
 	}
 
@@ -145,7 +162,7 @@ func InitializeData() error {
 }
 
 // createDefaultMenus 创建默认菜单并关联到角色
-func createDefaultMenus(adminRole *system.SysRole) error {
+func createDefaultMenus(db *gorm.DB, adminRole *system.SysRole) error {
 	// 创建默认菜单
 	menus := []system.SysMenu{
 		// 仪表盘
@@ -196,7 +213,7 @@ func createDefaultMenus(adminRole *system.SysRole) error {
 	}
 
 	// 批量创建菜单
-	if err := global.DB.Create(&menus).Error; err != nil {
+	if err := db.Create(&menus).Error; err != nil {
 		global.Logger.Error("Failed to create menus", zap.Error(err))
 		return err
 	}
@@ -204,8 +221,8 @@ func createDefaultMenus(adminRole *system.SysRole) error {
 
 	// 获取父菜单ID
 	var systemMenu, toolsMenu system.SysMenu
-	global.DB.Where("name = ?", "System").First(&systemMenu)
-	global.DB.Where("name = ?", "Tools").First(&toolsMenu)
+	db.Where("name = ?", "System").First(&systemMenu)
+	db.Where("name = ?", "Tools").First(&toolsMenu)
 
 	// 创建子菜单
 	subMenus := []system.SysMenu{
@@ -284,7 +301,7 @@ func createDefaultMenus(adminRole *system.SysRole) error {
 	}
 
 	// 批量创建子菜单
-	if err := global.DB.Create(&subMenus).Error; err != nil {
+	if err := db.Create(&subMenus).Error; err != nil {
 		global.Logger.Error("Failed to create sub menus", zap.Error(err))
 		return err
 	}
@@ -292,7 +309,7 @@ func createDefaultMenus(adminRole *system.SysRole) error {
 
 	// 将所有菜单关联到管理员角色
 	allMenus := append(menus, subMenus...)
-	if err := global.DB.Model(adminRole).Association("Menus").Append(allMenus); err != nil {
+	if err := db.Model(adminRole).Association("Menus").Append(allMenus); err != nil {
 		global.Logger.Error("Failed to associate menus with admin role", zap.Error(err))
 		return err
 	}
@@ -301,27 +318,15 @@ func createDefaultMenus(adminRole *system.SysRole) error {
 	return nil
 }
 
-// ensureAdminCasbinPolicies 确保 admin 角色拥有所有 API 访问权限
+// ensureAdminCasbinPolicies 为 admin 角色写入内置的 API 访问策略
+// 每条种子策略只写入一次并记录在 sys_casbin_seeds 中：新版本新增的种子策略会应用到已有数据库，
+// 而管理员撤销的策略不会在重启时被重新添加
 func ensureAdminCasbinPolicies() error {
 	if global.CasbinEnforcer == nil {
 		global.Logger.Warn("Casbin enforcer is nil, skipping policy initialization")
 		return nil
 	}
 
-	// 检查 admin 角色是否已有策略
-	policies, err := global.CasbinEnforcer.GetFilteredPolicy(0, "admin")
-	if err != nil {
-		global.Logger.Error("Failed to get filtered policies", zap.Error(err))
-		return err
-	}
-
-	if len(policies) > 0 {
-		global.Logger.Info("Admin role already has Casbin policies", zap.Int("count", len(policies)))
-		return nil
-	}
-
-	global.Logger.Info("Adding Casbin policies for admin role...")
-
 	// 为 admin 角色添加所有 API 访问权限
 	// 使用通配符 * 表示允许访问所有路径和方法
 	adminPolicies := [][]string{
@@ -332,16 +337,28 @@ func ensureAdminCasbinPolicies() error {
 		{"admin", "/api/v1/user/:id", "PUT"},
 		{"admin", "/api/v1/user/:id", "DELETE"},
 		{"admin", "/api/v1/user/:id/status", "PUT"},
+		{"admin", "/api/v1/user/:id/roles", "GET"},
+		{"admin", "/api/v1/user/:id/activity", "GET"},
 		{"admin", "/api/v1/user/reset-password", "POST"},
+		{"admin", "/api/v1/user/dormant", "GET"},
+		{"admin", "/api/v1/user/cleanup", "DELETE"},
+		{"admin", "/api/v1/user/batch-status", "POST"},
+		{"admin", "/api/v1/user/merge", "POST"},
+		{"admin", "/api/v1/user/:id/sessions", "GET"},
+		{"admin", "/api/v1/user/session/:sessionID", "DELETE"},
 
 		// 角色管理
 		{"admin", "/api/v1/role/list", "GET"},
 		{"admin", "/api/v1/role/:id", "GET"},
 		{"admin", "/api/v1/role", "POST"},
+		{"admin", "/api/v1/role/create-full", "POST"},
 		{"admin", "/api/v1/role/:id", "PUT"},
+		{"admin", "/api/v1/role/:id/remark", "PATCH"},
 		{"admin", "/api/v1/role/:id", "DELETE"},
 		{"admin", "/api/v1/role/assign-menus", "POST"},
+		{"admin", "/api/v1/role/bulk-assign-menus", "POST"},
 		{"admin", "/api/v1/role/:id/menus", "GET"},
+		{"admin", "/api/v1/role/:id/menu-tree", "GET"},
 		{"admin", "/api/v1/role/assign-apis", "POST"},
 		{"admin", "/api/v1/role/:id/apis", "GET"},
 
@@ -351,7 +368,34 @@ func ensureAdminCasbinPolicies() error {
 		{"admin", "/api/v1/menu/:id", "GET"},
 		{"admin", "/api/v1/menu", "POST"},
 		{"admin", "/api/v1/menu/:id", "PUT"},
+		{"admin", "/api/v1/menu/reorder", "PUT"},
 		{"admin", "/api/v1/menu/:id", "DELETE"},
+		{"admin", "/api/v1/menu/:id/restore", "POST"},
+		{"admin", "/api/v1/menu/:id/users", "GET"},
+		{"admin", "/api/v1/menu/export", "GET"},
+		{"admin", "/api/v1/menu/import", "POST"},
+		{"admin", "/api/v1/menu/by-component", "GET"},
+		{"admin", "/api/v1/menu/by-path", "GET"},
+
+		// 审计日志
+		{"admin", "/api/v1/system/audit-log", "GET"},
+		{"admin", "/api/v1/system/audit-log/export", "GET"},
+		{"admin", "/api/v1/system/operation-log", "GET"},
+
+		// 权限策略
+		{"admin", "/api/v1/system/casbin/import", "POST"},
+		{"admin", "/api/v1/system/casbin/export", "GET"},
+		{"admin", "/api/v1/system/casbin/orphaned", "DELETE"},
+
+		// 站内通知
+		{"admin", "/api/v1/system/notification/broadcast", "POST"},
+
+		// 系统动态配置
+		{"admin", "/api/v1/system/config", "GET"},
+		{"admin", "/api/v1/system/config/:key", "PUT"},
+
+		// 请求指标
+		{"admin", "/api/v1/system/metrics/latency", "GET"},
 
 		// 仪表盘
 		{"admin", "/api/v1/dashboard/stats", "GET"},
@@ -361,21 +405,92 @@ func ensureAdminCasbinPolicies() error {
 		{"admin", "/api/v1/tools/code-generator/generate", "POST"},
 		{"admin", "/api/v1/tools/db-inspector/tables", "GET"},
 		{"admin", "/api/v1/tools/db-inspector/table/:tableName", "GET"},
+		{"admin", "/api/v1/tools/db/*", "GET"},
+		{"admin", "/api/v1/tools/db/*", "POST"},
+		{"admin", "/api/v1/tools/db/*", "PUT"},
+		{"admin", "/api/v1/tools/db/*", "DELETE"},
+		{"admin", "/api/v1/tools/db-inspector/override", "GET"},
+		{"admin", "/api/v1/tools/db-inspector/override", "POST"},
+		{"admin", "/api/v1/tools/db-inspector/override/:userId", "DELETE"},
+	}
+
+	return seedCasbinPolicies(global.DB, adminPolicies)
+}
+
+// seedCasbinPolicies 写入尚未写入过的种子策略，并记录为已写入
+// 种子记录为空但已存在 admin 策略时，说明数据库来自记录种子之前的版本，
+// 此时无法区分缺失的策略是未写入还是已被撤销，只记录不添加，并对缺失的策略记录警告
+func seedCasbinPolicies(db *gorm.DB, policies [][]string) error {
+	var seeds []system.SysCasbinSeed
+	if err := db.Find(&seeds).Error; err != nil {
+		global.Logger.Error("Failed to load Casbin seed records", zap.Error(err))
+		return err
+	}
+	seeded := make(map[string]bool, len(seeds))
+	for _, seed := range seeds {
+		seeded[seed.V0+"|"+seed.V1+"|"+seed.V2] = true
+	}
+
+	legacy := false
+	if len(seeds) == 0 {
+		adminPolicies, err := global.CasbinEnforcer.GetFilteredPolicy(0, "admin")
+		if err != nil {
+			global.Logger.Error("Failed to load admin Casbin policies", zap.Error(err))
+			return err
+		}
+		legacy = len(adminPolicies) > 0
+	}
+
+	// 路径使用 keyMatch2 匹配，支持 /api/v1/user/:id/roles 这类包含多个动态段的模式
+	var newSeeds []system.SysCasbinSeed
+	var missingPolicies [][]string
+	for _, policy := range policies {
+		if seeded[strings.Join(policy, "|")] {
+			continue
+		}
+		newSeeds = append(newSeeds, system.SysCasbinSeed{V0: policy[0], V1: policy[1], V2: policy[2], SeededAt: time.Now()})
+
+		exists, err := global.CasbinEnforcer.HasPolicy(policy)
+		if err != nil {
+			global.Logger.Error("Failed to check Casbin policy", zap.Strings("policy", policy), zap.Error(err))
+			return err
+		}
+		if exists {
+			continue
+		}
+		if legacy {
+			global.Logger.Warn("Casbin seed policy is missing on an existing database, not adding it", zap.Strings("policy", policy))
+			continue
+		}
+		missingPolicies = append(missingPolicies, policy)
+	}
+
+	if len(newSeeds) == 0 {
+		global.Logger.Info("All Casbin seed policies already applied", zap.Int("count", len(policies)))
+		return nil
 	}
 
 	// 批量添加策略
-	_, err = global.CasbinEnforcer.AddPolicies(adminPolicies)
-	if err != nil {
-		global.Logger.Error("Failed to add Casbin policies for admin", zap.Error(err))
+	if len(missingPolicies) > 0 {
+		if _, err := global.CasbinEnforcer.AddPolicies(missingPolicies); err != nil {
+			global.Logger.Error("Failed to add Casbin policies for admin", zap.Error(err))
+			return err
+		}
+		InvalidateCasbinCache()
+	}
+
+	if err := db.Create(&newSeeds).Error; err != nil {
+		global.Logger.Error("Failed to record Casbin seed policies", zap.Error(err))
 		return err
 	}
 
-	global.Logger.Info("Casbin policies added for admin role", zap.Int("count", len(adminPolicies)))
+	global.Logger.Info("Casbin seed policies applied", zap.Int("added", len(missingPolicies)), zap.Int("recorded", len(newSeeds)))
 	return nil
 }
 
 // AutoMigrate 执行数据库自动迁移
-func AutoMigrate() error {
+// 模型定义和表结构的校验和与上次迁移记录一致时跳过建表迁移，force 为 true 时始终执行
+func AutoMigrate(force bool) error {
 	if global.DB == nil {
 		global.Logger.Error("Database connection is nil, cannot perform migration")
 		return gorm.ErrInvalidDB
@@ -383,19 +498,215 @@ func AutoMigrate() error {
 
 	global.Logger.Info("Starting database migration...")
 
-	err := RegisterTables(global.DB)
+	// 检查当前版本是否已有执行记录
+	if err := global.DB.AutoMigrate(&system.SysMigrationVersion{}); err != nil {
+		global.Logger.Error("Failed to migrate migration version table", zap.Error(err))
+		return err
+	}
+	checksum := migrationChecksum(migrationModels())
+	var recorded system.SysMigrationVersion
+	err := global.DB.Where("version = ?", migrationVersion).First(&recorded).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		global.Logger.Error("Failed to query migration version", zap.Error(err))
+		return err
+	}
+	alreadyRecorded := err == nil
+
+	// 比较表结构校验和，判断是否需要执行建表迁移
+	schemaChecksum, err := ComputeSchemaChecksum(global.DB, migrationModels())
 	if err != nil {
-		global.Logger.Error("Database migration failed", zap.Error(err))
+		global.Logger.Error("Failed to compute schema checksum", zap.Error(err))
 		return err
 	}
+	skipTables := !force && alreadyRecorded && recorded.SchemaChecksum == schemaChecksum
+
+	// 建表、记录迁移版本和初始化默认数据在同一事务中执行，任一步骤失败都整体回滚，
+	// 避免出现表已创建但缺少默认数据的状态。注意 MySQL 的 DDL 会隐式提交，
+	// 回滚只对数据生效；支持事务性 DDL 的数据库（如 SQLite）会同时回滚建表
+	err = global.DB.Transaction(func(tx *gorm.DB) error {
+		// Gorm AutoMigrate 是幂等的，表结构校验和未变化时跳过以加快启动
+		if skipTables {
+			global.Logger.Info("Schema checksum unchanged, skipping table migration", zap.String("schemaChecksum", schemaChecksum))
+		} else {
+			if err := migrateLegacySessions(tx); err != nil {
+				global.Logger.Error("Failed to migrate legacy sessions", zap.Error(err))
+				return err
+			}
+			if err := RegisterTables(tx); err != nil {
+				global.Logger.Error("Database migration failed", zap.Error(err))
+				return err
+			}
+		}
 
-	global.Logger.Info("Database migration completed successfully")
+		// 记录迁移版本
+		if err := recordMigrationVersion(tx, &recorded, alreadyRecorded, checksum); err != nil {
+			global.Logger.Error("Failed to record migration version", zap.Error(err))
+			return err
+		}
 
-	// 初始化默认数据
-	if err := InitializeData(); err != nil {
-		global.Logger.Error("Failed to initialize data", zap.Error(err))
+		// 迁移后表结构可能已变化，重新计算并记录校验和
+		if !skipTables {
+			newChecksum, err := ComputeSchemaChecksum(tx, migrationModels())
+			if err != nil {
+				global.Logger.Error("Failed to compute schema checksum", zap.Error(err))
+				return err
+			}
+			if err := tx.Model(&system.SysMigrationVersion{}).
+				Where("version = ?", migrationVersion).
+				Update("schema_checksum", newChecksum).Error; err != nil {
+				global.Logger.Error("Failed to record schema checksum", zap.Error(err))
+				return err
+			}
+		}
+
+		global.Logger.Info("Database migration completed successfully")
+
+		// 初始化默认数据
+		if err := InitializeData(tx); err != nil {
+			global.Logger.Error("Failed to initialize data", zap.Error(err))
+			return err
+		}
+		return nil
+	})
+	if err != nil {
 		return err
 	}
 
+	// Casbin 适配器使用独立连接，在事务提交后再补齐 admin 角色的策略
+	return ensureAdminCasbinPolicies()
+}
+
+// migrateLegacySessions 清理旧版会话表
+// 旧版以明文保存令牌且没有会话ID，这些会话无法按会话撤销：删除记录并移除明文令牌列。
+// 必须在 RegisterTables 为 session_id 建立唯一索引之前执行
+func migrateLegacySessions(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable(&system.SysUserSession{}) || !migrator.HasColumn(&system.SysUserSession{}, "access_token") {
+		return nil
+	}
+
+	global.Logger.Warn("Removing legacy sessions that stored plain-text tokens")
+	if err := db.Where("1 = 1").Delete(&system.SysUserSession{}).Error; err != nil {
+		return err
+	}
+	for _, column := range []string{"access_token", "refresh_token"} {
+		if err := migrator.DropColumn(&system.SysUserSession{}, column); err != nil {
+			return err
+		}
+	}
 	return nil
 }
+
+// recordMigrationVersion 写入迁移版本记录
+// 版本已记录时不重复插入；校验和不一致说明模型列表变更但版本号未更新，记录警告并更新校验和
+func recordMigrationVersion(db *gorm.DB, recorded *system.SysMigrationVersion, alreadyRecorded bool, checksum string) error {
+	if !alreadyRecorded {
+		global.Logger.Info("Recording migration version", zap.String("version", migrationVersion))
+		return db.Create(&system.SysMigrationVersion{
+			Version:    migrationVersion,
+			ExecutedAt: time.Now(),
+			Checksum:   checksum,
+		}).Error
+	}
+
+	if recorded.Checksum == checksum {
+		global.Logger.Info("Migration version already recorded", zap.String("version", migrationVersion))
+		return nil
+	}
+
+	global.Logger.Warn("Migration models changed without a version bump",
+		zap.String("version", migrationVersion),
+		zap.String("recordedChecksum", recorded.Checksum),
+		zap.String("currentChecksum", checksum),
+	)
+	return db.Model(recorded).Updates(map[string]interface{}{
+		"checksum":    checksum,
+		"executed_at": time.Now(),
+	}).Error
+}
+
+// migrationChecksum 计算迁移模型结构体名称的SHA256
+func migrationChecksum(models []interface{}) string {
+	names := make([]string, 0, len(models))
+	for _, model := range models {
+		names = append(names, reflect.TypeOf(model).Elem().Name())
+	}
+	sum := sha256.Sum256([]byte(strings.Join(names, ",")))
+	return hex.EncodeToString(sum[:])
+}
+
+// schemaColumn 表结构中的一列，用于计算表结构校验和
+type schemaColumn struct {
+	TableName  string  `gorm:"column:table_name"`
+	ColumnName string  `gorm:"column:column_name"`
+	ColumnType string  `gorm:"column:column_type"`
+	IsNullable string  `gorm:"column:is_nullable"`
+	Default    *string `gorm:"column:column_default"`
+	ColumnKey  string  `gorm:"column:column_key"`
+	Extra      string  `gorm:"column:extra"`
+}
+
+// ComputeSchemaChecksum 计算迁移模型的表结构校验和（SHA256）
+// 同时包含模型定义（字段、类型和 gorm 标签）和数据库中的实际列定义，
+// 因此模型变更和表结构被手动修改（含删表）都会导致校验和变化。
+// MySQL 通过一次 INFORMATION_SCHEMA.COLUMNS 查询读取全部表的列，其他数据库使用 Migrator().ColumnTypes 逐表读取
+func ComputeSchemaChecksum(db *gorm.DB, models []interface{}) (string, error) {
+	var lines []string
+	tableNames := make([]string, 0, len(models))
+
+	// 模型定义
+	for _, model := range models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return "", fmt.Errorf("failed to parse model: %w", err)
+		}
+		tableNames = append(tableNames, stmt.Schema.Table)
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName == "" {
+				continue
+			}
+			lines = append(lines, fmt.Sprintf("model|%s|%s|%s|%s",
+				stmt.Schema.Table, field.DBName, field.FieldType.String(), field.Tag.Get("gorm")))
+		}
+	}
+
+	// 数据库中的实际列定义
+	if db.Dialector.Name() == "mysql" {
+		var columns []schemaColumn
+		query := `SELECT TABLE_NAME AS table_name, COLUMN_NAME AS column_name, COLUMN_TYPE AS column_type,
+		                 IS_NULLABLE AS is_nullable, COLUMN_DEFAULT AS column_default, COLUMN_KEY AS column_key, EXTRA AS extra
+		          FROM INFORMATION_SCHEMA.COLUMNS
+		          WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME IN ?
+		          ORDER BY TABLE_NAME, ORDINAL_POSITION`
+		if err := db.Raw(query, tableNames).Scan(&columns).Error; err != nil {
+			return "", fmt.Errorf("failed to query table columns: %w", err)
+		}
+		for _, col := range columns {
+			def := "NULL"
+			if col.Default != nil {
+				def = *col.Default
+			}
+			lines = append(lines, fmt.Sprintf("table|%s|%s|%s|%s|%s|%s|%s",
+				col.TableName, col.ColumnName, col.ColumnType, col.IsNullable, def, col.ColumnKey, col.Extra))
+		}
+	} else {
+		for i, model := range models {
+			if !db.Migrator().HasTable(model) {
+				continue
+			}
+			columnTypes, err := db.Migrator().ColumnTypes(model)
+			if err != nil {
+				return "", fmt.Errorf("failed to get columns of %s: %w", tableNames[i], err)
+			}
+			for _, col := range columnTypes {
+				nullable, _ := col.Nullable()
+				def, _ := col.DefaultValue()
+				lines = append(lines, fmt.Sprintf("table|%s|%s|%s|%t|%s",
+					tableNames[i], col.Name(), col.DatabaseTypeName(), nullable, def))
+			}
+		}
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+	return hex.EncodeToString(sum[:]), nil
+}
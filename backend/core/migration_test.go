@@ -0,0 +1,171 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"k-admin-system/global"
+	"k-admin-system/model/system"
+
+	"gorm.io/gorm"
+)
+
+// hasPolicy 检查 enforcer 中是否存在指定策略
+func hasPolicy(t *testing.T, policy ...string) bool {
+	t.Helper()
+	exists, err := global.CasbinEnforcer.HasPolicy(policy)
+	if err != nil {
+		t.Fatalf("HasPolicy() error = %v", err)
+	}
+	return exists
+}
+
+func TestEnsureAdminCasbinPolicies_DoesNotRestoreRevokedPolicies(t *testing.T) {
+	db := setupTestDB(t)
+	setupTestCasbin(t, db)
+
+	if err := ensureAdminCasbinPolicies(); err != nil {
+		t.Fatalf("ensureAdminCasbinPolicies() error = %v", err)
+	}
+	if !hasPolicy(t, "admin", "/api/v1/user/:id", "DELETE") {
+		t.Fatal("fresh install is missing the admin seed policies")
+	}
+	var seeds int64
+	db.Model(&system.SysCasbinSeed{}).Count(&seeds)
+	if seeds == 0 {
+		t.Fatal("seed policies were not recorded")
+	}
+
+	// 管理员撤销策略后重启
+	if _, err := global.CasbinEnforcer.RemovePolicy("admin", "/api/v1/user/:id", "DELETE"); err != nil {
+		t.Fatalf("RemovePolicy() error = %v", err)
+	}
+	if err := ensureAdminCasbinPolicies(); err != nil {
+		t.Fatalf("ensureAdminCasbinPolicies() error = %v", err)
+	}
+	if hasPolicy(t, "admin", "/api/v1/user/:id", "DELETE") {
+		t.Error("revoked policy was restored on restart")
+	}
+}
+
+func TestSeedCasbinPolicies_AppliesNewSeeds(t *testing.T) {
+	setupTestCasbin(t, setupTestDB(t))
+
+	if err := seedCasbinPolicies(global.DB, [][]string{{"admin", "/api/v1/user/list", "GET"}}); err != nil {
+		t.Fatalf("seedCasbinPolicies() error = %v", err)
+	}
+
+	// 新版本新增的种子策略应用到已有数据库
+	policies := [][]string{{"admin", "/api/v1/user/list", "GET"}, {"admin", "/api/v1/config", "GET"}}
+	if err := seedCasbinPolicies(global.DB, policies); err != nil {
+		t.Fatalf("seedCasbinPolicies() error = %v", err)
+	}
+	if !hasPolicy(t, "admin", "/api/v1/config", "GET") {
+		t.Error("new seed policy was not added to an existing database")
+	}
+}
+
+func TestSeedCasbinPolicies_LegacyDatabase(t *testing.T) {
+	db := setupTestDB(t)
+	setupTestCasbin(t, db)
+
+	// 记录种子之前的版本写入的策略，其中一条已被撤销
+	if _, err := global.CasbinEnforcer.AddPolicy("admin", "/api/v1/user/list", "GET"); err != nil {
+		t.Fatalf("AddPolicy() error = %v", err)
+	}
+
+	policies := [][]string{{"admin", "/api/v1/user/list", "GET"}, {"admin", "/api/v1/user", "POST"}}
+	if err := seedCasbinPolicies(db, policies); err != nil {
+		t.Fatalf("seedCasbinPolicies() error = %v", err)
+	}
+	if hasPolicy(t, "admin", "/api/v1/user", "POST") {
+		t.Error("policy missing from a legacy database was re-added")
+	}
+	var seeds int64
+	db.Model(&system.SysCasbinSeed{}).Count(&seeds)
+	if seeds != int64(len(policies)) {
+		t.Errorf("recorded %d seeds, want %d", seeds, len(policies))
+	}
+}
+
+func TestMigrationVersion_MatchesModels(t *testing.T) {
+	// 修改 migrationModels 时必须同时更新 migrationVersion 和此处的校验和
+	const want = "329f5a0ee3933462e2dfba939ac54c635a0bd2a38f87ba57347e92b66b8715de"
+	if got := migrationChecksum(migrationModels()); got != want {
+		t.Errorf("migration models changed (checksum %s); bump migrationVersion (currently %q) and update this test", got, migrationVersion)
+	}
+}
+
+func TestAutoMigrate_RollsBackWhenInitializeDataFails(t *testing.T) {
+	db := setupTestDB(t)
+
+	// 创建管理员用户时失败
+	failUsers := func(tx *gorm.DB) {
+		if tx.Statement.Table == "sys_users" {
+			_ = tx.AddError(errors.New("simulated failure"))
+		}
+	}
+	if err := db.Callback().Create().Before("gorm:create").Register("test:fail_users", failUsers); err != nil {
+		t.Fatalf("failed to register callback: %v", err)
+	}
+
+	if err := AutoMigrate(false); err == nil {
+		t.Fatal("AutoMigrate() succeeded although InitializeData failed")
+	}
+	for _, model := range []interface{}{&system.SysRole{}, &system.SysUser{}, &system.SysMenu{}} {
+		if db.Migrator().HasTable(model) {
+			t.Errorf("table for %T was not rolled back", model)
+		}
+	}
+	var versions int64
+	db.Model(&system.SysMigrationVersion{}).Count(&versions)
+	if versions != 0 {
+		t.Errorf("migration version recorded for a failed migration")
+	}
+
+	// 故障消除后可以重新迁移
+	if err := db.Callback().Create().Remove("test:fail_users"); err != nil {
+		t.Fatalf("failed to remove callback: %v", err)
+	}
+	if err := AutoMigrate(false); err != nil {
+		t.Fatalf("AutoMigrate() error = %v", err)
+	}
+	var admin system.SysUser
+	if err := db.Where("username = ?", "admin").First(&admin).Error; err != nil {
+		t.Errorf("admin user was not created after retry: %v", err)
+	}
+}
+
+func TestAutoMigrate_SkipsUnchangedSchema(t *testing.T) {
+	db := setupTestDB(t)
+
+	if err := AutoMigrate(false); err != nil {
+		t.Fatalf("AutoMigrate() error = %v", err)
+	}
+
+	// 删除索引不改变列定义；表结构校验和不变时第二次迁移跳过建表，索引不会被重建
+	const index = "idx_sys_users_deleted_at"
+	if err := db.Migrator().DropIndex(&system.SysUser{}, index); err != nil {
+		t.Fatalf("DropIndex() error = %v", err)
+	}
+	if err := AutoMigrate(false); err != nil {
+		t.Fatalf("AutoMigrate() error = %v", err)
+	}
+	if db.Migrator().HasIndex(&system.SysUser{}, index) {
+		t.Error("second AutoMigrate() ran table migration although the schema was unchanged")
+	}
+
+	var versions int64
+	db.Model(&system.SysMigrationVersion{}).Count(&versions)
+	if versions != 1 {
+		t.Errorf("migration version rows = %d, want 1", versions)
+	}
+
+	// force 为 true 时始终执行建表迁移
+	if err := AutoMigrate(true); err != nil {
+		t.Fatalf("AutoMigrate(true) error = %v", err)
+	}
+	if !db.Migrator().HasIndex(&system.SysUser{}, index) {
+		t.Error("forced AutoMigrate() did not run table migration")
+	}
+}
@@ -4,25 +4,35 @@ import (
 	"context"
 	"fmt"
 	"k-admin-system/global"
+	"net"
+	"time"
 
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
 
+// redisDialer 建立Redis连接使用的拨号函数，为空时使用 go-redis 的默认拨号
+// 测试中替换为模拟连接失败的拨号函数
+var redisDialer func(ctx context.Context, network, addr string) (net.Conn, error)
+
 // InitRedis 初始化Redis连接
+// Redis尚未就绪时按与 InitDB 相同的指数退避策略重试，最多尝试 redis.max_retries 次
 func InitRedis() (*redis.Client, error) {
 	cfg := global.Config.Redis
 
-	client := redis.NewClient(&redis.Options{
-		Addr:     fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
-		Password: cfg.Password,
-		DB:       cfg.DB,
+	client, err := connectWithRetry("Redis", cfg.MaxRetries, global.Logger, func() (*redis.Client, error) {
+		return openRedis(&redis.Options{
+			Addr:        fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+			Password:    cfg.Password,
+			DB:          cfg.DB,
+			DialTimeout: time.Duration(cfg.DialTimeout) * time.Second,
+			Dialer:      redisDialer,
+			// 启动时的重试由 connectWithRetry 负责，每次尝试只拨号一次，使尝试次数与 redis.max_retries 一致
+			DialerRetries: 1,
+		})
 	})
-
-	// 测试连接
-	ctx := context.Background()
-	if err := client.Ping(ctx).Err(); err != nil {
-		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+	if err != nil {
+		return nil, err
 	}
 
 	global.Logger.Info("Redis connection established",
@@ -33,3 +43,16 @@ func InitRedis() (*redis.Client, error) {
 
 	return client, nil
 }
+
+// openRedis 创建Redis客户端并通过Ping验证连接，失败时关闭客户端
+func openRedis(opts *redis.Options) (*redis.Client, error) {
+	client := redis.NewClient(opts)
+
+	// 测试连接
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+
+	return client, nil
+}
@@ -0,0 +1,84 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"k-admin-system/config"
+	"k-admin-system/global"
+
+	"github.com/alicebob/miniredis/v2"
+	"go.uber.org/zap"
+)
+
+func TestInitRedis_RetryOnFailure(t *testing.T) {
+	mr := miniredis.RunT(t)
+	host, port, err := net.SplitHostPort(mr.Addr())
+	if err != nil {
+		t.Fatalf("failed to split address: %v", err)
+	}
+	portNumber, err := net.LookupPort("tcp", port)
+	if err != nil {
+		t.Fatalf("failed to parse port: %v", err)
+	}
+
+	// 前两次拨号失败，第三次连接到 miniredis
+	attempts := 0
+	var dialer net.Dialer
+	prevDialer, prevBackoff := redisDialer, retryInitialBackoff
+	prevConfig, prevLogger := global.Config, global.Logger
+	redisDialer = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		attempts++
+		if attempts < 3 {
+			return nil, errors.New("connection refused")
+		}
+		return dialer.DialContext(ctx, network, addr)
+	}
+	retryInitialBackoff = 10 * time.Millisecond
+	global.Config = &config.Config{Redis: config.RedisConfig{Host: host, Port: portNumber, MaxRetries: 5, DialTimeout: 1}}
+	global.Logger = zap.NewNop()
+	t.Cleanup(func() {
+		redisDialer, retryInitialBackoff = prevDialer, prevBackoff
+		global.Config, global.Logger = prevConfig, prevLogger
+	})
+
+	client, err := InitRedis()
+	if err != nil {
+		t.Fatalf("InitRedis() error = %v", err)
+	}
+	defer client.Close()
+
+	if attempts != 3 {
+		t.Errorf("dial attempts = %d, want 3", attempts)
+	}
+	if err := client.Set(context.Background(), "k", "v", 0).Err(); err != nil {
+		t.Errorf("client is not usable: %v", err)
+	}
+}
+
+func TestInitRedis_GivesUpAfterMaxRetries(t *testing.T) {
+	attempts := 0
+	prevDialer, prevBackoff := redisDialer, retryInitialBackoff
+	prevConfig, prevLogger := global.Config, global.Logger
+	redisDialer = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		attempts++
+		return nil, errors.New("connection refused")
+	}
+	retryInitialBackoff = time.Millisecond
+	global.Config = &config.Config{Redis: config.RedisConfig{Host: "127.0.0.1", Port: 6379, MaxRetries: 2, DialTimeout: 1}}
+	global.Logger = zap.NewNop()
+	t.Cleanup(func() {
+		redisDialer, retryInitialBackoff = prevDialer, prevBackoff
+		global.Config, global.Logger = prevConfig, prevLogger
+	})
+
+	if _, err := InitRedis(); err == nil {
+		t.Fatal("InitRedis() succeeded although every dial failed")
+	}
+	if attempts != 2 {
+		t.Errorf("InitRedis() made %d connection attempts, want 2", attempts)
+	}
+}
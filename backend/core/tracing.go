@@ -0,0 +1,51 @@
+package core
+
+import (
+	"context"
+	"fmt"
+
+	"k-admin-system/config"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
+)
+
+// InitTracer initializes the OpenTelemetry tracer provider with an OTLP/HTTP exporter
+// Returns a shutdown function that flushes pending spans. When tracing.otlp_endpoint
+// is empty tracing stays disabled and the returned shutdown function is a no-op
+func InitTracer(cfg *config.Config, log *zap.Logger) (func(context.Context) error, error) {
+	if cfg.Tracing.OTLPEndpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(context.Background(),
+		otlptracehttp.WithEndpointURL(cfg.Tracing.OTLPEndpoint),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", cfg.Tracing.ServiceName),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	log.Info("Tracing enabled",
+		zap.String("otlp_endpoint", cfg.Tracing.OTLPEndpoint),
+		zap.String("service_name", cfg.Tracing.ServiceName),
+	)
+
+	return provider.Shutdown, nil
+}
@@ -0,0 +1,26 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"k-admin-system/utils"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// InitValidator registers custom validation tags on Gin's default validator
+func InitValidator() error {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return errors.New("unexpected gin validator engine")
+	}
+
+	// e164 overrides the built-in tag with the stricter project-wide phone format
+	if err := v.RegisterValidation("e164", utils.ValidateE164); err != nil {
+		return fmt.Errorf("failed to register e164 validator: %w", err)
+	}
+
+	return nil
+}
@@ -55,10 +55,10 @@ func InitLogger(cfg *config.Config) (*zap.Logger, error) {
 		LocalTime:  true,                  // use local time for filenames
 	}
 
-	// Determine output destinations based on server mode
+	// Determine output destinations based on server mode and console_output
 	var core zapcore.Core
-	if cfg.Server.Mode == "debug" || cfg.Server.Mode == "test" {
-		// Development mode: output to both console and file
+	if cfg.Server.Mode == "debug" || cfg.Server.Mode == "test" || cfg.Logger.ConsoleOutput {
+		// Development mode or console output enabled: output to both console and file
 		consoleEncoder := zapcore.NewConsoleEncoder(encoderConfig)
 		consoleCore := zapcore.NewCore(
 			consoleEncoder,
@@ -72,7 +72,7 @@ func InitLogger(cfg *config.Config) (*zap.Logger, error) {
 		)
 		core = zapcore.NewTee(consoleCore, fileCore)
 	} else {
-		// Production mode: output to file only
+		// Production mode without console output: output to file only
 		core = zapcore.NewCore(
 			encoder,
 			zapcore.AddSync(fileWriter),
@@ -0,0 +1,73 @@
+package core
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k-admin-system/config"
+)
+
+// captureStdout 将 os.Stdout 重定向到管道，返回读取已写入内容的函数
+func captureStdout(t *testing.T) func() string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to create pipe: %v", err)
+	}
+	prevStdout := os.Stdout
+	os.Stdout = w
+	t.Cleanup(func() { os.Stdout = prevStdout })
+
+	return func() string {
+		os.Stdout = prevStdout
+		_ = w.Close()
+		out, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("failed to read stdout: %v", err)
+		}
+		return string(out)
+	}
+}
+
+func TestInitLogger_ConsoleOutput(t *testing.T) {
+	tests := []struct {
+		name          string
+		consoleOutput bool
+	}{
+		{"console output enabled", true},
+		{"console output disabled", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logPath := filepath.Join(t.TempDir(), "server.log")
+			cfg := &config.Config{
+				Server: config.ServerConfig{Mode: "release"},
+				Logger: config.LoggerConfig{Level: "info", Path: logPath, MaxSize: 1, ConsoleOutput: tt.consoleOutput},
+			}
+
+			readStdout := captureStdout(t)
+			logger, err := InitLogger(cfg)
+			if err != nil {
+				t.Fatalf("InitLogger() error = %v", err)
+			}
+			logger.Info("console output probe")
+			_ = logger.Sync()
+			stdout := readStdout()
+
+			if got := strings.Contains(stdout, "console output probe"); got != tt.consoleOutput {
+				t.Errorf("stdout contains the message = %v, want %v (stdout %q)", got, tt.consoleOutput, stdout)
+			}
+			// 无论是否输出到控制台，日志文件都会写入
+			content, err := os.ReadFile(logPath)
+			if err != nil {
+				t.Fatalf("failed to read log file: %v", err)
+			}
+			if !strings.Contains(string(content), `"msg":"console output probe"`) {
+				t.Errorf("log file = %q, want the JSON entry", content)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"k-admin-system/config"
 
 	"github.com/casbin/casbin/v3"
+	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
@@ -25,4 +26,7 @@ var (
 
 	// CasbinEnforcer holds the global Casbin enforcer instance
 	CasbinEnforcer *casbin.Enforcer
+
+	// Routes holds the registered Gin routes, set once route registration is complete
+	Routes gin.RoutesInfo
 )
@@ -21,8 +21,10 @@ package main
 // @description JWT token format: Bearer {token}
 
 import (
+	"context"
 	"flag"
 	"log"
+	"time"
 
 	systemApi "k-admin-system/api/v1/system"
 	"k-admin-system/config"
@@ -36,12 +38,26 @@ import (
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"go.uber.org/zap"
 )
 
+// Build metadata, overridable at build time:
+//
+//	go build -ldflags "-X main.GitCommit=$(git rev-parse HEAD) -X main.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+var (
+	Version   = "1.0.0"
+	GitCommit = ""
+	BuildTime = ""
+)
+
+// startTime records when the process started, used to report uptime
+var startTime = time.Now()
+
 func main() {
 	// Parse command line flags
-	configPath := flag.String("config", "", "Path to config file (YAML or JSON)")
+	configPath := flag.String("config", "", "Path to config file (YAML, JSON or TOML)")
+	forceMigrate := flag.Bool("force-migrate", false, "Run table migrations even if the schema checksum is unchanged")
 	flag.Parse()
 
 	// Load configuration
@@ -86,34 +102,73 @@ func main() {
 	global.CasbinEnforcer = casbinEnforcer
 
 	// Run database migrations
-	if err := core.AutoMigrate(); err != nil {
+	if err := core.AutoMigrate(*forceMigrate); err != nil {
 		logger.Fatal("Failed to run database migrations", zap.Error(err))
 	}
 
+	// Register custom request validators
+	if err := core.InitValidator(); err != nil {
+		logger.Fatal("Failed to initialize validator", zap.Error(err))
+	}
+
+	// Initialize tracing (no-op when tracing.otlp_endpoint is empty)
+	shutdownTracer, err := core.InitTracer(cfg, logger)
+	if err != nil {
+		logger.Fatal("Failed to initialize tracing", zap.Error(err))
+	}
+	defer shutdownTracer(context.Background())
+
 	// Set Gin mode based on configuration
 	gin.SetMode(cfg.Server.Mode)
 
 	// Initialize Gin router without default middleware
-	r := gin.New()
+	r, err := newEngine(cfg)
+	if err != nil {
+		logger.Fatal("Invalid trusted proxies", zap.Error(err))
+	}
 
 	// Configure middleware chain in correct order
-	// Order: Recovery → CORS → RateLimit → Logger → JWT → Casbin
+	// Order: RequestSizeLimit → Recovery → SecureHeaders → CORS → RateLimit → APIVersion → Logger → AuditLog → JWT → Casbin
+
+	// 1. Request size limit middleware (reject oversized bodies before any other work)
+	r.Use(middleware.RequestSizeLimit(cfg.Server.MaxRequestBodyBytes))
 
-	// 1. Recovery middleware (must be first to catch all panics)
+	// 2. Recovery middleware (catch all panics from the rest of the chain)
 	r.Use(middleware.Recovery())
 
-	// 2. CORS middleware (handle cross-origin requests early)
+	// 3. Secure headers middleware (set security headers on every response)
+	r.Use(middleware.SecureHeaders(cfg.Security))
+
+	// 4. CORS middleware (handle cross-origin requests early)
 	r.Use(middleware.CORS(cfg.CORS))
 
-	// 3. Rate limiting middleware (prevent abuse before processing)
+	// 5. Rate limiting middleware (prevent abuse before processing)
 	r.Use(middleware.RateLimit(cfg.RateLimit))
 
-	// 4. Logger middleware (log all requests)
+	// 6. API version middleware (resolve the requested version from the Accept header)
+	r.Use(middleware.APIVersion())
+
+	// 7. Logger middleware (log all requests, with trace IDs when tracing is enabled)
+	if cfg.Tracing.OTLPEndpoint != "" {
+		r.Use(otelgin.Middleware(cfg.Tracing.ServiceName))
+	}
 	r.Use(middleware.Logger())
 
+	// 8. Audit log middleware (persist API requests for later review)
+	r.Use(middleware.AuditLog())
+
 	// Health check endpoint (excluded from JWT and Casbin)
 	r.GET("/api/v1/health", systemApi.HealthCheck)
 
+	// Server info endpoint (public, excluded from JWT and Casbin; v2 response selected via the Accept header)
+	sysInfoApi := &systemApi.SysInfoApi{
+		Version:   Version,
+		GitCommit: GitCommit,
+		BuildTime: BuildTime,
+		StartTime: startTime,
+	}
+	r.GET("/api/v1/system/info", middleware.VersionedHandler(sysInfoApi.GetSysInfo, apiV2(sysInfoApi.GetSysInfoV2)))
+
 	// API v1 routes
 	apiV1 := r.Group("/api/v1")
 	{
@@ -122,6 +177,12 @@ func main() {
 		systemRouter.InitRoleRouter(apiV1)
 		systemRouter.InitMenuRouter(apiV1)
 		systemRouter.InitDashboardRouter(apiV1)
+		systemRouter.InitAuditLogRouter(apiV1)
+		systemRouter.InitOperationLogRouter(apiV1)
+		systemRouter.InitCasbinRouter(apiV1)
+		systemRouter.InitNotificationRouter(apiV1)
+		systemRouter.InitSysConfigRouter(apiV1)
+		systemRouter.InitMetricsRouter(apiV1)
 
 		// Tools module routes
 		toolsGroup := apiV1.Group("/tools")
@@ -132,9 +193,28 @@ func main() {
 	// Swagger documentation route
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	// Keep the route table for describing Casbin policies
+	global.Routes = r.Routes()
+
 	// Start server
 	logger.Info("Server starting", zap.String("port", cfg.Server.Port))
 	if err := r.Run(cfg.Server.Port); err != nil {
 		logger.Fatal("Failed to start server", zap.Error(err))
 	}
 }
+
+// apiV2 returns the per-version overrides for a route that has a v2 handler.
+// v2 shares the /api/v1 prefix and is selected with Accept: application/vnd.kadmin.v2+json
+func apiV2(handler gin.HandlerFunc) map[int]gin.HandlerFunc {
+	return map[int]gin.HandlerFunc{2: handler}
+}
+
+// newEngine creates the Gin engine without default middleware.
+// X-Forwarded-For is only trusted from server.trusted_proxies so c.ClientIP() is reliable
+func newEngine(cfg *config.Config) (*gin.Engine, error) {
+	r := gin.New()
+	if err := r.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	systemApi "k-admin-system/api/v1/system"
+	"k-admin-system/config"
+	"k-admin-system/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestNewEngine_TrustedProxies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.Config{Server: config.ServerConfig{TrustedProxies: []string{"10.0.0.1"}}}
+	r, err := newEngine(cfg)
+	if err != nil {
+		t.Fatalf("newEngine() error = %v", err)
+	}
+	r.GET("/ip", func(c *gin.Context) {
+		c.String(http.StatusOK, c.ClientIP())
+	})
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		want       string
+	}{
+		{"trusted proxy", "10.0.0.1:12345", "203.0.113.5"},
+		{"untrusted client", "192.0.2.1:12345", "192.0.2.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+			req.RemoteAddr = tt.remoteAddr
+			req.Header.Set("X-Forwarded-For", "203.0.113.5")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if got := w.Body.String(); got != tt.want {
+				t.Errorf("ClientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewEngine_RejectsInvalidProxy(t *testing.T) {
+	cfg := &config.Config{Server: config.ServerConfig{TrustedProxies: []string{"not-an-ip"}}}
+	if _, err := newEngine(cfg); err == nil {
+		t.Error("newEngine() accepted an invalid trusted proxy")
+	}
+}
+
+func TestSysInfoRoute_APIVersion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	sysInfoApi := &systemApi.SysInfoApi{Version: "1.2.3", GitCommit: "abc123", StartTime: time.Now()}
+	r := gin.New()
+	r.Use(middleware.APIVersion())
+	r.GET("/api/v1/system/info", middleware.VersionedHandler(sysInfoApi.GetSysInfo, apiV2(sysInfoApi.GetSysInfoV2)))
+
+	get := func(accept string) map[string]interface{} {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/system/info", nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		var resp struct {
+			Data map[string]interface{} `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return resp.Data
+	}
+
+	for _, accept := range []string{"", "application/json", "application/vnd.kadmin.v1+json", "application/vnd.kadmin.v3+json"} {
+		data := get(accept)
+		if data["version"] != "1.2.3" || data["build"] != nil {
+			t.Errorf("Accept %q: got %v, want the v1 response", accept, data)
+		}
+	}
+
+	data := get("application/vnd.kadmin.v2+json")
+	build, ok := data["build"].(map[string]interface{})
+	if !ok || build["version"] != "1.2.3" || build["gitCommit"] != "abc123" {
+		t.Errorf("Accept v2: build = %v, want version 1.2.3 and commit abc123", data["build"])
+	}
+	if _, ok := data["runtime"].(map[string]interface{}); !ok {
+		t.Errorf("Accept v2: runtime = %v, want an object", data["runtime"])
+	}
+	if _, ok := data["version"]; ok {
+		t.Errorf("Accept v2: got top-level version field, want the v2 response")
+	}
+}
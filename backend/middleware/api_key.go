@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"k-admin-system/model/common"
+	systemService "k-admin-system/service/system"
+
+	"github.com/gin-gonic/gin"
+)
+
+// apiKeyAuthenticatedKey 上下文标记：请求已通过API密钥认证，JWTAuth 会跳过令牌校验
+const apiKeyAuthenticatedKey = "apiKeyAuthenticated"
+
+// APIKeyAuth API密钥认证中间件
+// 请求携带 X-API-Key 请求头时校验密钥并写入与JWT认证相同的用户上下文；
+// 未携带时直接放行，由随后的 JWTAuth 完成认证。需放在 JWTAuth 之前使用
+func APIKeyAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader("X-API-Key")
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		apiKeyService := systemService.APIKeyService{}
+		user, err := apiKeyService.AuthenticateAPIKey(key)
+		if err != nil {
+			common.FailWithCode(c, 401, "API密钥无效")
+			c.Abort()
+			return
+		}
+
+		// 将用户信息存入上下文
+		c.Set("userId", user.ID)
+		c.Set("username", user.Username)
+		c.Set("roleId", user.RoleID)
+		c.Set(apiKeyAuthenticatedKey, true)
+
+		c.Next()
+	}
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"regexp"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultAPIVersion 未通过 Accept 头指定版本时使用的API版本
+const DefaultAPIVersion = 1
+
+// apiVersionPattern 匹配 Accept 头中的 vnd.kadmin.v<N>+json 媒体类型
+var apiVersionPattern = regexp.MustCompile(`vnd\.kadmin\.v(\d+)\+json`)
+
+// APIVersion API版本协商中间件
+// 从 Accept 头（如 application/vnd.kadmin.v2+json）解析请求的API版本，写入上下文键 "api_version"（int，默认1）
+// 所有版本共用 /api/v1 路由前缀，需要按版本区分行为的接口通过 VersionedHandler 注册
+//
+// 使用示例:
+//
+//	router.Use(middleware.APIVersion())
+//	group.GET("/list", middleware.VersionedHandler(api.GetListV1, map[int]gin.HandlerFunc{
+//		2: api.GetListV2,
+//	}))
+func APIVersion() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		version := DefaultAPIVersion
+		if match := apiVersionPattern.FindStringSubmatch(c.GetHeader("Accept")); match != nil {
+			if v, err := strconv.Atoi(match[1]); err == nil && v > 0 {
+				version = v
+			}
+		}
+
+		c.Set("api_version", version)
+		c.Next()
+	}
+}
+
+// VersionedHandler 按请求的API版本分发处理函数
+// overrides 中没有对应版本时使用 defaultHandler，因此只需为有变化的版本提供处理函数
+func VersionedHandler(defaultHandler gin.HandlerFunc, overrides map[int]gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if handler, ok := overrides[c.GetInt("api_version")]; ok {
+			handler(c)
+			return
+		}
+		defaultHandler(c)
+	}
+}
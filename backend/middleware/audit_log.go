@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"strings"
+	"time"
+
+	"k-admin-system/global"
+	"k-admin-system/model/system"
+	systemService "k-admin-system/service/system"
+	"k-admin-system/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// auditLogSkipPaths 不记录审计日志的路径
+var auditLogSkipPaths = map[string]bool{
+	"/api/v1/health": true,
+}
+
+// auditLogQueue 审计日志的异步写入队列，队列满时丢弃日志并记录警告
+var auditLogQueue = utils.NewAsyncQueue("audit_log", 4096, 4)
+
+// AuditLog 审计日志中间件
+// 在请求处理完成后记录操作者、请求信息和响应状态，经有界队列异步写入数据库，不影响响应时间
+// 只记录 /api 下的请求，跳过健康检查和CORS预检请求
+//
+// 使用示例:
+//
+//	router.Use(middleware.AuditLog())
+func AuditLog() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		path := c.Request.URL.Path
+		if c.Request.Method == "OPTIONS" || !strings.HasPrefix(path, "/api/") || auditLogSkipPaths[path] {
+			c.Next()
+			return
+		}
+
+		startTime := time.Now()
+
+		// 处理请求
+		c.Next()
+
+		auditLog := &system.SysAuditLog{
+			Method:     c.Request.Method,
+			Path:       path,
+			Query:      utils.TruncateRunes(c.Request.URL.RawQuery, 1024),
+			StatusCode: c.Writer.Status(),
+			Latency:    time.Since(startTime).Milliseconds(),
+			ClientIP:   c.ClientIP(),
+			UserAgent:  utils.TruncateRunes(c.Request.UserAgent(), 255),
+		}
+
+		// JWT中间件在认证通过后设置用户信息
+		if userID, exists := c.Get("userId"); exists {
+			if id, ok := userID.(uint); ok {
+				auditLog.UserID = id
+			}
+		}
+		if username, exists := c.Get("username"); exists {
+			if name, ok := username.(string); ok {
+				auditLog.Username = name
+			}
+		}
+
+		if global.DB == nil {
+			return
+		}
+
+		auditLogQueue.Submit(func() {
+			auditLogService := systemService.AuditLogService{}
+			if err := auditLogService.CreateLog(auditLog); err != nil && global.Logger != nil {
+				global.Logger.Warn("Failed to write audit log", zap.Error(err))
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"k-admin-system/config"
+	"k-admin-system/global"
+	"k-admin-system/model/system"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAuditLog_WritesThroughQueue(t *testing.T) {
+	setupTestEnv(t, &config.Config{})
+
+	r := gin.New()
+	r.Use(AuditLog())
+	r.GET("/api/v1/ping", func(c *gin.Context) {
+		c.Set("userId", uint(7))
+		c.Set("username", "alice")
+		c.Status(http.StatusNoContent)
+	})
+	r.GET("/api/v1/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/health", nil))
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/ping?x=1", nil))
+
+	// 日志由队列异步写入，等待写入完成
+	var logs []system.SysAuditLog
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if err := global.DB.Find(&logs).Error; err != nil {
+			t.Fatalf("failed to query audit logs: %v", err)
+		}
+		if len(logs) > 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if len(logs) != 1 {
+		t.Fatalf("audit logs = %d, want 1 (health check is skipped)", len(logs))
+	}
+	got := logs[0]
+	if got.Path != "/api/v1/ping" || got.Query != "x=1" || got.UserID != 7 || got.Username != "alice" || got.StatusCode != http.StatusNoContent {
+		t.Errorf("audit log = %+v", got)
+	}
+}
@@ -1,6 +1,9 @@
 package middleware
 
 import (
+	"net/http"
+
+	"k-admin-system/core"
 	"k-admin-system/global"
 	"k-admin-system/model/common"
 	"k-admin-system/model/system"
@@ -22,7 +25,7 @@ func CasbinAuth() gin.HandlerFunc {
 
 		roleId, ok := roleIdInterface.(uint)
 		if !ok {
-			common.FailWithCode(c, 500, "角色信息格式错误")
+			common.FailWithStatus(c, http.StatusInternalServerError, "角色信息格式错误")
 			c.Abort()
 			return
 		}
@@ -31,7 +34,7 @@ func CasbinAuth() gin.HandlerFunc {
 		var role system.SysRole
 		if err := global.DB.First(&role, roleId).Error; err != nil {
 			global.Logger.Error("Failed to query role: " + err.Error())
-			common.FailWithCode(c, 403, "角色不存在")
+			common.FailWithStatus(c, http.StatusForbidden, "角色不存在")
 			c.Abort()
 			return
 		}
@@ -40,18 +43,25 @@ func CasbinAuth() gin.HandlerFunc {
 		path := c.Request.URL.Path
 		method := c.Request.Method
 
+		// 超级管理员角色跳过权限检查
+		if global.Config != nil && global.Config.Casbin.SuperuserRoleKey != "" && role.RoleKey == global.Config.Casbin.SuperuserRoleKey {
+			global.Logger.Debug("Casbin bypassed for superuser role: " + role.RoleKey + " path: " + path + " method: " + method)
+			c.Next()
+			return
+		}
+
 		// 使用Casbin enforcer检查权限
-		allowed, err := global.CasbinEnforcer.Enforce(role.RoleKey, path, method)
+		allowed, err := core.CasbinEnforce(role.RoleKey, path, method)
 		if err != nil {
 			global.Logger.Error("Casbin enforce error: " + err.Error())
-			common.FailWithCode(c, 500, "权限检查失败")
+			common.FailWithStatus(c, http.StatusInternalServerError, "权限检查失败")
 			c.Abort()
 			return
 		}
 
 		if !allowed {
 			global.Logger.Warn("Access denied for role: " + role.RoleKey + " path: " + path + " method: " + method)
-			common.FailWithCode(c, 403, "无权访问")
+			common.FailWithStatus(c, http.StatusForbidden, "无权访问")
 			c.Abort()
 			return
 		}
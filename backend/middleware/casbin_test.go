@@ -0,0 +1,147 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"k-admin-system/config"
+	"k-admin-system/core"
+	"k-admin-system/global"
+	"k-admin-system/model/system"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newCasbinRouter 返回以 roleID 身份访问任意路径的路由，通过鉴权时返回 200
+func newCasbinRouter(roleID uint) *gin.Engine {
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("roleId", roleID)
+		c.Next()
+	})
+	r.NoRoute(CasbinAuth(), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return r
+}
+
+// setupCasbinEnforcer 基于测试数据库初始化空策略库的 enforcer，测试结束后恢复原值
+func setupCasbinEnforcer(t *testing.T) {
+	t.Helper()
+	enforcer, err := core.InitCasbin()
+	if err != nil {
+		t.Fatalf("failed to init casbin: %v", err)
+	}
+	prevEnforcer := global.CasbinEnforcer
+	global.CasbinEnforcer = enforcer
+	core.InvalidateCasbinCache()
+	t.Cleanup(func() {
+		global.CasbinEnforcer = prevEnforcer
+		core.InvalidateCasbinCache()
+	})
+}
+
+// casbinStatus 发送请求并返回响应状态码
+func casbinStatus(r http.Handler, method, path string) int {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+	return w.Code
+}
+
+func TestCasbinAuth_SuperuserBypassesPolicies(t *testing.T) {
+	setupTestEnv(t, &config.Config{Casbin: config.CasbinConfig{SuperuserRoleKey: "root"}})
+
+	// 策略库为空
+	setupCasbinEnforcer(t)
+
+	superuser := &system.SysRole{RoleName: "Root", RoleKey: "root", Status: true}
+	editor := &system.SysRole{RoleName: "Editor", RoleKey: "editor", Status: true}
+	for _, role := range []*system.SysRole{superuser, editor} {
+		if err := global.DB.Create(role).Error; err != nil {
+			t.Fatalf("failed to create role: %v", err)
+		}
+	}
+	superuserRouter := newCasbinRouter(superuser.ID)
+	editorRouter := newCasbinRouter(editor.ID)
+
+	methods := []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+	property := func(segments []string, methodIndex uint8) bool {
+		escaped := make([]string, 0, len(segments))
+		for _, segment := range segments {
+			escaped = append(escaped, url.PathEscape(segment))
+		}
+		path := "/api/v1/" + strings.Join(escaped, "/")
+		method := methods[int(methodIndex)%len(methods)]
+
+		return casbinStatus(superuserRouter, method, path) == http.StatusOK &&
+			casbinStatus(editorRouter, method, path) == http.StatusForbidden
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+// pathSegment 根据随机字节生成非空的路径段
+func pathSegment(seed []uint8) string {
+	const alphabet = "abcdefghijklmnopqrstuvwxyz0123456789-_"
+	if len(seed) == 0 {
+		return "x"
+	}
+	var b strings.Builder
+	for _, index := range seed {
+		b.WriteByte(alphabet[int(index)%len(alphabet)])
+	}
+	return b.String()
+}
+
+func TestCasbinAuth_KeyMatchPatterns(t *testing.T) {
+	setupTestEnv(t, &config.Config{})
+	setupCasbinEnforcer(t)
+
+	editor := &system.SysRole{RoleName: "Editor", RoleKey: "editor", Status: true}
+	if err := global.DB.Create(editor).Error; err != nil {
+		t.Fatalf("failed to create role: %v", err)
+	}
+
+	// 每个模式对应一个将随机路径段填入参数位置的路径生成函数
+	patterns := []struct {
+		pattern string
+		method  string
+		path    func(a, b string) string
+	}{
+		{"/api/v1/user/:id", http.MethodGet, func(a, _ string) string { return "/api/v1/user/" + a }},
+		{"/api/v1/role/:id/menus", http.MethodPut, func(a, _ string) string { return "/api/v1/role/" + a + "/menus" }},
+		{"/api/v1/tools/db/:table/records/:id", http.MethodDelete, func(a, b string) string { return "/api/v1/tools/db/" + a + "/records/" + b }},
+		{"/api/v1/notification/:id/read", http.MethodPost, func(a, _ string) string { return "/api/v1/notification/" + a + "/read" }},
+		{"/api/v1/files/*", http.MethodGet, func(a, b string) string { return "/api/v1/files/" + a + "/" + b }},
+	}
+	for _, p := range patterns {
+		if _, err := global.CasbinEnforcer.AddPolicy("editor", p.pattern, p.method); err != nil {
+			t.Fatalf("failed to add policy: %v", err)
+		}
+	}
+	core.InvalidateCasbinCache()
+	r := newCasbinRouter(editor.ID)
+
+	property := func(index uint8, a, b []uint8) bool {
+		p := patterns[int(index)%len(patterns)]
+		path := p.path(pathSegment(a), pathSegment(b))
+		otherMethod := http.MethodPatch
+
+		// 匹配的路径和方法允许访问，其他方法和多出一级的路径（通配符模式除外）拒绝访问
+		if casbinStatus(r, p.method, path) != http.StatusOK || casbinStatus(r, otherMethod, path) != http.StatusForbidden {
+			return false
+		}
+		if strings.HasSuffix(p.pattern, "*") {
+			return true
+		}
+		return casbinStatus(r, p.method, path+"/extra") == http.StatusForbidden
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
@@ -2,8 +2,10 @@ package middleware
 
 import (
 	"k-admin-system/config"
+	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/gin-gonic/gin"
 )
@@ -21,6 +23,8 @@ import (
 //	  allow_origins:
 //	    - "http://localhost:3000"
 //	    - "https://yourdomain.com"
+//	  allow_origin_regexes:
+//	    - "https://preview-[a-z0-9-]+\\.example\\.com"
 //	  allow_methods:
 //	    - "GET"
 //	    - "POST"
@@ -31,12 +35,24 @@ import (
 //	    - "Authorization"
 //	  allow_credentials: true
 //	  max_age: 86400
+//
+// 静态列表 allow_origins 未匹配时依次尝试 allow_origin_regexes 中的正则，正则需匹配完整origin（自动锚定首尾）
 func CORS(corsConfig config.CORSConfig) gin.HandlerFunc {
+	// 正则在首次请求时编译一次并缓存
+	var (
+		compileOnce   sync.Once
+		originRegexes []*regexp.Regexp
+	)
+
 	return func(c *gin.Context) {
 		origin := c.GetHeader("Origin")
 
+		compileOnce.Do(func() {
+			originRegexes = compileOriginRegexes(corsConfig.AllowOriginRegexes)
+		})
+
 		// 检查origin是否在允许列表中
-		if origin != "" && isOriginAllowed(origin, corsConfig.AllowOrigins) {
+		if origin != "" && (isOriginAllowed(origin, corsConfig.AllowOrigins) || matchesOriginRegex(origin, originRegexes)) {
 			// 设置允许的源
 			c.Header("Access-Control-Allow-Origin", origin)
 
@@ -97,3 +113,27 @@ func isOriginAllowed(origin string, allowedOrigins []string) bool {
 	}
 	return false
 }
+
+// compileOriginRegexes 编译origin正则，正则需匹配完整origin，非法的正则会被忽略（配置加载时已校验）
+func compileOriginRegexes(patterns []string) []*regexp.Regexp {
+	regexes := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		// 锚定首尾，避免 https://app\.example\.com 匹配 https://app.example.com.evil.io
+		re, err := regexp.Compile(`^(?:` + pattern + `)$`)
+		if err != nil {
+			continue
+		}
+		regexes = append(regexes, re)
+	}
+	return regexes
+}
+
+// matchesOriginRegex 检查origin是否匹配任一正则
+func matchesOriginRegex(origin string, regexes []*regexp.Regexp) bool {
+	for _, re := range regexes {
+		if re.MatchString(origin) {
+			return true
+		}
+	}
+	return false
+}
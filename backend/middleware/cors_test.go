@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"k-admin-system/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCORS_OriginRegexes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(CORS(config.CORSConfig{
+		AllowOrigins: []string{"http://localhost:3000"},
+		AllowOriginRegexes: []string{
+			`https://preview-[a-z0-9-]+\.example\.com`,
+			`^https://admin\.example\.org$`,
+		},
+	}))
+	r.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	tests := []struct {
+		origin  string
+		allowed bool
+	}{
+		{"http://localhost:3000", true},
+		{"https://preview-pr-42.example.com", true},
+		{"https://admin.example.org", true},
+		{"https://preview-pr-42.example.com.evil.io", false},
+		{"https://evil.io/https://preview-pr-42.example.com", false},
+		{"https://admin.example.org.evil.io", false},
+		{"https://preview-PR.example.com", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.origin, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+			req.Header.Set("Origin", tt.origin)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			got := w.Header().Get("Access-Control-Allow-Origin")
+			if tt.allowed && got != tt.origin {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.origin)
+			}
+			if !tt.allowed && got != "" {
+				t.Errorf("Access-Control-Allow-Origin = %q, want none", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"testing"
+
+	"k-admin-system/config"
+	"k-admin-system/global"
+	"k-admin-system/model/system"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// setupTestEnv 使用内存SQLite和miniredis初始化全局依赖，测试结束后恢复原值
+func setupTestEnv(t *testing.T, cfg *config.Config) *miniredis.Miniredis {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	// 内存数据库只存在于单个连接中
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get sql.DB: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	if err := db.AutoMigrate(&system.SysRole{}, &system.SysUser{}, &system.SysRateLimitOverride{}, &system.SysAuditLog{}); err != nil {
+		t.Fatalf("failed to migrate tables: %v", err)
+	}
+
+	mr := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	prevDB, prevRedis, prevLogger, prevConfig := global.DB, global.RedisClient, global.Logger, global.Config
+	global.DB = db
+	global.RedisClient = redisClient
+	global.Logger = zap.NewNop()
+	global.Config = cfg
+	t.Cleanup(func() {
+		_ = redisClient.Close()
+		_ = sqlDB.Close()
+		global.DB, global.RedisClient, global.Logger, global.Config = prevDB, prevRedis, prevLogger, prevConfig
+	})
+
+	return mr
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"k-admin-system/global"
 	"k-admin-system/model/common"
 	"k-admin-system/utils"
 	"strings"
@@ -11,6 +12,12 @@ import (
 // JWTAuth JWT认证中间件
 func JWTAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		// 已通过API密钥认证
+		if c.GetBool(apiKeyAuthenticatedKey) {
+			c.Next()
+			return
+		}
+
 		// 从请求头获取token
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -44,6 +51,16 @@ func JWTAuth() gin.HandlerFunc {
 			return
 		}
 
+		// 校验设备指纹，防止令牌被盗用后在其他设备上使用
+		if global.Config != nil && global.Config.JWT.FingerprintEnabled {
+			fingerprint := utils.ComputeFingerprint(c.GetHeader("User-Agent"), c.GetHeader("Accept-Language"))
+			if claims.Fingerprint != fingerprint {
+				common.FailWithCode(c, 401, "令牌与当前设备不匹配")
+				c.Abort()
+				return
+			}
+		}
+
 		// 将用户信息存入上下文
 		c.Set("userId", claims.UserID)
 		c.Set("username", claims.Username)
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"k-admin-system/config"
+	"k-admin-system/model/common"
+	"k-admin-system/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	testUserAgent      = "Mozilla/5.0 (X11; Linux x86_64) Firefox/128.0"
+	testAcceptLanguage = "zh-CN,zh;q=0.9"
+)
+
+func TestJWTAuth_Fingerprint(t *testing.T) {
+	tests := []struct {
+		name           string
+		enabled        bool
+		userAgent      string
+		acceptLanguage string
+		wantCode       int
+	}{
+		{"matching device", true, testUserAgent, testAcceptLanguage, 0},
+		{"different user agent", true, "curl/8.5.0", testAcceptLanguage, http.StatusUnauthorized},
+		{"different language", true, testUserAgent, "en-US", http.StatusUnauthorized},
+		{"missing headers", true, "", "", http.StatusUnauthorized},
+		{"fingerprint disabled", false, "curl/8.5.0", "en-US", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestEnv(t, &config.Config{JWT: config.JWTConfig{
+				Secret: "test-secret", AccessExpiration: 15, RefreshExpiration: 7, FingerprintEnabled: tt.enabled,
+			}})
+			accessToken, _, err := utils.GenerateToken(1, "alice", 1, utils.ComputeFingerprint(testUserAgent, testAcceptLanguage), "")
+			if err != nil {
+				t.Fatalf("GenerateToken() error = %v", err)
+			}
+
+			r := gin.New()
+			r.GET("/ping", JWTAuth(), func(c *gin.Context) {
+				common.OkWithData(c, c.GetString("username"))
+			})
+			req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+			req.Header.Set("Authorization", "Bearer "+accessToken)
+			req.Header.Set("User-Agent", tt.userAgent)
+			req.Header.Set("Accept-Language", tt.acceptLanguage)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			var resp common.Response
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.Code != tt.wantCode {
+				t.Fatalf("code = %d, want %d (msg %q)", resp.Code, tt.wantCode, resp.Msg)
+			}
+			if tt.wantCode == 0 && resp.Data != "alice" {
+				t.Errorf("data = %v, want alice", resp.Data)
+			}
+		})
+	}
+}
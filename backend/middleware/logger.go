@@ -2,12 +2,18 @@ package middleware
 
 import (
 	"k-admin-system/global"
+	systemService "k-admin-system/service/system"
+	"k-admin-system/utils"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
+// latencyQueue 路由耗时指标的异步写入队列
+var latencyQueue = utils.NewAsyncQueue("latency_metrics", 1024, 4)
+
 // Logger 请求日志中间件
 // 记录所有HTTP请求的详细信息，包括时间戳、方法、路径、状态码、延迟和客户端IP
 //
@@ -25,10 +31,25 @@ import (
 //	  "path": "/api/v1/users",
 //	  "status": 200,
 //	  "latency": "15.234ms",
-//	  "client_ip": "192.168.1.1"
+//	  "client_ip": "192.168.1.1",
+//	  "response_bytes": 1024,
+//	  "trace_id": "4bf92f3577b34da6a3ce929d0e0e4736",
+//	  "span_id": "00f067aa0ba902b7"
 //	}
+//
+// trace_id 和 span_id 仅在配置了链路追踪且请求存在有效Span时输出
+//
+// 开启 metrics.enabled 时，请求耗时按路由模式异步写入Redis有序集合 latency:<route>，
+// 供 GET /api/v1/system/metrics/latency 计算百分位耗时；未匹配路由的请求（如404）不记录。
+// 写入由有界队列 latencyQueue 执行，队列满时丢弃样本
+//
+// 同时解析 X-Timezone 请求头，将客户端时区（无效或未提供时使用配置的默认时区）
+// 以 "timezone" 键存入上下文，供响应中的时间字段转换使用
 func Logger() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		// 记录客户端时区
+		c.Set("timezone", requestTimezone(c))
+
 		// 记录请求开始时间
 		startTime := time.Now()
 
@@ -39,6 +60,10 @@ func Logger() gin.HandlerFunc {
 		path := c.Request.URL.Path
 		method := c.Request.Method
 
+		// 包装响应写入器以统计响应体大小
+		writer := &bodyCountingWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
 		// 处理请求
 		c.Next()
 
@@ -50,13 +75,65 @@ func Logger() gin.HandlerFunc {
 
 		// 记录日志
 		if global.Logger != nil {
-			global.Logger.Info("HTTP Request",
+			fields := []zap.Field{
 				zap.String("method", method),
 				zap.String("path", path),
 				zap.Int("status", statusCode),
 				zap.Duration("latency", latency),
 				zap.String("client_ip", clientIP),
-			)
+				zap.Int("response_bytes", writer.count),
+			}
+
+			// 附加链路追踪ID
+			if spanContext := trace.SpanFromContext(c.Request.Context()).SpanContext(); spanContext.IsValid() {
+				fields = append(fields,
+					zap.String("trace_id", spanContext.TraceID().String()),
+					zap.String("span_id", spanContext.SpanID().String()),
+				)
+			}
+
+			global.Logger.Info("HTTP Request", fields...)
+		}
+
+		// 记录路由耗时指标
+		if route := c.FullPath(); route != "" && global.Config != nil && global.Config.Metrics.Enabled && global.RedisClient != nil {
+			latencyQueue.Submit(func() {
+				metricsService := systemService.MetricsService{}
+				if err := metricsService.RecordLatency(route, latency); err != nil && global.Logger != nil {
+					global.Logger.Warn("Failed to record latency metric", zap.Error(err))
+				}
+			})
 		}
 	}
 }
+
+// bodyCountingWriter 统计写入响应体字节数的响应写入器
+type bodyCountingWriter struct {
+	gin.ResponseWriter
+	count int
+}
+
+// Write 写入响应体并累加字节数
+func (w *bodyCountingWriter) Write(data []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(data)
+	w.count += n
+	return n, err
+}
+
+// WriteString 写入字符串响应体并累加字节数
+func (w *bodyCountingWriter) WriteString(s string) (int, error) {
+	n, err := w.ResponseWriter.WriteString(s)
+	w.count += n
+	return n, err
+}
+
+// requestTimezone 返回 X-Timezone 请求头中的时区，无效时返回配置的默认时区
+func requestTimezone(c *gin.Context) string {
+	if tz := c.GetHeader("X-Timezone"); utils.IsValidTimezone(tz) {
+		return tz
+	}
+	if global.Config != nil {
+		return global.Config.Timezone.Default
+	}
+	return ""
+}
@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/quick"
+
+	"k-admin-system/global"
+
+	"github.com/gin-gonic/gin"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// observeLogs 将 global.Logger 替换为记录日志条目的观察者，测试结束后恢复原值
+func observeLogs(t *testing.T) *observer.ObservedLogs {
+	t.Helper()
+	core, logs := observer.New(zap.InfoLevel)
+	prevLogger, prevConfig := global.Logger, global.Config
+	global.Logger = zap.New(core)
+	global.Config = nil
+	t.Cleanup(func() {
+		global.Logger, global.Config = prevLogger, prevConfig
+	})
+	return logs
+}
+
+// requestLogFields 返回唯一一条 "HTTP Request" 日志的字段
+func requestLogFields(t *testing.T, logs *observer.ObservedLogs) map[string]interface{} {
+	t.Helper()
+	entries := logs.FilterMessage("HTTP Request").All()
+	if len(entries) != 1 {
+		t.Fatalf("expected one request log entry, got %d", len(entries))
+	}
+	return entries[0].ContextMap()
+}
+
+func TestLogger_IncludesTraceID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logs := observeLogs(t)
+
+	// 模拟 otelgin：为每个请求开启Span并记录到 recorder
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		ctx, span := tracer.Start(c.Request.Context(), c.Request.URL.Path)
+		defer span.End()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	})
+	r.Use(Logger())
+	r.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ping", nil))
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected one span, got %d", len(spans))
+	}
+	fields := requestLogFields(t, logs)
+	if got, want := fields["trace_id"], spans[0].SpanContext().TraceID().String(); got != want {
+		t.Errorf("trace_id = %v, want %s", got, want)
+	}
+	if got, want := fields["span_id"], spans[0].SpanContext().SpanID().String(); got != want {
+		t.Errorf("span_id = %v, want %s", got, want)
+	}
+}
+
+func TestLogger_OmitsTraceIDWithoutSpan(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logs := observeLogs(t)
+
+	r := gin.New()
+	r.Use(Logger())
+	r.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ping", nil))
+
+	fields := requestLogFields(t, logs)
+	if _, ok := fields["trace_id"]; ok {
+		t.Errorf("trace_id logged without an active span: %v", fields)
+	}
+}
+
+// TestLogger_ResponseBytesMatchesBody 属性测试：response_bytes 始终等于实际写出的响应体长度
+func TestLogger_ResponseBytesMatchesBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logs := observeLogs(t)
+
+	property := func(chunks [][]byte, useString bool) bool {
+		r := gin.New()
+		r.Use(Logger())
+		r.GET("/data", func(c *gin.Context) {
+			c.Status(http.StatusOK)
+			for _, chunk := range chunks {
+				if useString {
+					_, _ = c.Writer.WriteString(string(chunk))
+				} else {
+					_, _ = c.Writer.Write(chunk)
+				}
+			}
+		})
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/data", nil))
+
+		entries := logs.TakeAll()
+		if len(entries) != 1 {
+			return false
+		}
+		return entries[0].ContextMap()["response_bytes"] == int64(w.Body.Len())
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
@@ -6,6 +6,9 @@ import (
 	"k-admin-system/config"
 	"k-admin-system/global"
 	"k-admin-system/model/common"
+	systemService "k-admin-system/service/system"
+	"net"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -26,7 +29,12 @@ import (
 //	  requests: 100      # 允许的请求数
 //	  window: 60         # 时间窗口（秒）
 //	  key_func: "ip"     # 限流键函数: "ip" 或 "user"
+//	  whitelist:         # 不受限流的客户端IP或CIDR网段
+//	    - "127.0.0.1"
+//	    - "10.0.0.0/8"
 func RateLimit(rateLimitConfig config.RateLimitConfig) gin.HandlerFunc {
+	whitelist := parseWhitelist(rateLimitConfig.Whitelist)
+
 	return func(c *gin.Context) {
 		// 如果未启用限流，直接放行
 		if !rateLimitConfig.Enabled {
@@ -34,6 +42,12 @@ func RateLimit(rateLimitConfig config.RateLimitConfig) gin.HandlerFunc {
 			return
 		}
 
+		// 白名单中的客户端不受限流
+		if whitelist.contains(c.ClientIP()) {
+			c.Next()
+			return
+		}
+
 		// 如果Redis未初始化，记录警告并放行
 		if global.RedisClient == nil {
 			global.Logger.Warn("Rate limiting disabled: Redis client not initialized")
@@ -49,19 +63,48 @@ func RateLimit(rateLimitConfig config.RateLimitConfig) gin.HandlerFunc {
 			return
 		}
 
-		// 检查是否超过限流
-		allowed, err := checkRateLimit(key, rateLimitConfig.Requests, rateLimitConfig.Window)
-		if err != nil {
-			// Redis错误，记录日志但不阻止请求
-			global.Logger.Error(fmt.Sprintf("Rate limit check failed: %v", err))
+		if !enforceRateLimit(c, key, rateLimitConfig.Requests, rateLimitConfig.Window) {
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// UserRateLimit 用户级限流中间件
+// 必须注册在 JWTAuth 之后，此时上下文中已有 userId：
+// 配置了限流覆盖的用户按覆盖参数限流；key_func 为 "user" 时其余用户按全局参数限流。
+// 全局 RateLimit 在认证前执行，只能按IP限流，仍作为外层限制生效。
+//
+// 使用示例:
+//
+//	protectedGroup.Use(middleware.APIKeyAuth(), middleware.JWTAuth(), middleware.UserRateLimit())
+func UserRateLimit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rateLimitConfig := global.Config.RateLimit
+		if !rateLimitConfig.Enabled || global.RedisClient == nil {
 			c.Next()
 			return
 		}
 
-		if !allowed {
-			// 超过限流，返回429
-			common.FailWithCode(c, 429, "请求过于频繁，请稍后再试")
-			c.Abort()
+		userIDInterface, exists := c.Get("userId")
+		if !exists {
+			c.Next()
+			return
+		}
+		userID, ok := userIDInterface.(uint)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		maxRequests, window, limited := getUserRateLimitParams(userID, rateLimitConfig)
+		if !limited {
+			c.Next()
+			return
+		}
+
+		if !enforceRateLimit(c, fmt.Sprintf("rate_limit:user:%d", userID), maxRequests, window) {
 			return
 		}
 
@@ -69,28 +112,110 @@ func RateLimit(rateLimitConfig config.RateLimitConfig) gin.HandlerFunc {
 	}
 }
 
+// enforceRateLimit 检查限流并写入限流状态响应头，超过限流时返回429并中止请求
+// 返回值表示请求是否可以继续处理；Redis错误时记录日志并放行
+func enforceRateLimit(c *gin.Context, key string, maxRequests int, windowSeconds int) bool {
+	result, err := checkRateLimit(key, maxRequests, windowSeconds)
+	if err != nil {
+		// Redis错误，记录日志但不阻止请求
+		global.Logger.Error(fmt.Sprintf("Rate limit check failed: %v", err))
+		return true
+	}
+
+	// 无论是否放行都返回限流状态响应头
+	c.Header("X-Rate-Limit-Limit", strconv.Itoa(maxRequests))
+	c.Header("X-Rate-Limit-Remaining", strconv.Itoa(result.Remaining))
+	c.Header("X-Rate-Limit-Reset", strconv.FormatInt(result.Reset, 10))
+
+	if !result.Allowed {
+		// 超过限流，返回429
+		common.FailWithCode(c, 429, "请求过于频繁，请稍后再试")
+		c.Abort()
+		return false
+	}
+	return true
+}
+
+// ipWhitelist 限流白名单
+type ipWhitelist []*net.IPNet
+
+// parseWhitelist 解析白名单配置，单个IP按 /32（IPv6为 /128）网段处理，无效条目被忽略
+func parseWhitelist(entries []string) ipWhitelist {
+	whitelist := make(ipWhitelist, 0, len(entries))
+	for _, entry := range entries {
+		if _, ipNet, err := net.ParseCIDR(entry); err == nil {
+			whitelist = append(whitelist, ipNet)
+			continue
+		}
+		if ip := net.ParseIP(entry); ip != nil {
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
+			}
+			whitelist = append(whitelist, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+		}
+	}
+	return whitelist
+}
+
+// contains 判断客户端IP是否在白名单中
+func (w ipWhitelist) contains(clientIP string) bool {
+	if len(w) == 0 {
+		return false
+	}
+	ip := net.ParseIP(clientIP)
+	if ip == nil {
+		return false
+	}
+	for _, ipNet := range w {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
 // getRateLimitKey 根据配置获取限流键
+// 全局限流在认证前执行，此时无法识别用户，因此 "user" 模式也按IP限流，
+// 认证后的用户级限流由 UserRateLimit 负责
 func getRateLimitKey(c *gin.Context, keyFunc string) string {
 	switch keyFunc {
-	case "ip":
-		// 基于IP地址限流
+	case "ip", "user":
 		return fmt.Sprintf("rate_limit:ip:%s", c.ClientIP())
-	case "user":
-		// 基于用户ID限流（需要先通过JWT认证）
-		userID, exists := c.Get("userId")
-		if !exists {
-			// 未认证用户，回退到IP限流
-			return fmt.Sprintf("rate_limit:ip:%s", c.ClientIP())
-		}
-		return fmt.Sprintf("rate_limit:user:%v", userID)
 	default:
 		return ""
 	}
 }
 
+// getUserRateLimitParams 获取指定用户适用的限流参数
+// 用户配置了限流覆盖时使用覆盖配置；否则仅在 key_func 为 "user" 时使用全局配置，
+// limited 为 false 表示该用户无需额外的用户级限流
+func getUserRateLimitParams(userID uint, rateLimitConfig config.RateLimitConfig) (maxRequests int, windowSeconds int, limited bool) {
+	perUser := rateLimitConfig.KeyFunc == "user"
+
+	rateLimitService := systemService.RateLimitOverrideService{}
+	override, err := rateLimitService.GetUserOverride(userID)
+	if err != nil {
+		// 查询失败时回退到全局配置
+		global.Logger.Warn(fmt.Sprintf("Failed to get rate limit override for user %d: %v", userID, err))
+		return rateLimitConfig.Requests, rateLimitConfig.Window, perUser
+	}
+	if override != nil {
+		return override.Requests, override.WindowSeconds, true
+	}
+
+	return rateLimitConfig.Requests, rateLimitConfig.Window, perUser
+}
+
+// rateLimitResult 限流检查结果
+type rateLimitResult struct {
+	Allowed   bool  // 是否允许本次请求
+	Remaining int   // 当前窗口内剩余可用请求数
+	Reset     int64 // 窗口内最早的请求移出窗口的时间（Unix时间戳）
+}
+
 // checkRateLimit 使用滑动窗口算法检查是否超过限流
-// 返回 (是否允许, 错误)
-func checkRateLimit(key string, maxRequests int, windowSeconds int) (bool, error) {
+func checkRateLimit(key string, maxRequests int, windowSeconds int) (rateLimitResult, error) {
 	ctx := context.Background()
 	now := time.Now().Unix()
 	windowStart := now - int64(windowSeconds)
@@ -99,18 +224,22 @@ func checkRateLimit(key string, maxRequests int, windowSeconds int) (bool, error
 	// 1. 移除窗口外的旧记录
 	err := global.RedisClient.ZRemRangeByScore(ctx, key, "0", fmt.Sprintf("%d", windowStart)).Err()
 	if err != nil {
-		return false, fmt.Errorf("failed to remove old records: %w", err)
+		return rateLimitResult{}, fmt.Errorf("failed to remove old records: %w", err)
 	}
 
 	// 2. 统计当前窗口内的请求数
 	count, err := global.RedisClient.ZCard(ctx, key).Result()
 	if err != nil {
-		return false, fmt.Errorf("failed to count requests: %w", err)
+		return rateLimitResult{}, fmt.Errorf("failed to count requests: %w", err)
 	}
 
 	// 3. 检查是否超过限制
 	if count >= int64(maxRequests) {
-		return false, nil
+		return rateLimitResult{
+			Allowed:   false,
+			Remaining: 0,
+			Reset:     windowReset(ctx, key, now, windowSeconds),
+		}, nil
 	}
 
 	// 4. 添加当前请求到窗口
@@ -121,7 +250,7 @@ func checkRateLimit(key string, maxRequests int, windowSeconds int) (bool, error
 		Member: member,
 	}).Err()
 	if err != nil {
-		return false, fmt.Errorf("failed to add request record: %w", err)
+		return rateLimitResult{}, fmt.Errorf("failed to add request record: %w", err)
 	}
 
 	// 5. 设置键的过期时间（窗口大小的2倍，确保数据清理）
@@ -131,5 +260,18 @@ func checkRateLimit(key string, maxRequests int, windowSeconds int) (bool, error
 		global.Logger.Warn(fmt.Sprintf("Failed to set expiration for rate limit key: %v", err))
 	}
 
-	return true, nil
+	return rateLimitResult{
+		Allowed:   true,
+		Remaining: maxRequests - int(count) - 1,
+		Reset:     windowReset(ctx, key, now, windowSeconds),
+	}, nil
+}
+
+// windowReset 计算窗口内最早一条请求记录过期的时间，即剩余请求数开始恢复的时间
+func windowReset(ctx context.Context, key string, now int64, windowSeconds int) int64 {
+	oldest, err := global.RedisClient.ZRangeWithScores(ctx, key, 0, 0).Result()
+	if err != nil || len(oldest) == 0 {
+		return now + int64(windowSeconds)
+	}
+	return int64(oldest[0].Score) + int64(windowSeconds)
 }
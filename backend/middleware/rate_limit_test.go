@@ -0,0 +1,160 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/quick"
+
+	"k-admin-system/config"
+	"k-admin-system/global"
+	"k-admin-system/model/common"
+	"k-admin-system/model/system"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newUserRateLimitRouter 构造模拟 JWTAuth 之后执行 UserRateLimit 的路由
+func newUserRateLimitRouter(userID uint) *gin.Engine {
+	r := gin.New()
+	r.Use(RateLimit(global.Config.RateLimit))
+	r.GET("/ping", func(c *gin.Context) {
+		c.Set("userId", userID)
+		c.Next()
+	}, UserRateLimit(), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	return r
+}
+
+// doRequests 依次发送 n 个请求并返回每个响应体中的业务码（放行的请求为 0）
+func doRequests(t *testing.T, r *gin.Engine, n int) []int {
+	t.Helper()
+	codes := make([]int, 0, n)
+	for i := 0; i < n; i++ {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+		var resp common.Response
+		if w.Body.Len() > 0 {
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+		}
+		codes = append(codes, resp.Code)
+	}
+	return codes
+}
+
+func TestUserRateLimit_AppliesOverrideAfterAuth(t *testing.T) {
+	setupTestEnv(t, &config.Config{RateLimit: config.RateLimitConfig{
+		Enabled: true, Requests: 100, Window: 60, KeyFunc: "ip",
+	}})
+
+	role := system.SysRole{RoleName: "Tester", RoleKey: "tester", Status: true}
+	if err := global.DB.Create(&role).Error; err != nil {
+		t.Fatalf("failed to create role: %v", err)
+	}
+	user := system.SysUser{Username: "limited", Password: "x", RoleID: role.ID, Active: true}
+	if err := global.DB.Create(&user).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	override := system.SysRateLimitOverride{UserID: user.ID, Requests: 2, WindowSeconds: 60}
+	if err := global.DB.Create(&override).Error; err != nil {
+		t.Fatalf("failed to create override: %v", err)
+	}
+
+	codes := doRequests(t, newUserRateLimitRouter(user.ID), 3)
+	if codes[0] != 0 || codes[1] != 0 {
+		t.Fatalf("first two requests should pass, got %v", codes)
+	}
+	if codes[2] != http.StatusTooManyRequests {
+		t.Fatalf("third request should be limited by the override, got %d", codes[2])
+	}
+}
+
+func TestUserRateLimit_NoOverrideUsesIPLimitOnly(t *testing.T) {
+	setupTestEnv(t, &config.Config{RateLimit: config.RateLimitConfig{
+		Enabled: true, Requests: 3, Window: 60, KeyFunc: "ip",
+	}})
+
+	codes := doRequests(t, newUserRateLimitRouter(42), 4)
+	for i, code := range codes[:3] {
+		if code != 0 {
+			t.Fatalf("request %d should pass, got %d", i+1, code)
+		}
+	}
+	if codes[3] != http.StatusTooManyRequests {
+		t.Fatalf("fourth request should be limited by the IP limiter, got %d", codes[3])
+	}
+}
+
+func TestRateLimit_RemainingHeader(t *testing.T) {
+	setupTestEnv(t, &config.Config{RateLimit: config.RateLimitConfig{
+		Enabled: true, Requests: 3, Window: 60, KeyFunc: "ip",
+	}})
+	r := gin.New()
+	r.Use(RateLimit(global.Config.RateLimit))
+	r.GET("/ping", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	for i, want := range []string{"2", "1", "0", "0"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+		if got := w.Header().Get("X-Rate-Limit-Remaining"); got != want {
+			t.Errorf("request %d: X-Rate-Limit-Remaining = %q, want %q", i+1, got, want)
+		}
+		if got := w.Header().Get("X-Rate-Limit-Limit"); got != "3" {
+			t.Errorf("request %d: X-Rate-Limit-Limit = %q, want 3", i+1, got)
+		}
+		if limited := w.Code != http.StatusNoContent; limited != (i == 3) {
+			t.Errorf("request %d: status = %d, limited only on the fourth request", i+1, w.Code)
+		}
+	}
+}
+
+// TestRateLimit_WhitelistNeverLimited 属性测试：白名单网段内的任意IP都不受限流，网段外的IP照常限流
+func TestRateLimit_WhitelistNeverLimited(t *testing.T) {
+	setupTestEnv(t, &config.Config{RateLimit: config.RateLimitConfig{
+		Enabled: true, Requests: 1, Window: 60, KeyFunc: "ip",
+		Whitelist: []string{"10.0.0.0/8", "192.168.1.5"},
+	}})
+	r := gin.New()
+	r.Use(RateLimit(global.Config.RateLimit))
+	r.GET("/ping", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	statuses := func(ip string, n int) []int {
+		codes := make([]int, 0, n)
+		for i := 0; i < n; i++ {
+			req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+			req.RemoteAddr = ip + ":12345"
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			codes = append(codes, w.Code)
+		}
+		return codes
+	}
+
+	property := func(b1, b2, b3 byte) bool {
+		for _, code := range statuses(fmt.Sprintf("10.%d.%d.%d", b1, b2, b3), 3) {
+			if code != http.StatusNoContent {
+				return false
+			}
+		}
+		// 同样的后缀落在白名单网段之外时第二个请求被限流
+		codes := statuses(fmt.Sprintf("11.%d.%d.%d", b1, b2, b3), 2)
+		return codes[0] == http.StatusNoContent && codes[1] != http.StatusNoContent
+	}
+	if err := quick.Check(property, &quick.Config{MaxCount: 50}); err != nil {
+		t.Error(err)
+	}
+
+	for i, code := range statuses("192.168.1.5", 3) {
+		if code != http.StatusNoContent {
+			t.Errorf("request %d from a whitelisted single IP: status = %d", i+1, code)
+		}
+	}
+	if codes := statuses("192.168.1.6", 2); codes[1] == http.StatusNoContent {
+		t.Errorf("IP next to a whitelisted single IP was not limited: %v", codes)
+	}
+}
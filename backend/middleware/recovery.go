@@ -24,12 +24,14 @@ import (
 //	  "timestamp": "2024-01-01T12:00:00Z",
 //	  "level": "error",
 //	  "msg": "Panic recovered",
-//	  "error": "runtime error: invalid memory address or nil pointer dereference",
+//	  "error": "runtime.errorString: runtime error: invalid memory address or nil pointer dereference",
 //	  "path": "/api/v1/users",
 //	  "method": "GET",
 //	  "stack": "goroutine 1 [running]:\n..."
 //	}
 //
+// error 字段带有panic值的类型前缀，用于区分运行时错误和 panic("...") 等字符串panic
+//
 // 响应格式:
 //
 //	{
@@ -51,7 +53,7 @@ func Recovery() gin.HandlerFunc {
 				// 记录panic日志
 				if global.Logger != nil {
 					global.Logger.Error("Panic recovered",
-						zap.Any("error", err),
+						zap.String("error", fmt.Sprintf("%T: %v", err, err)),
 						zap.String("path", path),
 						zap.String("method", method),
 						zap.String("stack", stack),
@@ -59,11 +61,7 @@ func Recovery() gin.HandlerFunc {
 				}
 
 				// 返回500错误响应
-				c.JSON(http.StatusOK, common.Response{
-					Code: 500,
-					Data: nil,
-					Msg:  fmt.Sprintf("Internal server error: %v", err),
-				})
+				common.FailWithStatus(c, http.StatusInternalServerError, fmt.Sprintf("Internal server error: %v", err))
 
 				// 中止请求处理
 				c.Abort()
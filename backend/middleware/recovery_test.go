@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"k-admin-system/model/common"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRecovery_LogsPanicTypeAndStack(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		panicFn   func()
+		wantError string // error 字段前缀
+	}{
+		{"string", func() { panic("boom") }, "string: boom"},
+		{"error", func() { panic(errors.New("db down")) }, "*errors.errorString: db down"},
+		{"runtime error", func() {
+			var m map[string]int
+			m["x"] = 1
+		}, "runtime."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs := observeLogs(t)
+			r := gin.New()
+			r.Use(Recovery())
+			r.GET("/panic", func(c *gin.Context) { tt.panicFn() })
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+			var resp common.Response
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to decode response %q: %v", w.Body.String(), err)
+			}
+			if w.Code != http.StatusInternalServerError || resp.Code != http.StatusInternalServerError {
+				t.Errorf("status = %d, code = %d, want 500/500", w.Code, resp.Code)
+			}
+
+			entries := logs.FilterMessage("Panic recovered").All()
+			if len(entries) != 1 {
+				t.Fatalf("expected one panic log entry, got %d", len(entries))
+			}
+			fields := entries[0].ContextMap()
+			if got, _ := fields["error"].(string); !strings.HasPrefix(got, tt.wantError) {
+				t.Errorf("error = %q, want prefix %q", got, tt.wantError)
+			}
+			if stack, _ := fields["stack"].(string); !strings.Contains(stack, "goroutine") || !strings.Contains(stack, "recovery_test.go") {
+				t.Errorf("stack does not point at the panicking handler:\n%s", stack)
+			}
+			if fields["path"] != "/panic" || fields["method"] != http.MethodGet {
+				t.Errorf("path/method = %v %v, want GET /panic", fields["method"], fields["path"])
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"k-admin-system/model/common"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newSizeLimitRouter 返回限制请求体为 maxBytes 的路由，处理函数按常规方式绑定JSON，绑定失败返回400
+func newSizeLimitRouter(maxBytes int64) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(RequestSizeLimit(maxBytes))
+	r.POST("/echo", func(c *gin.Context) {
+		var req struct {
+			Name string `json:"name"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			common.FailWithStatus(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		common.OkWithData(c, req.Name)
+	})
+	return r
+}
+
+// postJSON 发送JSON请求，chunked 为 true 时不声明 Content-Length
+func postJSON(r http.Handler, body string, chunked bool) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/echo", io.NopCloser(strings.NewReader(body)))
+	req.Header.Set("Content-Type", "application/json")
+	if chunked {
+		req.ContentLength = -1
+	} else {
+		req.ContentLength = int64(len(body))
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestRequestSizeLimit(t *testing.T) {
+	const limit = 64
+	small := `{"name":"alice"}`
+	large := `{"name":"` + strings.Repeat("a", 2*limit) + `"}`
+
+	tests := []struct {
+		name    string
+		body    string
+		chunked bool
+		want    int
+	}{
+		{"content length within limit", small, false, http.StatusOK},
+		{"content length over limit", large, false, http.StatusRequestEntityTooLarge},
+		{"chunked within limit", small, true, http.StatusOK},
+		{"chunked over limit", large, true, http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := postJSON(newSizeLimitRouter(limit), tt.body, tt.chunked)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d (body %s)", w.Code, tt.want, w.Body.String())
+			}
+
+			// 响应体只包含一个JSON对象，处理函数的400响应已被丢弃
+			var resp common.Response
+			decoder := json.NewDecoder(bytes.NewReader(w.Body.Bytes()))
+			if err := decoder.Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if decoder.More() {
+				t.Errorf("response contains more than one JSON object: %s", w.Body.String())
+			}
+			if tt.want == http.StatusOK && resp.Data != "alice" {
+				t.Errorf("data = %v, want alice", resp.Data)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"k-admin-system/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SecureHeaders 安全响应头中间件
+// 为每个响应设置安全相关的HTTP响应头，降低点击劫持、MIME嗅探和XSS等风险
+//
+// 使用示例:
+//
+//	router.Use(middleware.SecureHeaders(global.Config.Security))
+//
+// 配置示例 (config.yaml):
+//
+//	security:
+//	  csp: "default-src 'self'"  # 为空时不设置Content-Security-Policy
+func SecureHeaders(securityConfig config.SecurityConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// 禁止浏览器进行MIME类型嗅探
+		c.Header("X-Content-Type-Options", "nosniff")
+
+		// 禁止页面被嵌入iframe
+		c.Header("X-Frame-Options", "DENY")
+
+		// 启用浏览器内置的XSS过滤
+		c.Header("X-XSS-Protection", "1; mode=block")
+
+		// 跨域请求时仅发送源信息
+		c.Header("Referrer-Policy", "strict-origin-when-cross-origin")
+
+		// 设置内容安全策略
+		if securityConfig.CSP != "" {
+			c.Header("Content-Security-Policy", securityConfig.CSP)
+		}
+
+		c.Next()
+	}
+}
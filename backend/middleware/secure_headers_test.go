@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"k-admin-system/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestSecureHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	want := map[string]string{
+		"X-Content-Type-Options": "nosniff",
+		"X-Frame-Options":        "DENY",
+		"X-XSS-Protection":       "1; mode=block",
+		"Referrer-Policy":        "strict-origin-when-cross-origin",
+	}
+
+	tests := []struct {
+		name    string
+		csp     string
+		wantCSP string
+	}{
+		{"with CSP", "default-src 'self'", "default-src 'self'"},
+		{"without CSP", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(SecureHeaders(config.SecurityConfig{CSP: tt.csp}))
+			r.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+
+			for header, value := range want {
+				if got := w.Header().Get(header); got != value {
+					t.Errorf("%s = %q, want %q", header, got, value)
+				}
+			}
+			if got := w.Header().Get("Content-Security-Policy"); got != tt.wantCSP {
+				t.Errorf("Content-Security-Policy = %q, want %q", got, tt.wantCSP)
+			}
+		})
+	}
+}
@@ -0,0 +1,9 @@
+package common
+
+// PagedResponse 统一分页列表响应结构
+type PagedResponse[T any] struct {
+	List     []T   `json:"list"`
+	Total    int64 `json:"total"`
+	Page     int   `json:"page"`
+	PageSize int   `json:"pageSize"`
+}
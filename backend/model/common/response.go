@@ -1,6 +1,7 @@
 package common
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -42,6 +43,8 @@ func OkWithDetailed(c *gin.Context, data interface{}, msg string) {
 
 // Fail 失败响应
 func Fail(c *gin.Context, msg string) {
+	// 记录到上下文，供操作日志等后续处理读取失败原因
+	_ = c.Error(errors.New(msg))
 	c.JSON(http.StatusOK, Response{
 		Code: 1,
 		Data: nil,
@@ -49,11 +52,52 @@ func Fail(c *gin.Context, msg string) {
 	})
 }
 
+// FailWithDetailed 失败响应带数据
+func FailWithDetailed(c *gin.Context, data interface{}, msg string) {
+	_ = c.Error(errors.New(msg))
+	c.JSON(http.StatusOK, Response{
+		Code: 1,
+		Data: data,
+		Msg:  msg,
+	})
+}
+
 // FailWithCode 失败响应带错误码
 func FailWithCode(c *gin.Context, code int, msg string) {
+	_ = c.Error(errors.New(msg))
+	c.JSON(http.StatusOK, Response{
+		Code: code,
+		Data: nil,
+		Msg:  msg,
+	})
+}
+
+// FailWithCodeDetailed 失败响应带错误码和数据
+func FailWithCodeDetailed(c *gin.Context, code int, data interface{}, msg string) {
+	_ = c.Error(errors.New(msg))
 	c.JSON(http.StatusOK, Response{
 		Code: code,
+		Data: data,
+		Msg:  msg,
+	})
+}
+
+// FailWithStatus 失败响应并设置HTTP状态码，响应体中的 code 与HTTP状态码一致
+func FailWithStatus(c *gin.Context, httpStatus int, msg string) {
+	_ = c.Error(errors.New(msg))
+	c.JSON(httpStatus, Response{
+		Code: httpStatus,
 		Data: nil,
 		Msg:  msg,
 	})
 }
+
+// FailWithStatusDetailed 失败响应带数据并设置HTTP状态码，响应体中的 code 与HTTP状态码一致
+func FailWithStatusDetailed(c *gin.Context, httpStatus int, data interface{}, msg string) {
+	_ = c.Error(errors.New(msg))
+	c.JSON(httpStatus, Response{
+		Code: httpStatus,
+		Data: data,
+		Msg:  msg,
+	})
+}
@@ -0,0 +1,32 @@
+package common
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestFailWithStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, status := range []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		FailWithStatus(c, status, "failed")
+
+		var resp Response
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		// HTTP状态码与响应体中的 code 一致
+		if w.Code != status || resp.Code != status {
+			t.Errorf("FailWithStatus(%d): status = %d, code = %d", status, w.Code, resp.Code)
+		}
+		if resp.Msg != "failed" || len(c.Errors) != 1 {
+			t.Errorf("FailWithStatus(%d): msg = %q, context errors = %v", status, resp.Msg, c.Errors)
+		}
+	}
+}
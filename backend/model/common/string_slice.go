@@ -0,0 +1,52 @@
+package common
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+)
+
+// StringSlice 字符串切片类型，以JSON数组形式存储在数据库中
+type StringSlice []string
+
+// Scan 实现 sql.Scanner 接口
+func (s *StringSlice) Scan(value interface{}) error {
+	if value == nil {
+		*s = StringSlice{}
+		return nil
+	}
+
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New("failed to unmarshal StringSlice value")
+	}
+
+	if len(bytes) == 0 {
+		*s = StringSlice{}
+		return nil
+	}
+	return json.Unmarshal(bytes, s)
+}
+
+// Value 实现 driver.Valuer 接口
+func (s StringSlice) Value() (driver.Value, error) {
+	// nil 切片存储为空数组，避免写入 JSON null
+	if s == nil {
+		return "[]", nil
+	}
+	bytes, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	return string(bytes), nil
+}
+
+// GormDataType 指定Gorm数据类型
+func (StringSlice) GormDataType() string {
+	return "json"
+}
@@ -0,0 +1,23 @@
+package system
+
+import (
+	"time"
+
+	"k-admin-system/model/common"
+)
+
+// SysAPIKey 用户API密钥
+// 供机器账号在服务间调用时代替JWT令牌使用，只保存密钥的SHA-256哈希
+type SysAPIKey struct {
+	common.BaseModel
+	Name      string     `gorm:"type:varchar(100);not null" json:"name"`
+	HashedKey string     `gorm:"type:char(64);uniqueIndex;not null" json:"-"`
+	UserID    uint       `gorm:"index;not null" json:"userId"`
+	ExpiresAt *time.Time `json:"expiresAt"` // 为空表示永不过期
+	Active    bool       `gorm:"default:true" json:"active"`
+}
+
+// TableName 指定表名
+func (SysAPIKey) TableName() string {
+	return "sys_api_keys"
+}
@@ -0,0 +1,25 @@
+package system
+
+import (
+	"k-admin-system/model/common"
+)
+
+// SysAuditLog 审计日志
+// 记录每个API请求的操作者、请求信息和处理结果，用于事后追溯
+type SysAuditLog struct {
+	common.BaseModel
+	UserID     uint   `gorm:"index" json:"userId"` // 未登录请求为0
+	Username   string `gorm:"type:varchar(50)" json:"username"`
+	Method     string `gorm:"type:varchar(10);index" json:"method"`
+	Path       string `gorm:"type:varchar(255)" json:"path"`
+	Query      string `gorm:"type:varchar(1024)" json:"query"`
+	StatusCode int    `json:"statusCode"`
+	Latency    int64  `json:"latency"` // 毫秒
+	ClientIP   string `gorm:"type:varchar(64)" json:"clientIp"`
+	UserAgent  string `gorm:"type:varchar(255)" json:"userAgent"`
+}
+
+// TableName 指定表名
+func (SysAuditLog) TableName() string {
+	return "sys_audit_logs"
+}
@@ -0,0 +1,18 @@
+package system
+
+import "time"
+
+// SysCasbinSeed 已写入过的内置 Casbin 种子策略
+// 每条种子策略只在首次出现时写入一次，管理员之后撤销的策略不会在重启时被重新添加
+type SysCasbinSeed struct {
+	ID       uint      `gorm:"primarykey;autoIncrement" json:"id"`
+	V0       string    `gorm:"size:100;uniqueIndex:idx_casbin_seed" json:"v0"` // 角色标识
+	V1       string    `gorm:"size:100;uniqueIndex:idx_casbin_seed" json:"v1"` // 路径
+	V2       string    `gorm:"size:100;uniqueIndex:idx_casbin_seed" json:"v2"` // 方法
+	SeededAt time.Time `gorm:"not null" json:"seededAt"`
+}
+
+// TableName 指定表名
+func (SysCasbinSeed) TableName() string {
+	return "sys_casbin_seeds"
+}
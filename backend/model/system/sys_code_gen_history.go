@@ -0,0 +1,22 @@
+package system
+
+import (
+	"time"
+)
+
+// SysCodeGenHistory 代码生成历史
+// 记录每次代码生成写入磁盘的表、结构体和生成的文件，删除记录不会删除已生成的文件
+type SysCodeGenHistory struct {
+	ID             uint      `gorm:"primarykey" json:"id"`
+	GenTableName   string    `gorm:"column:table_name;type:varchar(64);index" json:"tableName"` // 字段名避免与 TableName 方法冲突
+	StructName     string    `gorm:"type:varchar(64)" json:"structName"`
+	PackageName    string    `gorm:"type:varchar(64)" json:"packageName"`
+	GeneratedBy    string    `gorm:"type:varchar(50)" json:"generatedBy"`
+	FilesGenerated []string  `gorm:"type:json;serializer:json" json:"filesGenerated"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (SysCodeGenHistory) TableName() string {
+	return "sys_code_gen_histories"
+}
@@ -0,0 +1,20 @@
+package system
+
+import (
+	"k-admin-system/model/common"
+)
+
+// SysConfig 系统动态配置
+// 以键值对形式保存可在运行时修改的配置（如站点标题、维护模式），修改后无需重启
+type SysConfig struct {
+	common.BaseModel
+	Key         string `gorm:"column:config_key;type:varchar(100);uniqueIndex;not null" json:"key"` // key 为 MySQL 保留字，列名使用 config_key
+	Value       string `gorm:"type:text" json:"value"`
+	Category    string `gorm:"type:varchar(50);index" json:"category"`
+	Description string `gorm:"type:varchar(255)" json:"description"`
+}
+
+// TableName 指定表名
+func (SysConfig) TableName() string {
+	return "sys_configs"
+}
@@ -10,10 +10,10 @@ import (
 
 // MenuMeta 菜单元数据
 type MenuMeta struct {
-	Icon      string `json:"icon"`
-	Title     string `json:"title"`
-	Hidden    bool   `json:"hidden"`
-	KeepAlive bool   `json:"keep_alive"`
+	Icon      string `json:"icon" yaml:"icon"`
+	Title     string `json:"title" yaml:"title"`
+	Hidden    bool   `json:"hidden" yaml:"hidden"`
+	KeepAlive bool   `json:"keep_alive" yaml:"keep_alive"`
 }
 
 // Scan 实现 sql.Scanner 接口
@@ -36,15 +36,15 @@ func (m MenuMeta) Value() (driver.Value, error) {
 // SysMenu 系统菜单模型
 type SysMenu struct {
 	common.BaseModel
-	ParentID  uint      `gorm:"default:0" json:"parentId"`
-	Path      string    `gorm:"type:varchar(100)" json:"path"`
-	Name      string    `gorm:"type:varchar(50)" json:"name"`
-	Component string    `gorm:"type:varchar(100)" json:"component"`
-	Sort      int       `gorm:"default:0" json:"sort"`
-	Meta      MenuMeta  `gorm:"type:json;serializer:json" json:"meta"`
-	BtnPerms  []string  `gorm:"type:json;serializer:json" json:"btn_perms"`
-	Children  []SysMenu `gorm:"-" json:"children,omitempty"`
-	Roles     []SysRole `gorm:"many2many:sys_role_menus;" json:"-"`
+	ParentID  uint               `gorm:"default:0" json:"parentId"`
+	Path      string             `gorm:"type:varchar(100)" json:"path"`
+	Name      string             `gorm:"type:varchar(50)" json:"name"`
+	Component string             `gorm:"type:varchar(100)" json:"component"`
+	Sort      int                `gorm:"default:0" json:"sort"`
+	Meta      MenuMeta           `gorm:"type:json;serializer:json" json:"meta"`
+	BtnPerms  common.StringSlice `gorm:"type:json" json:"btn_perms"`
+	Children  []SysMenu          `gorm:"-" json:"children,omitempty"`
+	Roles     []SysRole          `gorm:"many2many:sys_role_menus;" json:"-"`
 }
 
 // TableName 指定表名
@@ -0,0 +1,49 @@
+package system
+
+import (
+	"reflect"
+	"testing"
+
+	"k-admin-system/model/common"
+)
+
+func TestSysMenu_BtnPermsRoundTrip(t *testing.T) {
+	db := openUserTestDB(t)
+	if err := db.AutoMigrate(&SysMenu{}); err != nil {
+		t.Fatalf("failed to migrate menus: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		perms   common.StringSlice
+		wantRaw string
+		want    common.StringSlice
+	}{
+		{"two permissions", common.StringSlice{"a", "b"}, `["a","b"]`, common.StringSlice{"a", "b"}},
+		{"nil stored as empty array", nil, `[]`, common.StringSlice{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			menu := SysMenu{Path: "/" + tt.name, Name: tt.name, BtnPerms: tt.perms}
+			if err := db.Create(&menu).Error; err != nil {
+				t.Fatalf("failed to create menu: %v", err)
+			}
+
+			var raw string
+			if err := db.Table("sys_menus").Select("btn_perms").Where("id = ?", menu.ID).Scan(&raw).Error; err != nil {
+				t.Fatalf("failed to read raw column: %v", err)
+			}
+			if raw != tt.wantRaw {
+				t.Errorf("stored btn_perms = %s, want %s", raw, tt.wantRaw)
+			}
+
+			var loaded SysMenu
+			if err := db.First(&loaded, menu.ID).Error; err != nil {
+				t.Fatalf("failed to reload menu: %v", err)
+			}
+			if !reflect.DeepEqual(loaded.BtnPerms, tt.want) {
+				t.Errorf("BtnPerms = %#v, want %#v", loaded.BtnPerms, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package system
+
+import "time"
+
+// SysMigrationVersion 数据库迁移版本记录
+// 每个迁移版本执行后写入一行，用于排查表结构漂移
+type SysMigrationVersion struct {
+	ID             uint      `gorm:"primarykey;autoIncrement" json:"id"`
+	Version        string    `gorm:"size:100;uniqueIndex;not null" json:"version"`
+	ExecutedAt     time.Time `gorm:"not null" json:"executedAt"`
+	Checksum       string    `gorm:"size:64;not null" json:"checksum"` // 迁移模型结构体名称的SHA256
+	SchemaChecksum string    `gorm:"size:64" json:"schemaChecksum"`    // 模型定义与实际表结构的SHA256，未变化时跳过建表迁移
+}
+
+// TableName 指定表名
+func (SysMigrationVersion) TableName() string {
+	return "sys_migration_versions"
+}
@@ -0,0 +1,32 @@
+package system
+
+import (
+	"time"
+)
+
+// SysNotification 系统通知
+// 管理员广播的站内通知，每个接收用户对应一条 SysUserNotification 记录
+type SysNotification struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	Title     string    `gorm:"type:varchar(200);not null" json:"title"`
+	Content   string    `gorm:"type:text" json:"content"`
+	CreatedBy uint      `gorm:"index" json:"createdBy"` // 发布通知的用户ID
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (SysNotification) TableName() string {
+	return "sys_notifications"
+}
+
+// SysUserNotification 用户通知接收记录
+type SysUserNotification struct {
+	NotificationID uint       `gorm:"primaryKey" json:"notificationId"`
+	UserID         uint       `gorm:"primaryKey;index" json:"userId"`
+	ReadAt         *time.Time `json:"readAt"` // 已读时间，为空表示未读
+}
+
+// TableName 指定表名
+func (SysUserNotification) TableName() string {
+	return "sys_user_notifications"
+}
@@ -0,0 +1,24 @@
+package system
+
+import (
+	"k-admin-system/model/common"
+)
+
+// SysOperationLog 操作日志
+// 与记录所有请求的审计日志不同，只记录通过认证后处理函数层面的业务操作及其结果
+type SysOperationLog struct {
+	common.BaseModel
+	UserID   uint   `gorm:"index" json:"userId"` // 未登录操作（如登录）为0
+	Method   string `gorm:"type:varchar(10)" json:"method"`
+	Path     string `gorm:"type:varchar(255)" json:"path"`
+	Module   string `gorm:"type:varchar(50);index" json:"module"`
+	Action   string `gorm:"type:varchar(50);index" json:"action"`
+	Duration int64  `json:"duration"` // 毫秒
+	Success  bool   `gorm:"index" json:"success"`
+	ErrorMsg string `gorm:"type:varchar(512)" json:"errorMsg"`
+}
+
+// TableName 指定表名
+func (SysOperationLog) TableName() string {
+	return "sys_operation_logs"
+}
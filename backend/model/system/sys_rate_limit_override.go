@@ -0,0 +1,19 @@
+package system
+
+import (
+	"k-admin-system/model/common"
+)
+
+// SysRateLimitOverride 用户级限流覆盖配置
+// 为指定用户设置独立的限流参数，优先级高于全局限流配置
+type SysRateLimitOverride struct {
+	common.BaseModel
+	UserID        uint `gorm:"uniqueIndex;not null" json:"userId"`
+	Requests      int  `gorm:"not null" json:"requests"`
+	WindowSeconds int  `gorm:"not null" json:"windowSeconds"`
+}
+
+// TableName 指定表名
+func (SysRateLimitOverride) TableName() string {
+	return "sys_rate_limit_overrides"
+}
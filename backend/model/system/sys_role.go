@@ -1,23 +1,216 @@
 package system
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
 	"k-admin-system/model/common"
 )
 
 // SysRole 系统角色模型
 type SysRole struct {
 	common.BaseModel
-	RoleName  string    `gorm:"type:varchar(50);not null" json:"roleName"`
-	RoleKey   string    `gorm:"type:varchar(50);uniqueIndex;not null" json:"roleKey"`
-	DataScope string    `gorm:"type:varchar(20);default:'all'" json:"dataScope"`
-	Sort      int       `gorm:"default:0" json:"sort"`
-	Status    bool      `gorm:"default:true" json:"status"`
-	Remark    string    `gorm:"type:varchar(255)" json:"remark"`
-	Users     []SysUser `gorm:"foreignKey:RoleID" json:"-"`
-	Menus     []SysMenu `gorm:"many2many:sys_role_menus;" json:"-"`
+	RoleName          string    `gorm:"type:varchar(50);not null" json:"roleName"`
+	RoleKey           string    `gorm:"type:varchar(50);uniqueIndex;not null" json:"roleKey"`
+	DataScope         string    `gorm:"type:varchar(20);default:'all'" json:"dataScope"`
+	Sort              int       `gorm:"default:0" json:"sort"`
+	Status            bool      `gorm:"default:true" json:"status"`
+	Remark            string    `gorm:"type:varchar(255)" json:"remark"`
+	DBInspectorFilter string    `gorm:"type:text" json:"dbInspectorFilter"` // 数据库检查器行级过滤，JSON格式 {"表名": "age > 18 AND status = 'active'"}，为空表示不过滤
+	Users             []SysUser `gorm:"foreignKey:RoleID" json:"-"`
+	Menus             []SysMenu `gorm:"many2many:sys_role_menus;" json:"-"`
 }
 
 // TableName 指定表名
 func (SysRole) TableName() string {
 	return "sys_roles"
 }
+
+// RowCondition 数据库检查器行级过滤中的单个条件
+type RowCondition struct {
+	Column   string      // 列名
+	Operator string      // 比较运算符，或 IS NULL / IS NOT NULL
+	Value    interface{} // 字符串、int64 或 float64；IS NULL / IS NOT NULL 时为 nil
+}
+
+// rowFilterOperators 行级过滤支持的比较运算符，按长度优先匹配
+var rowFilterOperators = []string{">=", "<=", "!=", "<>", "=", ">", "<"}
+
+// DBInspectorFilters 解析数据库检查器行级过滤配置，返回表名到过滤条件的映射
+// 每个表的条件只支持 "列 运算符 值" 用 AND 连接的形式（如 age > 18 AND status = 'active'），
+// 值为数字或单引号字符串（” 表示单引号）；解析结果以参数绑定方式查询，不会拼接进SQL
+func (r SysRole) DBInspectorFilters() (map[string][]RowCondition, error) {
+	filters := map[string][]RowCondition{}
+	if strings.TrimSpace(r.DBInspectorFilter) == "" {
+		return filters, nil
+	}
+
+	var raw map[string]string
+	if err := json.Unmarshal([]byte(r.DBInspectorFilter), &raw); err != nil {
+		return nil, fmt.Errorf("invalid DB inspector filter: %w", err)
+	}
+	for table, expr := range raw {
+		if strings.TrimSpace(expr) == "" {
+			continue
+		}
+		conditions, err := parseRowFilter(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DB inspector filter for table %s: %w", table, err)
+		}
+		filters[table] = conditions
+	}
+
+	return filters, nil
+}
+
+// parseRowFilter 将过滤表达式解析为条件列表
+func parseRowFilter(expr string) ([]RowCondition, error) {
+	p := &rowFilterParser{input: expr}
+	var conditions []RowCondition
+	for {
+		p.skipSpaces()
+		cond, err := p.condition()
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, cond)
+
+		p.skipSpaces()
+		if p.done() {
+			return conditions, nil
+		}
+		if !p.keyword("AND") {
+			return nil, fmt.Errorf("unexpected %q, only AND-joined conditions are supported", p.rest())
+		}
+	}
+}
+
+// rowFilterParser 行级过滤表达式解析器
+type rowFilterParser struct {
+	input string
+	pos   int
+}
+
+func (p *rowFilterParser) done() bool { return p.pos >= len(p.input) }
+
+func (p *rowFilterParser) rest() string { return p.input[p.pos:] }
+
+func (p *rowFilterParser) skipSpaces() {
+	for !p.done() && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+}
+
+// keyword 匹配不区分大小写的关键字，关键字之后必须是空白或输入结束
+func (p *rowFilterParser) keyword(word string) bool {
+	end := p.pos + len(word)
+	if end > len(p.input) || !strings.EqualFold(p.input[p.pos:end], word) {
+		return false
+	}
+	if end < len(p.input) && !unicode.IsSpace(rune(p.input[end])) {
+		return false
+	}
+	p.pos = end
+	return true
+}
+
+// condition 解析 "列 运算符 值" 或 "列 IS [NOT] NULL"
+func (p *rowFilterParser) condition() (RowCondition, error) {
+	column := p.identifier()
+	if column == "" {
+		return RowCondition{}, fmt.Errorf("expected column name at %q", p.rest())
+	}
+	p.skipSpaces()
+
+	if p.keyword("IS") {
+		p.skipSpaces()
+		operator := "IS NULL"
+		if p.keyword("NOT") {
+			p.skipSpaces()
+			operator = "IS NOT NULL"
+		}
+		if !p.keyword("NULL") {
+			return RowCondition{}, fmt.Errorf("expected NULL at %q", p.rest())
+		}
+		return RowCondition{Column: column, Operator: operator}, nil
+	}
+
+	operator := ""
+	for _, op := range rowFilterOperators {
+		if strings.HasPrefix(p.rest(), op) {
+			operator = op
+			break
+		}
+	}
+	if operator == "" {
+		return RowCondition{}, fmt.Errorf("expected comparison operator at %q", p.rest())
+	}
+	p.pos += len(operator)
+	p.skipSpaces()
+
+	value, err := p.literal()
+	if err != nil {
+		return RowCondition{}, err
+	}
+	return RowCondition{Column: column, Operator: operator, Value: value}, nil
+}
+
+// identifier 解析由字母、数字和下划线组成且不以数字开头的列名
+func (p *rowFilterParser) identifier() string {
+	start := p.pos
+	for !p.done() {
+		ch := p.input[p.pos]
+		if ch == '_' || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || (p.pos > start && ch >= '0' && ch <= '9') {
+			p.pos++
+			continue
+		}
+		break
+	}
+	return p.input[start:p.pos]
+}
+
+// literal 解析单引号字符串或数字
+func (p *rowFilterParser) literal() (interface{}, error) {
+	if p.done() {
+		return nil, errors.New("expected value at end of filter")
+	}
+
+	if p.input[p.pos] == '\'' {
+		var sb strings.Builder
+		for i := p.pos + 1; i < len(p.input); i++ {
+			if p.input[i] != '\'' {
+				sb.WriteByte(p.input[i])
+				continue
+			}
+			if i+1 < len(p.input) && p.input[i+1] == '\'' {
+				sb.WriteByte('\'')
+				i++
+				continue
+			}
+			p.pos = i + 1
+			return sb.String(), nil
+		}
+		return nil, errors.New("unterminated string literal")
+	}
+
+	start := p.pos
+	if p.input[p.pos] == '-' {
+		p.pos++
+	}
+	for !p.done() && (p.input[p.pos] == '.' || (p.input[p.pos] >= '0' && p.input[p.pos] <= '9')) {
+		p.pos++
+	}
+	number := p.input[start:p.pos]
+	if n, err := strconv.ParseInt(number, 10, 64); err == nil {
+		return n, nil
+	}
+	if f, err := strconv.ParseFloat(number, 64); err == nil {
+		return f, nil
+	}
+	p.pos = start
+	return nil, fmt.Errorf("expected number or quoted string at %q", p.rest())
+}
@@ -0,0 +1,41 @@
+package system
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDBInspectorFilters_ParsesConditions(t *testing.T) {
+	role := SysRole{DBInspectorFilter: `{"people": "age > 18 and name != 'O''Brien' AND email IS NOT NULL AND score <= -1.5"}`}
+
+	filters, err := role.DBInspectorFilters()
+	if err != nil {
+		t.Fatalf("DBInspectorFilters() error = %v", err)
+	}
+	want := []RowCondition{
+		{Column: "age", Operator: ">", Value: int64(18)},
+		{Column: "name", Operator: "!=", Value: "O'Brien"},
+		{Column: "email", Operator: "IS NOT NULL"},
+		{Column: "score", Operator: "<=", Value: -1.5},
+	}
+	if !reflect.DeepEqual(filters["people"], want) {
+		t.Errorf("DBInspectorFilters() = %#v, want %#v", filters["people"], want)
+	}
+}
+
+func TestDBInspectorFilters_RejectsRawSQL(t *testing.T) {
+	for _, expr := range []string{
+		"age > 18) OR (1=1",
+		"age > 18 OR 1=1",
+		"age > 18 -- comment",
+		"age > 18 /* comment */",
+		"age > (SELECT 1)",
+		"name = 'unterminated",
+		"age > 18; DROP TABLE people",
+	} {
+		role := SysRole{DBInspectorFilter: `{"people": "` + expr + `"}`}
+		if _, err := role.DBInspectorFilters(); err == nil {
+			t.Errorf("DBInspectorFilters(%q) succeeded, want error", expr)
+		}
+	}
+}
@@ -1,24 +1,95 @@
 package system
 
 import (
+	"time"
+
 	"k-admin-system/model/common"
+	"k-admin-system/utils"
+
+	"gorm.io/gorm"
 )
 
 // SysUser 系统用户模型
 type SysUser struct {
 	common.BaseModel
-	Username  string   `gorm:"type:varchar(50);uniqueIndex;not null" json:"username"`
-	Password  string   `gorm:"type:varchar(255);not null" json:"-"`
-	Nickname  string   `gorm:"type:varchar(50)" json:"nickname"`
-	HeaderImg string   `gorm:"type:varchar(255)" json:"headerImg"`
-	Phone     string   `gorm:"type:varchar(20)" json:"phone"`
-	Email     string   `gorm:"type:varchar(100)" json:"email"`
-	RoleID    uint     `gorm:"not null" json:"roleId"`
-	Role      *SysRole `gorm:"foreignKey:RoleID" json:"role,omitempty"`
-	Active    bool     `gorm:"default:true" json:"active"`
+	Username            string     `gorm:"type:varchar(50);uniqueIndex;not null" json:"username"`
+	Password            string     `gorm:"type:varchar(255);not null" json:"-"`
+	Nickname            string     `gorm:"type:varchar(50)" json:"nickname"`
+	HeaderImg           string     `gorm:"type:varchar(255)" json:"headerImg"`
+	Phone               string     `gorm:"type:varchar(20)" json:"phone"`
+	Email               string     `gorm:"type:varchar(100)" json:"email"`
+	RoleID              uint       `gorm:"not null" json:"roleId"`
+	Role                *SysRole   `gorm:"foreignKey:RoleID" json:"role,omitempty"`
+	Active              bool       `gorm:"default:true" json:"active"`
+	LastLoginAt         *time.Time `json:"lastLoginAt"`
+	MFASecret           string     `gorm:"type:varchar(64)" json:"-"` // TOTP密钥，系统表不通过数据库检查器暴露
+	MFAEnabled          bool       `gorm:"default:false" json:"mfaEnabled"`
+	FailedLoginAttempts int        `gorm:"default:0" json:"failedLoginAttempts"` // 连续登录失败次数
+	LockedUntil         *time.Time `json:"lockedUntil"`                          // 锁定截止时间
+	PasswordChangedAt   *time.Time `json:"passwordChangedAt"`                    // 最近一次设置密码的时间，为空表示未记录
 }
 
 // TableName 指定表名
 func (SysUser) TableName() string {
 	return "sys_users"
 }
+
+// BeforeCreate 创建用户时记录密码设置时间，未显式指定时取当前时间
+func (u *SysUser) BeforeCreate(tx *gorm.DB) error {
+	if u.PasswordChangedAt == nil {
+		now := time.Now()
+		u.PasswordChangedAt = &now
+	}
+	return nil
+}
+
+// BeforeUpdate 仅在密码发生变更时刷新密码设置时间
+// 适用于 Model(&user).Updates(...) 形式的更新；Save 整体保存时由调用方自行维护该字段
+func (u *SysUser) BeforeUpdate(tx *gorm.DB) error {
+	if tx.Statement.Changed("Password") {
+		tx.Statement.SetColumn("PasswordChangedAt", time.Now())
+	}
+	return nil
+}
+
+// Sanitize 返回用于API响应的用户副本：清空密码并对邮箱、手机号脱敏
+func (u SysUser) Sanitize() SysUser {
+	u.Password = ""
+	u.Email = utils.MaskEmail(u.Email)
+	u.Phone = utils.MaskPhone(u.Phone)
+	return u
+}
+
+// InTimezone 返回时间字段转换到指定时区后的用户副本，用于API响应
+func (u SysUser) InTimezone(tz string) SysUser {
+	u.CreatedAt = utils.ConvertToTimezone(u.CreatedAt, tz)
+	u.UpdatedAt = utils.ConvertToTimezone(u.UpdatedAt, tz)
+	if u.LastLoginAt != nil {
+		lastLoginAt := utils.ConvertToTimezone(*u.LastLoginAt, tz)
+		u.LastLoginAt = &lastLoginAt
+	}
+	if u.LockedUntil != nil {
+		lockedUntil := utils.ConvertToTimezone(*u.LockedUntil, tz)
+		u.LockedUntil = &lockedUntil
+	}
+	if u.PasswordChangedAt != nil {
+		passwordChangedAt := utils.ConvertToTimezone(*u.PasswordChangedAt, tz)
+		u.PasswordChangedAt = &passwordChangedAt
+	}
+	return u
+}
+
+// CreatedBetween 按创建时间范围过滤的查询作用域（包含起止时间）
+func CreatedBetween(start, end time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("created_at >= ? AND created_at <= ?", start, end)
+	}
+}
+
+// DormantSince 最近 d 时间内未登录的查询作用域
+// 从未登录过的用户（LastLoginAt 为 NULL）无论何时创建都视为休眠
+func DormantSince(d time.Duration) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("last_login_at < ? OR last_login_at IS NULL", time.Now().Add(-d))
+	}
+}
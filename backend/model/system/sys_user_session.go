@@ -0,0 +1,25 @@
+package system
+
+import (
+	"time"
+)
+
+// SysUserSession 用户登录会话
+// 每次登录创建一条记录，令牌加入黑名单（如撤销会话）时删除，用于管理员查看和撤销活跃会话
+// 只保存令牌的SHA-256哈希，撤销时按会话ID使该会话签发的全部令牌失效
+type SysUserSession struct {
+	ID               uint      `gorm:"primarykey" json:"id"`
+	UserID           uint      `gorm:"index;not null" json:"userId"`
+	SessionID        string    `gorm:"type:char(32);uniqueIndex;not null" json:"-"` // 令牌中的 sid
+	AccessTokenHash  string    `gorm:"type:char(64);index" json:"-"`
+	RefreshTokenHash string    `gorm:"type:char(64);index" json:"-"`
+	DeviceInfo       string    `gorm:"type:varchar(255)" json:"deviceInfo"` // 登录时的 User-Agent
+	IP               string    `gorm:"type:varchar(64)" json:"ip"`
+	ExpiresAt        time.Time `gorm:"index" json:"expiresAt"` // 刷新令牌过期时间
+	CreatedAt        time.Time `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (SysUserSession) TableName() string {
+	return "sys_user_sessions"
+}
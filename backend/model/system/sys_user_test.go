@@ -0,0 +1,82 @@
+package system
+
+import (
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openUserTestDB 创建包含用户表的内存SQLite数据库
+func openUserTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get sql.DB: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	if err := db.AutoMigrate(&SysRole{}, &SysUser{}); err != nil {
+		t.Fatalf("failed to migrate tables: %v", err)
+	}
+	return db
+}
+
+// reloadPasswordChangedAt 重新读取用户的密码设置时间
+func reloadPasswordChangedAt(t *testing.T, db *gorm.DB, id uint) time.Time {
+	t.Helper()
+	var user SysUser
+	if err := db.First(&user, id).Error; err != nil {
+		t.Fatalf("failed to reload user: %v", err)
+	}
+	if user.PasswordChangedAt == nil {
+		t.Fatal("PasswordChangedAt is nil")
+	}
+	return *user.PasswordChangedAt
+}
+
+func TestSysUser_BeforeCreateSetsPasswordChangedAt(t *testing.T) {
+	db := openUserTestDB(t)
+
+	user := &SysUser{Username: "alice", Password: "hash"}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	if changedAt := reloadPasswordChangedAt(t, db, user.ID); changedAt.IsZero() {
+		t.Error("PasswordChangedAt is zero after create")
+	}
+}
+
+func TestSysUser_BeforeUpdateOnlyTracksPasswordChanges(t *testing.T) {
+	db := openUserTestDB(t)
+
+	initial := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+	changedAt := initial
+	user := &SysUser{Username: "alice", Password: "hash", PasswordChangedAt: &changedAt}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	// 修改非密码字段
+	if err := db.Model(user).Updates(map[string]interface{}{"nickname": "Alice", "email": "alice@example.com"}).Error; err != nil {
+		t.Fatalf("failed to update user: %v", err)
+	}
+	if changedAt := reloadPasswordChangedAt(t, db, user.ID); !changedAt.Equal(initial) {
+		t.Errorf("PasswordChangedAt = %v after a non-password update, want %v", changedAt, initial)
+	}
+
+	// 修改密码
+	if err := db.Model(user).Updates(map[string]interface{}{"password": "new-hash"}).Error; err != nil {
+		t.Fatalf("failed to update password: %v", err)
+	}
+	if changedAt := reloadPasswordChangedAt(t, db, user.ID); !changedAt.After(initial) {
+		t.Errorf("PasswordChangedAt = %v after a password update, want later than %v", changedAt, initial)
+	}
+}
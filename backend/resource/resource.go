@@ -0,0 +1,11 @@
+package resource
+
+import (
+	_ "embed"
+)
+
+// CasbinModel Casbin RBAC模型配置
+// 通过 go:embed 编译进二进制文件，部署时无需额外携带模型配置文件
+//
+//go:embed model.conf
+var CasbinModel string
@@ -0,0 +1,24 @@
+-- Migration: create {{.TableName}}{{if .TableComment}} ({{.TableComment}}){{end}}
+-- Generated by K-Admin code generator. Safe to run more than once: the table is
+-- only created when missing, and columns are only added when they do not exist yet.
+
+CREATE TABLE IF NOT EXISTS `{{.TableName}}` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `created_at` datetime(3) DEFAULT NULL,
+  `updated_at` datetime(3) DEFAULT NULL,
+  `deleted_at` datetime(3) DEFAULT NULL,
+{{- range .Fields}}
+  {{.ColumnDef}},
+{{- end}}
+  PRIMARY KEY (`id`),
+  KEY `idx_{{.TableName}}_deleted_at` (`deleted_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+{{range .Fields}}
+-- Add `{{.ColumnName}}` to a table created before the column existed
+SET @ddl = IF((SELECT COUNT(*) FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = '{{$.TableName}}' AND COLUMN_NAME = '{{.ColumnName}}') = 0,
+  {{.AddColumnSQL}},
+  'DO 0');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+{{end -}}
@@ -0,0 +1,26 @@
+package {{.PackageName}}
+
+import (
+{{- if .NeedsTimeImport}}
+	"time"
+{{end}}
+	"{{.ModulePath}}/model/common"
+)
+{{range .Fields}}{{if .EnumDecl}}
+// {{.FieldType}} {{$.StructName}}.{{.FieldName}} 的取值
+{{.EnumDecl}}{{end}}{{end}}
+// {{.StructName}} {{if .TableComment}}{{.TableComment}}{{else}}{{.TableName}} 表模型{{end}}
+type {{.StructName}} struct {
+	common.BaseModel
+{{- range .Fields}}
+	{{.FieldName}} {{.FieldType}} `gorm:"{{.GormTag}}" json:"{{.JSONTag}}"`{{if .Comment}} // {{.Comment}}{{end}}
+{{- if .AssociationDecl}}
+	{{.AssociationDecl}}
+{{- end}}
+{{- end}}
+}
+
+// TableName 指定表名
+func ({{.StructName}}) TableName() string {
+	return "{{.TableName}}"
+}
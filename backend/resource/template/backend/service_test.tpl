@@ -0,0 +1,203 @@
+package {{.PackageName}}
+
+import (
+	"testing"
+	"testing/quick"
+{{- if .NeedsTimeImport}}
+	"time"
+{{- end}}
+
+	"{{.ModulePath}}/global"
+	"{{.ModulePath}}/model/{{.PackageName}}"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// setup{{.StructName}}TestDB 使用内存 SQLite 数据库初始化 global.DB，测试结束后恢复原值
+func setup{{.StructName}}TestDB(t *testing.T) {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	// 内存数据库只存在于单个连接中
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get sql.DB: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	if err := db.AutoMigrate(&{{.PackageName}}.{{.StructName}}{}); err != nil {
+		t.Fatalf("failed to migrate test database: %v", err)
+	}
+
+	prevDB := global.DB
+	global.DB = db
+	t.Cleanup(func() {
+		_ = sqlDB.Close()
+		global.DB = prevDB
+	})
+}
+
+// new{{.StructName}}ForTest 返回各字段均已赋值的测试记录
+func new{{.StructName}}ForTest() *{{.PackageName}}.{{.StructName}} {
+	return &{{.PackageName}}.{{.StructName}}{
+{{- range .Fields}}{{if .TestValue}}
+		{{.FieldName}}: {{.TestValue}},
+{{- end}}{{end}}
+	}
+}
+
+// assert{{.StructName}}Fields 校验记录的字段值与期望一致
+func assert{{.StructName}}Fields(t *testing.T, got, want *{{.PackageName}}.{{.StructName}}) {
+	t.Helper()
+{{- range .Fields}}{{if .TestValue}}
+{{- if eq .FieldType "time.Time"}}
+	if !got.{{.FieldName}}.Equal(want.{{.FieldName}}) {
+{{- else}}
+	if got.{{.FieldName}} != want.{{.FieldName}} {
+{{- end}}
+		t.Errorf("{{.FieldName}} = %v, want %v", got.{{.FieldName}}, want.{{.FieldName}})
+	}
+{{- end}}{{end}}
+}
+
+func TestCreate{{.StructName}}(t *testing.T) {
+	setup{{.StructName}}TestDB(t)
+	service := {{.StructName}}Service{}
+
+	{{.LowerStructName}} := new{{.StructName}}ForTest()
+	if err := service.Create{{.StructName}}({{.LowerStructName}}); err != nil {
+		t.Fatalf("Create{{.StructName}} failed: %v", err)
+	}
+	if {{.LowerStructName}}.ID == 0 {
+		t.Fatal("expected ID to be set after create")
+	}
+
+	var stored {{.PackageName}}.{{.StructName}}
+	if err := global.DB.First(&stored, {{.LowerStructName}}.ID).Error; err != nil {
+		t.Fatalf("failed to load created record: %v", err)
+	}
+	assert{{.StructName}}Fields(t, &stored, new{{.StructName}}ForTest())
+}
+
+func TestGet{{.StructName}}ByID(t *testing.T) {
+	setup{{.StructName}}TestDB(t)
+	service := {{.StructName}}Service{}
+
+	{{.LowerStructName}} := new{{.StructName}}ForTest()
+	if err := service.Create{{.StructName}}({{.LowerStructName}}); err != nil {
+		t.Fatalf("Create{{.StructName}} failed: %v", err)
+	}
+
+	found, err := service.Get{{.StructName}}ByID({{.LowerStructName}}.ID)
+	if err != nil {
+		t.Fatalf("Get{{.StructName}}ByID failed: %v", err)
+	}
+	if found.ID != {{.LowerStructName}}.ID {
+		t.Fatalf("expected ID %d, got %d", {{.LowerStructName}}.ID, found.ID)
+	}
+	assert{{.StructName}}Fields(t, found, {{.LowerStructName}})
+
+	if _, err := service.Get{{.StructName}}ByID({{.LowerStructName}}.ID + 1); err == nil {
+		t.Fatal("expected error when getting a missing record")
+	}
+}
+
+func TestGet{{.StructName}}List(t *testing.T) {
+	setup{{.StructName}}TestDB(t)
+	service := {{.StructName}}Service{}
+
+	for i := 0; i < 3; i++ {
+		if err := service.Create{{.StructName}}(new{{.StructName}}ForTest()); err != nil {
+			t.Fatalf("Create{{.StructName}} failed: %v", err)
+		}
+	}
+
+	list, total, err := service.Get{{.StructName}}List(1, 2)
+	if err != nil {
+		t.Fatalf("Get{{.StructName}}List failed: %v", err)
+	}
+	if total != 3 {
+		t.Fatalf("expected total 3, got %d", total)
+	}
+	if len(list) != 2 {
+		t.Fatalf("expected 2 records on first page, got %d", len(list))
+	}
+
+	list, _, err = service.Get{{.StructName}}List(2, 2)
+	if err != nil {
+		t.Fatalf("Get{{.StructName}}List failed: %v", err)
+	}
+	if len(list) != 1 {
+		t.Fatalf("expected 1 record on second page, got %d", len(list))
+	}
+}
+
+func TestUpdate{{.StructName}}(t *testing.T) {
+	setup{{.StructName}}TestDB(t)
+	service := {{.StructName}}Service{}
+
+	{{.LowerStructName}} := new{{.StructName}}ForTest()
+	if err := service.Create{{.StructName}}({{.LowerStructName}}); err != nil {
+		t.Fatalf("Create{{.StructName}} failed: %v", err)
+	}
+
+{{- range .Fields}}{{if .TestUpdateValue}}
+	{{$.LowerStructName}}.{{.FieldName}} = {{.TestUpdateValue}}
+{{- end}}{{end}}
+	if err := service.Update{{.StructName}}({{.LowerStructName}}); err != nil {
+		t.Fatalf("Update{{.StructName}} failed: %v", err)
+	}
+
+	updated, err := service.Get{{.StructName}}ByID({{.LowerStructName}}.ID)
+	if err != nil {
+		t.Fatalf("Get{{.StructName}}ByID failed: %v", err)
+	}
+	assert{{.StructName}}Fields(t, updated, {{.LowerStructName}})
+}
+
+func TestDelete{{.StructName}}(t *testing.T) {
+	setup{{.StructName}}TestDB(t)
+	service := {{.StructName}}Service{}
+
+	{{.LowerStructName}} := new{{.StructName}}ForTest()
+	if err := service.Create{{.StructName}}({{.LowerStructName}}); err != nil {
+		t.Fatalf("Create{{.StructName}} failed: %v", err)
+	}
+
+	if err := service.Delete{{.StructName}}({{.LowerStructName}}.ID); err != nil {
+		t.Fatalf("Delete{{.StructName}} failed: %v", err)
+	}
+	if _, err := service.Get{{.StructName}}ByID({{.LowerStructName}}.ID); err == nil {
+		t.Fatal("expected error when getting deleted record")
+	}
+}
+
+// TestProperty{{.StructName}}IDUniqueness 属性测试：任意数量的创建操作都应产生互不相同的ID
+func TestProperty{{.StructName}}IDUniqueness(t *testing.T) {
+	setup{{.StructName}}TestDB(t)
+	service := {{.StructName}}Service{}
+
+	seen := make(map[uint]bool)
+	property := func(n uint8) bool {
+		for i := 0; i < int(n%20)+1; i++ {
+			{{.LowerStructName}} := new{{.StructName}}ForTest()
+			if err := service.Create{{.StructName}}({{.LowerStructName}}); err != nil {
+				return false
+			}
+			if seen[{{.LowerStructName}}.ID] {
+				return false
+			}
+			seen[{{.LowerStructName}}.ID] = true
+		}
+		return true
+	}
+
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
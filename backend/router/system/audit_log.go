@@ -0,0 +1,22 @@
+package system
+
+import (
+	"k-admin-system/api/v1/system"
+	"k-admin-system/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InitAuditLogRouter 初始化审计日志路由
+func InitAuditLogRouter(router *gin.RouterGroup) {
+	auditLogApi := system.AuditLogApi{}
+
+	// 受保护的路由（需要JWT认证和管理员权限）
+	protectedGroup := router.Group("/system/audit-log")
+	protectedGroup.Use(middleware.APIKeyAuth(), middleware.JWTAuth(), middleware.UserRateLimit())
+	protectedGroup.Use(middleware.CasbinAuth())
+	{
+		protectedGroup.GET("", auditLogApi.GetAuditLogList)
+		protectedGroup.GET("/export", auditLogApi.ExportAuditLogs)
+	}
+}
@@ -0,0 +1,23 @@
+package system
+
+import (
+	"k-admin-system/api/v1/system"
+	"k-admin-system/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InitCasbinRouter 初始化Casbin策略管理路由
+func InitCasbinRouter(router *gin.RouterGroup) {
+	casbinApi := system.CasbinApi{}
+
+	// 受保护的路由（需要JWT认证和管理员权限）
+	protectedGroup := router.Group("/system/casbin")
+	protectedGroup.Use(middleware.APIKeyAuth(), middleware.JWTAuth(), middleware.UserRateLimit())
+	protectedGroup.Use(middleware.CasbinAuth())
+	{
+		protectedGroup.POST("/import", casbinApi.ImportPolicies)
+		protectedGroup.GET("/export", casbinApi.ExportPolicies)
+		protectedGroup.DELETE("/orphaned", casbinApi.RemoveOrphanedPolicies)
+	}
+}
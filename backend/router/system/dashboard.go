@@ -13,7 +13,7 @@ func InitDashboardRouter(router *gin.RouterGroup) {
 
 	// 受保护的路由（需要JWT认证）
 	protectedGroup := router.Group("/dashboard")
-	protectedGroup.Use(middleware.JWTAuth())
+	protectedGroup.Use(middleware.APIKeyAuth(), middleware.JWTAuth(), middleware.UserRateLimit())
 	{
 		protectedGroup.GET("/stats", dashboardApi.GetDashboardStats)
 	}
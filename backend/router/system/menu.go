@@ -13,21 +13,30 @@ func InitMenuRouter(router *gin.RouterGroup) {
 
 	// 受保护的路由（需要JWT认证和Casbin授权）
 	protectedGroup := router.Group("/menu")
-	protectedGroup.Use(middleware.JWTAuth())
+	protectedGroup.Use(middleware.APIKeyAuth(), middleware.JWTAuth(), middleware.UserRateLimit())
 	protectedGroup.Use(middleware.CasbinAuth())
 	{
 		// 菜单CRUD操作
 		protectedGroup.POST("", menuApi.CreateMenu)
 		protectedGroup.PUT("", menuApi.UpdateMenu)
+		protectedGroup.PUT("/reorder", menuApi.ReorderMenus)
 		protectedGroup.DELETE("/:id", menuApi.DeleteMenu)
+		protectedGroup.POST("/:id/restore", menuApi.RestoreMenu)
 		protectedGroup.GET("/:id", menuApi.GetMenu)
+		protectedGroup.GET("/:id/users", menuApi.GetMenuUsers)
 		protectedGroup.GET("/all", menuApi.GetAllMenus)
+		protectedGroup.GET("/by-component", menuApi.GetMenusByComponent)
+		protectedGroup.GET("/by-path", menuApi.GetMenuByPath)
+
+		// 菜单导入导出
+		protectedGroup.GET("/export", menuApi.ExportMenuTree)
+		protectedGroup.POST("/import", menuApi.ImportMenus)
 	}
 
 	// 菜单树查询（仅需要JWT认证，不需要Casbin授权）
 	// 因为该接口根据roleId过滤菜单，已经实现了权限控制
 	menuTreeGroup := router.Group("/menu")
-	menuTreeGroup.Use(middleware.JWTAuth())
+	menuTreeGroup.Use(middleware.APIKeyAuth(), middleware.JWTAuth(), middleware.UserRateLimit())
 	{
 		menuTreeGroup.GET("/tree", menuApi.GetMenuTree)
 	}
@@ -0,0 +1,21 @@
+package system
+
+import (
+	"k-admin-system/api/v1/system"
+	"k-admin-system/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InitMetricsRouter 初始化请求指标路由
+func InitMetricsRouter(router *gin.RouterGroup) {
+	metricsApi := system.MetricsApi{}
+
+	// 受保护的路由（需要JWT认证和管理员权限）
+	protectedGroup := router.Group("/system/metrics")
+	protectedGroup.Use(middleware.APIKeyAuth(), middleware.JWTAuth(), middleware.UserRateLimit())
+	protectedGroup.Use(middleware.CasbinAuth())
+	{
+		protectedGroup.GET("/latency", metricsApi.GetLatency)
+	}
+}
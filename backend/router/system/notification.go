@@ -0,0 +1,29 @@
+package system
+
+import (
+	"k-admin-system/api/v1/system"
+	"k-admin-system/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InitNotificationRouter 初始化站内通知路由
+func InitNotificationRouter(router *gin.RouterGroup) {
+	notificationApi := system.NotificationApi{}
+
+	// 通知广播（需要JWT认证和管理员权限）
+	adminGroup := router.Group("/system/notification")
+	adminGroup.Use(middleware.APIKeyAuth(), middleware.JWTAuth(), middleware.UserRateLimit())
+	adminGroup.Use(middleware.CasbinAuth())
+	{
+		adminGroup.POST("/broadcast", notificationApi.Broadcast)
+	}
+
+	// 当前用户的通知（需要JWT认证）
+	userGroup := router.Group("/user")
+	userGroup.Use(middleware.APIKeyAuth(), middleware.JWTAuth(), middleware.UserRateLimit())
+	{
+		userGroup.GET("/notifications", notificationApi.GetUnreadNotifications)
+		userGroup.PATCH("/notification/:id/read", notificationApi.MarkAsRead)
+	}
+}
@@ -0,0 +1,21 @@
+package system
+
+import (
+	"k-admin-system/api/v1/system"
+	"k-admin-system/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InitOperationLogRouter 初始化操作日志路由
+func InitOperationLogRouter(router *gin.RouterGroup) {
+	operationLogApi := system.OperationLogApi{}
+
+	// 受保护的路由（需要JWT认证和管理员权限）
+	protectedGroup := router.Group("/system/operation-log")
+	protectedGroup.Use(middleware.APIKeyAuth(), middleware.JWTAuth(), middleware.UserRateLimit())
+	protectedGroup.Use(middleware.CasbinAuth())
+	{
+		protectedGroup.GET("", operationLogApi.GetOperationLogList)
+	}
+}
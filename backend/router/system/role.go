@@ -13,19 +13,23 @@ func InitRoleRouter(router *gin.RouterGroup) {
 
 	// 受保护的路由（需要JWT认证和管理员权限）
 	protectedGroup := router.Group("/role")
-	protectedGroup.Use(middleware.JWTAuth())
+	protectedGroup.Use(middleware.APIKeyAuth(), middleware.JWTAuth(), middleware.UserRateLimit())
 	protectedGroup.Use(middleware.CasbinAuth())
 	{
 		// 角色CRUD操作
 		protectedGroup.POST("", roleApi.CreateRole)
+		protectedGroup.POST("/create-full", roleApi.CreateRoleFull)
 		protectedGroup.PUT("", roleApi.UpdateRole)
+		protectedGroup.PATCH("/:id/remark", roleApi.PatchRoleRemark)
 		protectedGroup.DELETE("/:id", roleApi.DeleteRole)
 		protectedGroup.GET("/:id", roleApi.GetRole)
 		protectedGroup.GET("/list", roleApi.GetRoleList)
 
 		// 权限分配
 		protectedGroup.POST("/assign-menus", roleApi.AssignMenus)
+		protectedGroup.POST("/bulk-assign-menus", roleApi.BulkAssignMenus)
 		protectedGroup.GET("/:id/menus", roleApi.GetRoleMenus)
+		protectedGroup.GET("/:id/menu-tree", roleApi.GetRoleMenuTree)
 		protectedGroup.POST("/assign-apis", roleApi.AssignAPIs)
 		protectedGroup.GET("/:id/apis", roleApi.GetRoleAPIs)
 	}
@@ -0,0 +1,22 @@
+package system
+
+import (
+	"k-admin-system/api/v1/system"
+	"k-admin-system/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InitSysConfigRouter 初始化系统动态配置路由
+func InitSysConfigRouter(router *gin.RouterGroup) {
+	sysConfigApi := system.SysConfigApi{}
+
+	// 受保护的路由（需要JWT认证和管理员权限）
+	protectedGroup := router.Group("/system/config")
+	protectedGroup.Use(middleware.APIKeyAuth(), middleware.JWTAuth(), middleware.UserRateLimit())
+	protectedGroup.Use(middleware.CasbinAuth())
+	{
+		protectedGroup.GET("", sysConfigApi.GetConfigList)
+		protectedGroup.PUT("/:key", sysConfigApi.SetConfig)
+	}
+}
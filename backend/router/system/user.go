@@ -15,11 +15,12 @@ func InitUserRouter(router *gin.RouterGroup) {
 	publicGroup := router.Group("/user")
 	{
 		publicGroup.POST("/login", userApi.Login)
+		publicGroup.POST("/change-expired-password", userApi.ChangeExpiredPassword)
 	}
 
 	// 受保护的路由（需要JWT认证）
 	protectedGroup := router.Group("/user")
-	protectedGroup.Use(middleware.JWTAuth())
+	protectedGroup.Use(middleware.APIKeyAuth(), middleware.JWTAuth(), middleware.UserRateLimit())
 	{
 		// 用户CRUD操作
 		protectedGroup.POST("", userApi.CreateUser)
@@ -27,12 +28,37 @@ func InitUserRouter(router *gin.RouterGroup) {
 		protectedGroup.DELETE("/:id", userApi.DeleteUser)
 		protectedGroup.GET("/:id", userApi.GetUser)
 		protectedGroup.GET("/list", userApi.GetUserList)
+		protectedGroup.GET("/search", userApi.SearchUsers)
+		protectedGroup.GET("/created", userApi.GetUsersCreated)
+		protectedGroup.GET("/:id/roles", userApi.GetUserRoles)
+
+		// 用户活动概要（需要Casbin授权）
+		protectedGroup.GET("/:id/activity", middleware.CasbinAuth(), userApi.GetUserActivity)
+
+		// 登录会话管理（需要Casbin授权）
+		protectedGroup.GET("/:id/sessions", middleware.CasbinAuth(), userApi.GetUserSessions)
+		protectedGroup.DELETE("/session/:sessionID", middleware.CasbinAuth(), userApi.RevokeSession)
 
 		// 密码管理
 		protectedGroup.POST("/change-password", userApi.ChangePassword)
 		protectedGroup.POST("/reset-password", userApi.ResetPassword)
 
-		// 状态管理
+		// 两步验证
+		protectedGroup.POST("/mfa/setup", userApi.SetupMFA)
+		protectedGroup.POST("/mfa/verify", userApi.VerifyMFA)
+
+		// API密钥
+		protectedGroup.POST("/api-key", userApi.CreateAPIKey)
+
+		// 状态管理（批量操作需要Casbin授权）
 		protectedGroup.POST("/toggle-status", userApi.ToggleStatus)
+		protectedGroup.POST("/batch-status", middleware.CasbinAuth(), userApi.BatchToggleStatus)
+
+		// 重复账号合并（需要Casbin授权）
+		protectedGroup.POST("/merge", middleware.CasbinAuth(), userApi.MergeUsers)
+
+		// 不活跃用户查询与清理（需要Casbin授权）
+		protectedGroup.GET("/dormant", middleware.CasbinAuth(), userApi.GetDormantUsers)
+		protectedGroup.DELETE("/cleanup", middleware.CasbinAuth(), userApi.CleanupInactiveUsers)
 	}
 }
@@ -18,17 +18,25 @@ func InitCodeGeneratorRouter(router *gin.RouterGroup) {
 
 	// 所有Code Generator路由都需要JWT认证和管理员权限
 	genGroup := router.Group("/gen")
-	genGroup.Use(middleware.JWTAuth())
+	genGroup.Use(middleware.APIKeyAuth(), middleware.JWTAuth(), middleware.UserRateLimit())
 	// TODO: 添加Casbin中间件检查管理员权限
 	// genGroup.Use(middleware.CasbinAuth())
 	{
+		// 获取可用于代码生成的表
+		genGroup.GET("/tables", codeGenApi.GetTables)
+
 		// 获取表元数据
 		genGroup.GET("/metadata/:tableName", codeGenApi.GetTableMetadata)
 
 		// 代码生成
 		genGroup.POST("/preview", codeGenApi.PreviewCode)
+		genGroup.GET("/preview/:tableName", codeGenApi.PreviewFromTable)
 		genGroup.POST("/generate", codeGenApi.GenerateCode)
 
+		// 代码生成历史
+		genGroup.GET("/history", codeGenApi.GetGenerationHistory)
+		genGroup.DELETE("/history/:id", codeGenApi.DeleteGenerationRecord)
+
 		// 表创建
 		genGroup.POST("/table", codeGenApi.CreateTable)
 	}
@@ -1,6 +1,7 @@
 package tools
 
 import (
+	"k-admin-system/api/v1/system"
 	"k-admin-system/api/v1/tools"
 	"k-admin-system/middleware"
 
@@ -10,17 +11,24 @@ import (
 // InitDBInspectorRouter 初始化数据库检查器路由
 func InitDBInspectorRouter(router *gin.RouterGroup) {
 	dbInspectorApi := &tools.DBInspectorAPI{}
+	rateLimitOverrideApi := &system.RateLimitOverrideApi{}
 
 	// 所有DB Inspector路由都需要JWT认证和管理员权限
 	dbGroup := router.Group("/db")
-	dbGroup.Use(middleware.JWTAuth())
-	// TODO: 添加Casbin中间件检查管理员权限
-	// dbGroup.Use(middleware.CasbinAuth())
+	dbGroup.Use(middleware.APIKeyAuth(), middleware.JWTAuth(), middleware.UserRateLimit())
+	dbGroup.Use(middleware.CasbinAuth())
 	{
 		// 表管理
 		dbGroup.GET("/tables", dbInspectorApi.GetTables)
 		dbGroup.GET("/tables/:tableName/schema", dbInspectorApi.GetTableSchema)
+		dbGroup.GET("/tables/:tableName/foreign-keys", dbInspectorApi.GetForeignKeys)
+		dbGroup.GET("/tables/:tableName/indexes", dbInspectorApi.GetTableIndexes)
 		dbGroup.GET("/tables/:tableName/data", dbInspectorApi.GetTableData)
+		dbGroup.GET("/tables/:tableName/sample", dbInspectorApi.GetSampleRows)
+		dbGroup.GET("/tables/:tableName/columns/:columnName/nulls", dbInspectorApi.CountNullValues)
+		dbGroup.GET("/tables/:tableName/columns/:columnName/distinct", dbInspectorApi.GetDistinctValues)
+		dbGroup.GET("/tables/:tableName/backup", dbInspectorApi.BackupTable)
+		dbGroup.POST("/erd", dbInspectorApi.GenerateERDiagram)
 
 		// 记录CRUD操作
 		dbGroup.POST("/tables/:tableName/records", dbInspectorApi.CreateRecord)
@@ -29,5 +37,16 @@ func InitDBInspectorRouter(router *gin.RouterGroup) {
 
 		// SQL执行（需要超级管理员权限）
 		dbGroup.POST("/execute", dbInspectorApi.ExecuteSQL)
+		dbGroup.POST("/explain", dbInspectorApi.RunExplain)
+	}
+
+	// 用户级限流覆盖管理（需要JWT认证和管理员权限）
+	overrideGroup := router.Group("/db-inspector/override")
+	overrideGroup.Use(middleware.APIKeyAuth(), middleware.JWTAuth(), middleware.UserRateLimit())
+	overrideGroup.Use(middleware.CasbinAuth())
+	{
+		overrideGroup.GET("", rateLimitOverrideApi.GetOverrideList)
+		overrideGroup.POST("", rateLimitOverrideApi.SetOverride)
+		overrideGroup.DELETE("/:userId", rateLimitOverrideApi.DeleteOverride)
 	}
 }
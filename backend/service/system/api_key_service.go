@@ -0,0 +1,87 @@
+package system
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"k-admin-system/global"
+	"k-admin-system/model/system"
+	"k-admin-system/utils"
+
+	"gorm.io/gorm"
+)
+
+// APIKeyService API密钥服务
+type APIKeyService struct{}
+
+// CreateAPIKey 为用户生成API密钥
+// 返回的原始密钥只在此处出现一次，数据库中仅保存其哈希
+func (s *APIKeyService) CreateAPIKey(userID uint, name string, expiresAt *time.Time) (string, *system.SysAPIKey, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return "", nil, err
+	}
+
+	// 检查用户是否存在
+	var user system.SysUser
+	if err := global.DB.First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", nil, errors.New("user not found")
+		}
+		return "", nil, fmt.Errorf("failed to query user: %w", err)
+	}
+
+	key, hashedKey, err := utils.GenerateAPIKey()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to generate API key: %w", err)
+	}
+
+	apiKey := &system.SysAPIKey{
+		Name:      name,
+		HashedKey: hashedKey,
+		UserID:    userID,
+		ExpiresAt: expiresAt,
+		Active:    true,
+	}
+	if err := global.DB.Create(apiKey).Error; err != nil {
+		return "", nil, fmt.Errorf("failed to create API key: %w", err)
+	}
+
+	return key, apiKey, nil
+}
+
+// AuthenticateAPIKey 校验API密钥并返回其所属用户
+// 密钥必须处于启用状态且未过期，所属用户也必须处于启用状态
+func (s *APIKeyService) AuthenticateAPIKey(key string) (*system.SysUser, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return nil, err
+	}
+
+	var apiKey system.SysAPIKey
+	if err := global.DB.Where("hashed_key = ?", utils.HashAPIKey(key)).First(&apiKey).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invalid API key")
+		}
+		return nil, fmt.Errorf("failed to query API key: %w", err)
+	}
+
+	if !apiKey.Active {
+		return nil, errors.New("API key is disabled")
+	}
+	if apiKey.ExpiresAt != nil && apiKey.ExpiresAt.Before(time.Now()) {
+		return nil, errors.New("API key has expired")
+	}
+
+	var user system.SysUser
+	if err := global.DB.First(&user, apiKey.UserID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, fmt.Errorf("failed to query user: %w", err)
+	}
+	if !user.Active {
+		return nil, errors.New("user account is disabled")
+	}
+
+	return &user, nil
+}
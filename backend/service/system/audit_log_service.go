@@ -0,0 +1,140 @@
+package system
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"k-admin-system/global"
+	"k-admin-system/model/system"
+	"k-admin-system/utils"
+
+	"gorm.io/gorm"
+)
+
+// AuditLogService 审计日志服务
+type AuditLogService struct{}
+
+// AuditLogFilter 审计日志查询条件，零值字段不参与过滤
+type AuditLogFilter struct {
+	UserID       *uint
+	Method       string
+	StartTime    *time.Time
+	EndTime      *time.Time
+	PathContains string
+}
+
+// CreateLog 写入审计日志
+func (s *AuditLogService) CreateLog(log *system.SysAuditLog) error {
+	if err := utils.DBMustInit(); err != nil {
+		return err
+	}
+
+	if err := global.DB.Create(log).Error; err != nil {
+		return fmt.Errorf("failed to create audit log: %w", err)
+	}
+	return nil
+}
+
+// GetLogs 分页查询审计日志，按时间倒序
+func (s *AuditLogService) GetLogs(filter AuditLogFilter, page, pageSize int) ([]system.SysAuditLog, int64, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return nil, 0, err
+	}
+
+	var logs []system.SysAuditLog
+	var total int64
+
+	query := s.buildQuery(filter)
+
+	// 获取总数
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count audit logs: %w", err)
+	}
+
+	// 分页查询
+	offset := (page - 1) * pageSize
+	if err := query.Order("id DESC").Offset(offset).Limit(pageSize).Find(&logs).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to query audit logs: %w", err)
+	}
+
+	return logs, total, nil
+}
+
+// exportFlushInterval 导出时每写入多少行刷新一次输出
+const exportFlushInterval = 100
+
+// ExportLogs 按过滤条件将审计日志以NDJSON格式（每行一个JSON对象）写入 w，按ID升序
+// 使用游标逐行读取，避免一次性加载全部记录；w 实现 Flush() 时每批数据写出后同步刷新
+// 返回导出的记录数
+func (s *AuditLogService) ExportLogs(filter AuditLogFilter, w io.Writer) (int64, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return 0, err
+	}
+
+	rows, err := s.buildQuery(filter).Order("id ASC").Rows()
+	if err != nil {
+		return 0, fmt.Errorf("failed to query audit logs: %w", err)
+	}
+	defer rows.Close()
+
+	bw := bufio.NewWriter(w)
+	encoder := json.NewEncoder(bw)
+	flush := func() error {
+		if err := bw.Flush(); err != nil {
+			return fmt.Errorf("failed to write audit logs: %w", err)
+		}
+		if flusher, ok := w.(interface{ Flush() }); ok {
+			flusher.Flush()
+		}
+		return nil
+	}
+
+	var count int64
+	for rows.Next() {
+		var log system.SysAuditLog
+		if err := global.DB.ScanRows(rows, &log); err != nil {
+			return count, fmt.Errorf("failed to scan audit log: %w", err)
+		}
+		if err := encoder.Encode(&log); err != nil {
+			return count, fmt.Errorf("failed to encode audit log: %w", err)
+		}
+		count++
+
+		if count%exportFlushInterval == 0 {
+			if err := flush(); err != nil {
+				return count, err
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return count, fmt.Errorf("failed to iterate audit logs: %w", err)
+	}
+
+	return count, flush()
+}
+
+// buildQuery 根据过滤条件构建查询
+func (s *AuditLogService) buildQuery(filter AuditLogFilter) *gorm.DB {
+	query := global.DB.Model(&system.SysAuditLog{})
+
+	if filter.UserID != nil {
+		query = query.Where("user_id = ?", *filter.UserID)
+	}
+	if filter.Method != "" {
+		query = query.Where("method = ?", filter.Method)
+	}
+	if filter.StartTime != nil {
+		query = query.Where("created_at >= ?", *filter.StartTime)
+	}
+	if filter.EndTime != nil {
+		query = query.Where("created_at <= ?", *filter.EndTime)
+	}
+	if filter.PathContains != "" {
+		query = query.Where("path LIKE ?", "%"+filter.PathContains+"%")
+	}
+
+	return query
+}
@@ -0,0 +1,155 @@
+package system
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"k-admin-system/global"
+	"k-admin-system/model/system"
+)
+
+// createTestAuditLogs 写入5条审计日志，创建时间依次间隔1小时，返回第一条的创建时间
+func createTestAuditLogs(t *testing.T) time.Time {
+	t.Helper()
+	base := time.Now().Add(-24 * time.Hour).Truncate(time.Second)
+	logs := []system.SysAuditLog{
+		{UserID: 1, Method: "GET", Path: "/api/v1/user/list"},
+		{UserID: 1, Method: "POST", Path: "/api/v1/user"},
+		{UserID: 2, Method: "GET", Path: "/api/v1/role/list"},
+		{UserID: 2, Method: "DELETE", Path: "/api/v1/user/3"},
+		{UserID: 3, Method: "GET", Path: "/api/v1/menu/tree"},
+	}
+	for i := range logs {
+		logs[i].CreatedAt = base.Add(time.Duration(i) * time.Hour)
+		if err := global.DB.Create(&logs[i]).Error; err != nil {
+			t.Fatalf("failed to create audit log: %v", err)
+		}
+	}
+	return base
+}
+
+func TestAuditLogService_GetLogs(t *testing.T) {
+	setupTestEnv(t)
+	base := createTestAuditLogs(t)
+
+	userID := uint(1)
+	start, end := base.Add(time.Hour), base.Add(3*time.Hour)
+	tests := []struct {
+		name      string
+		filter    AuditLogFilter
+		wantPaths []string // 按ID倒序
+	}{
+		{"no filter", AuditLogFilter{}, []string{"/api/v1/menu/tree", "/api/v1/user/3", "/api/v1/role/list", "/api/v1/user", "/api/v1/user/list"}},
+		{"user", AuditLogFilter{UserID: &userID}, []string{"/api/v1/user", "/api/v1/user/list"}},
+		{"method", AuditLogFilter{Method: "GET"}, []string{"/api/v1/menu/tree", "/api/v1/role/list", "/api/v1/user/list"}},
+		{"time range", AuditLogFilter{StartTime: &start, EndTime: &end}, []string{"/api/v1/user/3", "/api/v1/role/list", "/api/v1/user"}},
+		{"path contains", AuditLogFilter{PathContains: "/user"}, []string{"/api/v1/user/3", "/api/v1/user", "/api/v1/user/list"}},
+		{"combined", AuditLogFilter{Method: "GET", PathContains: "list", StartTime: &start}, []string{"/api/v1/role/list"}},
+	}
+	s := &AuditLogService{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs, total, err := s.GetLogs(tt.filter, 1, 10)
+			if err != nil {
+				t.Fatalf("GetLogs failed: %v", err)
+			}
+			if total != int64(len(tt.wantPaths)) || len(logs) != len(tt.wantPaths) {
+				t.Fatalf("expected %d logs, got total %d and %d rows", len(tt.wantPaths), total, len(logs))
+			}
+			for i, log := range logs {
+				if log.Path != tt.wantPaths[i] {
+					t.Errorf("log %d path = %q, want %q", i, log.Path, tt.wantPaths[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAuditLogService_GetLogsPagination(t *testing.T) {
+	setupTestEnv(t)
+	createTestAuditLogs(t)
+
+	logs, total, err := (&AuditLogService{}).GetLogs(AuditLogFilter{}, 2, 2)
+	if err != nil {
+		t.Fatalf("GetLogs failed: %v", err)
+	}
+	if total != 5 {
+		t.Fatalf("expected total 5, got %d", total)
+	}
+	if len(logs) != 2 || logs[0].Path != "/api/v1/role/list" || logs[1].Path != "/api/v1/user" {
+		t.Fatalf("unexpected second page: %+v", logs)
+	}
+}
+
+// flushCountingBuffer 记录 Flush 调用次数的缓冲区
+type flushCountingBuffer struct {
+	bytes.Buffer
+	flushes int
+}
+
+func (b *flushCountingBuffer) Flush() { b.flushes++ }
+
+func TestAuditLogService_ExportLogsNDJSON(t *testing.T) {
+	setupTestEnv(t)
+
+	const total = 500
+	logs := make([]system.SysAuditLog, 0, total)
+	for i := 0; i < total; i++ {
+		method := "GET"
+		if i%5 == 0 {
+			method = "POST"
+		}
+		logs = append(logs, system.SysAuditLog{UserID: 1, Method: method, Path: fmt.Sprintf("/api/v1/item/%d", i)})
+	}
+	if err := global.DB.CreateInBatches(&logs, 100).Error; err != nil {
+		t.Fatalf("failed to create audit logs: %v", err)
+	}
+
+	var buf flushCountingBuffer
+	count, err := (&AuditLogService{}).ExportLogs(AuditLogFilter{}, &buf)
+	if err != nil {
+		t.Fatalf("ExportLogs() error = %v", err)
+	}
+	if count != total {
+		t.Errorf("ExportLogs() = %d, want %d", count, total)
+	}
+	if want := total/exportFlushInterval + 1; buf.flushes != want {
+		t.Errorf("Flush called %d times, want %d", buf.flushes, want)
+	}
+
+	// 每行一个JSON对象，按ID升序
+	scanner := bufio.NewScanner(&buf.Buffer)
+	var lines int
+	var prevID uint
+	for scanner.Scan() {
+		var log system.SysAuditLog
+		if err := json.Unmarshal(scanner.Bytes(), &log); err != nil {
+			t.Fatalf("line %d is not valid JSON: %v", lines+1, err)
+		}
+		if log.ID <= prevID {
+			t.Fatalf("line %d has ID %d after %d", lines+1, log.ID, prevID)
+		}
+		prevID = log.ID
+		lines++
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("failed to read export: %v", err)
+	}
+	if lines != total {
+		t.Errorf("export has %d lines, want %d", lines, total)
+	}
+
+	// 导出同样应用过滤条件
+	var filtered bytes.Buffer
+	count, err = (&AuditLogService{}).ExportLogs(AuditLogFilter{Method: "POST"}, &filtered)
+	if err != nil {
+		t.Fatalf("ExportLogs(POST) error = %v", err)
+	}
+	if count != total/5 || bytes.Count(filtered.Bytes(), []byte("\n")) != total/5 {
+		t.Errorf("ExportLogs(POST) = %d rows, %d lines, want %d", count, bytes.Count(filtered.Bytes(), []byte("\n")), total/5)
+	}
+}
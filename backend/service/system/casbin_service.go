@@ -0,0 +1,152 @@
+package system
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"k-admin-system/core"
+	"k-admin-system/global"
+	"k-admin-system/model/system"
+	"k-admin-system/utils"
+)
+
+// casbinCSVHeader 策略CSV文件的表头
+var casbinCSVHeader = []string{"role_key", "path", "method"}
+
+// validPolicyMethods 策略允许的HTTP方法
+var validPolicyMethods = map[string]bool{
+	"GET":     true,
+	"POST":    true,
+	"PUT":     true,
+	"PATCH":   true,
+	"DELETE":  true,
+	"HEAD":    true,
+	"OPTIONS": true,
+}
+
+// CasbinService Casbin策略管理服务
+type CasbinService struct{}
+
+// ImportPoliciesFromCSV 从CSV批量导入策略，每行格式为 role_key,path,method
+// 首行为表头时自动跳过；已存在的策略和文件内重复的行会被忽略，返回实际新增的策略数量
+// 任一行校验失败时不导入任何策略
+func (s *CasbinService) ImportPoliciesFromCSV(r io.Reader) (int, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return 0, err
+	}
+
+	if global.CasbinEnforcer == nil {
+		return 0, errors.New("casbin enforcer not initialized")
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = len(casbinCSVHeader)
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse CSV: %w", err)
+	}
+
+	// 查询已有角色，用于校验 role_key
+	var roleKeys []string
+	if err := global.DB.Model(&system.SysRole{}).Pluck("role_key", &roleKeys).Error; err != nil {
+		return 0, fmt.Errorf("failed to query roles: %w", err)
+	}
+	existingRoles := make(map[string]bool, len(roleKeys))
+	for _, key := range roleKeys {
+		existingRoles[key] = true
+	}
+
+	var policies [][]string
+	seen := make(map[string]bool)
+	for i, record := range records {
+		line := i + 1
+		roleKey := strings.TrimSpace(record[0])
+		path := strings.TrimSpace(record[1])
+		method := strings.ToUpper(strings.TrimSpace(record[2]))
+
+		// 跳过表头
+		if i == 0 && strings.EqualFold(roleKey, casbinCSVHeader[0]) {
+			continue
+		}
+
+		if !existingRoles[roleKey] {
+			return 0, fmt.Errorf("line %d: role %q not found", line, roleKey)
+		}
+		if !strings.HasPrefix(path, "/") {
+			return 0, fmt.Errorf("line %d: path must start with /", line)
+		}
+		if !validPolicyMethods[method] {
+			return 0, fmt.Errorf("line %d: invalid HTTP method %q", line, record[2])
+		}
+
+		policy := []string{roleKey, path, method}
+		key := strings.Join(policy, "\x00")
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		exists, err := global.CasbinEnforcer.HasPolicy(policy)
+		if err != nil {
+			return 0, fmt.Errorf("failed to check policy: %w", err)
+		}
+		if !exists {
+			policies = append(policies, policy)
+		}
+	}
+
+	if len(policies) == 0 {
+		return 0, nil
+	}
+
+	// 批量添加策略
+	if _, err := global.CasbinEnforcer.AddPolicies(policies); err != nil {
+		return 0, fmt.Errorf("failed to add policies: %w", err)
+	}
+	core.InvalidateCasbinCache()
+
+	return len(policies), nil
+}
+
+// ExportPoliciesToCSV 将策略导出为CSV（含表头），roleKey 为空时导出全部策略
+func (s *CasbinService) ExportPoliciesToCSV(roleKey string, w io.Writer) error {
+	if err := utils.DBMustInit(); err != nil {
+		return err
+	}
+
+	if global.CasbinEnforcer == nil {
+		return errors.New("casbin enforcer not initialized")
+	}
+
+	var policies [][]string
+	var err error
+	if roleKey == "" {
+		policies, err = global.CasbinEnforcer.GetPolicy()
+	} else {
+		policies, err = global.CasbinEnforcer.GetFilteredPolicy(0, roleKey)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get policies: %w", err)
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(casbinCSVHeader); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	for _, policy := range policies {
+		if len(policy) < len(casbinCSVHeader) {
+			continue
+		}
+		if err := writer.Write(policy[:len(casbinCSVHeader)]); err != nil {
+			return fmt.Errorf("failed to write CSV: %w", err)
+		}
+	}
+	writer.Flush()
+
+	return writer.Error()
+}
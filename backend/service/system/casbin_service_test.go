@@ -0,0 +1,128 @@
+package system
+
+import (
+	"bytes"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"k-admin-system/global"
+)
+
+// sortedPolicies 返回按字典序排序的策略，便于比较
+func sortedPolicies(t *testing.T) [][]string {
+	t.Helper()
+	policies, err := global.CasbinEnforcer.GetPolicy()
+	if err != nil {
+		t.Fatalf("GetPolicy() error = %v", err)
+	}
+	sort.Slice(policies, func(i, j int) bool {
+		return strings.Join(policies[i], ",") < strings.Join(policies[j], ",")
+	})
+	return policies
+}
+
+func TestCasbinService_ExportImportRoundTrip(t *testing.T) {
+	setupTestEnv(t)
+	setupTestCasbin(t)
+	createTestRole(t, "editor")
+	createTestRole(t, "viewer")
+	service := &CasbinService{}
+
+	if _, err := global.CasbinEnforcer.AddPolicies([][]string{
+		{"editor", "/api/v1/post/:id", "PUT"},
+		{"editor", "/api/v1/post", "POST"},
+		{"viewer", "/api/v1/post/:id", "GET"},
+	}); err != nil {
+		t.Fatalf("AddPolicies() error = %v", err)
+	}
+	want := sortedPolicies(t)
+
+	var buf bytes.Buffer
+	if err := service.ExportPoliciesToCSV("", &buf); err != nil {
+		t.Fatalf("ExportPoliciesToCSV() error = %v", err)
+	}
+	exported := buf.String()
+	if !strings.HasPrefix(exported, "role_key,path,method\n") {
+		t.Fatalf("exported CSV is missing the header: %q", exported)
+	}
+
+	// 在新环境中导入导出的CSV，策略应完全一致
+	setupTestEnv(t)
+	setupTestCasbin(t)
+	createTestRole(t, "editor")
+	createTestRole(t, "viewer")
+
+	count, err := service.ImportPoliciesFromCSV(strings.NewReader(exported))
+	if err != nil {
+		t.Fatalf("ImportPoliciesFromCSV() error = %v", err)
+	}
+	if count != len(want) {
+		t.Errorf("ImportPoliciesFromCSV() = %d, want %d", count, len(want))
+	}
+	if got := sortedPolicies(t); !reflect.DeepEqual(got, want) {
+		t.Errorf("policies after round trip = %v, want %v", got, want)
+	}
+
+	// 再次导入时已有策略被忽略
+	count, err = service.ImportPoliciesFromCSV(strings.NewReader(exported))
+	if err != nil {
+		t.Fatalf("ImportPoliciesFromCSV() error = %v", err)
+	}
+	if count != 0 {
+		t.Errorf("re-import added %d policies, want 0", count)
+	}
+
+	buf.Reset()
+	if err := service.ExportPoliciesToCSV("viewer", &buf); err != nil {
+		t.Fatalf("ExportPoliciesToCSV(viewer) error = %v", err)
+	}
+	if got := buf.String(); got != "role_key,path,method\nviewer,/api/v1/post/:id,GET\n" {
+		t.Errorf("ExportPoliciesToCSV(viewer) = %q", got)
+	}
+}
+
+func TestCasbinService_ImportPoliciesFromCSV(t *testing.T) {
+	tests := []struct {
+		name      string
+		csv       string
+		wantCount int
+		wantErr   string
+	}{
+		{"without header", "editor,/api/v1/post,get\n", 1, ""},
+		{"duplicate rows", "role_key,path,method\neditor,/api/v1/post,GET\neditor, /api/v1/post ,GET\n", 1, ""},
+		{"unknown role", "role_key,path,method\neditor,/api/v1/post,GET\nghost,/api/v1/post,GET\n", 0, `line 3: role "ghost" not found`},
+		{"path without slash", "editor,api/v1/post,GET\n", 0, "line 1: path must start with /"},
+		{"invalid method", "editor,/api/v1/post,FETCH\n", 0, `line 1: invalid HTTP method "FETCH"`},
+		{"wrong field count", "editor,/api/v1/post\n", 0, "failed to parse CSV"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestEnv(t)
+			setupTestCasbin(t)
+			createTestRole(t, "editor")
+
+			count, err := (&CasbinService{}).ImportPoliciesFromCSV(strings.NewReader(tt.csv))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ImportPoliciesFromCSV() error = %v, want %q", err, tt.wantErr)
+				}
+				// 任一行校验失败时不导入任何策略
+				if policies := sortedPolicies(t); len(policies) != 0 {
+					t.Errorf("policies after failed import = %v, want none", policies)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ImportPoliciesFromCSV() error = %v", err)
+			}
+			if count != tt.wantCount {
+				t.Errorf("ImportPoliciesFromCSV() = %d, want %d", count, tt.wantCount)
+			}
+			if has, _ := global.CasbinEnforcer.HasPolicy("editor", "/api/v1/post", "GET"); !has {
+				t.Error("imported policy not found")
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"k-admin-system/global"
 	"k-admin-system/model/system"
+	"k-admin-system/utils"
 )
 
 // DashboardService 仪表盘服务
@@ -12,14 +13,23 @@ type DashboardService struct{}
 
 // DashboardStats 仪表盘统计数据
 type DashboardStats struct {
-	UserCount   int64 `json:"userCount"`
-	RoleCount   int64 `json:"roleCount"`
-	MenuCount   int64 `json:"menuCount"`
-	ConfigCount int64 `json:"configCount"`
+	UserCount   int64            `json:"userCount"`
+	RoleCount   int64            `json:"roleCount"`
+	MenuCount   int64            `json:"menuCount"`
+	ConfigCount int64            `json:"configCount"`
+	RoleStats   []RoleStat       `json:"roleStats"`   // 每个角色的用户数和菜单数
+	RecentUsers []system.SysUser `json:"recentUsers"` // 最近注册的用户
 }
 
+// dashboardRecentUsers 仪表盘展示的最近注册用户数量
+const dashboardRecentUsers = 10
+
 // GetDashboardStats 获取仪表盘统计数据
 func (s *DashboardService) GetDashboardStats() (*DashboardStats, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return nil, err
+	}
+
 	stats := &DashboardStats{}
 
 	// 统计用户数量
@@ -37,6 +47,22 @@ func (s *DashboardService) GetDashboardStats() (*DashboardStats, error) {
 		return nil, fmt.Errorf("failed to count menus: %w", err)
 	}
 
+	// 统计每个角色的用户数和菜单数
+	roleService := RoleService{}
+	roleStats, err := roleService.GetRoleStats()
+	if err != nil {
+		return nil, err
+	}
+	stats.RoleStats = roleStats
+
+	// 最近注册的用户
+	userService := UserService{}
+	recentUsers, err := userService.GetRecentlyCreatedUsers(dashboardRecentUsers)
+	if err != nil {
+		return nil, err
+	}
+	stats.RecentUsers = recentUsers
+
 	// 系统配置数量（这里暂时使用固定值，后续可以根据实际配置表统计）
 	stats.ConfigCount = 15
 
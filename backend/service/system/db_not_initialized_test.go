@@ -0,0 +1,55 @@
+package system
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"k-admin-system/global"
+	"k-admin-system/model/system"
+	"k-admin-system/utils"
+)
+
+func TestServices_NilDBReturnsErrDatabaseNotInitialized(t *testing.T) {
+	prevDB := global.DB
+	global.DB = nil
+	t.Cleanup(func() { global.DB = prevDB })
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"UserService.GetUserByID", func() error { _, err := (&UserService{}).GetUserByID(1); return err }},
+		{"UserService.BatchToggleStatus", func() error { _, err := (&UserService{}).BatchToggleStatus([]uint{1}, false); return err }},
+		{"UserService.GetUsersByLastLoginRange", func() error {
+			_, err := (&UserService{}).GetUsersByLastLoginRange(time.Now().Add(-time.Hour), time.Now(), nil)
+			return err
+		}},
+		{"RoleService.CreateRole", func() error { return (&RoleService{}).CreateRole(&system.SysRole{RoleKey: "editor"}) }},
+		{"RoleService.GetRoleMenuTree", func() error { _, err := (&RoleService{}).GetRoleMenuTree(1); return err }},
+		{"MenuService.CreateMenu", func() error { return (&MenuService{}).CreateMenu(&system.SysMenu{Path: "/x"}) }},
+		{"MenuService.GetAllMenus", func() error { _, err := (&MenuService{}).GetAllMenus(); return err }},
+		{"AuditLogService.GetLogs", func() error { _, _, err := (&AuditLogService{}).GetLogs(AuditLogFilter{}, 1, 10); return err }},
+		{"AuditLogService.ExportLogs", func() error {
+			_, err := (&AuditLogService{}).ExportLogs(AuditLogFilter{}, &strings.Builder{})
+			return err
+		}},
+		{"CasbinService.ImportPoliciesFromCSV", func() error {
+			_, err := (&CasbinService{}).ImportPoliciesFromCSV(strings.NewReader("editor,/x,GET"))
+			return err
+		}},
+		{"DashboardService.GetDashboardStats", func() error { _, err := (&DashboardService{}).GetDashboardStats(); return err }},
+		{"OperationLogService.GetLogs", func() error {
+			_, _, err := (&OperationLogService{}).GetLogs(OperationLogFilter{}, 1, 10)
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, utils.ErrDatabaseNotInitialized) {
+				t.Errorf("error = %v, want ErrDatabaseNotInitialized", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package system
+
+import (
+	"testing"
+
+	"k-admin-system/config"
+	"k-admin-system/core"
+	"k-admin-system/global"
+	"k-admin-system/model/system"
+	"k-admin-system/utils"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/glebarez/sqlite"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// setupTestEnv 使用内存SQLite和miniredis初始化全局依赖，测试结束后恢复原值
+func setupTestEnv(t *testing.T) *miniredis.Miniredis {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	// 内存数据库只存在于单个连接中
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get sql.DB: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	if err := db.AutoMigrate(
+		&system.SysRole{},
+		&system.SysMenu{},
+		&system.SysUser{},
+		&system.SysAuditLog{},
+		&system.SysOperationLog{},
+		&system.SysUserSession{},
+		&system.SysAPIKey{},
+		&system.SysNotification{},
+		&system.SysUserNotification{},
+		&system.SysConfig{},
+	); err != nil {
+		t.Fatalf("failed to migrate tables: %v", err)
+	}
+
+	mr := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	prevDB, prevRedis, prevLogger, prevConfig := global.DB, global.RedisClient, global.Logger, global.Config
+	global.DB = db
+	global.RedisClient = redisClient
+	global.Logger = zap.NewNop()
+	global.Config = testConfig()
+	t.Cleanup(func() {
+		_ = redisClient.Close()
+		_ = sqlDB.Close()
+		global.DB, global.RedisClient, global.Logger, global.Config = prevDB, prevRedis, prevLogger, prevConfig
+	})
+
+	return mr
+}
+
+// testConfig 测试使用的最小配置
+func testConfig() *config.Config {
+	return &config.Config{
+		JWT: config.JWTConfig{
+			Secret:            "test-secret",
+			AccessExpiration:  15,
+			RefreshExpiration: 7,
+		},
+		Security: config.SecurityConfig{
+			PasswordHashAlgorithm: "bcrypt",
+			MaxFailedAttempts:     5,
+			LockDuration:          15,
+			MinPasswordStrength:   1,
+			PasswordMaxAgeDays:    90,
+		},
+		Timezone: config.TimezoneConfig{Default: "UTC"},
+	}
+}
+
+// createTestRole 创建测试角色
+func createTestRole(t *testing.T, roleKey string) *system.SysRole {
+	t.Helper()
+	role := &system.SysRole{RoleName: roleKey, RoleKey: roleKey, Status: true}
+	if err := global.DB.Create(role).Error; err != nil {
+		t.Fatalf("failed to create role: %v", err)
+	}
+	return role
+}
+
+// createTestUser 创建测试用户，password 为明文密码
+func createTestUser(t *testing.T, username, password string, roleID uint) *system.SysUser {
+	t.Helper()
+	hashed, err := utils.HashPassword(password)
+	if err != nil {
+		t.Fatalf("failed to hash password: %v", err)
+	}
+	user := &system.SysUser{Username: username, Password: hashed, RoleID: roleID, Active: true}
+	if err := global.DB.Create(user).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	return user
+}
+
+// setupTestCasbin 基于测试数据库初始化 Casbin enforcer，测试结束后恢复原值
+func setupTestCasbin(t *testing.T) {
+	t.Helper()
+	enforcer, err := core.InitCasbin()
+	if err != nil {
+		t.Fatalf("failed to init casbin: %v", err)
+	}
+	prevEnforcer := global.CasbinEnforcer
+	global.CasbinEnforcer = enforcer
+	core.InvalidateCasbinCache()
+	t.Cleanup(func() {
+		global.CasbinEnforcer = prevEnforcer
+		core.InvalidateCasbinCache()
+	})
+}
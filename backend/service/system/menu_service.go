@@ -1,21 +1,46 @@
 package system
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 
 	"k-admin-system/global"
 	"k-admin-system/model/system"
+	"k-admin-system/utils"
 
 	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ErrDuplicateMenuPath 菜单路由路径已被其他菜单使用
+var ErrDuplicateMenuPath = errors.New("menu path already exists")
+
 // MenuService 菜单服务
 type MenuService struct{}
 
+// MenuExportNode 菜单导出节点
+// 导出时不包含ID和父菜单ID，层级关系通过 Children 表达，便于纳入版本控制
+type MenuExportNode struct {
+	Path      string           `json:"path" yaml:"path"`
+	Name      string           `json:"name" yaml:"name"`
+	Component string           `json:"component" yaml:"component"`
+	Sort      int              `json:"sort" yaml:"sort"`
+	Meta      system.MenuMeta  `json:"meta" yaml:"meta"`
+	BtnPerms  []string         `json:"btnPerms" yaml:"btn_perms"`
+	Children  []MenuExportNode `json:"children,omitempty" yaml:"children,omitempty"`
+}
+
 // CreateMenu 创建菜单
+// Sort 为0时自动设置为同一父菜单下的最大排序号加1
 func (s *MenuService) CreateMenu(menu *system.SysMenu) error {
+	if err := utils.DBMustInit(); err != nil {
+		return err
+	}
+
 	// 如果有父菜单，检查父菜单是否存在
 	if menu.ParentID > 0 {
 		var parent system.SysMenu
@@ -27,16 +52,41 @@ func (s *MenuService) CreateMenu(menu *system.SysMenu) error {
 		}
 	}
 
-	// 创建菜单
-	if err := global.DB.Create(menu).Error; err != nil {
-		return fmt.Errorf("failed to create menu: %w", err)
+	// 检查路由路径是否重复
+	if err := s.ValidateMenuPath(menu.Path, 0); err != nil {
+		return err
 	}
 
-	return nil
+	// 在事务中分配排序号并创建菜单，避免并发创建时得到相同的排序号
+	return global.DB.Transaction(func(tx *gorm.DB) error {
+		// 未指定排序时排在同级菜单末尾
+		if menu.Sort == 0 {
+			var maxSort int
+			if err := tx.Model(&system.SysMenu{}).
+				Clauses(clause.Locking{Strength: "UPDATE"}).
+				Select("COALESCE(MAX(sort), 0)").
+				Where("parent_id = ?", menu.ParentID).
+				Scan(&maxSort).Error; err != nil {
+				return fmt.Errorf("failed to query max sort: %w", err)
+			}
+			menu.Sort = maxSort + 1
+		}
+
+		// 创建菜单
+		if err := tx.Create(menu).Error; err != nil {
+			return fmt.Errorf("failed to create menu: %w", err)
+		}
+
+		return nil
+	})
 }
 
 // UpdateMenu 更新菜单信息
 func (s *MenuService) UpdateMenu(menu *system.SysMenu) error {
+	if err := utils.DBMustInit(); err != nil {
+		return err
+	}
+
 	// 检查菜单是否存在
 	var existingMenu system.SysMenu
 	if err := global.DB.First(&existingMenu, menu.ID).Error; err != nil {
@@ -60,6 +110,11 @@ func (s *MenuService) UpdateMenu(menu *system.SysMenu) error {
 		}
 	}
 
+	// 检查路由路径是否与其他菜单重复
+	if err := s.ValidateMenuPath(menu.Path, menu.ID); err != nil {
+		return err
+	}
+
 	// 更新菜单
 	if err := global.DB.Save(menu).Error; err != nil {
 		return fmt.Errorf("failed to update menu: %w", err)
@@ -70,6 +125,10 @@ func (s *MenuService) UpdateMenu(menu *system.SysMenu) error {
 
 // DeleteMenu 删除菜单
 func (s *MenuService) DeleteMenu(id uint) error {
+	if err := utils.DBMustInit(); err != nil {
+		return err
+	}
+
 	// 检查菜单是否存在
 	var menu system.SysMenu
 	if err := global.DB.First(&menu, id).Error; err != nil {
@@ -96,8 +155,131 @@ func (s *MenuService) DeleteMenu(id uint) error {
 	return nil
 }
 
+// RestoreMenu 恢复已软删除的菜单
+// 已存在相同路径的菜单，或父菜单已被删除时不允许恢复
+func (s *MenuService) RestoreMenu(id uint) error {
+	if err := utils.DBMustInit(); err != nil {
+		return err
+	}
+
+	// 检查菜单是否存在且已被删除
+	var menu system.SysMenu
+	if err := global.DB.Unscoped().First(&menu, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("menu not found")
+		}
+		return fmt.Errorf("failed to query menu: %w", err)
+	}
+	if !menu.DeletedAt.Valid {
+		return errors.New("menu is not deleted")
+	}
+
+	// 检查父菜单是否存在
+	if menu.ParentID != 0 {
+		var parentCount int64
+		if err := global.DB.Model(&system.SysMenu{}).Where("id = ?", menu.ParentID).Count(&parentCount).Error; err != nil {
+			return fmt.Errorf("failed to check parent menu: %w", err)
+		}
+		if parentCount == 0 {
+			return errors.New("parent menu not found, restore the parent menu first")
+		}
+	}
+
+	// 检查是否已有相同路径的菜单
+	if err := s.ValidateMenuPath(menu.Path, menu.ID); err != nil {
+		return err
+	}
+
+	// 恢复菜单
+	if err := global.DB.Unscoped().Model(&menu).Update("deleted_at", nil).Error; err != nil {
+		return fmt.Errorf("failed to restore menu: %w", err)
+	}
+
+	return nil
+}
+
+// SortMenusByParent 按给定顺序重排同一父菜单下的子菜单
+// orderedIDs 必须恰好包含 parentID 下的全部子菜单，排序号依次设置为 1..n
+func (s *MenuService) SortMenusByParent(parentID uint, orderedIDs []uint) error {
+	if err := utils.DBMustInit(); err != nil {
+		return err
+	}
+
+	if len(orderedIDs) == 0 {
+		return errors.New("menu IDs are required")
+	}
+
+	seen := make(map[uint]bool, len(orderedIDs))
+	for _, id := range orderedIDs {
+		if seen[id] {
+			return fmt.Errorf("duplicate menu ID: %d", id)
+		}
+		seen[id] = true
+	}
+
+	return global.DB.Transaction(func(tx *gorm.DB) error {
+		// 锁定请求中的菜单，避免并发重排或移动父菜单
+		var menus []system.SysMenu
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "parent_id").
+			Where("id IN ?", orderedIDs).
+			Find(&menus).Error; err != nil {
+			return fmt.Errorf("failed to query menus: %w", err)
+		}
+		if len(menus) != len(orderedIDs) {
+			return errors.New("menu not found")
+		}
+		for _, menu := range menus {
+			if menu.ParentID != parentID {
+				return fmt.Errorf("menu %d does not belong to parent menu %d", menu.ID, parentID)
+			}
+		}
+
+		// 必须包含全部子菜单，否则未提交的菜单会与新的排序号冲突
+		var siblingCount int64
+		if err := tx.Model(&system.SysMenu{}).Where("parent_id = ?", parentID).Count(&siblingCount).Error; err != nil {
+			return fmt.Errorf("failed to count child menus: %w", err)
+		}
+		if siblingCount != int64(len(orderedIDs)) {
+			return errors.New("menu IDs must include every child menu of the parent")
+		}
+
+		for i, id := range orderedIDs {
+			if err := tx.Model(&system.SysMenu{}).Where("id = ?", id).Update("sort", i+1).Error; err != nil {
+				return fmt.Errorf("failed to update menu sort: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// ValidateMenuPath 检查路由路径是否已被其他未删除的菜单使用，excludeID 为需要排除的菜单ID（创建时传0）
+// 路径重复会导致前端路由冲突，存在重复时返回 ErrDuplicateMenuPath
+func (s *MenuService) ValidateMenuPath(path string, excludeID uint) error {
+	if err := utils.DBMustInit(); err != nil {
+		return err
+	}
+
+	var count int64
+	if err := global.DB.Model(&system.SysMenu{}).
+		Where("path = ? AND id != ?", path, excludeID).
+		Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to check menu path uniqueness: %w", err)
+	}
+	if count > 0 {
+		return ErrDuplicateMenuPath
+	}
+
+	return nil
+}
+
 // GetMenuByID 根据ID获取菜单
 func (s *MenuService) GetMenuByID(id uint) (*system.SysMenu, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return nil, err
+	}
+
 	var menu system.SysMenu
 	if err := global.DB.First(&menu, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -109,8 +291,29 @@ func (s *MenuService) GetMenuByID(id uint) (*system.SysMenu, error) {
 	return &menu, nil
 }
 
+// GetMenuByPath 根据路由路径获取菜单，供前端按当前路由查询菜单元数据
+func (s *MenuService) GetMenuByPath(path string) (*system.SysMenu, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return nil, err
+	}
+
+	var menu system.SysMenu
+	if err := global.DB.Where("path = ?", path).First(&menu).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("menu not found")
+		}
+		return nil, fmt.Errorf("failed to query menu: %w", err)
+	}
+
+	return &menu, nil
+}
+
 // GetAllMenus 获取所有菜单（不构建树结构）
 func (s *MenuService) GetAllMenus() ([]system.SysMenu, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return nil, err
+	}
+
 	var menus []system.SysMenu
 	if err := global.DB.Order("sort ASC, id ASC").Find(&menus).Error; err != nil {
 		return nil, fmt.Errorf("failed to query menus: %w", err)
@@ -119,9 +322,30 @@ func (s *MenuService) GetAllMenus() ([]system.SysMenu, error) {
 	return menus, nil
 }
 
+// GetMenusByComponent 查询组件路径包含指定字符串的菜单，component 中的通配符按字面量匹配
+// 用于前端重构组件路径前确认哪些菜单引用了该组件
+func (s *MenuService) GetMenusByComponent(component string) ([]system.SysMenu, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return nil, err
+	}
+
+	var menus []system.SysMenu
+	if err := global.DB.Where("component LIKE ? "+likeEscapeClause(), "%"+escapeLike(component)+"%").
+		Order("sort ASC, id ASC").
+		Find(&menus).Error; err != nil {
+		return nil, fmt.Errorf("failed to query menus: %w", err)
+	}
+
+	return menus, nil
+}
+
 // GetMenuTree 获取菜单树（根据角色过滤）
 // 如果 roleID 为 0，返回所有菜单
 func (s *MenuService) GetMenuTree(roleID uint) ([]system.SysMenu, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return nil, err
+	}
+
 	var menus []system.SysMenu
 
 	global.Logger.Info("GetMenuTree called",
@@ -184,6 +408,10 @@ func (s *MenuService) BuildMenuTree(menus []system.SysMenu, parentID uint) []sys
 
 // GetMenusByRoleIDs 根据多个角色ID获取菜单树（用于用户有多个角色的情况）
 func (s *MenuService) GetMenusByRoleIDs(roleIDs []uint) ([]system.SysMenu, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return nil, err
+	}
+
 	if len(roleIDs) == 0 {
 		return make([]system.SysMenu, 0), nil // 返回空数组而不是 nil
 	}
@@ -203,3 +431,125 @@ func (s *MenuService) GetMenusByRoleIDs(roleIDs []uint) ([]system.SysMenu, error
 	tree := s.BuildMenuTree(menus, 0)
 	return tree, nil
 }
+
+// ExportMenuTree 导出完整菜单树
+// format 支持 "json" 和 "yaml"
+func (s *MenuService) ExportMenuTree(w io.Writer, format string) error {
+	if err := utils.DBMustInit(); err != nil {
+		return err
+	}
+
+	menus, err := s.GetAllMenus()
+	if err != nil {
+		return err
+	}
+
+	nodes := toMenuExportNodes(s.BuildMenuTree(menus, 0))
+
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(nodes); err != nil {
+			return fmt.Errorf("failed to encode menu tree as JSON: %w", err)
+		}
+	case "yaml":
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(nodes); err != nil {
+			return fmt.Errorf("failed to encode menu tree as YAML: %w", err)
+		}
+		if err := encoder.Close(); err != nil {
+			return fmt.Errorf("failed to encode menu tree as YAML: %w", err)
+		}
+	default:
+		return fmt.Errorf("unsupported export format: %s", format)
+	}
+
+	return nil
+}
+
+// ImportMenusFromYAML 从YAML导入菜单树
+// 按路由路径匹配已有菜单：存在则更新，不存在则创建，已有菜单的ID和角色关联保持不变
+// 返回导入（创建或更新）的菜单数量
+func (s *MenuService) ImportMenusFromYAML(r io.Reader) (int, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return 0, err
+	}
+
+	var nodes []MenuExportNode
+	if err := yaml.NewDecoder(r).Decode(&nodes); err != nil {
+		return 0, fmt.Errorf("failed to decode menu tree from YAML: %w", err)
+	}
+
+	imported := 0
+	err := global.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		imported, err = importMenuNodes(tx, nodes, 0)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return imported, nil
+}
+
+// importMenuNodes 递归导入菜单节点
+func importMenuNodes(tx *gorm.DB, nodes []MenuExportNode, parentID uint) (int, error) {
+	imported := 0
+	for _, node := range nodes {
+		if node.Path == "" || node.Name == "" {
+			return 0, errors.New("menu path and name are required")
+		}
+
+		var menu system.SysMenu
+		err := tx.Where("path = ?", node.Path).First(&menu).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, fmt.Errorf("failed to query menu: %w", err)
+		}
+
+		menu.ParentID = parentID
+		menu.Path = node.Path
+		menu.Name = node.Name
+		menu.Component = node.Component
+		menu.Sort = node.Sort
+		menu.Meta = node.Meta
+		menu.BtnPerms = node.BtnPerms
+
+		if err := tx.Save(&menu).Error; err != nil {
+			return 0, fmt.Errorf("failed to save menu %s: %w", node.Path, err)
+		}
+		imported++
+
+		count, err := importMenuNodes(tx, node.Children, menu.ID)
+		if err != nil {
+			return 0, err
+		}
+		imported += count
+	}
+
+	return imported, nil
+}
+
+// toMenuExportNodes 将菜单树转换为导出节点
+func toMenuExportNodes(menus []system.SysMenu) []MenuExportNode {
+	nodes := make([]MenuExportNode, 0, len(menus))
+	for _, menu := range menus {
+		btnPerms := []string(menu.BtnPerms)
+		if btnPerms == nil {
+			btnPerms = []string{}
+		}
+		nodes = append(nodes, MenuExportNode{
+			Path:      menu.Path,
+			Name:      menu.Name,
+			Component: menu.Component,
+			Sort:      menu.Sort,
+			Meta:      menu.Meta,
+			BtnPerms:  btnPerms,
+			Children:  toMenuExportNodes(menu.Children),
+		})
+	}
+
+	return nodes
+}
@@ -0,0 +1,308 @@
+package system
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"k-admin-system/global"
+	"k-admin-system/model/system"
+)
+
+// createTestMenu 创建测试菜单
+func createTestMenu(t *testing.T, path, name, component string) *system.SysMenu {
+	t.Helper()
+	menu := &system.SysMenu{Path: path, Name: name, Component: component}
+	if err := global.DB.Create(menu).Error; err != nil {
+		t.Fatalf("failed to create menu: %v", err)
+	}
+	return menu
+}
+
+func TestGetMenusByComponent(t *testing.T) {
+	setupTestEnv(t)
+	userMenu := createTestMenu(t, "/system/user", "User", "views/system/user/index")
+	profileMenu := createTestMenu(t, "/system/profile", "Profile", "views/system/user/index")
+	createTestMenu(t, "/system/role", "Role", "views/system/role/index")
+
+	s := MenuService{}
+	menus, err := s.GetMenusByComponent("views/system/user")
+	if err != nil {
+		t.Fatalf("GetMenusByComponent() error = %v", err)
+	}
+	if len(menus) != 2 || menus[0].ID != userMenu.ID || menus[1].ID != profileMenu.ID {
+		t.Errorf("GetMenusByComponent() = %v, want the user and profile menus", menus)
+	}
+
+	// 通配符按字面量匹配
+	menus, err = s.GetMenusByComponent("views/system/%")
+	if err != nil {
+		t.Fatalf("GetMenusByComponent() error = %v", err)
+	}
+	if len(menus) != 0 {
+		t.Errorf("GetMenusByComponent(%%) returned %d menus, want 0", len(menus))
+	}
+}
+
+func TestGetMenuByPath(t *testing.T) {
+	setupTestEnv(t)
+	createTestMenu(t, "/system/user", "User", "views/system/user/index")
+	roleMenu := createTestMenu(t, "/system/role", "Role", "views/system/role/index")
+	createTestMenu(t, "/system/role/detail", "RoleDetail", "views/system/role/detail")
+
+	s := MenuService{}
+	menu, err := s.GetMenuByPath("/system/role")
+	if err != nil {
+		t.Fatalf("GetMenuByPath() error = %v", err)
+	}
+	if menu.ID != roleMenu.ID || menu.Name != "Role" {
+		t.Errorf("GetMenuByPath() = %+v, want the role menu", menu)
+	}
+
+	if _, err := s.GetMenuByPath("/system/missing"); err == nil || err.Error() != "menu not found" {
+		t.Errorf("GetMenuByPath() of an unknown path error = %v, want menu not found", err)
+	}
+}
+
+func TestExportMenuTree_ImportRoundTrip(t *testing.T) {
+	setupTestEnv(t)
+	root := &system.SysMenu{Path: "/system", Name: "System", Sort: 1, Meta: system.MenuMeta{Icon: "setting", Title: "System"}}
+	if err := global.DB.Create(root).Error; err != nil {
+		t.Fatalf("failed to create menu: %v", err)
+	}
+	children := []system.SysMenu{
+		{ParentID: root.ID, Path: "/system/user", Name: "User", Component: "views/system/user/index", Sort: 1,
+			Meta: system.MenuMeta{Title: "Users", KeepAlive: true}, BtnPerms: []string{"user:add", "user:delete"}},
+		{ParentID: root.ID, Path: "/system/role", Name: "Role", Component: "views/system/role/index", Sort: 2,
+			Meta: system.MenuMeta{Title: "Roles", Hidden: true}},
+	}
+	for i := range children {
+		if err := global.DB.Create(&children[i]).Error; err != nil {
+			t.Fatalf("failed to create menu: %v", err)
+		}
+	}
+
+	s := MenuService{}
+	var exported bytes.Buffer
+	if err := s.ExportMenuTree(&exported, "yaml"); err != nil {
+		t.Fatalf("ExportMenuTree() error = %v", err)
+	}
+	if !bytes.Contains(exported.Bytes(), []byte("user:delete")) {
+		t.Fatalf("ExportMenuTree() output is missing button permissions:\n%s", exported.String())
+	}
+
+	// 导入到空数据库后再次导出，结果应与原导出一致
+	setupTestEnv(t)
+	imported, err := s.ImportMenusFromYAML(bytes.NewReader(exported.Bytes()))
+	if err != nil {
+		t.Fatalf("ImportMenusFromYAML() error = %v", err)
+	}
+	if imported != 3 {
+		t.Errorf("ImportMenusFromYAML() imported %d menus, want 3", imported)
+	}
+	var reexported bytes.Buffer
+	if err := s.ExportMenuTree(&reexported, "yaml"); err != nil {
+		t.Fatalf("ExportMenuTree() error = %v", err)
+	}
+	if reexported.String() != exported.String() {
+		t.Errorf("round-tripped menu tree =\n%s\nwant\n%s", reexported.String(), exported.String())
+	}
+
+	// 再次导入按路径更新已有菜单，不产生重复记录
+	if _, err := s.ImportMenusFromYAML(bytes.NewReader(exported.Bytes())); err != nil {
+		t.Fatalf("ImportMenusFromYAML() error = %v", err)
+	}
+	var count int64
+	global.DB.Model(&system.SysMenu{}).Count(&count)
+	if count != 3 {
+		t.Errorf("menu count after importing twice = %d, want 3", count)
+	}
+}
+
+// menuIDs 返回菜单ID列表
+func menuIDs(menus []system.SysMenu) []uint {
+	ids := make([]uint, 0, len(menus))
+	for _, menu := range menus {
+		ids = append(ids, menu.ID)
+	}
+	return ids
+}
+
+func TestDeleteRestoreMenu_GetAllMenus(t *testing.T) {
+	setupTestEnv(t)
+	userMenu := createTestMenu(t, "/system/user", "User", "views/system/user/index")
+	roleMenu := createTestMenu(t, "/system/role", "Role", "views/system/role/index")
+
+	s := MenuService{}
+	if err := s.DeleteMenu(userMenu.ID); err != nil {
+		t.Fatalf("DeleteMenu() error = %v", err)
+	}
+	menus, err := s.GetAllMenus()
+	if err != nil {
+		t.Fatalf("GetAllMenus() error = %v", err)
+	}
+	if ids := menuIDs(menus); len(ids) != 1 || ids[0] != roleMenu.ID {
+		t.Errorf("GetAllMenus() after delete = %v, want only the role menu", ids)
+	}
+	if err := s.RestoreMenu(roleMenu.ID); err == nil {
+		t.Error("RestoreMenu() of a menu that is not deleted succeeded")
+	}
+
+	if err := s.RestoreMenu(userMenu.ID); err != nil {
+		t.Fatalf("RestoreMenu() error = %v", err)
+	}
+	menus, err = s.GetAllMenus()
+	if err != nil {
+		t.Fatalf("GetAllMenus() error = %v", err)
+	}
+	if ids := menuIDs(menus); len(ids) != 2 || ids[0] != userMenu.ID || ids[1] != roleMenu.ID {
+		t.Errorf("GetAllMenus() after restore = %v, want both menus", ids)
+	}
+	if menus[0].Path != "/system/user" || menus[0].DeletedAt.Valid {
+		t.Errorf("restored menu = %+v, want the original path and no deletion time", menus[0])
+	}
+}
+
+func TestMenuService_RejectsDuplicatePaths(t *testing.T) {
+	setupTestEnv(t)
+	s := MenuService{}
+
+	user := &system.SysMenu{Path: "/system/user", Name: "User", Component: "views/system/user/index"}
+	if err := s.CreateMenu(user); err != nil {
+		t.Fatalf("CreateMenu() error = %v", err)
+	}
+	role := &system.SysMenu{Path: "/system/role", Name: "Role", Component: "views/system/role/index"}
+	if err := s.CreateMenu(role); err != nil {
+		t.Fatalf("CreateMenu() error = %v", err)
+	}
+
+	// 创建重复路径
+	duplicate := &system.SysMenu{Path: "/system/user", Name: "User2", Component: "views/system/user/index"}
+	if err := s.CreateMenu(duplicate); !errors.Is(err, ErrDuplicateMenuPath) {
+		t.Errorf("CreateMenu() with a duplicate path error = %v, want ErrDuplicateMenuPath", err)
+	}
+	if duplicate.ID != 0 {
+		t.Errorf("duplicate menu was stored with ID %d", duplicate.ID)
+	}
+
+	// 更新为其他菜单的路径失败，保留自身路径成功
+	role.Path = "/system/user"
+	if err := s.UpdateMenu(role); !errors.Is(err, ErrDuplicateMenuPath) {
+		t.Errorf("UpdateMenu() to another menu's path error = %v, want ErrDuplicateMenuPath", err)
+	}
+	user.Component = "views/system/users/index"
+	if err := s.UpdateMenu(user); err != nil {
+		t.Errorf("UpdateMenu() keeping its own path error = %v", err)
+	}
+
+	// 删除后路径可被复用，路径被占用时不能恢复
+	if err := s.DeleteMenu(user.ID); err != nil {
+		t.Fatalf("DeleteMenu() error = %v", err)
+	}
+	reused := &system.SysMenu{Path: "/system/user", Name: "UserNew", Component: "views/system/user/index"}
+	if err := s.CreateMenu(reused); err != nil {
+		t.Fatalf("CreateMenu() reusing a deleted menu's path error = %v", err)
+	}
+	if err := s.RestoreMenu(user.ID); !errors.Is(err, ErrDuplicateMenuPath) {
+		t.Errorf("RestoreMenu() onto a taken path error = %v, want ErrDuplicateMenuPath", err)
+	}
+}
+
+func TestCreateMenu_AssignsSequentialSort(t *testing.T) {
+	setupTestEnv(t)
+	s := MenuService{}
+
+	parent := &system.SysMenu{Path: "/system", Name: "System", Component: "Layout"}
+	if err := s.CreateMenu(parent); err != nil {
+		t.Fatalf("CreateMenu() error = %v", err)
+	}
+	if parent.Sort != 1 {
+		t.Errorf("first top-level menu sort = %d, want 1", parent.Sort)
+	}
+
+	for i := 1; i <= 5; i++ {
+		menu := &system.SysMenu{
+			ParentID:  parent.ID,
+			Path:      fmt.Sprintf("/system/page%d", i),
+			Name:      fmt.Sprintf("Page%d", i),
+			Component: fmt.Sprintf("views/system/page%d/index", i),
+		}
+		if err := s.CreateMenu(menu); err != nil {
+			t.Fatalf("CreateMenu() error = %v", err)
+		}
+		if menu.Sort != i {
+			t.Errorf("child menu %d sort = %d, want %d", i, menu.Sort, i)
+		}
+	}
+
+	// 显式指定的排序保持不变，且按父菜单分别计数
+	explicit := &system.SysMenu{ParentID: parent.ID, Path: "/system/pinned", Name: "Pinned", Component: "views/system/pinned/index", Sort: 10}
+	if err := s.CreateMenu(explicit); err != nil {
+		t.Fatalf("CreateMenu() error = %v", err)
+	}
+	if explicit.Sort != 10 {
+		t.Errorf("explicit sort = %d, want 10", explicit.Sort)
+	}
+	sibling := &system.SysMenu{Path: "/monitor", Name: "Monitor", Component: "Layout"}
+	if err := s.CreateMenu(sibling); err != nil {
+		t.Fatalf("CreateMenu() error = %v", err)
+	}
+	if sibling.Sort != 2 {
+		t.Errorf("second top-level menu sort = %d, want 2", sibling.Sort)
+	}
+}
+
+func TestSortMenusByParent_ReordersSiblings(t *testing.T) {
+	setupTestEnv(t)
+	s := MenuService{}
+	parent := createTestMenu(t, "/system", "System", "Layout")
+
+	children := make([]*system.SysMenu, 0, 5)
+	for i := 1; i <= 5; i++ {
+		child := &system.SysMenu{ParentID: parent.ID, Path: fmt.Sprintf("/system/page%d", i), Name: fmt.Sprintf("Page%d", i), Component: "views/page", Sort: i}
+		if err := global.DB.Create(child).Error; err != nil {
+			t.Fatalf("failed to create menu: %v", err)
+		}
+		children = append(children, child)
+	}
+
+	// 倒序排列后再交换中间两项
+	order := []uint{children[4].ID, children[3].ID, children[1].ID, children[2].ID, children[0].ID}
+	if err := s.SortMenusByParent(parent.ID, order); err != nil {
+		t.Fatalf("SortMenusByParent() error = %v", err)
+	}
+	var sorted []system.SysMenu
+	if err := global.DB.Where("parent_id = ?", parent.ID).Order("sort ASC").Find(&sorted).Error; err != nil {
+		t.Fatalf("failed to load menus: %v", err)
+	}
+	for i, menu := range sorted {
+		if menu.ID != order[i] || menu.Sort != i+1 {
+			t.Errorf("position %d = menu %d with sort %d, want menu %d with sort %d", i, menu.ID, menu.Sort, order[i], i+1)
+		}
+	}
+
+	tests := []struct {
+		name string
+		ids  []uint
+	}{
+		{"missing sibling", order[:4]},
+		{"duplicate id", []uint{order[0], order[0], order[1], order[2], order[3]}},
+		{"menu of another parent", append([]uint{parent.ID}, order[:4]...)},
+		{"empty list", nil},
+	}
+	for _, tt := range tests {
+		if err := s.SortMenusByParent(parent.ID, tt.ids); err == nil {
+			t.Errorf("SortMenusByParent() with %s succeeded", tt.name)
+		}
+	}
+	// 失败的重排不修改已有排序
+	var unchanged []system.SysMenu
+	if err := global.DB.Where("parent_id = ?", parent.ID).Order("sort ASC").Find(&unchanged).Error; err != nil {
+		t.Fatalf("failed to load menus: %v", err)
+	}
+	if got := menuIDs(unchanged); !reflect.DeepEqual(got, order) {
+		t.Errorf("order after rejected requests = %v, want %v", got, order)
+	}
+}
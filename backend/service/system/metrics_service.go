@@ -0,0 +1,118 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"k-admin-system/global"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// latencyRetention 请求耗时样本的保留时间
+const latencyRetention = 24 * time.Hour
+
+// latencyMaxSamples 每个路由最多保留的样本数，超出时只保留最新的样本，避免高频路由的有序集合无限增长
+const latencyMaxSamples = 5000
+
+// MetricsService 请求指标服务
+type MetricsService struct{}
+
+// LatencyStat 路由耗时百分位统计
+type LatencyStat struct {
+	Path       string  `json:"path"`
+	Percentile float64 `json:"percentile"`
+	LatencyMs  float64 `json:"latencyMs"`
+	Samples    int     `json:"samples"`
+}
+
+// RecordLatency 记录一次请求耗时
+// 样本写入有序集合 latency:<path>，score 为记录时间（毫秒时间戳），member 为 "<耗时毫秒>:<纳秒时间戳>"，
+// 追加纳秒时间戳保证相同耗时的样本不会互相覆盖；同时清理超过保留时间的样本，并只保留最新的 latencyMaxSamples 个样本
+func (s *MetricsService) RecordLatency(path string, latency time.Duration) error {
+	if global.RedisClient == nil {
+		return errors.New("redis client not initialized")
+	}
+
+	ctx := context.Background()
+	key := latencyKey(path)
+	now := time.Now()
+	member := fmt.Sprintf("%s:%d", strconv.FormatFloat(float64(latency.Microseconds())/1000, 'f', 3, 64), now.UnixNano())
+	cutoff := now.Add(-latencyRetention).UnixMilli()
+
+	_, err := global.RedisClient.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, key, redis.Z{Score: float64(now.UnixMilli()), Member: member})
+		pipe.ZRemRangeByScore(ctx, key, "-inf", fmt.Sprintf("(%d", cutoff))
+		pipe.ZRemRangeByRank(ctx, key, 0, -latencyMaxSamples-1)
+		pipe.Expire(ctx, key, latencyRetention)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record latency: %w", err)
+	}
+	return nil
+}
+
+// GetLatencyPercentile 计算路由最近24小时（最多 latencyMaxSamples 个最新样本）请求耗时的第 percentile 百分位（毫秒）
+// path 为路由模式（如 /api/v1/user/:id），与记录时使用的 gin 路由一致
+func (s *MetricsService) GetLatencyPercentile(path string, percentile float64) (*LatencyStat, error) {
+	if global.RedisClient == nil {
+		return nil, errors.New("redis client not initialized")
+	}
+	if percentile <= 0 || percentile > 100 {
+		return nil, errors.New("percentile must be greater than 0 and at most 100")
+	}
+
+	ctx := context.Background()
+	cutoff := time.Now().Add(-latencyRetention).UnixMilli()
+	members, err := global.RedisClient.ZRangeByScore(ctx, latencyKey(path), &redis.ZRangeBy{
+		Min: strconv.FormatInt(cutoff, 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query latency samples: %w", err)
+	}
+
+	values := make([]float64, 0, len(members))
+	for _, member := range members {
+		ms, _, _ := strings.Cut(member, ":")
+		value, err := strconv.ParseFloat(ms, 64)
+		if err != nil {
+			continue
+		}
+		values = append(values, value)
+	}
+	if len(values) == 0 {
+		return nil, errors.New("no latency samples for path")
+	}
+
+	return &LatencyStat{
+		Path:       path,
+		Percentile: percentile,
+		LatencyMs:  latencyPercentile(values, percentile),
+		Samples:    len(values),
+	}, nil
+}
+
+// latencyPercentile 使用最近秩法计算百分位：排序后取第 ceil(p/100*N) 个值
+func latencyPercentile(values []float64, percentile float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	rank := int(math.Ceil(percentile / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// latencyKey 路由耗时样本的缓存键
+func latencyKey(path string) string {
+	return "latency:" + path
+}
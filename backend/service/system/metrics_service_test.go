@@ -0,0 +1,72 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"k-admin-system/global"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestGetLatencyPercentile_P50(t *testing.T) {
+	setupTestEnv(t)
+	s := &MetricsService{}
+	route := "/api/v1/user/:id"
+
+	for _, ms := range []int{50, 10, 40, 20, 30} {
+		if err := s.RecordLatency(route, time.Duration(ms)*time.Millisecond); err != nil {
+			t.Fatalf("RecordLatency() error = %v", err)
+		}
+	}
+
+	stat, err := s.GetLatencyPercentile(route, 50)
+	if err != nil {
+		t.Fatalf("GetLatencyPercentile() error = %v", err)
+	}
+	if stat.LatencyMs != 30 || stat.Samples != 5 {
+		t.Errorf("GetLatencyPercentile(50) = %+v, want 30ms over 5 samples", stat)
+	}
+
+	if _, err := s.GetLatencyPercentile("/api/v1/unknown", 50); err == nil {
+		t.Error("GetLatencyPercentile() for a route without samples succeeded")
+	}
+}
+
+func TestRecordLatency_KeepsNewestSamples(t *testing.T) {
+	setupTestEnv(t)
+	s := &MetricsService{}
+	route := "/api/v1/menu/list"
+
+	// 预先写入超出上限的样本，再记录一次触发裁剪
+	now := time.Now().UnixMilli()
+	samples := make([]redis.Z, 0, latencyMaxSamples+10)
+	for i := 0; i < latencyMaxSamples+10; i++ {
+		samples = append(samples, redis.Z{Score: float64(now - int64(i)), Member: fmt.Sprintf("1.000:%d", i)})
+	}
+	if err := global.RedisClient.ZAdd(context.Background(), latencyKey(route), samples...).Err(); err != nil {
+		t.Fatalf("ZAdd() error = %v", err)
+	}
+	if err := s.RecordLatency(route, 2*time.Millisecond); err != nil {
+		t.Fatalf("RecordLatency() error = %v", err)
+	}
+
+	count, err := global.RedisClient.ZCard(context.Background(), latencyKey(route)).Result()
+	if err != nil {
+		t.Fatalf("ZCard() error = %v", err)
+	}
+	if count != latencyMaxSamples {
+		t.Errorf("stored samples = %d, want %d", count, latencyMaxSamples)
+	}
+
+	// 最新的样本必须保留，被裁剪的是最旧的样本
+	stat, err := s.GetLatencyPercentile(route, 100)
+	if err != nil {
+		t.Fatalf("GetLatencyPercentile() error = %v", err)
+	}
+	if stat.LatencyMs != 2 {
+		t.Errorf("newest sample was trimmed, max latency = %v, want 2", stat.LatencyMs)
+	}
+}
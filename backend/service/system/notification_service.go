@@ -0,0 +1,106 @@
+package system
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"k-admin-system/global"
+	"k-admin-system/model/system"
+	"k-admin-system/utils"
+
+	"gorm.io/gorm"
+)
+
+// notificationBatchSize 广播通知时每批插入的用户通知记录数
+const notificationBatchSize = 500
+
+// NotificationService 站内通知服务
+type NotificationService struct{}
+
+// Broadcast 向所有启用的用户广播通知
+// 通知记录和每个用户的接收记录在同一事务中写入，任一步失败则全部回滚
+func (s *NotificationService) Broadcast(title, content string, createdBy uint) (*system.SysNotification, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return nil, err
+	}
+
+	notification := &system.SysNotification{
+		Title:     title,
+		Content:   content,
+		CreatedBy: createdBy,
+	}
+
+	err := global.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(notification).Error; err != nil {
+			return fmt.Errorf("failed to create notification: %w", err)
+		}
+
+		var userIDs []uint
+		if err := tx.Model(&system.SysUser{}).Where("active = ?", true).Pluck("id", &userIDs).Error; err != nil {
+			return fmt.Errorf("failed to query active users: %w", err)
+		}
+		if len(userIDs) == 0 {
+			return nil
+		}
+
+		recipients := make([]system.SysUserNotification, 0, len(userIDs))
+		for _, userID := range userIDs {
+			recipients = append(recipients, system.SysUserNotification{
+				NotificationID: notification.ID,
+				UserID:         userID,
+			})
+		}
+		if err := tx.CreateInBatches(recipients, notificationBatchSize).Error; err != nil {
+			return fmt.Errorf("failed to create user notifications: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return notification, nil
+}
+
+// GetUnreadNotifications 获取用户的未读通知，按创建时间倒序
+func (s *NotificationService) GetUnreadNotifications(userID uint) ([]system.SysNotification, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return nil, err
+	}
+
+	notifications := make([]system.SysNotification, 0)
+	if err := global.DB.Model(&system.SysNotification{}).
+		Joins("JOIN sys_user_notifications ON sys_user_notifications.notification_id = sys_notifications.id").
+		Where("sys_user_notifications.user_id = ? AND sys_user_notifications.read_at IS NULL", userID).
+		Order("sys_notifications.created_at DESC").
+		Find(&notifications).Error; err != nil {
+		return nil, fmt.Errorf("failed to query notifications: %w", err)
+	}
+	return notifications, nil
+}
+
+// MarkAsRead 将用户的通知标记为已读，已读的通知保持原已读时间
+func (s *NotificationService) MarkAsRead(userID, notificationID uint) error {
+	if err := utils.DBMustInit(); err != nil {
+		return err
+	}
+
+	var userNotification system.SysUserNotification
+	if err := global.DB.Where("notification_id = ? AND user_id = ?", notificationID, userID).
+		First(&userNotification).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("notification not found")
+		}
+		return fmt.Errorf("failed to query notification: %w", err)
+	}
+
+	if userNotification.ReadAt != nil {
+		return nil
+	}
+
+	if err := global.DB.Model(&userNotification).Update("read_at", time.Now()).Error; err != nil {
+		return fmt.Errorf("failed to mark notification as read: %w", err)
+	}
+	return nil
+}
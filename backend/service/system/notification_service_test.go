@@ -0,0 +1,144 @@
+package system
+
+import (
+	"testing"
+	"time"
+
+	"k-admin-system/global"
+	"k-admin-system/model/system"
+)
+
+func TestBroadcast_FansOutToActiveUsers(t *testing.T) {
+	setupTestEnv(t)
+	role := createTestRole(t, "user")
+	alice := createTestUser(t, "alice", "Password123!", role.ID)
+	bob := createTestUser(t, "bob", "Password123!", role.ID)
+	carol := createTestUser(t, "carol", "Password123!", role.ID)
+	if err := global.DB.Model(carol).Update("active", false).Error; err != nil {
+		t.Fatalf("failed to deactivate user: %v", err)
+	}
+
+	service := &NotificationService{}
+	notification, err := service.Broadcast("维护通知", "系统将于今晚维护", alice.ID)
+	if err != nil {
+		t.Fatalf("Broadcast() error = %v", err)
+	}
+	if notification.ID == 0 {
+		t.Fatal("Broadcast() returned notification without ID")
+	}
+
+	var recipients []system.SysUserNotification
+	if err := global.DB.Where("notification_id = ?", notification.ID).Order("user_id").Find(&recipients).Error; err != nil {
+		t.Fatalf("failed to query recipients: %v", err)
+	}
+	if len(recipients) != 2 {
+		t.Fatalf("Broadcast() created %d recipients, want 2", len(recipients))
+	}
+	if recipients[0].UserID != alice.ID || recipients[1].UserID != bob.ID {
+		t.Errorf("Broadcast() recipients = [%d %d], want [%d %d]", recipients[0].UserID, recipients[1].UserID, alice.ID, bob.ID)
+	}
+	for _, recipient := range recipients {
+		if recipient.ReadAt != nil {
+			t.Errorf("recipient %d ReadAt = %v, want nil", recipient.UserID, recipient.ReadAt)
+		}
+	}
+
+	unread, err := service.GetUnreadNotifications(carol.ID)
+	if err != nil {
+		t.Fatalf("GetUnreadNotifications() error = %v", err)
+	}
+	if len(unread) != 0 {
+		t.Errorf("inactive user has %d unread notifications, want 0", len(unread))
+	}
+}
+
+func TestBroadcast_RollsBackWhenFanOutFails(t *testing.T) {
+	setupTestEnv(t)
+	role := createTestRole(t, "user")
+	createTestUser(t, "alice", "Password123!", role.ID)
+	if err := global.DB.Migrator().DropTable(&system.SysUserNotification{}); err != nil {
+		t.Fatalf("failed to drop table: %v", err)
+	}
+
+	if _, err := (&NotificationService{}).Broadcast("title", "content", 1); err == nil {
+		t.Fatal("Broadcast() error = nil, want fan-out failure")
+	}
+
+	var count int64
+	if err := global.DB.Model(&system.SysNotification{}).Count(&count).Error; err != nil {
+		t.Fatalf("failed to count notifications: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("notification rows after failed broadcast = %d, want 0", count)
+	}
+}
+
+func TestMarkAsRead(t *testing.T) {
+	setupTestEnv(t)
+	role := createTestRole(t, "user")
+	alice := createTestUser(t, "alice", "Password123!", role.ID)
+	bob := createTestUser(t, "bob", "Password123!", role.ID)
+
+	service := &NotificationService{}
+	notification, err := service.Broadcast("title", "content", alice.ID)
+	if err != nil {
+		t.Fatalf("Broadcast() error = %v", err)
+	}
+
+	if err := service.MarkAsRead(alice.ID, notification.ID); err != nil {
+		t.Fatalf("MarkAsRead() error = %v", err)
+	}
+
+	unread, err := service.GetUnreadNotifications(alice.ID)
+	if err != nil {
+		t.Fatalf("GetUnreadNotifications() error = %v", err)
+	}
+	if len(unread) != 0 {
+		t.Errorf("alice unread after MarkAsRead = %d, want 0", len(unread))
+	}
+	// 其他用户的接收记录不受影响
+	unread, err = service.GetUnreadNotifications(bob.ID)
+	if err != nil {
+		t.Fatalf("GetUnreadNotifications() error = %v", err)
+	}
+	if len(unread) != 1 || unread[0].ID != notification.ID {
+		t.Errorf("bob unread = %v, want notification %d", unread, notification.ID)
+	}
+
+	// 重复标记保持原已读时间
+	var first system.SysUserNotification
+	if err := global.DB.Where("notification_id = ? AND user_id = ?", notification.ID, alice.ID).First(&first).Error; err != nil {
+		t.Fatalf("failed to query user notification: %v", err)
+	}
+	if first.ReadAt == nil {
+		t.Fatal("ReadAt = nil after MarkAsRead")
+	}
+	time.Sleep(10 * time.Millisecond)
+	if err := service.MarkAsRead(alice.ID, notification.ID); err != nil {
+		t.Fatalf("second MarkAsRead() error = %v", err)
+	}
+	var second system.SysUserNotification
+	if err := global.DB.Where("notification_id = ? AND user_id = ?", notification.ID, alice.ID).First(&second).Error; err != nil {
+		t.Fatalf("failed to query user notification: %v", err)
+	}
+	if !second.ReadAt.Equal(*first.ReadAt) {
+		t.Errorf("ReadAt changed from %v to %v on second MarkAsRead", first.ReadAt, second.ReadAt)
+	}
+}
+
+func TestMarkAsRead_NotRecipient(t *testing.T) {
+	setupTestEnv(t)
+	role := createTestRole(t, "user")
+	alice := createTestUser(t, "alice", "Password123!", role.ID)
+
+	service := &NotificationService{}
+	notification, err := service.Broadcast("title", "content", alice.ID)
+	if err != nil {
+		t.Fatalf("Broadcast() error = %v", err)
+	}
+
+	err = service.MarkAsRead(alice.ID+100, notification.ID)
+	if err == nil || err.Error() != "notification not found" {
+		t.Errorf("MarkAsRead() for non-recipient error = %v, want notification not found", err)
+	}
+}
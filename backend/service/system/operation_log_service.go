@@ -0,0 +1,76 @@
+package system
+
+import (
+	"fmt"
+	"time"
+
+	"k-admin-system/global"
+	"k-admin-system/model/system"
+	"k-admin-system/utils"
+
+	"gorm.io/gorm"
+)
+
+// OperationLogService 操作日志服务
+type OperationLogService struct{}
+
+// OperationLogFilter 操作日志查询条件，零值字段不参与过滤
+type OperationLogFilter struct {
+	UserID    *uint
+	Module    string
+	Action    string
+	Success   *bool
+	StartTime *time.Time
+	EndTime   *time.Time
+}
+
+// GetLogs 分页查询操作日志，按时间倒序
+func (s *OperationLogService) GetLogs(filter OperationLogFilter, page, pageSize int) ([]system.SysOperationLog, int64, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return nil, 0, err
+	}
+
+	var logs []system.SysOperationLog
+	var total int64
+
+	query := s.buildQuery(filter)
+
+	// 获取总数
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count operation logs: %w", err)
+	}
+
+	// 分页查询
+	offset := (page - 1) * pageSize
+	if err := query.Order("id DESC").Offset(offset).Limit(pageSize).Find(&logs).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to query operation logs: %w", err)
+	}
+
+	return logs, total, nil
+}
+
+// buildQuery 根据过滤条件构建查询
+func (s *OperationLogService) buildQuery(filter OperationLogFilter) *gorm.DB {
+	query := global.DB.Model(&system.SysOperationLog{})
+
+	if filter.UserID != nil {
+		query = query.Where("user_id = ?", *filter.UserID)
+	}
+	if filter.Module != "" {
+		query = query.Where("module = ?", filter.Module)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.Success != nil {
+		query = query.Where("success = ?", *filter.Success)
+	}
+	if filter.StartTime != nil {
+		query = query.Where("created_at >= ?", *filter.StartTime)
+	}
+	if filter.EndTime != nil {
+		query = query.Where("created_at <= ?", *filter.EndTime)
+	}
+
+	return query
+}
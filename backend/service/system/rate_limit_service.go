@@ -0,0 +1,172 @@
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"k-admin-system/global"
+	"k-admin-system/model/system"
+	"k-admin-system/utils"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// rateLimitOverrideCacheTTL 用户限流覆盖配置的缓存时间
+const rateLimitOverrideCacheTTL = 5 * time.Minute
+
+// rateLimitOverrideNone 缓存中表示“该用户没有覆盖配置”的占位值，避免重复查询数据库
+const rateLimitOverrideNone = "none"
+
+// RateLimitOverrideService 用户级限流覆盖服务
+type RateLimitOverrideService struct{}
+
+// GetOverrideList 获取所有用户限流覆盖配置
+func (s *RateLimitOverrideService) GetOverrideList() ([]system.SysRateLimitOverride, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return nil, err
+	}
+
+	var overrides []system.SysRateLimitOverride
+	if err := global.DB.Order("user_id ASC").Find(&overrides).Error; err != nil {
+		return nil, fmt.Errorf("failed to query rate limit overrides: %w", err)
+	}
+
+	return overrides, nil
+}
+
+// SetOverride 创建或更新用户限流覆盖配置
+func (s *RateLimitOverrideService) SetOverride(override *system.SysRateLimitOverride) error {
+	if err := utils.DBMustInit(); err != nil {
+		return err
+	}
+
+	if override.Requests <= 0 {
+		return errors.New("requests must be greater than 0")
+	}
+	if override.WindowSeconds <= 0 {
+		return errors.New("window seconds must be greater than 0")
+	}
+
+	// 检查用户是否存在
+	var user system.SysUser
+	if err := global.DB.First(&user, override.UserID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("user not found")
+		}
+		return fmt.Errorf("failed to query user: %w", err)
+	}
+
+	// 已存在则更新，否则创建
+	var existing system.SysRateLimitOverride
+	err := global.DB.Where("user_id = ?", override.UserID).First(&existing).Error
+	switch {
+	case err == nil:
+		existing.Requests = override.Requests
+		existing.WindowSeconds = override.WindowSeconds
+		if err := global.DB.Save(&existing).Error; err != nil {
+			return fmt.Errorf("failed to update rate limit override: %w", err)
+		}
+		*override = existing
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		if err := global.DB.Create(override).Error; err != nil {
+			return fmt.Errorf("failed to create rate limit override: %w", err)
+		}
+	default:
+		return fmt.Errorf("failed to query rate limit override: %w", err)
+	}
+
+	s.invalidateCache(override.UserID)
+	return nil
+}
+
+// DeleteOverride 删除用户限流覆盖配置，恢复使用全局配置
+func (s *RateLimitOverrideService) DeleteOverride(userID uint) error {
+	if err := utils.DBMustInit(); err != nil {
+		return err
+	}
+
+	// 使用硬删除，保证 user_id 唯一索引可以被再次使用
+	result := global.DB.Unscoped().Where("user_id = ?", userID).Delete(&system.SysRateLimitOverride{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete rate limit override: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("rate limit override not found")
+	}
+
+	s.invalidateCache(userID)
+	return nil
+}
+
+// GetUserOverride 获取用户的限流覆盖配置
+// 优先从Redis缓存读取（5分钟TTL），未命中时查询数据库并回写缓存
+// 用户没有覆盖配置时返回 (nil, nil)
+func (s *RateLimitOverrideService) GetUserOverride(userID uint) (*system.SysRateLimitOverride, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	key := rateLimitOverrideCacheKey(userID)
+
+	// 读取缓存
+	if global.RedisClient != nil {
+		cached, err := global.RedisClient.Get(ctx, key).Result()
+		if err == nil {
+			if cached == rateLimitOverrideNone {
+				return nil, nil
+			}
+			var override system.SysRateLimitOverride
+			if err := json.Unmarshal([]byte(cached), &override); err == nil {
+				return &override, nil
+			}
+		} else if !errors.Is(err, redis.Nil) {
+			global.Logger.Warn("Failed to read rate limit override cache", zap.Error(err))
+		}
+	}
+
+	// 查询数据库
+	var override system.SysRateLimitOverride
+	err := global.DB.Where("user_id = ?", userID).First(&override).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to query rate limit override: %w", err)
+	}
+	found := err == nil
+
+	// 回写缓存
+	if global.RedisClient != nil {
+		value := rateLimitOverrideNone
+		if found {
+			data, _ := json.Marshal(override)
+			value = string(data)
+		}
+		if err := global.RedisClient.Set(ctx, key, value, rateLimitOverrideCacheTTL).Err(); err != nil {
+			global.Logger.Warn("Failed to write rate limit override cache", zap.Error(err))
+		}
+	}
+
+	if !found {
+		return nil, nil
+	}
+	return &override, nil
+}
+
+// invalidateCache 清除用户限流覆盖配置缓存
+func (s *RateLimitOverrideService) invalidateCache(userID uint) {
+	if global.RedisClient == nil {
+		return
+	}
+	if err := global.RedisClient.Del(context.Background(), rateLimitOverrideCacheKey(userID)).Err(); err != nil {
+		global.Logger.Warn("Failed to invalidate rate limit override cache", zap.Error(err))
+	}
+}
+
+// rateLimitOverrideCacheKey 用户限流覆盖配置的缓存键
+func rateLimitOverrideCacheKey(userID uint) string {
+	return fmt.Sprintf("rate_limit:override:%d", userID)
+}
@@ -3,21 +3,51 @@ package system
 import (
 	"errors"
 	"fmt"
+	"strings"
 
+	"k-admin-system/core"
 	"k-admin-system/global"
 	"k-admin-system/model/system"
+	"k-admin-system/utils"
 
+	"github.com/casbin/casbin/v3/util"
+	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // RoleService 角色服务
 type RoleService struct{}
 
+// CasbinPolicy Casbin策略（角色标识、API路径、HTTP方法）
+type CasbinPolicy struct {
+	RoleKey string `json:"roleKey"`
+	Path    string `json:"path"`
+	Method  string `json:"method"`
+}
+
+// CasbinPolicyDTO 角色的API权限及其可读描述
+// Description 为匹配到的Gin路由处理函数名（如 UserApi.GetUserList），未匹配到路由时为空
+type CasbinPolicyDTO struct {
+	Path        string `json:"path"`
+	Method      string `json:"method"`
+	Description string `json:"description"`
+}
+
 // CreateRole 创建角色
 func (s *RoleService) CreateRole(role *system.SysRole) error {
-	// 检查角色键是否已存在（排除软删除的记录）
+	if err := utils.DBMustInit(); err != nil {
+		return err
+	}
+
+	// 校验数据库检查器行级过滤配置
+	if _, err := role.DBInspectorFilters(); err != nil {
+		return err
+	}
+
+	// 检查角色键是否已存在（包含软删除的记录，role_key 唯一索引同样覆盖已删除的行）
 	var count int64
-	if err := global.DB.Model(&system.SysRole{}).Where("role_key = ? AND deleted_at IS NULL", role.RoleKey).Count(&count).Error; err != nil {
+	if err := global.DB.Unscoped().Model(&system.SysRole{}).Where("role_key = ?", role.RoleKey).Count(&count).Error; err != nil {
 		return fmt.Errorf("failed to check role key uniqueness: %w", err)
 	}
 	if count > 0 {
@@ -32,8 +62,121 @@ func (s *RoleService) CreateRole(role *system.SysRole) error {
 	return nil
 }
 
+// CreateRoleWithMenusAndPolicies 原子地创建角色、分配菜单并写入Casbin策略
+// policies 格式: [][]string{{"path", "method"}, ...}，策略主体为新角色的 RoleKey
+// 角色和菜单关联在同一事务中写入，事务提交后再添加策略；添加策略失败时删除已创建的角色，
+// 保证任一步骤失败都不会留下角色、菜单关联或策略
+func (s *RoleService) CreateRoleWithMenusAndPolicies(role *system.SysRole, menuIDs []uint, policies [][]string) error {
+	if err := utils.DBMustInit(); err != nil {
+		return err
+	}
+
+	if global.CasbinEnforcer == nil {
+		return errors.New("casbin enforcer not initialized")
+	}
+
+	// 校验数据库检查器行级过滤配置
+	if _, err := role.DBInspectorFilters(); err != nil {
+		return err
+	}
+
+	// 预先校验策略，避免事务提交后才发现无效数据
+	rules := make([][]string, 0, len(policies))
+	for i, policy := range policies {
+		if len(policy) != 2 {
+			return fmt.Errorf("policy %d: expected [path, method]", i+1)
+		}
+		path := strings.TrimSpace(policy[0])
+		method := strings.ToUpper(strings.TrimSpace(policy[1]))
+		if !strings.HasPrefix(path, "/") {
+			return fmt.Errorf("policy %d: path must start with /", i+1)
+		}
+		if !validPolicyMethods[method] {
+			return fmt.Errorf("policy %d: invalid HTTP method %q", i+1, policy[1])
+		}
+		rules = append(rules, []string{role.RoleKey, path, method})
+	}
+
+	err := global.DB.Transaction(func(tx *gorm.DB) error {
+		// 检查角色键是否已存在（包含软删除的记录）
+		var count int64
+		if err := tx.Unscoped().Model(&system.SysRole{}).Where("role_key = ?", role.RoleKey).Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to check role key uniqueness: %w", err)
+		}
+		if count > 0 {
+			return errors.New("role key already exists")
+		}
+
+		// 检查菜单是否都存在
+		if len(menuIDs) > 0 {
+			uniqueIDs := make(map[uint]struct{}, len(menuIDs))
+			for _, id := range menuIDs {
+				uniqueIDs[id] = struct{}{}
+			}
+			var menuCount int64
+			if err := tx.Model(&system.SysMenu{}).Where("id IN ?", menuIDs).Count(&menuCount).Error; err != nil {
+				return fmt.Errorf("failed to query menus: %w", err)
+			}
+			if menuCount != int64(len(uniqueIDs)) {
+				return errors.New("menu not found")
+			}
+		}
+
+		if err := tx.Create(role).Error; err != nil {
+			return fmt.Errorf("failed to create role: %w", err)
+		}
+
+		return s.assignMenus(tx, role.ID, menuIDs)
+	})
+	if err != nil {
+		return err
+	}
+
+	// 事务提交后写入策略，失败时回滚已创建的角色
+	if err := s.addPolicies(rules); err != nil {
+		if cleanupErr := global.DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Model(role).Association("Menus").Clear(); err != nil {
+				return err
+			}
+			return tx.Unscoped().Delete(role).Error
+		}); cleanupErr != nil {
+			return fmt.Errorf("%w (failed to roll back role: %v)", err, cleanupErr)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// addPolicies 添加尚不存在的Casbin策略
+func (s *RoleService) addPolicies(rules [][]string) error {
+	missing := make([][]string, 0, len(rules))
+	for _, rule := range rules {
+		exists, err := global.CasbinEnforcer.HasPolicy(rule)
+		if err != nil {
+			return fmt.Errorf("failed to check policy: %w", err)
+		}
+		if !exists {
+			missing = append(missing, rule)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	if _, err := global.CasbinEnforcer.AddPolicies(missing); err != nil {
+		return fmt.Errorf("failed to add policies: %w", err)
+	}
+	core.InvalidateCasbinCache()
+	return nil
+}
+
 // UpdateRole 更新角色信息
 func (s *RoleService) UpdateRole(role *system.SysRole) error {
+	if err := utils.DBMustInit(); err != nil {
+		return err
+	}
+
 	// 检查角色是否存在
 	var existingRole system.SysRole
 	if err := global.DB.First(&existingRole, role.ID).Error; err != nil {
@@ -43,11 +186,22 @@ func (s *RoleService) UpdateRole(role *system.SysRole) error {
 		return fmt.Errorf("failed to query role: %w", err)
 	}
 
-	// 如果更新角色键，检查新角色键是否已被其他角色使用（排除软删除的记录）
-	if role.RoleKey != existingRole.RoleKey {
+	// 校验数据库检查器行级过滤配置
+	if _, err := role.DBInspectorFilters(); err != nil {
+		return err
+	}
+
+	// 如果更新角色键，检查新角色键是否已被其他角色使用（包含软删除的记录）
+	keyChanged := role.RoleKey != existingRole.RoleKey
+	if keyChanged {
+		// 受保护角色的角色键被超级管理员放行和鉴权逻辑引用，不允许修改
+		if isProtectedRole(existingRole.RoleKey) {
+			return errors.New("cannot change key of protected role")
+		}
+
 		var count int64
-		if err := global.DB.Model(&system.SysRole{}).
-			Where("role_key = ? AND id != ? AND deleted_at IS NULL", role.RoleKey, role.ID).
+		if err := global.DB.Unscoped().Model(&system.SysRole{}).
+			Where("role_key = ? AND id != ?", role.RoleKey, role.ID).
 			Count(&count).Error; err != nil {
 			return fmt.Errorf("failed to check role key uniqueness: %w", err)
 		}
@@ -61,11 +215,124 @@ func (s *RoleService) UpdateRole(role *system.SysRole) error {
 		return fmt.Errorf("failed to update role: %w", err)
 	}
 
+	// 角色保存成功后将 Casbin 策略迁移到新角色键，迁移失败时恢复原角色记录
+	if keyChanged {
+		if err := s.ValidateRoleKeyChange(existingRole.RoleKey, role.RoleKey); err != nil {
+			if rollbackErr := global.DB.Save(&existingRole).Error; rollbackErr != nil {
+				return fmt.Errorf("%w (role rollback failed: %v)", err, rollbackErr)
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ValidateRoleKeyChange 角色键变更时将 Casbin 策略迁移到新角色键，避免旧角色键下的策略成为孤立策略
+// 权限策略（p）和角色继承关系（g）分别整批替换，gorm adapter 在事务中执行每批替换；
+// 新角色键下已有策略时拒绝迁移，避免与残留策略合并
+func (s *RoleService) ValidateRoleKeyChange(oldKey, newKey string) error {
+	if oldKey == newKey {
+		return nil
+	}
+	if newKey == "" {
+		return errors.New("role key is required")
+	}
+
+	existing, err := global.CasbinEnforcer.GetFilteredPolicy(0, newKey)
+	if err != nil {
+		return fmt.Errorf("failed to get policies: %w", err)
+	}
+	if len(existing) > 0 {
+		return fmt.Errorf("role key %s already has Casbin policies", newKey)
+	}
+
+	// 权限策略
+	oldPolicies, err := global.CasbinEnforcer.GetFilteredPolicy(0, oldKey)
+	if err != nil {
+		return fmt.Errorf("failed to get policies: %w", err)
+	}
+	newPolicies := replaceRoleKey(oldPolicies, oldKey, newKey)
+	if len(oldPolicies) > 0 {
+		if _, err := global.CasbinEnforcer.UpdatePolicies(oldPolicies, newPolicies); err != nil {
+			return fmt.Errorf("failed to migrate policies: %w", err)
+		}
+	}
+
+	// 角色继承关系，角色键可能出现在任一位置
+	groupingPolicies, err := global.CasbinEnforcer.GetGroupingPolicy()
+	if err != nil {
+		return fmt.Errorf("failed to get grouping policies: %w", err)
+	}
+	var oldGrouping [][]string
+	for _, rule := range groupingPolicies {
+		for _, field := range rule {
+			if field == oldKey {
+				oldGrouping = append(oldGrouping, rule)
+				break
+			}
+		}
+	}
+	if len(oldGrouping) > 0 {
+		if _, err := global.CasbinEnforcer.UpdateGroupingPolicies(oldGrouping, replaceRoleKey(oldGrouping, oldKey, newKey)); err != nil {
+			// 撤销已迁移的权限策略
+			if len(oldPolicies) > 0 {
+				if _, rollbackErr := global.CasbinEnforcer.UpdatePolicies(newPolicies, oldPolicies); rollbackErr != nil {
+					return fmt.Errorf("failed to migrate grouping policies: %w (policy rollback failed: %v)", err, rollbackErr)
+				}
+			}
+			return fmt.Errorf("failed to migrate grouping policies: %w", err)
+		}
+	}
+
+	core.InvalidateCasbinCache()
+	return nil
+}
+
+// replaceRoleKey 返回将规则中所有 oldKey 字段替换为 newKey 后的副本
+func replaceRoleKey(rules [][]string, oldKey, newKey string) [][]string {
+	replaced := make([][]string, len(rules))
+	for i, rule := range rules {
+		newRule := make([]string, len(rule))
+		for j, field := range rule {
+			if field == oldKey {
+				field = newKey
+			}
+			newRule[j] = field
+		}
+		replaced[i] = newRule
+	}
+	return replaced
+}
+
+// UpdateRemark 仅更新角色备注
+func (s *RoleService) UpdateRemark(roleID uint, remark string) error {
+	if err := utils.DBMustInit(); err != nil {
+		return err
+	}
+
+	// 检查角色是否存在
+	var count int64
+	if err := global.DB.Model(&system.SysRole{}).Where("id = ?", roleID).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to query role: %w", err)
+	}
+	if count == 0 {
+		return errors.New("role not found")
+	}
+
+	if err := global.DB.Model(&system.SysRole{}).Where("id = ?", roleID).Update("remark", remark).Error; err != nil {
+		return fmt.Errorf("failed to update role remark: %w", err)
+	}
+
 	return nil
 }
 
 // DeleteRole 删除角色
 func (s *RoleService) DeleteRole(id uint) error {
+	if err := utils.DBMustInit(); err != nil {
+		return err
+	}
+
 	// 检查角色是否存在
 	var role system.SysRole
 	if err := global.DB.First(&role, id).Error; err != nil {
@@ -74,6 +341,9 @@ func (s *RoleService) DeleteRole(id uint) error {
 		}
 		return fmt.Errorf("failed to query role: %w", err)
 	}
+	if isProtectedRole(role.RoleKey) {
+		return errors.New("cannot delete protected role")
+	}
 
 	// 检查是否有用户关联此角色
 	var userCount int64
@@ -92,8 +362,110 @@ func (s *RoleService) DeleteRole(id uint) error {
 	return nil
 }
 
+// DeleteRoleWithReassignment 将角色下的用户迁移到替代角色后删除角色
+// 替代角色必须存在且处于启用状态；校验替代角色、迁移用户和（软）删除角色在同一事务中执行，
+// 事务提交后移除该角色键下的 Casbin 策略
+func (s *RoleService) DeleteRoleWithReassignment(roleID, replacementRoleID uint) error {
+	if err := utils.DBMustInit(); err != nil {
+		return err
+	}
+
+	if roleID == replacementRoleID {
+		return errors.New("replacement role must differ from the deleted role")
+	}
+
+	var role system.SysRole
+	err := global.DB.Transaction(func(tx *gorm.DB) error {
+		// 检查角色是否存在
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&role, roleID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("role not found")
+			}
+			return fmt.Errorf("failed to query role: %w", err)
+		}
+		if isProtectedRole(role.RoleKey) {
+			return errors.New("cannot delete protected role")
+		}
+
+		// 检查替代角色是否存在且启用，加锁防止其在迁移期间被删除或禁用
+		var replacement system.SysRole
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&replacement, replacementRoleID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("replacement role not found")
+			}
+			return fmt.Errorf("failed to query replacement role: %w", err)
+		}
+		if !replacement.Status {
+			return errors.New("replacement role is disabled")
+		}
+
+		// 迁移用户到替代角色
+		if err := tx.Model(&system.SysUser{}).Where("role_id = ?", roleID).
+			Update("role_id", replacementRoleID).Error; err != nil {
+			return fmt.Errorf("failed to reassign users: %w", err)
+		}
+
+		// 删除角色
+		if err := tx.Delete(&role).Error; err != nil {
+			return fmt.Errorf("failed to delete role: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.removeRolePolicies(role.RoleKey)
+}
+
+// isProtectedRole 判断角色是否受保护（内置管理员角色或配置的超级管理员角色），受保护角色不允许删除
+func isProtectedRole(roleKey string) bool {
+	if roleKey == "admin" {
+		return true
+	}
+	return global.Config != nil && global.Config.Casbin.SuperuserRoleKey != "" &&
+		roleKey == global.Config.Casbin.SuperuserRoleKey
+}
+
+// removeRolePolicies 移除角色键下的权限策略（p）以及包含该角色键的角色继承关系（g）
+func (s *RoleService) removeRolePolicies(roleKey string) error {
+	if global.CasbinEnforcer == nil {
+		return nil
+	}
+
+	if _, err := global.CasbinEnforcer.RemoveFilteredPolicy(0, roleKey); err != nil {
+		return fmt.Errorf("failed to remove role policies: %w", err)
+	}
+
+	groupingPolicies, err := global.CasbinEnforcer.GetGroupingPolicy()
+	if err != nil {
+		return fmt.Errorf("failed to get grouping policies: %w", err)
+	}
+	var roleGrouping [][]string
+	for _, rule := range groupingPolicies {
+		for _, field := range rule {
+			if field == roleKey {
+				roleGrouping = append(roleGrouping, rule)
+				break
+			}
+		}
+	}
+	if len(roleGrouping) > 0 {
+		if _, err := global.CasbinEnforcer.RemoveGroupingPolicies(roleGrouping); err != nil {
+			return fmt.Errorf("failed to remove role grouping policies: %w", err)
+		}
+	}
+
+	core.InvalidateCasbinCache()
+	return nil
+}
+
 // GetRoleByID 根据ID获取角色
 func (s *RoleService) GetRoleByID(id uint) (*system.SysRole, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return nil, err
+	}
+
 	var role system.SysRole
 	if err := global.DB.First(&role, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -105,11 +477,43 @@ func (s *RoleService) GetRoleByID(id uint) (*system.SysRole, error) {
 	return &role, nil
 }
 
-// GetRoleList 获取角色列表（支持分页）
-func (s *RoleService) GetRoleList(page, pageSize int) ([]system.SysRole, int64, error) {
+// roleSortFields 角色列表允许排序的字段（请求参数 → 数据库列），防止SQL注入
+var roleSortFields = map[string]string{
+	"sort":       "sort",
+	"createdAt":  "created_at",
+	"created_at": "created_at",
+	"roleName":   "role_name",
+	"role_name":  "role_name",
+}
+
+// GetRoleList 获取角色列表（支持分页和排序）
+// sortBy 为空时按 sort 升序、ID 倒序排列；sortOrder 为 asc 或 desc，默认 asc
+func (s *RoleService) GetRoleList(page, pageSize int, sortBy, sortOrder string) ([]system.SysRole, int64, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return nil, 0, err
+	}
+
 	var roles []system.SysRole
 	var total int64
 
+	// 构建排序条件
+	orderBy := "sort ASC, id DESC"
+	if sortBy != "" {
+		field, ok := roleSortFields[sortBy]
+		if !ok {
+			return nil, 0, fmt.Errorf("invalid sort field: %s", sortBy)
+		}
+		order := "ASC"
+		switch strings.ToLower(sortOrder) {
+		case "", "asc":
+		case "desc":
+			order = "DESC"
+		default:
+			return nil, 0, fmt.Errorf("invalid sort order: %s", sortOrder)
+		}
+		orderBy = fmt.Sprintf("%s %s, id DESC", field, order)
+	}
+
 	// 获取总数
 	if err := global.DB.Model(&system.SysRole{}).Count(&total).Error; err != nil {
 		return nil, 0, fmt.Errorf("failed to count roles: %w", err)
@@ -117,18 +521,56 @@ func (s *RoleService) GetRoleList(page, pageSize int) ([]system.SysRole, int64,
 
 	// 分页查询
 	offset := (page - 1) * pageSize
-	if err := global.DB.Offset(offset).Limit(pageSize).Order("sort ASC, id DESC").Find(&roles).Error; err != nil {
+	if err := global.DB.Offset(offset).Limit(pageSize).Order(orderBy).Find(&roles).Error; err != nil {
 		return nil, 0, fmt.Errorf("failed to query roles: %w", err)
 	}
 
 	return roles, total, nil
 }
 
+// RoleMenuAssignment 单个角色的菜单分配
+type RoleMenuAssignment struct {
+	RoleID  uint   `json:"roleId" binding:"required"`
+	MenuIDs []uint `json:"menuIds"`
+}
+
 // AssignMenus 为角色分配菜单权限
 func (s *RoleService) AssignMenus(roleID uint, menuIDs []uint) error {
+	if err := utils.DBMustInit(); err != nil {
+		return err
+	}
+
+	// 使用事务更新角色菜单关联
+	return global.DB.Transaction(func(tx *gorm.DB) error {
+		return s.assignMenus(tx, roleID, menuIDs)
+	})
+}
+
+// BulkAssignMenus 在单个事务中为多个角色分配菜单权限，任一角色失败则全部回滚
+func (s *RoleService) BulkAssignMenus(assignments []RoleMenuAssignment) error {
+	if err := utils.DBMustInit(); err != nil {
+		return err
+	}
+
+	if len(assignments) == 0 {
+		return errors.New("no assignments provided")
+	}
+
+	return global.DB.Transaction(func(tx *gorm.DB) error {
+		for _, assignment := range assignments {
+			if err := s.assignMenus(tx, assignment.RoleID, assignment.MenuIDs); err != nil {
+				return fmt.Errorf("role %d: %w", assignment.RoleID, err)
+			}
+		}
+		return nil
+	})
+}
+
+// assignMenus 在给定事务中替换角色的菜单关联
+func (s *RoleService) assignMenus(tx *gorm.DB, roleID uint, menuIDs []uint) error {
 	// 检查角色是否存在
 	var role system.SysRole
-	if err := global.DB.First(&role, roleID).Error; err != nil {
+	if err := tx.First(&role, roleID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return errors.New("role not found")
 		}
@@ -138,30 +580,21 @@ func (s *RoleService) AssignMenus(roleID uint, menuIDs []uint) error {
 	// 查询菜单
 	var menus []system.SysMenu
 	if len(menuIDs) > 0 {
-		if err := global.DB.Where("id IN ?", menuIDs).Find(&menus).Error; err != nil {
+		if err := tx.Where("id IN ?", menuIDs).Find(&menus).Error; err != nil {
 			return fmt.Errorf("failed to query menus: %w", err)
 		}
 	}
 
-	// 使用事务更新角色菜单关联
-	err := global.DB.Transaction(func(tx *gorm.DB) error {
-		// 清除现有关联
-		if err := tx.Model(&role).Association("Menus").Clear(); err != nil {
-			return fmt.Errorf("failed to clear existing menu associations: %w", err)
-		}
+	// 清除现有关联
+	if err := tx.Model(&role).Association("Menus").Clear(); err != nil {
+		return fmt.Errorf("failed to clear existing menu associations: %w", err)
+	}
 
-		// 添加新关联
-		if len(menus) > 0 {
-			if err := tx.Model(&role).Association("Menus").Append(&menus); err != nil {
-				return fmt.Errorf("failed to assign menus: %w", err)
-			}
+	// 添加新关联
+	if len(menus) > 0 {
+		if err := tx.Model(&role).Association("Menus").Append(&menus); err != nil {
+			return fmt.Errorf("failed to assign menus: %w", err)
 		}
-
-		return nil
-	})
-
-	if err != nil {
-		return err
 	}
 
 	return nil
@@ -169,6 +602,10 @@ func (s *RoleService) AssignMenus(roleID uint, menuIDs []uint) error {
 
 // GetRoleMenus 获取角色的菜单权限
 func (s *RoleService) GetRoleMenus(roleID uint) ([]uint, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return nil, err
+	}
+
 	// 检查角色是否存在
 	var role system.SysRole
 	if err := global.DB.First(&role, roleID).Error; err != nil {
@@ -193,9 +630,34 @@ func (s *RoleService) GetRoleMenus(roleID uint) ([]uint, error) {
 	return menuIDs, nil
 }
 
+// GetRoleMenuTree 获取角色已分配菜单的树形结构（按排序号排序）
+func (s *RoleService) GetRoleMenuTree(roleID uint) ([]system.SysMenu, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return nil, err
+	}
+
+	// 检查角色是否存在并加载菜单
+	var role system.SysRole
+	if err := global.DB.Preload("Menus", func(db *gorm.DB) *gorm.DB {
+		return db.Order("sort ASC, id ASC")
+	}).First(&role, roleID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("role not found")
+		}
+		return nil, fmt.Errorf("failed to query role menus: %w", err)
+	}
+
+	menuService := MenuService{}
+	return menuService.BuildMenuTree(role.Menus, 0), nil
+}
+
 // AssignAPIs 为角色分配API权限（通过Casbin策略）
 // policies 格式: [][]string{{"path", "method"}, ...}
 func (s *RoleService) AssignAPIs(roleID uint, policies [][]string) error {
+	if err := utils.DBMustInit(); err != nil {
+		return err
+	}
+
 	// 检查角色是否存在
 	var role system.SysRole
 	if err := global.DB.First(&role, roleID).Error; err != nil {
@@ -211,8 +673,16 @@ func (s *RoleService) AssignAPIs(roleID uint, policies [][]string) error {
 	return errors.New("API permission assignment not yet implemented - requires Casbin manager")
 }
 
-// GetRoleAPIs 获取角色的API权限
-func (s *RoleService) GetRoleAPIs(roleID uint) ([][]string, error) {
+// GetRoleCasbinPolicies 获取角色的API权限，并根据已注册的Gin路由生成可读描述
+func (s *RoleService) GetRoleCasbinPolicies(roleID uint) ([]CasbinPolicyDTO, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return nil, err
+	}
+
+	if global.CasbinEnforcer == nil {
+		return nil, errors.New("casbin enforcer not initialized")
+	}
+
 	// 检查角色是否存在
 	var role system.SysRole
 	if err := global.DB.First(&role, roleID).Error; err != nil {
@@ -222,8 +692,190 @@ func (s *RoleService) GetRoleAPIs(roleID uint) ([][]string, error) {
 		return nil, fmt.Errorf("failed to query role: %w", err)
 	}
 
-	// TODO: 实现Casbin策略查询
-	// 这将在Task 8中实现Casbin manager后完成
-	// 目前返回空列表
-	return [][]string{}, nil
+	policies, err := global.CasbinEnforcer.GetFilteredPolicy(0, role.RoleKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get policies: %w", err)
+	}
+
+	result := make([]CasbinPolicyDTO, 0, len(policies))
+	for _, policy := range policies {
+		if len(policy) < 3 {
+			continue
+		}
+		result = append(result, CasbinPolicyDTO{
+			Path:        policy[1],
+			Method:      policy[2],
+			Description: describePolicy(global.Routes, policy[1], policy[2]),
+		})
+	}
+
+	return result, nil
+}
+
+// describePolicy 在已注册路由中查找与策略匹配的路由，返回其处理函数名
+// 优先精确匹配路径，其次按 keyMatch2 规则匹配（与 model.conf 的匹配器一致）
+func describePolicy(routes gin.RoutesInfo, path, method string) string {
+	var matched *gin.RouteInfo
+	for i := range routes {
+		route := &routes[i]
+		if route.Method != method {
+			continue
+		}
+		if route.Path == path {
+			return handlerLabel(route.Handler)
+		}
+		if matched == nil && util.KeyMatch2(route.Path, path) {
+			matched = route
+		}
+	}
+	if matched == nil {
+		return ""
+	}
+	return handlerLabel(matched.Handler)
+}
+
+// handlerLabel 将Gin处理函数全名转换为可读形式
+// 例如 k-admin-system/api/v1/system.(*UserApi).GetUserList-fm 转换为 UserApi.GetUserList
+func handlerLabel(handler string) string {
+	name := strings.TrimSuffix(handler, "-fm")
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	// 去掉包名前缀
+	if i := strings.Index(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	return strings.NewReplacer("(*", "", ")", "").Replace(name)
+}
+
+// GetRolesByUserID 获取用户拥有的角色列表
+// 目前用户通过 SysUser.RoleID 只关联一个角色，这里通过子查询 sys_users 返回角色切片，
+// 以便上层调用方按多角色的方式处理。后续支持多角色分配时需要新增用户-角色关联表并调整此查询
+func (s *RoleService) GetRolesByUserID(userID uint) ([]system.SysRole, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return nil, err
+	}
+
+	// 检查用户是否存在
+	var user system.SysUser
+	if err := global.DB.First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, fmt.Errorf("failed to query user: %w", err)
+	}
+
+	roles := make([]system.SysRole, 0)
+	subQuery := global.DB.Model(&system.SysUser{}).Select("role_id").Where("id = ?", userID)
+	if err := global.DB.Where("id IN (?)", subQuery).Order("sort ASC, id ASC").Find(&roles).Error; err != nil {
+		return nil, fmt.Errorf("failed to query user roles: %w", err)
+	}
+
+	return roles, nil
+}
+
+// RoleStat 角色统计信息
+type RoleStat struct {
+	RoleID    uint   `json:"roleId"`
+	RoleName  string `json:"roleName"`
+	UserCount int64  `json:"userCount"`
+	MenuCount int64  `json:"menuCount"`
+}
+
+// GetRoleStats 获取每个角色的用户数和菜单数
+// 通过一次关联 sys_roles、sys_users 和 sys_role_menus 的查询统计，连接会产生用户×菜单的组合行，因此使用 COUNT(DISTINCT)
+func (s *RoleService) GetRoleStats() ([]RoleStat, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return nil, err
+	}
+
+	stats := make([]RoleStat, 0)
+	if err := global.DB.Table("sys_roles AS r").
+		Select("r.id AS role_id, r.role_name, COUNT(DISTINCT u.id) AS user_count, COUNT(DISTINCT rm.sys_menu_id) AS menu_count").
+		Joins("LEFT JOIN sys_users AS u ON u.role_id = r.id AND u.deleted_at IS NULL").
+		Joins("LEFT JOIN sys_role_menus AS rm ON rm.sys_role_id = r.id").
+		Where("r.deleted_at IS NULL").
+		Group("r.id, r.role_name, r.sort").
+		Order("r.sort ASC, r.id ASC").
+		Scan(&stats).Error; err != nil {
+		return nil, fmt.Errorf("failed to query role stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+// GetOrphanedPolicies 获取主体（角色标识）不对应任何有效角色的Casbin策略
+// 删除角色时未同步清理策略会留下此类孤立策略
+func (s *RoleService) GetOrphanedPolicies() ([]CasbinPolicy, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return nil, err
+	}
+
+	if global.CasbinEnforcer == nil {
+		return nil, errors.New("casbin enforcer not initialized")
+	}
+
+	subjects, err := global.CasbinEnforcer.GetAllSubjects()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get policy subjects: %w", err)
+	}
+
+	// 查询有效（未删除）角色的角色标识
+	var roleKeys []string
+	if err := global.DB.Model(&system.SysRole{}).Pluck("role_key", &roleKeys).Error; err != nil {
+		return nil, fmt.Errorf("failed to query roles: %w", err)
+	}
+	activeRoles := make(map[string]bool, len(roleKeys))
+	for _, key := range roleKeys {
+		activeRoles[key] = true
+	}
+
+	orphaned := make([]CasbinPolicy, 0)
+	for _, subject := range subjects {
+		if activeRoles[subject] {
+			continue
+		}
+		policies, err := global.CasbinEnforcer.GetFilteredPolicy(0, subject)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get policies of %s: %w", subject, err)
+		}
+		for _, policy := range policies {
+			if len(policy) < 3 {
+				continue
+			}
+			orphaned = append(orphaned, CasbinPolicy{
+				RoleKey: policy[0],
+				Path:    policy[1],
+				Method:  policy[2],
+			})
+		}
+	}
+
+	return orphaned, nil
+}
+
+// RemoveOrphanedPolicies 删除所有孤立的Casbin策略，返回被删除的策略
+func (s *RoleService) RemoveOrphanedPolicies() ([]CasbinPolicy, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return nil, err
+	}
+
+	orphaned, err := s.GetOrphanedPolicies()
+	if err != nil {
+		return nil, err
+	}
+	if len(orphaned) == 0 {
+		return orphaned, nil
+	}
+
+	rules := make([][]string, 0, len(orphaned))
+	for _, policy := range orphaned {
+		rules = append(rules, []string{policy.RoleKey, policy.Path, policy.Method})
+	}
+	if _, err := global.CasbinEnforcer.RemovePolicies(rules); err != nil {
+		return nil, fmt.Errorf("failed to remove orphaned policies: %w", err)
+	}
+	core.InvalidateCasbinCache()
+
+	return orphaned, nil
 }
@@ -0,0 +1,478 @@
+package system
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"k-admin-system/global"
+	"k-admin-system/model/system"
+)
+
+func TestDeleteRoleWithReassignment_MovesUsersAndRemovesPolicies(t *testing.T) {
+	setupTestEnv(t)
+	setupTestCasbin(t)
+
+	oldRole := createTestRole(t, "editor")
+	newRole := createTestRole(t, "viewer")
+	for i := 0; i < 5; i++ {
+		createTestUser(t, fmt.Sprintf("editor%d", i), "Passw0rd!", oldRole.ID)
+	}
+	if _, err := global.CasbinEnforcer.AddPolicy("editor", "/api/v1/menu/list", "GET"); err != nil {
+		t.Fatalf("failed to add policy: %v", err)
+	}
+
+	s := &RoleService{}
+	if err := s.DeleteRoleWithReassignment(oldRole.ID, newRole.ID); err != nil {
+		t.Fatalf("DeleteRoleWithReassignment failed: %v", err)
+	}
+
+	var moved int64
+	global.DB.Model(&system.SysUser{}).Where("role_id = ?", newRole.ID).Count(&moved)
+	if moved != 5 {
+		t.Fatalf("expected 5 users on the replacement role, got %d", moved)
+	}
+	var remaining int64
+	global.DB.Model(&system.SysUser{}).Where("role_id = ?", oldRole.ID).Count(&remaining)
+	if remaining != 0 {
+		t.Fatalf("expected no users on the deleted role, got %d", remaining)
+	}
+	if err := global.DB.First(&system.SysRole{}, oldRole.ID).Error; err == nil {
+		t.Fatal("expected the role to be deleted")
+	}
+
+	policies, err := global.CasbinEnforcer.GetFilteredPolicy(0, "editor")
+	if err != nil {
+		t.Fatalf("failed to get policies: %v", err)
+	}
+	if len(policies) != 0 {
+		t.Fatalf("expected the deleted role's policies to be removed, got %v", policies)
+	}
+}
+
+func TestDeleteRoleWithReassignment_RefusesProtectedRoles(t *testing.T) {
+	setupTestEnv(t)
+	setupTestCasbin(t)
+	global.Config.Casbin.SuperuserRoleKey = "root"
+
+	adminRole := createTestRole(t, "admin")
+	rootRole := createTestRole(t, "root")
+	replacement := createTestRole(t, "viewer")
+	createTestUser(t, "admin", "Passw0rd!", adminRole.ID)
+
+	s := &RoleService{}
+	for _, role := range []*system.SysRole{adminRole, rootRole} {
+		if err := s.DeleteRoleWithReassignment(role.ID, replacement.ID); err == nil {
+			t.Fatalf("expected deleting role %q to be refused", role.RoleKey)
+		}
+	}
+
+	var count int64
+	global.DB.Model(&system.SysUser{}).Where("role_id = ?", adminRole.ID).Count(&count)
+	if count != 1 {
+		t.Fatalf("expected the admin user to keep its role, got %d users", count)
+	}
+}
+
+func TestDeleteRoleWithReassignment_RejectsDisabledReplacement(t *testing.T) {
+	setupTestEnv(t)
+
+	oldRole := createTestRole(t, "editor")
+	disabled := createTestRole(t, "viewer")
+	global.DB.Model(disabled).Update("status", false)
+	user := createTestUser(t, "editor", "Passw0rd!", oldRole.ID)
+
+	s := &RoleService{}
+	if err := s.DeleteRoleWithReassignment(oldRole.ID, disabled.ID); err == nil {
+		t.Fatal("expected a disabled replacement role to be rejected")
+	}
+
+	var reloaded system.SysUser
+	global.DB.First(&reloaded, user.ID)
+	if reloaded.RoleID != oldRole.ID {
+		t.Fatalf("expected the user to keep role %d, got %d", oldRole.ID, reloaded.RoleID)
+	}
+}
+
+func TestUpdateRole_RenameMigratesPolicies(t *testing.T) {
+	setupTestEnv(t)
+	setupTestCasbin(t)
+
+	role := createTestRole(t, "editor")
+	if _, err := global.CasbinEnforcer.AddPolicy("editor", "/api/v1/menu/list", "GET"); err != nil {
+		t.Fatalf("failed to add policy: %v", err)
+	}
+
+	role.RoleKey = "writer"
+	if err := (&RoleService{}).UpdateRole(role); err != nil {
+		t.Fatalf("UpdateRole failed: %v", err)
+	}
+
+	var stored system.SysRole
+	if err := global.DB.First(&stored, role.ID).Error; err != nil {
+		t.Fatalf("failed to load role: %v", err)
+	}
+	if stored.RoleKey != "writer" {
+		t.Fatalf("expected role key writer, got %q", stored.RoleKey)
+	}
+	if allowed, _ := global.CasbinEnforcer.Enforce("writer", "/api/v1/menu/list", "GET"); !allowed {
+		t.Error("expected the policy to move to the new role key")
+	}
+	if allowed, _ := global.CasbinEnforcer.Enforce("editor", "/api/v1/menu/list", "GET"); allowed {
+		t.Error("expected no policy to remain under the old role key")
+	}
+}
+
+func TestUpdateRole_RefusesRenamingProtectedRoles(t *testing.T) {
+	setupTestEnv(t)
+	setupTestCasbin(t)
+	global.Config.Casbin.SuperuserRoleKey = "root"
+
+	for _, key := range []string{"admin", "root"} {
+		role := createTestRole(t, key)
+		role.RoleKey = key + "_renamed"
+		if err := (&RoleService{}).UpdateRole(role); err == nil {
+			t.Fatalf("expected renaming protected role %s to fail", key)
+		}
+
+		var stored system.SysRole
+		if err := global.DB.First(&stored, role.ID).Error; err != nil {
+			t.Fatalf("failed to load role: %v", err)
+		}
+		if stored.RoleKey != key {
+			t.Errorf("expected role key %s to be kept, got %q", key, stored.RoleKey)
+		}
+	}
+}
+
+func TestUpdateRole_RestoresRoleWhenPolicyMigrationFails(t *testing.T) {
+	setupTestEnv(t)
+	setupTestCasbin(t)
+
+	role := createTestRole(t, "editor")
+	// 新角色键下残留策略，策略迁移会被拒绝
+	if _, err := global.CasbinEnforcer.AddPolicies([][]string{
+		{"editor", "/api/v1/menu/list", "GET"},
+		{"writer", "/api/v1/user/list", "GET"},
+	}); err != nil {
+		t.Fatalf("failed to add policies: %v", err)
+	}
+
+	role.RoleKey = "writer"
+	if err := (&RoleService{}).UpdateRole(role); err == nil {
+		t.Fatal("expected UpdateRole to fail when the new key already has policies")
+	}
+
+	var stored system.SysRole
+	if err := global.DB.First(&stored, role.ID).Error; err != nil {
+		t.Fatalf("failed to load role: %v", err)
+	}
+	if stored.RoleKey != "editor" {
+		t.Errorf("expected role key to be restored to editor, got %q", stored.RoleKey)
+	}
+	if allowed, _ := global.CasbinEnforcer.Enforce("editor", "/api/v1/menu/list", "GET"); !allowed {
+		t.Error("expected the old role key to keep its policy")
+	}
+}
+
+func TestGetRolesByUserID_ReturnsOnlyTheUsersRole(t *testing.T) {
+	setupTestEnv(t)
+
+	admin := createTestRole(t, "admin")
+	createTestRole(t, "editor")
+	createTestRole(t, "viewer")
+	if admin.ID != 1 {
+		t.Fatalf("expected the first role to have ID 1, got %d", admin.ID)
+	}
+	user := createTestUser(t, "alice", "Passw0rd!", 1)
+
+	roles, err := (&RoleService{}).GetRolesByUserID(user.ID)
+	if err != nil {
+		t.Fatalf("GetRolesByUserID failed: %v", err)
+	}
+	if len(roles) != 1 || roles[0].ID != 1 || roles[0].RoleKey != "admin" {
+		t.Fatalf("expected exactly role 1, got %+v", roles)
+	}
+
+	if _, err := (&RoleService{}).GetRolesByUserID(user.ID + 1); err == nil {
+		t.Error("expected an error for a missing user")
+	}
+}
+
+func TestGetRoleList_SortsByRoleName(t *testing.T) {
+	setupTestEnv(t)
+	for _, name := range []string{"Viewer", "Admin", "Editor", "Auditor"} {
+		role := &system.SysRole{RoleName: name, RoleKey: strings.ToLower(name), Status: true}
+		if err := global.DB.Create(role).Error; err != nil {
+			t.Fatalf("failed to create role: %v", err)
+		}
+	}
+
+	s := &RoleService{}
+	for _, sortBy := range []string{"role_name", "roleName"} {
+		roles, total, err := s.GetRoleList(1, 10, sortBy, "asc")
+		if err != nil {
+			t.Fatalf("GetRoleList(%s) failed: %v", sortBy, err)
+		}
+		names := make([]string, 0, len(roles))
+		for _, role := range roles {
+			names = append(names, role.RoleName)
+		}
+		if want := []string{"Admin", "Auditor", "Editor", "Viewer"}; total != 4 || !reflect.DeepEqual(names, want) {
+			t.Errorf("GetRoleList(%s asc) = %v (total %d), want %v", sortBy, names, total, want)
+		}
+	}
+
+	roles, _, err := s.GetRoleList(1, 10, "role_name", "desc")
+	if err != nil {
+		t.Fatalf("GetRoleList failed: %v", err)
+	}
+	if roles[0].RoleName != "Viewer" {
+		t.Errorf("first role sorted desc = %s, want Viewer", roles[0].RoleName)
+	}
+
+	if _, _, err := s.GetRoleList(1, 10, "role_name; DROP TABLE sys_roles", "asc"); err == nil {
+		t.Error("expected an error for an unknown sort field")
+	}
+}
+
+func TestBulkAssignMenus_ThreeRoles(t *testing.T) {
+	setupTestEnv(t)
+	s := &RoleService{}
+
+	roles := []*system.SysRole{createTestRole(t, "editor"), createTestRole(t, "viewer"), createTestRole(t, "auditor")}
+	menus := make([]uint, 0, 4)
+	for i := 0; i < 4; i++ {
+		menus = append(menus, createTestMenu(t, fmt.Sprintf("/menu%d", i), fmt.Sprintf("Menu%d", i), "").ID)
+	}
+	if err := s.AssignMenus(roles[2].ID, []uint{menus[3]}); err != nil {
+		t.Fatalf("AssignMenus failed: %v", err)
+	}
+
+	want := map[uint][]uint{
+		roles[0].ID: {menus[0], menus[1]},
+		roles[1].ID: {menus[2]},
+		roles[2].ID: {},
+	}
+	assignments := make([]RoleMenuAssignment, 0, len(roles))
+	for _, role := range roles {
+		assignments = append(assignments, RoleMenuAssignment{RoleID: role.ID, MenuIDs: want[role.ID]})
+	}
+	if err := s.BulkAssignMenus(assignments); err != nil {
+		t.Fatalf("BulkAssignMenus failed: %v", err)
+	}
+
+	assertRoleMenus := func(want map[uint][]uint) {
+		t.Helper()
+		for roleID, wantMenus := range want {
+			got, err := s.GetRoleMenus(roleID)
+			if err != nil {
+				t.Fatalf("GetRoleMenus(%d) failed: %v", roleID, err)
+			}
+			sort.Slice(got, func(i, j int) bool { return got[i] < got[j] })
+			if len(got) != len(wantMenus) || (len(got) > 0 && !reflect.DeepEqual(got, wantMenus)) {
+				t.Errorf("role %d menus = %v, want %v", roleID, got, wantMenus)
+			}
+		}
+	}
+	assertRoleMenus(want)
+
+	// 任一角色失败时整批回滚
+	err := s.BulkAssignMenus([]RoleMenuAssignment{
+		{RoleID: roles[0].ID, MenuIDs: []uint{menus[3]}},
+		{RoleID: roles[1].ID, MenuIDs: []uint{menus[3]}},
+		{RoleID: 999, MenuIDs: []uint{menus[3]}},
+	})
+	if err == nil {
+		t.Fatal("expected BulkAssignMenus to fail for a missing role")
+	}
+	assertRoleMenus(want)
+}
+
+// TestCreateRole_DuplicateKeyAlwaysFails 属性测试：任意角色键第二次创建都失败，删除后仍不可复用
+func TestCreateRole_DuplicateKeyAlwaysFails(t *testing.T) {
+	setupTestEnv(t)
+	service := &RoleService{}
+
+	var seq int
+	property := func(suffix string, deleteFirst bool) bool {
+		seq++
+		roleKey := fmt.Sprintf("role_%d_%s", seq, suffix)
+		role := &system.SysRole{RoleName: roleKey, RoleKey: roleKey, Status: true}
+		if err := service.CreateRole(role); err != nil {
+			t.Logf("CreateRole(%q) error = %v", roleKey, err)
+			return false
+		}
+		// 软删除的角色同样占用角色键
+		if deleteFirst {
+			if err := service.DeleteRole(role.ID); err != nil {
+				t.Logf("DeleteRole(%q) error = %v", roleKey, err)
+				return false
+			}
+		}
+
+		duplicate := &system.SysRole{RoleName: "duplicate", RoleKey: roleKey, Status: true}
+		err := service.CreateRole(duplicate)
+		return err != nil && err.Error() == "role key already exists" && duplicate.ID == 0
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+
+	var count int64
+	global.DB.Unscoped().Model(&system.SysRole{}).Where("role_name = ?", "duplicate").Count(&count)
+	if count != 0 {
+		t.Errorf("%d duplicate roles were stored", count)
+	}
+}
+
+func TestGetOrphanedPolicies_AfterDeletingRole(t *testing.T) {
+	setupTestEnv(t)
+	setupTestCasbin(t)
+	editor := createTestRole(t, "editor")
+	createTestRole(t, "viewer")
+	s := &RoleService{}
+
+	if _, err := global.CasbinEnforcer.AddPolicies([][]string{
+		{"editor", "/api/v1/post", "POST"},
+		{"editor", "/api/v1/post/:id", "PUT"},
+		{"viewer", "/api/v1/post/:id", "GET"},
+	}); err != nil {
+		t.Fatalf("AddPolicies() error = %v", err)
+	}
+
+	orphaned, err := s.GetOrphanedPolicies()
+	if err != nil {
+		t.Fatalf("GetOrphanedPolicies() error = %v", err)
+	}
+	if len(orphaned) != 0 {
+		t.Fatalf("GetOrphanedPolicies() before delete = %+v, want none", orphaned)
+	}
+
+	if err := s.DeleteRole(editor.ID); err != nil {
+		t.Fatalf("DeleteRole() error = %v", err)
+	}
+	orphaned, err = s.GetOrphanedPolicies()
+	if err != nil {
+		t.Fatalf("GetOrphanedPolicies() error = %v", err)
+	}
+	sort.Slice(orphaned, func(i, j int) bool { return orphaned[i].Path < orphaned[j].Path })
+	want := []CasbinPolicy{
+		{RoleKey: "editor", Path: "/api/v1/post", Method: "POST"},
+		{RoleKey: "editor", Path: "/api/v1/post/:id", Method: "PUT"},
+	}
+	if !reflect.DeepEqual(orphaned, want) {
+		t.Fatalf("GetOrphanedPolicies() = %+v, want %+v", orphaned, want)
+	}
+
+	// 清理后只保留有效角色的策略
+	if _, err := s.RemoveOrphanedPolicies(); err != nil {
+		t.Fatalf("RemoveOrphanedPolicies() error = %v", err)
+	}
+	policies, err := global.CasbinEnforcer.GetPolicy()
+	if err != nil {
+		t.Fatalf("GetPolicy() error = %v", err)
+	}
+	if len(policies) != 1 || policies[0][0] != "viewer" {
+		t.Errorf("policies after cleanup = %v, want only the viewer policy", policies)
+	}
+	if orphaned, _ := s.GetOrphanedPolicies(); len(orphaned) != 0 {
+		t.Errorf("GetOrphanedPolicies() after cleanup = %+v, want none", orphaned)
+	}
+}
+
+func TestGetRoleMenuTree_Nesting(t *testing.T) {
+	setupTestEnv(t)
+	role := createTestRole(t, "editor")
+
+	newMenu := func(parentID uint, path string, sort int) *system.SysMenu {
+		menu := &system.SysMenu{ParentID: parentID, Path: path, Name: path, Component: "views" + path, Sort: sort}
+		if err := global.DB.Create(menu).Error; err != nil {
+			t.Fatalf("failed to create menu: %v", err)
+		}
+		return menu
+	}
+	systemMenu := newMenu(0, "/system", 2)
+	userMenu := newMenu(systemMenu.ID, "/system/user", 2)
+	roleMenu := newMenu(systemMenu.ID, "/system/role", 1)
+	newMenu(systemMenu.ID, "/system/dict", 3)
+	monitorMenu := newMenu(0, "/monitor", 1)
+	logMenu := newMenu(monitorMenu.ID, "/monitor/log", 1)
+
+	// 未分配的菜单不出现在树中
+	assigned := []*system.SysMenu{userMenu, systemMenu, logMenu, roleMenu, monitorMenu}
+	if err := global.DB.Model(role).Association("Menus").Append(assigned); err != nil {
+		t.Fatalf("failed to assign menus: %v", err)
+	}
+
+	tree, err := (&RoleService{}).GetRoleMenuTree(role.ID)
+	if err != nil {
+		t.Fatalf("GetRoleMenuTree() error = %v", err)
+	}
+	// 以 "路径[子菜单...]" 的形式描述树结构
+	var describe func(menus []system.SysMenu) string
+	describe = func(menus []system.SysMenu) string {
+		parts := make([]string, 0, len(menus))
+		for _, menu := range menus {
+			part := menu.Path
+			if len(menu.Children) > 0 {
+				part += "[" + describe(menu.Children) + "]"
+			}
+			parts = append(parts, part)
+		}
+		return strings.Join(parts, ",")
+	}
+	want := "/monitor[/monitor/log],/system[/system/role,/system/user]"
+	if got := describe(tree); got != want {
+		t.Errorf("GetRoleMenuTree() = %s, want %s", got, want)
+	}
+
+	if _, err := (&RoleService{}).GetRoleMenuTree(role.ID + 1); err == nil || err.Error() != "role not found" {
+		t.Errorf("GetRoleMenuTree() for a missing role error = %v", err)
+	}
+}
+
+func TestGetRoleStats_TwoRoles(t *testing.T) {
+	setupTestEnv(t)
+	editor := createTestRole(t, "editor")
+	viewer := createTestRole(t, "viewer")
+	removed := createTestRole(t, "removed")
+
+	users := make([]*system.SysUser, 0, 3)
+	for i := 0; i < 3; i++ {
+		users = append(users, createTestUser(t, fmt.Sprintf("editor%d", i), "Password123!", editor.ID))
+	}
+	// 已删除的用户和角色不计入
+	if err := global.DB.Delete(users[2]).Error; err != nil {
+		t.Fatalf("failed to delete user: %v", err)
+	}
+	menus := []*system.SysMenu{
+		createTestMenu(t, "/a", "A", "views/a"),
+		createTestMenu(t, "/b", "B", "views/b"),
+		createTestMenu(t, "/c", "C", "views/c"),
+	}
+	if err := global.DB.Model(editor).Association("Menus").Append(menus[0], menus[1]); err != nil {
+		t.Fatalf("failed to assign menus: %v", err)
+	}
+	if err := global.DB.Model(viewer).Association("Menus").Append(menus[2]); err != nil {
+		t.Fatalf("failed to assign menus: %v", err)
+	}
+	if err := global.DB.Delete(removed).Error; err != nil {
+		t.Fatalf("failed to delete role: %v", err)
+	}
+
+	stats, err := (&RoleService{}).GetRoleStats()
+	if err != nil {
+		t.Fatalf("GetRoleStats() error = %v", err)
+	}
+	want := []RoleStat{
+		{RoleID: editor.ID, RoleName: "editor", UserCount: 2, MenuCount: 2},
+		{RoleID: viewer.ID, RoleName: "viewer", UserCount: 0, MenuCount: 1},
+	}
+	if !reflect.DeepEqual(stats, want) {
+		t.Errorf("GetRoleStats() = %+v, want %+v", stats, want)
+	}
+}
@@ -0,0 +1,68 @@
+package system
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"k-admin-system/global"
+	"k-admin-system/model/system"
+	"k-admin-system/utils"
+
+	"gorm.io/gorm"
+)
+
+// SessionService 用户会话服务
+type SessionService struct{}
+
+// CreateSession 记录一次登录产生的会话
+func (s *SessionService) CreateSession(session *system.SysUserSession) error {
+	if err := utils.DBMustInit(); err != nil {
+		return err
+	}
+
+	if err := global.DB.Create(session).Error; err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+	return nil
+}
+
+// GetUserSessions 获取用户未过期的会话，按创建时间倒序
+func (s *SessionService) GetUserSessions(userID uint) ([]system.SysUserSession, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return nil, err
+	}
+
+	var sessions []system.SysUserSession
+	if err := global.DB.Where("user_id = ? AND expires_at > ?", userID, time.Now()).
+		Order("created_at DESC").
+		Find(&sessions).Error; err != nil {
+		return nil, fmt.Errorf("failed to query sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// RevokeSession 撤销会话：使该会话签发的全部令牌（包括刷新得到的访问令牌）失效并删除会话记录
+func (s *SessionService) RevokeSession(sessionID uint) error {
+	if err := utils.DBMustInit(); err != nil {
+		return err
+	}
+
+	var session system.SysUserSession
+	if err := global.DB.First(&session, sessionID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("session not found")
+		}
+		return fmt.Errorf("failed to query session: %w", err)
+	}
+
+	// 会话已过期时令牌均已失效，RevokeSession 会直接返回
+	if err := utils.RevokeSession(session.SessionID, time.Until(session.ExpiresAt)); err != nil {
+		return err
+	}
+
+	if err := global.DB.Delete(&session).Error; err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package system
+
+import (
+	"errors"
+	"testing"
+
+	"k-admin-system/global"
+	"k-admin-system/model/system"
+	"k-admin-system/utils"
+)
+
+func TestSessionLifecycle(t *testing.T) {
+	setupTestEnv(t)
+	role := createTestRole(t, "editor")
+	user := createTestUser(t, "alice", "Passw0rd!", role.ID)
+
+	userService := UserService{}
+	accessToken, refreshToken, _, err := userService.Login("alice", "Passw0rd!", "", "", "127.0.0.1", "test-agent")
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+
+	sessionService := SessionService{}
+	sessions, err := sessionService.GetUserSessions(user.ID)
+	if err != nil {
+		t.Fatalf("GetUserSessions() error = %v", err)
+	}
+	if len(sessions) != 1 {
+		t.Fatalf("GetUserSessions() returned %d sessions, want 1", len(sessions))
+	}
+
+	// 只保存令牌哈希
+	var stored system.SysUserSession
+	if err := global.DB.First(&stored, sessions[0].ID).Error; err != nil {
+		t.Fatalf("failed to load session: %v", err)
+	}
+	if stored.SessionID == "" {
+		t.Error("session ID was not recorded")
+	}
+	if stored.AccessTokenHash != utils.HashToken(accessToken) || stored.RefreshTokenHash != utils.HashToken(refreshToken) {
+		t.Error("session does not store the token hashes")
+	}
+
+	// 刷新得到的访问令牌属于同一会话
+	refreshedToken, err := utils.RefreshToken(refreshToken)
+	if err != nil {
+		t.Fatalf("RefreshToken() error = %v", err)
+	}
+	claims, err := utils.ParseToken(refreshedToken)
+	if err != nil {
+		t.Fatalf("ParseToken(refreshed) error = %v", err)
+	}
+	if claims.SessionID != stored.SessionID {
+		t.Errorf("refreshed token sid = %q, want %q", claims.SessionID, stored.SessionID)
+	}
+
+	if err := sessionService.RevokeSession(stored.ID); err != nil {
+		t.Fatalf("RevokeSession() error = %v", err)
+	}
+
+	for name, token := range map[string]string{"access": accessToken, "refresh": refreshToken, "refreshed": refreshedToken} {
+		if _, err := utils.ParseToken(token); !errors.Is(err, utils.ErrTokenBlacklisted) {
+			t.Errorf("ParseToken(%s) after revoke error = %v, want ErrTokenBlacklisted", name, err)
+		}
+	}
+
+	sessions, err = sessionService.GetUserSessions(user.ID)
+	if err != nil {
+		t.Fatalf("GetUserSessions() error = %v", err)
+	}
+	if len(sessions) != 0 {
+		t.Errorf("GetUserSessions() after revoke returned %d sessions, want 0", len(sessions))
+	}
+
+	if err := sessionService.RevokeSession(stored.ID); err == nil || err.Error() != "session not found" {
+		t.Errorf("RevokeSession() on deleted session error = %v, want session not found", err)
+	}
+}
@@ -0,0 +1,182 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"k-admin-system/global"
+	"k-admin-system/model/system"
+	"k-admin-system/utils"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// sysConfigCacheTTL 动态配置值的缓存时间
+const sysConfigCacheTTL = 5 * time.Minute
+
+// SysConfigService 系统动态配置服务
+type SysConfigService struct{}
+
+// GetConfigList 获取动态配置列表，category 为空时返回全部
+func (s *SysConfigService) GetConfigList(category string) ([]system.SysConfig, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return nil, err
+	}
+
+	query := global.DB.Model(&system.SysConfig{})
+	if category != "" {
+		query = query.Where("category = ?", category)
+	}
+
+	configs := make([]system.SysConfig, 0)
+	if err := query.Order("category ASC, config_key ASC").Find(&configs).Error; err != nil {
+		return nil, fmt.Errorf("failed to query configs: %w", err)
+	}
+	return configs, nil
+}
+
+// sysConfigCacheWriteScript 仅在配置版本号未变化时回写缓存
+// KEYS[1] 为缓存键，KEYS[2] 为版本号键；ARGV 依次为配置值、查询数据库前读取的版本号和TTL（秒）
+var sysConfigCacheWriteScript = redis.NewScript(`
+if (redis.call('GET', KEYS[2]) or '') ~= ARGV[2] then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[1], 'EX', ARGV[3])
+return 1
+`)
+
+// Get 获取配置值
+// 优先从Redis缓存读取（5分钟TTL），未命中时查询数据库并回写缓存。
+// 回写前检查配置版本号：查询期间配置被 Set 更新时放弃回写，避免把旧值写回缓存
+func (s *SysConfigService) Get(key string) (string, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return "", err
+	}
+
+	ctx := context.Background()
+	cacheKey := sysConfigCacheKey(key)
+
+	// 读取缓存
+	if global.RedisClient != nil {
+		cached, err := global.RedisClient.Get(ctx, cacheKey).Result()
+		if err == nil {
+			return cached, nil
+		}
+		if !errors.Is(err, redis.Nil) {
+			global.Logger.Warn("Failed to read config cache", zap.Error(err))
+		}
+	}
+
+	// 查询数据库前记录版本号
+	version := s.cacheVersion(ctx, key)
+
+	// 查询数据库
+	var config system.SysConfig
+	if err := global.DB.Where("config_key = ?", key).First(&config).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", errors.New("config not found")
+		}
+		return "", fmt.Errorf("failed to query config: %w", err)
+	}
+
+	// 回写缓存
+	s.writeCache(ctx, key, config.Value, version)
+
+	return config.Value, nil
+}
+
+// Set 创建或更新配置，事务提交后递增配置版本号并清除该配置的缓存
+// 更新已有配置时，Category 和 Description 为空表示保留原值
+func (s *SysConfigService) Set(config *system.SysConfig) error {
+	if err := utils.DBMustInit(); err != nil {
+		return err
+	}
+
+	if config.Key == "" {
+		return errors.New("config key is required")
+	}
+
+	err := global.DB.Transaction(func(tx *gorm.DB) error {
+		var existing system.SysConfig
+		err := tx.Where("config_key = ?", config.Key).First(&existing).Error
+		switch {
+		case err == nil:
+			existing.Value = config.Value
+			if config.Category != "" {
+				existing.Category = config.Category
+			}
+			if config.Description != "" {
+				existing.Description = config.Description
+			}
+			if err := tx.Save(&existing).Error; err != nil {
+				return fmt.Errorf("failed to update config: %w", err)
+			}
+			*config = existing
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			if err := tx.Create(config).Error; err != nil {
+				return fmt.Errorf("failed to create config: %w", err)
+			}
+		default:
+			return fmt.Errorf("failed to query config: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.invalidateCache(config.Key)
+	return nil
+}
+
+// cacheVersion 读取配置的版本号，键不存在或Redis不可用时返回空字符串
+func (s *SysConfigService) cacheVersion(ctx context.Context, key string) string {
+	if global.RedisClient == nil {
+		return ""
+	}
+	version, err := global.RedisClient.Get(ctx, sysConfigVersionKey(key)).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		global.Logger.Warn("Failed to read config cache version", zap.Error(err))
+	}
+	return version
+}
+
+// writeCache 在版本号仍为 version 时回写配置值缓存
+func (s *SysConfigService) writeCache(ctx context.Context, key, value, version string) {
+	if global.RedisClient == nil {
+		return
+	}
+	keys := []string{sysConfigCacheKey(key), sysConfigVersionKey(key)}
+	if err := sysConfigCacheWriteScript.Run(ctx, global.RedisClient, keys, value, version, int(sysConfigCacheTTL.Seconds())).Err(); err != nil {
+		global.Logger.Warn("Failed to write config cache", zap.Error(err))
+	}
+}
+
+// invalidateCache 递增配置版本号并清除配置值缓存
+// 先递增版本号，使更新前开始查询数据库的 Get 无法再回写旧值
+func (s *SysConfigService) invalidateCache(key string) {
+	if global.RedisClient == nil {
+		return
+	}
+	ctx := context.Background()
+	if err := global.RedisClient.Incr(ctx, sysConfigVersionKey(key)).Err(); err != nil {
+		global.Logger.Warn("Failed to bump config cache version", zap.Error(err))
+	}
+	if err := global.RedisClient.Del(ctx, sysConfigCacheKey(key)).Err(); err != nil {
+		global.Logger.Warn("Failed to invalidate config cache", zap.Error(err))
+	}
+}
+
+// sysConfigCacheKey 配置值的缓存键
+func sysConfigCacheKey(key string) string {
+	return "sys_config:" + key
+}
+
+// sysConfigVersionKey 配置版本号的键，每次更新配置时递增
+func sysConfigVersionKey(key string) string {
+	return "sys_config_version:" + key
+}
@@ -0,0 +1,52 @@
+package system
+
+import (
+	"context"
+	"testing"
+
+	"k-admin-system/global"
+	"k-admin-system/model/system"
+)
+
+func TestSysConfigService_SetInvalidatesCache(t *testing.T) {
+	setupTestEnv(t)
+	s := SysConfigService{}
+
+	if err := s.Set(&system.SysConfig{Key: "site_title", Value: "K-Admin"}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if value, err := s.Get("site_title"); err != nil || value != "K-Admin" {
+		t.Fatalf("Get() = %q, %v, want K-Admin", value, err)
+	}
+	if cached, _ := global.RedisClient.Get(context.Background(), sysConfigCacheKey("site_title")).Result(); cached != "K-Admin" {
+		t.Fatalf("cached value = %q, want K-Admin", cached)
+	}
+
+	if err := s.Set(&system.SysConfig{Key: "site_title", Value: "Console"}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if value, err := s.Get("site_title"); err != nil || value != "Console" {
+		t.Errorf("Get() after update = %q, %v, want Console", value, err)
+	}
+}
+
+func TestSysConfigService_StaleReadDoesNotRepopulateCache(t *testing.T) {
+	setupTestEnv(t)
+	s := SysConfigService{}
+	ctx := context.Background()
+
+	if err := s.Set(&system.SysConfig{Key: "maintenance_mode", Value: "false"}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	// Get 在更新前读取了版本号和旧值，更新完成后才回写缓存
+	version := s.cacheVersion(ctx, "maintenance_mode")
+	if err := s.Set(&system.SysConfig{Key: "maintenance_mode", Value: "true"}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	s.writeCache(ctx, "maintenance_mode", "false", version)
+
+	if value, err := s.Get("maintenance_mode"); err != nil || value != "true" {
+		t.Errorf("Get() = %q, %v, want true", value, err)
+	}
+}
@@ -0,0 +1,136 @@
+package system
+
+import (
+	"errors"
+	"testing"
+
+	"k-admin-system/global"
+	"k-admin-system/model/system"
+	"k-admin-system/utils"
+
+	"gorm.io/gorm"
+)
+
+func TestMergeDuplicateAccounts(t *testing.T) {
+	setupTestEnv(t)
+	adminRole := createTestRole(t, "admin")
+	role := createTestRole(t, "editor")
+	operator := createTestUser(t, "operator", "Passw0rd!", adminRole.ID)
+	primary := createTestUser(t, "alice", "Passw0rd!", role.ID)
+	duplicate := createTestUser(t, "alice2", "Passw0rd!", role.ID)
+
+	userService := UserService{}
+	accessToken, _, _, err := userService.Login("alice2", "Passw0rd!", "", "", "", "")
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	apiKeyService := APIKeyService{}
+	rawKey, _, err := apiKeyService.CreateAPIKey(duplicate.ID, "import", nil)
+	if err != nil {
+		t.Fatalf("CreateAPIKey() error = %v", err)
+	}
+	if err := global.DB.Create(&system.SysAuditLog{UserID: duplicate.ID, Method: "GET", Path: "/api/v1/user/list"}).Error; err != nil {
+		t.Fatalf("failed to create audit log: %v", err)
+	}
+
+	if err := userService.MergeDuplicateAccounts(primary.ID, duplicate.ID, operator.ID); err != nil {
+		t.Fatalf("MergeDuplicateAccounts() error = %v", err)
+	}
+
+	// 重复账号被软删除
+	if err := global.DB.First(&system.SysUser{}, duplicate.ID).Error; err == nil {
+		t.Error("duplicate user was not deleted")
+	}
+
+	// 会话和API密钥失效
+	if _, err := utils.ParseToken(accessToken); err == nil {
+		t.Error("duplicate user's access token is still valid")
+	}
+	var sessions int64
+	global.DB.Model(&system.SysUserSession{}).Where("user_id = ?", duplicate.ID).Count(&sessions)
+	if sessions != 0 {
+		t.Errorf("duplicate user still has %d sessions", sessions)
+	}
+	if _, err := apiKeyService.AuthenticateAPIKey(rawKey); err == nil {
+		t.Error("duplicate user's API key still authenticates")
+	}
+
+	// 审计日志转移到主账号，另有一条合并事件
+	var remaining, moved int64
+	global.DB.Model(&system.SysAuditLog{}).Where("user_id = ?", duplicate.ID).Count(&remaining)
+	global.DB.Model(&system.SysAuditLog{}).Where("user_id = ? AND path = ?", primary.ID, "/api/v1/user/list").Count(&moved)
+	if remaining != 0 || moved != 1 {
+		t.Errorf("audit logs after merge: %d on duplicate, %d moved to primary; want 0 and 1", remaining, moved)
+	}
+	var event system.SysAuditLog
+	if err := global.DB.Where("path = ? AND user_id = ?", "/api/v1/user/merge", operator.ID).First(&event).Error; err != nil {
+		t.Fatalf("merge audit event not recorded: %v", err)
+	}
+	if event.Username != "operator" || event.Query == "" {
+		t.Errorf("merge audit event = %+v", event)
+	}
+}
+
+func TestMergeDuplicateAccounts_RefusesProtectedAccounts(t *testing.T) {
+	setupTestEnv(t)
+	adminRole := createTestRole(t, "admin")
+	role := createTestRole(t, "editor")
+	admin := createTestUser(t, "admin", "Passw0rd!", adminRole.ID)
+	operator := createTestUser(t, "operator", "Passw0rd!", role.ID)
+	primary := createTestUser(t, "alice", "Passw0rd!", role.ID)
+
+	userService := UserService{}
+	if err := userService.MergeDuplicateAccounts(primary.ID, admin.ID, operator.ID); err == nil {
+		t.Error("merging the admin account as the duplicate succeeded")
+	}
+	if err := userService.MergeDuplicateAccounts(primary.ID, operator.ID, operator.ID); err == nil {
+		t.Error("merging the caller's own account as the duplicate succeeded")
+	}
+
+	var remaining int64
+	global.DB.Model(&system.SysUser{}).Count(&remaining)
+	if remaining != 3 {
+		t.Errorf("users remaining = %d, want 3", remaining)
+	}
+}
+
+func TestMergeDuplicateAccounts_RollbackKeepsSessions(t *testing.T) {
+	setupTestEnv(t)
+	role := createTestRole(t, "editor")
+	operator := createTestUser(t, "operator", "Passw0rd!", role.ID)
+	primary := createTestUser(t, "alice", "Passw0rd!", role.ID)
+	duplicate := createTestUser(t, "alice2", "Passw0rd!", role.ID)
+
+	userService := UserService{}
+	accessToken, _, _, err := userService.Login("alice2", "Passw0rd!", "", "", "", "")
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if err := global.DB.Create(&system.SysAuditLog{UserID: duplicate.ID, Method: "GET", Path: "/api/v1/user/list"}).Error; err != nil {
+		t.Fatalf("failed to create audit log: %v", err)
+	}
+
+	// 合并事件写入失败，事务回滚
+	if err := global.DB.Callback().Create().Before("gorm:create").Register("test:fail_audit", func(db *gorm.DB) {
+		if db.Statement.Table == "sys_audit_logs" {
+			db.AddError(errors.New("audit insert failed"))
+		}
+	}); err != nil {
+		t.Fatalf("failed to register callback: %v", err)
+	}
+	if err := userService.MergeDuplicateAccounts(primary.ID, duplicate.ID, operator.ID); err == nil {
+		t.Fatal("MergeDuplicateAccounts() error = nil, want audit insert failure")
+	}
+
+	if _, err := utils.ParseToken(accessToken); err != nil {
+		t.Errorf("rolled-back merge revoked the duplicate's session: %v", err)
+	}
+	var history int64
+	global.DB.Model(&system.SysAuditLog{}).Where("user_id = ?", duplicate.ID).Count(&history)
+	if history != 1 {
+		t.Errorf("duplicate's audit logs after rollback = %d, want 1", history)
+	}
+	if err := global.DB.First(&system.SysUser{}, duplicate.ID).Error; err != nil {
+		t.Errorf("duplicate user missing after rollback: %v", err)
+	}
+}
@@ -1,52 +1,251 @@
 package system
 
 import (
+	"bytes"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"image/png"
+	"strings"
+	"time"
 
 	"k-admin-system/global"
 	"k-admin-system/model/system"
 	"k-admin-system/utils"
 
+	"github.com/pquerna/otp/totp"
 	"gorm.io/gorm"
 )
 
 // UserService 用户服务
 type UserService struct{}
 
+// ErrPasswordExpired 密码超过 security.password_max_age_days 未修改，需修改密码后才能登录
+var ErrPasswordExpired = errors.New("password expired")
+
+// ActivitySummary 用户活动概要（基于审计日志统计）
+type ActivitySummary struct {
+	TotalRequests    int64            `json:"totalRequests"`
+	MethodBreakdown  map[string]int64 `json:"methodBreakdown"`
+	MostVisitedPaths []string         `json:"mostVisitedPaths"` // 访问次数最多的前5个路径
+	LastActivity     *time.Time       `json:"lastActivity"`
+}
+
+// mfaIssuer TOTP认证器中显示的签发方名称
+const mfaIssuer = "K-Admin"
+
 // Login 用户登录
-// 验证用户凭据并生成访问令牌和刷新令牌
-func (s *UserService) Login(username, password string) (accessToken, refreshToken string, user *system.SysUser, err error) {
+// 验证用户凭据并生成访问令牌和刷新令牌，已启用两步验证的用户还需提供有效的TOTP验证码
+// fingerprint 为客户端设备指纹，会写入令牌；ip 和 deviceInfo 记录到登录会话中
+func (s *UserService) Login(username, password, totpCode, fingerprint, ip, deviceInfo string) (accessToken, refreshToken string, user *system.SysUser, err error) {
+	if err := utils.DBMustInit(); err != nil {
+		return "", "", nil, err
+	}
+
+	dbUser, err := s.verifyCredentials(username, password, totpCode)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	// 检查密码是否过期
+	if isPasswordExpired(dbUser, global.Config.Security.PasswordMaxAgeDays) {
+		return "", "", nil, ErrPasswordExpired
+	}
+
+	// 生成令牌
+	sessionID, err := utils.GenerateSessionID()
+	if err != nil {
+		return "", "", nil, fmt.Errorf("failed to generate session ID: %w", err)
+	}
+	accessToken, refreshToken, err = utils.GenerateToken(dbUser.ID, dbUser.Username, dbUser.RoleID, fingerprint, sessionID)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("failed to generate tokens: %w", err)
+	}
+
+	// 记录登录会话，会话有效期与刷新令牌一致
+	if len(deviceInfo) > 255 {
+		deviceInfo = deviceInfo[:255]
+	}
+	sessionService := SessionService{}
+	if err := sessionService.CreateSession(&system.SysUserSession{
+		UserID:           dbUser.ID,
+		SessionID:        sessionID,
+		AccessTokenHash:  utils.HashToken(accessToken),
+		RefreshTokenHash: utils.HashToken(refreshToken),
+		DeviceInfo:       deviceInfo,
+		IP:               ip,
+		ExpiresAt:        time.Now().Add(time.Duration(global.Config.JWT.RefreshExpiration) * 24 * time.Hour),
+	}); err != nil {
+		return "", "", nil, err
+	}
+
+	// 记录最后登录时间并重置失败计数
+	now := time.Now()
+	if err := global.DB.Model(&dbUser).Updates(map[string]interface{}{
+		"last_login_at":         now,
+		"failed_login_attempts": 0,
+		"locked_until":          nil,
+	}).Error; err != nil {
+		return "", "", nil, fmt.Errorf("failed to update last login time: %w", err)
+	}
+	dbUser.LastLoginAt = &now
+	dbUser.FailedLoginAttempts = 0
+	dbUser.LockedUntil = nil
+
+	return accessToken, refreshToken, dbUser, nil
+}
+
+// verifyCredentials 校验用户名、密码和两步验证码
+// 账号被禁用或处于锁定期时直接拒绝；密码或验证码错误计入连续失败次数
+func (s *UserService) verifyCredentials(username, password, totpCode string) (*system.SysUser, error) {
 	// 查询用户
 	var dbUser system.SysUser
 	if err := global.DB.Where("username = ?", username).First(&dbUser).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return "", "", nil, errors.New("invalid username or password")
+			return nil, errors.New("invalid username or password")
 		}
-		return "", "", nil, fmt.Errorf("failed to query user: %w", err)
+		return nil, fmt.Errorf("failed to query user: %w", err)
 	}
 
 	// 检查用户是否激活
 	if !dbUser.Active {
-		return "", "", nil, errors.New("user account is disabled")
+		return nil, errors.New("user account is disabled")
+	}
+
+	// 检查账号是否处于锁定期，锁定期内不校验密码
+	if dbUser.LockedUntil != nil && dbUser.LockedUntil.After(time.Now()) {
+		return nil, fmt.Errorf("account locked until %s", dbUser.LockedUntil.Format(time.RFC3339))
 	}
 
 	// 验证密码
 	if !utils.CheckPassword(dbUser.Password, password) {
-		return "", "", nil, errors.New("invalid username or password")
+		if err := s.recordFailedLogin(&dbUser); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("invalid username or password")
 	}
 
-	// 生成令牌
-	accessToken, refreshToken, err = utils.GenerateToken(dbUser.ID, dbUser.Username, dbUser.RoleID)
+	// 验证两步验证码
+	if dbUser.MFAEnabled {
+		if totpCode == "" {
+			return nil, errors.New("TOTP code required")
+		}
+		// 验证码错误同样计入连续失败次数，防止在已知密码时暴力枚举6位验证码
+		if !totp.Validate(totpCode, dbUser.MFASecret) {
+			if err := s.recordFailedLogin(&dbUser); err != nil {
+				return nil, err
+			}
+			return nil, errors.New("invalid TOTP code")
+		}
+	}
+
+	return &dbUser, nil
+}
+
+// ChangeExpiredPassword 修改已过期的密码（无需登录）
+// 密码过期后无法登录获取令牌，因此凭用户名、旧密码（以及已启用时的TOTP验证码）直接修改；
+// 仅允许修改已过期的密码，校验失败同样计入连续失败次数
+func (s *UserService) ChangeExpiredPassword(username, oldPassword, totpCode, newPassword string) error {
+	if err := utils.DBMustInit(); err != nil {
+		return err
+	}
+
+	user, err := s.verifyCredentials(username, oldPassword, totpCode)
 	if err != nil {
-		return "", "", nil, fmt.Errorf("failed to generate tokens: %w", err)
+		return err
+	}
+	if !isPasswordExpired(user, global.Config.Security.PasswordMaxAgeDays) {
+		return errors.New("password has not expired")
+	}
+	if oldPassword == newPassword {
+		return errors.New("new password must differ from the old password")
+	}
+
+	// 校验新密码强度
+	if err := checkPasswordStrength(newPassword); err != nil {
+		return err
+	}
+
+	hashedPassword, err := utils.HashPassword(newPassword)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	// 更新密码并重置失败计数（PasswordChangedAt 由 BeforeUpdate 钩子同步更新）
+	if err := global.DB.Model(user).Updates(map[string]interface{}{
+		"password":              hashedPassword,
+		"failed_login_attempts": 0,
+		"locked_until":          nil,
+	}).Error; err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	return nil
+}
+
+// recordFailedLogin 记录一次密码或两步验证码错误
+// 连续失败次数达到 security.max_failed_attempts 时锁定账号 security.lock_duration 分钟，并重新开始计数。
+// 计数在数据库中原子自增，是否锁定取决于自增后读回的值，并发的失败登录不会丢失计数
+func (s *UserService) recordFailedLogin(user *system.SysUser) error {
+	return global.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&system.SysUser{}).Where("id = ?", user.ID).
+			Update("failed_login_attempts", gorm.Expr("failed_login_attempts + ?", 1)).Error; err != nil {
+			return fmt.Errorf("failed to record failed login: %w", err)
+		}
+
+		// 自增语句已持有该行的写锁，事务内读回的是本次自增后的值
+		var attempts int
+		if err := tx.Model(&system.SysUser{}).Where("id = ?", user.ID).
+			Pluck("failed_login_attempts", &attempts).Error; err != nil {
+			return fmt.Errorf("failed to read failed login attempts: %w", err)
+		}
+		user.FailedLoginAttempts = attempts
+		if attempts < global.Config.Security.MaxFailedAttempts {
+			return nil
+		}
+
+		lockedUntil := time.Now().Add(time.Duration(global.Config.Security.LockDuration) * time.Minute)
+		if err := tx.Model(&system.SysUser{}).Where("id = ?", user.ID).Updates(map[string]interface{}{
+			"failed_login_attempts": 0,
+			"locked_until":          lockedUntil,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to lock account: %w", err)
+		}
+		user.FailedLoginAttempts = 0
+		user.LockedUntil = &lockedUntil
+		return nil
+	})
+}
+
+// isPasswordExpired 判断用户密码是否已超过 maxAgeDays 天未修改
+// 未记录修改时间的用户（该字段引入前创建的账号）不视为过期
+func isPasswordExpired(user *system.SysUser, maxAgeDays int) bool {
+	if maxAgeDays <= 0 || user.PasswordChangedAt == nil {
+		return false
 	}
+	return user.PasswordChangedAt.Before(time.Now().AddDate(0, 0, -maxAgeDays))
+}
 
-	return accessToken, refreshToken, &dbUser, nil
+// checkPasswordStrength 校验密码强度不低于 security.min_password_strength，不满足时在错误中返回改进建议
+func checkPasswordStrength(password string) error {
+	score, hints := utils.PasswordStrength(password)
+	if int(score) < global.Config.Security.MinPasswordStrength {
+		return fmt.Errorf("password is too weak: %s", strings.Join(hints, "; "))
+	}
+	return nil
 }
 
 // CreateUser 创建用户
 func (s *UserService) CreateUser(user *system.SysUser) error {
+	if err := utils.DBMustInit(); err != nil {
+		return err
+	}
+
+	// 校验手机号格式
+	if user.Phone != "" && !utils.IsValidE164(user.Phone) {
+		return errors.New("invalid phone number, expected E.164 format")
+	}
+
 	// 检查用户名是否已存在
 	var count int64
 	if err := global.DB.Model(&system.SysUser{}).Where("username = ?", user.Username).Count(&count).Error; err != nil {
@@ -56,6 +255,11 @@ func (s *UserService) CreateUser(user *system.SysUser) error {
 		return errors.New("username already exists")
 	}
 
+	// 校验密码强度
+	if err := checkPasswordStrength(user.Password); err != nil {
+		return err
+	}
+
 	// 加密密码
 	hashedPassword, err := utils.HashPassword(user.Password)
 	if err != nil {
@@ -63,7 +267,7 @@ func (s *UserService) CreateUser(user *system.SysUser) error {
 	}
 	user.Password = hashedPassword
 
-	// 创建用户
+	// 创建用户（PasswordChangedAt 由 BeforeCreate 钩子设置）
 	if err := global.DB.Create(user).Error; err != nil {
 		return fmt.Errorf("failed to create user: %w", err)
 	}
@@ -73,6 +277,10 @@ func (s *UserService) CreateUser(user *system.SysUser) error {
 
 // UpdateUser 更新用户信息
 func (s *UserService) UpdateUser(user *system.SysUser) error {
+	if err := utils.DBMustInit(); err != nil {
+		return err
+	}
+
 	// 检查用户是否存在
 	var existingUser system.SysUser
 	if err := global.DB.First(&existingUser, user.ID).Error; err != nil {
@@ -82,6 +290,19 @@ func (s *UserService) UpdateUser(user *system.SysUser) error {
 		return fmt.Errorf("failed to query user: %w", err)
 	}
 
+	// 客户端回传的是脱敏后的原值时，保留数据库中的真实邮箱和手机号
+	if existingUser.Email != "" && user.Email == utils.MaskEmail(existingUser.Email) {
+		user.Email = existingUser.Email
+	}
+	if existingUser.Phone != "" && user.Phone == utils.MaskPhone(existingUser.Phone) {
+		user.Phone = existingUser.Phone
+	}
+
+	// 校验手机号格式
+	if user.Phone != "" && !utils.IsValidE164(user.Phone) {
+		return errors.New("invalid phone number, expected E.164 format")
+	}
+
 	// 如果更新用户名，检查新用户名是否已被其他用户使用
 	if user.Username != existingUser.Username {
 		var count int64
@@ -102,21 +323,40 @@ func (s *UserService) UpdateUser(user *system.SysUser) error {
 			return fmt.Errorf("failed to hash password: %w", err)
 		}
 		user.Password = hashedPassword
+		now := time.Now()
+		user.PasswordChangedAt = &now
 	} else {
 		// 如果没有提供新密码，保留原密码
 		user.Password = existingUser.Password
+		user.PasswordChangedAt = existingUser.PasswordChangedAt
 	}
 
-	// 更新用户
-	if err := global.DB.Save(user).Error; err != nil {
+	// 只更新可编辑的列，MFA、登录锁定、最后登录时间等由登录流程维护的字段保持不变
+	if err := global.DB.Model(&system.SysUser{}).Where("id = ?", user.ID).
+		Select(userEditableColumns).
+		Updates(user).Error; err != nil {
 		return fmt.Errorf("failed to update user: %w", err)
 	}
 
+	// 重新加载完整记录，供调用方返回
+	if err := global.DB.First(user, user.ID).Error; err != nil {
+		return fmt.Errorf("failed to reload user: %w", err)
+	}
+
 	return nil
 }
 
+// userEditableColumns UpdateUser 允许修改的列
+var userEditableColumns = []string{
+	"username", "password", "nickname", "header_img", "phone", "email", "role_id", "active", "password_changed_at",
+}
+
 // DeleteUser 删除用户（软删除）
 func (s *UserService) DeleteUser(id uint) error {
+	if err := utils.DBMustInit(); err != nil {
+		return err
+	}
+
 	// 检查用户是否存在
 	var user system.SysUser
 	if err := global.DB.Preload("Role").First(&user, id).Error; err != nil {
@@ -141,6 +381,10 @@ func (s *UserService) DeleteUser(id uint) error {
 
 // GetUserByID 根据ID获取用户
 func (s *UserService) GetUserByID(id uint) (*system.SysUser, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return nil, err
+	}
+
 	var user system.SysUser
 	if err := global.DB.First(&user, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -154,6 +398,10 @@ func (s *UserService) GetUserByID(id uint) (*system.SysUser, error) {
 
 // GetUserList 获取用户列表（支持分页和过滤）
 func (s *UserService) GetUserList(page, pageSize int, filters map[string]interface{}) ([]system.SysUser, int64, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return nil, 0, err
+	}
+
 	var users []system.SysUser
 	var total int64
 
@@ -196,6 +444,10 @@ func (s *UserService) GetUserList(page, pageSize int, filters map[string]interfa
 
 // ChangePassword 修改密码（需要验证旧密码）
 func (s *UserService) ChangePassword(userID uint, oldPassword, newPassword string) error {
+	if err := utils.DBMustInit(); err != nil {
+		return err
+	}
+
 	// 查询用户
 	var user system.SysUser
 	if err := global.DB.First(&user, userID).Error; err != nil {
@@ -210,13 +462,18 @@ func (s *UserService) ChangePassword(userID uint, oldPassword, newPassword strin
 		return errors.New("old password is incorrect")
 	}
 
+	// 校验新密码强度
+	if err := checkPasswordStrength(newPassword); err != nil {
+		return err
+	}
+
 	// 加密新密码
 	hashedPassword, err := utils.HashPassword(newPassword)
 	if err != nil {
 		return fmt.Errorf("failed to hash password: %w", err)
 	}
 
-	// 更新密码
+	// 更新密码（PasswordChangedAt 由 BeforeUpdate 钩子同步更新）
 	if err := global.DB.Model(&user).Update("password", hashedPassword).Error; err != nil {
 		return fmt.Errorf("failed to update password: %w", err)
 	}
@@ -226,6 +483,10 @@ func (s *UserService) ChangePassword(userID uint, oldPassword, newPassword strin
 
 // ResetPassword 重置密码（管理员操作，不需要验证旧密码）
 func (s *UserService) ResetPassword(userID uint, newPassword string) error {
+	if err := utils.DBMustInit(); err != nil {
+		return err
+	}
+
 	// 查询用户
 	var user system.SysUser
 	if err := global.DB.First(&user, userID).Error; err != nil {
@@ -241,7 +502,7 @@ func (s *UserService) ResetPassword(userID uint, newPassword string) error {
 		return fmt.Errorf("failed to hash password: %w", err)
 	}
 
-	// 更新密码
+	// 更新密码（PasswordChangedAt 由 BeforeUpdate 钩子同步更新）
 	if err := global.DB.Model(&user).Update("password", hashedPassword).Error; err != nil {
 		return fmt.Errorf("failed to update password: %w", err)
 	}
@@ -251,6 +512,10 @@ func (s *UserService) ResetPassword(userID uint, newPassword string) error {
 
 // ToggleUserStatus 切换用户状态（启用/禁用）
 func (s *UserService) ToggleUserStatus(userID uint, active bool) error {
+	if err := utils.DBMustInit(); err != nil {
+		return err
+	}
+
 	// 查询用户
 	var user system.SysUser
 	if err := global.DB.Preload("Role").First(&user, userID).Error; err != nil {
@@ -272,3 +537,495 @@ func (s *UserService) ToggleUserStatus(userID uint, active bool) error {
 
 	return nil
 }
+
+// BatchToggleStatus 在单个事务中批量启用或禁用用户，返回受影响的行数
+// 禁用时如果包含超级管理员则整体拒绝
+func (s *UserService) BatchToggleStatus(ids []uint, active bool) (int64, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return 0, err
+	}
+
+	if len(ids) == 0 {
+		return 0, errors.New("user IDs are required")
+	}
+
+	var affected int64
+	err := global.DB.Transaction(func(tx *gorm.DB) error {
+		// 防止禁用超级管理员
+		if !active {
+			var adminCount int64
+			if err := tx.Model(&system.SysUser{}).
+				Joins("JOIN sys_roles ON sys_roles.id = sys_users.role_id").
+				Where("sys_users.id IN ? AND sys_roles.role_key = ?", ids, "admin").
+				Count(&adminCount).Error; err != nil {
+				return fmt.Errorf("failed to check user roles: %w", err)
+			}
+			if adminCount > 0 {
+				return errors.New("cannot disable super administrator")
+			}
+		}
+
+		result := tx.Model(&system.SysUser{}).Where("id IN ?", ids).Update("active", active)
+		if result.Error != nil {
+			return fmt.Errorf("failed to update user status: %w", result.Error)
+		}
+		affected = result.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return affected, nil
+}
+
+// MergeDuplicateAccounts 将重复账号合并到主账号
+// operatorID 为执行合并的用户ID。重复账号不能是管理员账号或操作者本人；
+// 在单个事务中将重复账号的审计日志转移到主账号、删除其登录会话、停用其API密钥并软删除该账号，
+// 同时记录一条合并审计事件。事务提交后再在Redis中撤销会话，合并失败时会话不受影响
+func (s *UserService) MergeDuplicateAccounts(primaryID, duplicateID, operatorID uint) error {
+	if err := utils.DBMustInit(); err != nil {
+		return err
+	}
+
+	if primaryID == duplicateID {
+		return errors.New("cannot merge a user into itself")
+	}
+	if duplicateID == operatorID {
+		return errors.New("cannot merge your own account as the duplicate")
+	}
+
+	var sessions []system.SysUserSession
+	err := global.DB.Transaction(func(tx *gorm.DB) error {
+		// 检查两个用户都存在
+		var users []system.SysUser
+		if err := tx.Preload("Role").Where("id IN ?", []uint{primaryID, duplicateID}).Find(&users).Error; err != nil {
+			return fmt.Errorf("failed to query users: %w", err)
+		}
+		if len(users) != 2 {
+			return errors.New("user not found")
+		}
+		for _, user := range users {
+			if user.ID == duplicateID && user.Role != nil && isProtectedRole(user.Role.RoleKey) {
+				return errors.New("cannot merge an admin account as the duplicate")
+			}
+		}
+
+		// 将重复账号的审计日志转移到主账号
+		if err := tx.Model(&system.SysAuditLog{}).Where("user_id = ?", duplicateID).
+			Update("user_id", primaryID).Error; err != nil {
+			return fmt.Errorf("failed to move audit logs: %w", err)
+		}
+
+		// 删除重复账号的登录会话，提交后再撤销其已签发的令牌
+		if err := tx.Where("user_id = ?", duplicateID).Find(&sessions).Error; err != nil {
+			return fmt.Errorf("failed to query sessions: %w", err)
+		}
+		if err := tx.Where("user_id = ?", duplicateID).Delete(&system.SysUserSession{}).Error; err != nil {
+			return fmt.Errorf("failed to delete sessions: %w", err)
+		}
+
+		// 停用重复账号的API密钥
+		if err := tx.Model(&system.SysAPIKey{}).Where("user_id = ?", duplicateID).Update("active", false).Error; err != nil {
+			return fmt.Errorf("failed to disable API keys: %w", err)
+		}
+
+		// 软删除重复账号
+		if err := tx.Delete(&system.SysUser{}, duplicateID).Error; err != nil {
+			return fmt.Errorf("failed to delete duplicate user: %w", err)
+		}
+
+		// 记录合并审计事件
+		event := &system.SysAuditLog{
+			UserID:     operatorID,
+			Method:     "POST",
+			Path:       "/api/v1/user/merge",
+			Query:      fmt.Sprintf("primaryId=%d&duplicateId=%d", primaryID, duplicateID),
+			StatusCode: 200,
+		}
+		var operator system.SysUser
+		if err := tx.Select("id", "username").First(&operator, operatorID).Error; err == nil {
+			event.Username = operator.Username
+		}
+		if err := tx.Create(event).Error; err != nil {
+			return fmt.Errorf("failed to record merge audit event: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// 使重复账号已签发的令牌立即失效
+	for _, session := range sessions {
+		if err := utils.RevokeSession(session.SessionID, time.Until(session.ExpiresAt)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetUsersWithExpiredPasswords 获取密码超过 maxAgeDays 天未修改的用户
+// 未记录密码修改时间的用户不包含在内，与登录时的过期判断一致
+func (s *UserService) GetUsersWithExpiredPasswords(maxAgeDays int) ([]system.SysUser, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return nil, err
+	}
+
+	if maxAgeDays < 1 {
+		return nil, errors.New("max age must be at least 1 day")
+	}
+
+	var users []system.SysUser
+	if err := global.DB.Preload("Role").
+		Where("password_changed_at < ?", time.Now().AddDate(0, 0, -maxAgeDays)).
+		Order("password_changed_at ASC").
+		Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("failed to query users with expired passwords: %w", err)
+	}
+
+	return users, nil
+}
+
+// GetInactiveUsers 获取长期未登录的用户
+// 返回最后登录时间早于 since 之前的用户，以及从未登录过（LastLoginAt 为 NULL）的用户
+func (s *UserService) GetInactiveUsers(since time.Duration) ([]system.SysUser, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return nil, err
+	}
+
+	var users []system.SysUser
+	if err := global.DB.Preload("Role").
+		Scopes(system.DormantSince(since)).
+		Order("id ASC").
+		Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("failed to query inactive users: %w", err)
+	}
+
+	return users, nil
+}
+
+// CleanupInactiveUsers 在单个事务中软删除长期未登录的用户
+// 超级管理员不会被删除，返回实际删除的用户数量
+func (s *UserService) CleanupInactiveUsers(since time.Duration) (int64, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return 0, err
+	}
+
+	users, err := s.GetInactiveUsers(since)
+	if err != nil {
+		return 0, err
+	}
+
+	// 排除超级管理员
+	ids := make([]uint, 0, len(users))
+	for _, user := range users {
+		if user.Role != nil && user.Role.RoleKey == "admin" {
+			continue
+		}
+		ids = append(ids, user.ID)
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	var deletedCount int64
+	err = global.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id IN ?", ids).Delete(&system.SysUser{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete inactive users: %w", result.Error)
+		}
+		deletedCount = result.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return deletedCount, nil
+}
+
+// SearchUsers 用户快速搜索（用于全局搜索框的输入联想）
+// 对用户名和昵称做前缀匹配以便利用索引，仅返回 id、username、nickname、header_img
+func (s *UserService) SearchUsers(query string, limit int) ([]system.SysUser, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return nil, err
+	}
+
+	var users []system.SysUser
+
+	pattern := escapeLike(query) + "%"
+	escape := likeEscapeClause()
+	if err := global.DB.Select("id", "username", "nickname", "header_img").
+		Where(fmt.Sprintf("username LIKE ? %s OR nickname LIKE ? %s", escape, escape), pattern, pattern).
+		Order("username ASC").
+		Limit(limit).
+		Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("failed to search users: %w", err)
+	}
+
+	return users, nil
+}
+
+// maxRecentUsers 最近注册用户查询的最大返回数量
+const maxRecentUsers = 100
+
+// GetRecentlyCreatedUsers 获取最近创建的用户，按创建时间倒序
+// limit 会被限制在 1 到 maxRecentUsers 之间
+func (s *UserService) GetRecentlyCreatedUsers(limit int) ([]system.SysUser, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return nil, err
+	}
+
+	if limit < 1 {
+		limit = 1
+	}
+	if limit > maxRecentUsers {
+		limit = maxRecentUsers
+	}
+
+	var users []system.SysUser
+	if err := global.DB.Preload("Role").
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("failed to query recently created users: %w", err)
+	}
+
+	return users, nil
+}
+
+// escapeLike 转义 LIKE 模式中的通配符，使输入按字面量匹配
+// 以反斜杠作为转义符，查询条件中需要同时使用 likeEscapeClause 声明
+func escapeLike(s string) string {
+	return strings.NewReplacer("\\", "\\\\", "%", "\\%", "_", "\\_").Replace(s)
+}
+
+// likeEscapeClause 返回声明反斜杠为转义符的 ESCAPE 子句
+// 只有 MySQL 默认以反斜杠转义 LIKE 通配符，其他数据库必须显式声明；MySQL 字符串字面量中的反斜杠本身需要转义
+func likeEscapeClause() string {
+	if global.DB.Dialector.Name() == "mysql" {
+		return `ESCAPE '\\'`
+	}
+	return `ESCAPE '\'`
+}
+
+// GetUsersByMenuAccess 获取可以访问指定菜单的用户（其角色已分配该菜单），用于下线菜单前评估影响范围
+func (s *UserService) GetUsersByMenuAccess(menuID uint) ([]system.SysUser, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return nil, err
+	}
+
+	// 检查菜单是否存在
+	var count int64
+	if err := global.DB.Model(&system.SysMenu{}).Where("id = ?", menuID).Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("failed to query menu: %w", err)
+	}
+	if count == 0 {
+		return nil, errors.New("menu not found")
+	}
+
+	users := make([]system.SysUser, 0)
+	roleQuery := global.DB.Table("sys_role_menus").Select("sys_role_id").Where("sys_menu_id = ?", menuID)
+	if err := global.DB.Preload("Role").
+		Where("role_id IN (?)", roleQuery).
+		Order("id ASC").
+		Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("failed to query users by menu: %w", err)
+	}
+
+	return users, nil
+}
+
+// GetUsersCreatedBetween 获取指定时间范围内创建的用户（包含起止时间），用于用户增长报表
+func (s *UserService) GetUsersCreatedBetween(start, end time.Time) ([]system.SysUser, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return nil, err
+	}
+
+	if end.Before(start) {
+		return nil, errors.New("end time must not be before start time")
+	}
+
+	var users []system.SysUser
+	if err := global.DB.Scopes(system.CreatedBetween(start, end)).
+		Preload("Role").
+		Order("created_at ASC").
+		Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("failed to query users: %w", err)
+	}
+
+	return users, nil
+}
+
+// GetUsersByLastLoginRange 获取最后登录时间在指定范围内的用户（包含起止时间），用于休眠账号排查
+// active 不为 nil 时按启用状态过滤；从未登录过的用户不在结果中
+func (s *UserService) GetUsersByLastLoginRange(start, end time.Time, active *bool) ([]system.SysUser, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return nil, err
+	}
+
+	if end.Before(start) {
+		return nil, errors.New("end time must not be before start time")
+	}
+
+	db := global.DB.Where("last_login_at >= ? AND last_login_at <= ?", start, end)
+	if active != nil {
+		db = db.Where("active = ?", *active)
+	}
+
+	var users []system.SysUser
+	if err := db.Preload("Role").
+		Order("last_login_at ASC").
+		Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("failed to query users: %w", err)
+	}
+
+	return users, nil
+}
+
+// GetUserActivitySummary 统计用户在最近 period 时间内的请求活动
+func (s *UserService) GetUserActivitySummary(userID uint, period time.Duration) (*ActivitySummary, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return nil, err
+	}
+
+	// 检查用户是否存在
+	var count int64
+	if err := global.DB.Model(&system.SysUser{}).Where("id = ?", userID).Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("failed to query user: %w", err)
+	}
+	if count == 0 {
+		return nil, errors.New("user not found")
+	}
+
+	since := time.Now().Add(-period)
+	baseQuery := func() *gorm.DB {
+		return global.DB.Model(&system.SysAuditLog{}).Where("user_id = ? AND created_at >= ?", userID, since)
+	}
+
+	summary := &ActivitySummary{
+		MethodBreakdown:  map[string]int64{},
+		MostVisitedPaths: []string{},
+	}
+
+	// 总请求数
+	if err := baseQuery().Count(&summary.TotalRequests).Error; err != nil {
+		return nil, fmt.Errorf("failed to count requests: %w", err)
+	}
+	if summary.TotalRequests == 0 {
+		return summary, nil
+	}
+
+	// 按请求方法分组统计
+	var methodCounts []struct {
+		Method string
+		Count  int64
+	}
+	if err := baseQuery().Select("method, COUNT(*) AS count").Group("method").Scan(&methodCounts).Error; err != nil {
+		return nil, fmt.Errorf("failed to count requests by method: %w", err)
+	}
+	for _, mc := range methodCounts {
+		summary.MethodBreakdown[mc.Method] = mc.Count
+	}
+
+	// 访问次数最多的路径
+	var pathCounts []struct {
+		Path  string
+		Count int64
+	}
+	if err := baseQuery().Select("path, COUNT(*) AS count").
+		Group("path").
+		Order("count DESC, path ASC").
+		Limit(5).
+		Scan(&pathCounts).Error; err != nil {
+		return nil, fmt.Errorf("failed to count requests by path: %w", err)
+	}
+	for _, pc := range pathCounts {
+		summary.MostVisitedPaths = append(summary.MostVisitedPaths, pc.Path)
+	}
+
+	// 最后活动时间
+	var lastLog system.SysAuditLog
+	if err := baseQuery().Select("created_at").Order("created_at DESC").Take(&lastLog).Error; err != nil {
+		return nil, fmt.Errorf("failed to query last activity: %w", err)
+	}
+	summary.LastActivity = &lastLog.CreatedAt
+
+	return summary, nil
+}
+
+// SetupMFA 为用户生成新的TOTP密钥，返回密钥和二维码（data URI格式的PNG图片）
+// 密钥在通过 VerifyMFA 验证前不会启用两步验证；已启用时需先停用才能重新设置
+func (s *UserService) SetupMFA(userID uint) (secret, qrCodeURL string, err error) {
+	if err := utils.DBMustInit(); err != nil {
+		return "", "", err
+	}
+
+	var user system.SysUser
+	if err := global.DB.First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", "", errors.New("user not found")
+		}
+		return "", "", fmt.Errorf("failed to query user: %w", err)
+	}
+	if user.MFAEnabled {
+		return "", "", errors.New("MFA is already enabled")
+	}
+
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      mfaIssuer,
+		AccountName: user.Username,
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate TOTP secret: %w", err)
+	}
+
+	// 生成二维码图片
+	img, err := key.Image(200, 200)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate QR code: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return "", "", fmt.Errorf("failed to encode QR code: %w", err)
+	}
+
+	if err := global.DB.Model(&user).Update("mfa_secret", key.Secret()).Error; err != nil {
+		return "", "", fmt.Errorf("failed to save TOTP secret: %w", err)
+	}
+
+	qrCodeURL = "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes())
+	return key.Secret(), qrCodeURL, nil
+}
+
+// VerifyMFA 使用TOTP验证码校验已保存的密钥，校验通过后启用两步验证
+func (s *UserService) VerifyMFA(userID uint, code string) error {
+	if err := utils.DBMustInit(); err != nil {
+		return err
+	}
+
+	var user system.SysUser
+	if err := global.DB.First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("user not found")
+		}
+		return fmt.Errorf("failed to query user: %w", err)
+	}
+	if user.MFASecret == "" {
+		return errors.New("MFA is not set up")
+	}
+
+	if !totp.Validate(code, user.MFASecret) {
+		return errors.New("invalid TOTP code")
+	}
+
+	if !user.MFAEnabled {
+		if err := global.DB.Model(&user).Update("mfa_enabled", true).Error; err != nil {
+			return fmt.Errorf("failed to enable MFA: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,635 @@
+package system
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"k-admin-system/global"
+	"k-admin-system/model/system"
+
+	"github.com/pquerna/otp/totp"
+)
+
+func TestLogin_InvalidTOTPCountsAsFailedAttempt(t *testing.T) {
+	setupTestEnv(t)
+	role := createTestRole(t, "editor")
+	user := createTestUser(t, "alice", "Passw0rd!", role.ID)
+
+	key, err := totp.Generate(totp.GenerateOpts{Issuer: "K-Admin", AccountName: "alice"})
+	if err != nil {
+		t.Fatalf("failed to generate TOTP key: %v", err)
+	}
+	if err := global.DB.Model(user).Updates(map[string]interface{}{
+		"mfa_secret":  key.Secret(),
+		"mfa_enabled": true,
+	}).Error; err != nil {
+		t.Fatalf("failed to enable MFA: %v", err)
+	}
+
+	userService := UserService{}
+	for i := 0; i < global.Config.Security.MaxFailedAttempts; i++ {
+		if _, _, _, err := userService.Login("alice", "Passw0rd!", "000000", "", "", ""); err == nil {
+			t.Fatal("Login() with wrong TOTP code succeeded")
+		}
+	}
+
+	var locked system.SysUser
+	if err := global.DB.First(&locked, user.ID).Error; err != nil {
+		t.Fatalf("failed to load user: %v", err)
+	}
+	if locked.LockedUntil == nil {
+		t.Fatal("account was not locked after repeated invalid TOTP codes")
+	}
+
+	_, _, _, err = userService.Login("alice", "Passw0rd!", "000000", "", "", "")
+	if err == nil || !strings.HasPrefix(err.Error(), "account locked") {
+		t.Errorf("Login() on locked account error = %v, want account locked", err)
+	}
+}
+
+// expirePassword 将用户的密码设置时间回拨到 days 天前（绕过 BeforeUpdate 钩子）
+func expirePassword(t *testing.T, user *system.SysUser, days int) {
+	t.Helper()
+	if err := global.DB.Model(user).UpdateColumn("password_changed_at", time.Now().AddDate(0, 0, -days)).Error; err != nil {
+		t.Fatalf("failed to backdate password: %v", err)
+	}
+}
+
+func TestChangeExpiredPassword_UnblocksLogin(t *testing.T) {
+	setupTestEnv(t)
+	role := createTestRole(t, "editor")
+	user := createTestUser(t, "bob", "Passw0rd!", role.ID)
+	expirePassword(t, user, global.Config.Security.PasswordMaxAgeDays+1)
+
+	userService := UserService{}
+	if _, _, _, err := userService.Login("bob", "Passw0rd!", "", "", "", ""); !errors.Is(err, ErrPasswordExpired) {
+		t.Fatalf("Login() error = %v, want ErrPasswordExpired", err)
+	}
+
+	if err := userService.ChangeExpiredPassword("bob", "wrong", "", "N3w-Passw0rd!"); err == nil {
+		t.Fatal("ChangeExpiredPassword() with wrong old password succeeded")
+	}
+	if err := userService.ChangeExpiredPassword("bob", "Passw0rd!", "", "N3w-Passw0rd!"); err != nil {
+		t.Fatalf("ChangeExpiredPassword() error = %v", err)
+	}
+
+	if _, _, _, err := userService.Login("bob", "N3w-Passw0rd!", "", "", "", ""); err != nil {
+		t.Fatalf("Login() after changing expired password error = %v", err)
+	}
+}
+
+func TestChangeExpiredPassword_RejectsUnexpiredPassword(t *testing.T) {
+	setupTestEnv(t)
+	role := createTestRole(t, "editor")
+	createTestUser(t, "carol", "Passw0rd!", role.ID)
+
+	userService := UserService{}
+	if err := userService.ChangeExpiredPassword("carol", "Passw0rd!", "", "N3w-Passw0rd!"); err == nil {
+		t.Fatal("ChangeExpiredPassword() on a current password succeeded")
+	}
+}
+
+func TestLogin_ZeroMaxAgeDisablesExpiry(t *testing.T) {
+	setupTestEnv(t)
+	global.Config.Security.PasswordMaxAgeDays = 0
+	role := createTestRole(t, "editor")
+	user := createTestUser(t, "dave", "Passw0rd!", role.ID)
+	expirePassword(t, user, 3650)
+
+	userService := UserService{}
+	if _, _, _, err := userService.Login("dave", "Passw0rd!", "", "", "", ""); err != nil {
+		t.Fatalf("Login() with expiry disabled error = %v", err)
+	}
+}
+
+func TestLogin_ConcurrentFailuresLockAccount(t *testing.T) {
+	setupTestEnv(t)
+	role := createTestRole(t, "editor")
+	user := createTestUser(t, "erin", "Passw0rd!", role.ID)
+
+	userService := UserService{}
+	attempts := global.Config.Security.MaxFailedAttempts
+	var wg sync.WaitGroup
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, _, _ = userService.Login("erin", "wrong-password", "", "", "", "")
+		}()
+	}
+	wg.Wait()
+
+	var reloaded system.SysUser
+	if err := global.DB.First(&reloaded, user.ID).Error; err != nil {
+		t.Fatalf("failed to load user: %v", err)
+	}
+	if reloaded.LockedUntil == nil || !reloaded.LockedUntil.After(time.Now()) {
+		t.Fatalf("account not locked after %d concurrent failures (attempts = %d)", attempts, reloaded.FailedLoginAttempts)
+	}
+	if reloaded.FailedLoginAttempts != 0 {
+		t.Errorf("failed attempts = %d after locking, want 0", reloaded.FailedLoginAttempts)
+	}
+}
+
+func TestSearchUsers_PrefixMatch(t *testing.T) {
+	setupTestEnv(t)
+	role := createTestRole(t, "editor")
+	for i := 0; i < 20; i++ {
+		name := fmt.Sprintf("user%02d", i)
+		if i < 5 {
+			name = fmt.Sprintf("jo%02d", i)
+		}
+		createTestUser(t, name, "Passw0rd!", role.ID)
+	}
+	createTestUser(t, "mojo", "Passw0rd!", role.ID)
+	createTestUser(t, "jo_x", "Passw0rd!", role.ID)
+
+	s := UserService{}
+	users, err := s.SearchUsers("jo", 10)
+	if err != nil {
+		t.Fatalf("SearchUsers() error = %v", err)
+	}
+	if len(users) != 6 {
+		t.Fatalf("SearchUsers(jo) returned %d users, want 6", len(users))
+	}
+	for _, user := range users {
+		if !strings.HasPrefix(user.Username, "jo") {
+			t.Errorf("SearchUsers(jo) returned %q", user.Username)
+		}
+		if user.Password != "" {
+			t.Error("SearchUsers() selected the password column")
+		}
+	}
+
+	// 通配符按字面量匹配
+	users, err = s.SearchUsers("jo_", 10)
+	if err != nil {
+		t.Fatalf("SearchUsers() error = %v", err)
+	}
+	if len(users) != 1 || users[0].Username != "jo_x" {
+		t.Errorf("SearchUsers(jo_) = %v, want only jo_x", users)
+	}
+}
+
+func TestGetRecentlyCreatedUsers(t *testing.T) {
+	setupTestEnv(t)
+	role := createTestRole(t, "editor")
+
+	// 以固定哈希批量创建，创建时间逐个递增
+	base := time.Now().Add(-time.Hour)
+	for i := 0; i < 20; i++ {
+		user := &system.SysUser{Username: fmt.Sprintf("user%02d", i), Password: "hash", RoleID: role.ID, Active: true}
+		user.CreatedAt = base.Add(time.Duration(i) * time.Minute)
+		if err := global.DB.Create(user).Error; err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+	}
+
+	s := UserService{}
+	users, err := s.GetRecentlyCreatedUsers(5)
+	if err != nil {
+		t.Fatalf("GetRecentlyCreatedUsers() error = %v", err)
+	}
+	if len(users) != 5 {
+		t.Fatalf("GetRecentlyCreatedUsers(5) returned %d users", len(users))
+	}
+	for i, user := range users {
+		if want := fmt.Sprintf("user%02d", 19-i); user.Username != want {
+			t.Errorf("users[%d] = %s, want %s", i, user.Username, want)
+		}
+	}
+
+	// limit 限制在 1 到 100 之间
+	if users, _ := s.GetRecentlyCreatedUsers(0); len(users) != 1 {
+		t.Errorf("GetRecentlyCreatedUsers(0) returned %d users, want 1", len(users))
+	}
+	if users, _ := s.GetRecentlyCreatedUsers(1000); len(users) != 20 {
+		t.Errorf("GetRecentlyCreatedUsers(1000) returned %d users, want 20", len(users))
+	}
+}
+
+func TestGetUsersCreatedBetween_InclusiveBounds(t *testing.T) {
+	setupTestEnv(t)
+	role := createTestRole(t, "editor")
+
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 3, 31, 23, 59, 59, 0, time.UTC)
+	createdAt := map[string]time.Time{
+		"before": start.Add(-time.Second),
+		"start":  start,
+		"middle": start.Add(15 * 24 * time.Hour),
+		"end":    end,
+		"after":  end.Add(time.Second),
+	}
+	for name, at := range createdAt {
+		user := &system.SysUser{Username: name, Password: "hash", RoleID: role.ID, Active: true}
+		user.CreatedAt = at
+		if err := global.DB.Create(user).Error; err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+	}
+
+	s := UserService{}
+	users, err := s.GetUsersCreatedBetween(start, end)
+	if err != nil {
+		t.Fatalf("GetUsersCreatedBetween() error = %v", err)
+	}
+	names := make([]string, 0, len(users))
+	for _, user := range users {
+		names = append(names, user.Username)
+	}
+	if want := []string{"start", "middle", "end"}; strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Errorf("GetUsersCreatedBetween() = %v, want %v", names, want)
+	}
+
+	// 起止时间相同时仅返回恰好在该时刻创建的用户
+	users, err = s.GetUsersCreatedBetween(start, start)
+	if err != nil {
+		t.Fatalf("GetUsersCreatedBetween() error = %v", err)
+	}
+	if len(users) != 1 || users[0].Username != "start" {
+		t.Errorf("GetUsersCreatedBetween(start, start) = %v, want only the user created at start", users)
+	}
+
+	if _, err := s.GetUsersCreatedBetween(end, start); err == nil {
+		t.Error("GetUsersCreatedBetween() accepted an end before the start")
+	}
+}
+
+// setLastLogin 设置用户的最后登录时间，nil 表示从未登录
+func setLastLogin(t *testing.T, user *system.SysUser, lastLoginAt *time.Time) {
+	t.Helper()
+	if err := global.DB.Model(user).Update("last_login_at", lastLoginAt).Error; err != nil {
+		t.Fatalf("failed to set last login: %v", err)
+	}
+}
+
+func TestGetInactiveUsers_NeverLoggedInCountsAsInactive(t *testing.T) {
+	setupTestEnv(t)
+	role := createTestRole(t, "editor")
+
+	recent := time.Now().Add(-24 * time.Hour)
+	old := time.Now().Add(-100 * 24 * time.Hour)
+	active := createTestUser(t, "active", "Password123!", role.ID)
+	setLastLogin(t, active, &recent)
+	dormant := createTestUser(t, "dormant", "Password123!", role.ID)
+	setLastLogin(t, dormant, &old)
+	// 刚创建、从未登录的用户同样视为不活跃
+	never := createTestUser(t, "never", "Password123!", role.ID)
+
+	users, err := (&UserService{}).GetInactiveUsers(90 * 24 * time.Hour)
+	if err != nil {
+		t.Fatalf("GetInactiveUsers() error = %v", err)
+	}
+	got := make([]uint, 0, len(users))
+	for _, user := range users {
+		got = append(got, user.ID)
+	}
+	if want := []uint{dormant.ID, never.ID}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("GetInactiveUsers() ids = %v, want %v", got, want)
+	}
+}
+
+func TestCleanupInactiveUsers_SkipsAdmin(t *testing.T) {
+	setupTestEnv(t)
+	adminRole := createTestRole(t, "admin")
+	editorRole := createTestRole(t, "editor")
+
+	createTestUser(t, "admin", "Password123!", adminRole.ID)
+	never := createTestUser(t, "never", "Password123!", editorRole.ID)
+	recent := time.Now()
+	active := createTestUser(t, "active", "Password123!", editorRole.ID)
+	setLastLogin(t, active, &recent)
+
+	deleted, err := (&UserService{}).CleanupInactiveUsers(90 * 24 * time.Hour)
+	if err != nil {
+		t.Fatalf("CleanupInactiveUsers() error = %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("CleanupInactiveUsers() deleted %d users, want 1", deleted)
+	}
+
+	var remaining []string
+	if err := global.DB.Model(&system.SysUser{}).Order("id").Pluck("username", &remaining).Error; err != nil {
+		t.Fatalf("failed to query users: %v", err)
+	}
+	if fmt.Sprint(remaining) != "[admin active]" {
+		t.Errorf("remaining users = %v, want [admin active]", remaining)
+	}
+	var softDeleted system.SysUser
+	if err := global.DB.Unscoped().First(&softDeleted, never.ID).Error; err != nil || !softDeleted.DeletedAt.Valid {
+		t.Errorf("never-logged-in user was not soft deleted: %v", err)
+	}
+}
+
+// profileEdit 模拟更新用户接口：只包含请求中的字段
+func profileEdit(user *system.SysUser) *system.SysUser {
+	edit := &system.SysUser{
+		Username: user.Username,
+		Nickname: "edited",
+		RoleID:   user.RoleID,
+		Active:   true,
+	}
+	edit.ID = user.ID
+	return edit
+}
+
+func TestUpdateUser_KeepsMFAEnabled(t *testing.T) {
+	setupTestEnv(t)
+	role := createTestRole(t, "editor")
+	user := createTestUser(t, "alice", "Password123!", role.ID)
+	if err := global.DB.Model(user).Updates(map[string]interface{}{"mfa_enabled": true, "mfa_secret": "JBSWY3DPEHPK3PXP"}).Error; err != nil {
+		t.Fatalf("failed to enable MFA: %v", err)
+	}
+
+	if err := (&UserService{}).UpdateUser(profileEdit(user)); err != nil {
+		t.Fatalf("UpdateUser() error = %v", err)
+	}
+
+	var stored system.SysUser
+	if err := global.DB.First(&stored, user.ID).Error; err != nil {
+		t.Fatalf("failed to load user: %v", err)
+	}
+	if stored.Nickname != "edited" {
+		t.Errorf("Nickname = %q, want edited", stored.Nickname)
+	}
+	if !stored.MFAEnabled || stored.MFASecret != "JBSWY3DPEHPK3PXP" {
+		t.Errorf("MFA after profile edit: enabled = %v, secret = %q; want it kept", stored.MFAEnabled, stored.MFASecret)
+	}
+}
+
+func TestLogin_LockExpiry(t *testing.T) {
+	tests := []struct {
+		name         string
+		lockedFor    time.Duration // 相对当前时间的锁定截止时间
+		password     string
+		wantErr      string
+		wantAttempts int
+		wantLocked   bool
+	}{
+		{"lock active rejects correct password", time.Minute, "Passw0rd!", "account locked", 3, true},
+		{"expired lock allows login", -time.Minute, "Passw0rd!", "", 0, false},
+		{"expired lock counts a new failure", -time.Minute, "wrong", "invalid username or password", 4, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestEnv(t)
+			role := createTestRole(t, "editor")
+			user := createTestUser(t, "erin", "Passw0rd!", role.ID)
+			lockedUntil := time.Now().Add(tt.lockedFor)
+			if err := global.DB.Model(user).Updates(map[string]interface{}{
+				"failed_login_attempts": 3,
+				"locked_until":          lockedUntil,
+			}).Error; err != nil {
+				t.Fatalf("failed to lock user: %v", err)
+			}
+
+			_, _, _, err := (&UserService{}).Login("erin", tt.password, "", "", "", "")
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("Login() error = %v, want success", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("Login() error = %v, want %q", err, tt.wantErr)
+			}
+
+			var reloaded system.SysUser
+			if err := global.DB.First(&reloaded, user.ID).Error; err != nil {
+				t.Fatalf("failed to load user: %v", err)
+			}
+			if reloaded.FailedLoginAttempts != tt.wantAttempts {
+				t.Errorf("failed attempts = %d, want %d", reloaded.FailedLoginAttempts, tt.wantAttempts)
+			}
+			// 过期的锁定记录不再生效，失败计数未达上限时不会重新锁定
+			if locked := reloaded.LockedUntil != nil && reloaded.LockedUntil.After(time.Now()); locked != tt.wantLocked {
+				t.Errorf("locked = %v (until %v), want %v", locked, reloaded.LockedUntil, tt.wantLocked)
+			}
+		})
+	}
+}
+
+func TestUpdateUser_KeepsLockout(t *testing.T) {
+	setupTestEnv(t)
+	role := createTestRole(t, "editor")
+	user := createTestUser(t, "erin", "Passw0rd!", role.ID)
+	lockedUntil := time.Now().Add(time.Hour)
+	if err := global.DB.Model(user).Updates(map[string]interface{}{
+		"failed_login_attempts": 4,
+		"locked_until":          lockedUntil,
+	}).Error; err != nil {
+		t.Fatalf("failed to lock user: %v", err)
+	}
+
+	if err := (&UserService{}).UpdateUser(profileEdit(user)); err != nil {
+		t.Fatalf("UpdateUser() error = %v", err)
+	}
+
+	var stored system.SysUser
+	if err := global.DB.First(&stored, user.ID).Error; err != nil {
+		t.Fatalf("failed to load user: %v", err)
+	}
+	if stored.FailedLoginAttempts != 4 || stored.LockedUntil == nil || !stored.LockedUntil.After(time.Now()) {
+		t.Errorf("lockout after profile edit: attempts = %d, locked until %v; want it kept", stored.FailedLoginAttempts, stored.LockedUntil)
+	}
+}
+
+func TestUpdateUser_KeepsLoginHistory(t *testing.T) {
+	setupTestEnv(t)
+	role := createTestRole(t, "editor")
+	user := createTestUser(t, "alice", "Password123!", role.ID)
+	lastLogin := time.Now().Add(-time.Hour)
+	setLastLogin(t, user, &lastLogin)
+
+	if err := (&UserService{}).UpdateUser(profileEdit(user)); err != nil {
+		t.Fatalf("UpdateUser() error = %v", err)
+	}
+
+	var stored system.SysUser
+	if err := global.DB.First(&stored, user.ID).Error; err != nil {
+		t.Fatalf("failed to load user: %v", err)
+	}
+	if stored.LastLoginAt == nil || !stored.LastLoginAt.Equal(lastLogin) {
+		t.Errorf("LastLoginAt after profile edit = %v, want %v", stored.LastLoginAt, lastLogin)
+	}
+	if !stored.CreatedAt.Equal(user.CreatedAt) {
+		t.Errorf("CreatedAt after profile edit = %v, want %v", stored.CreatedAt, user.CreatedAt)
+	}
+
+	// 编辑过的用户不会被当作从未登录而清理
+	inactive, err := (&UserService{}).GetInactiveUsers(24 * time.Hour)
+	if err != nil {
+		t.Fatalf("GetInactiveUsers() error = %v", err)
+	}
+	if len(inactive) != 0 {
+		t.Errorf("GetInactiveUsers() = %d users after a profile edit, want 0", len(inactive))
+	}
+}
+
+func TestGetUserActivitySummary(t *testing.T) {
+	setupTestEnv(t)
+	role := createTestRole(t, "editor")
+	alice := createTestUser(t, "alice", "Password123!", role.ID)
+	bob := createTestUser(t, "bob", "Password123!", role.ID)
+
+	now := time.Now().Truncate(time.Second)
+	logs := []system.SysAuditLog{
+		{UserID: alice.ID, Method: "GET", Path: "/api/v1/user/list"},
+		{UserID: alice.ID, Method: "GET", Path: "/api/v1/user/list"},
+		{UserID: alice.ID, Method: "GET", Path: "/api/v1/user/list"},
+		{UserID: alice.ID, Method: "GET", Path: "/api/v1/role/list"},
+		{UserID: alice.ID, Method: "GET", Path: "/api/v1/role/list"},
+		{UserID: alice.ID, Method: "POST", Path: "/api/v1/user"},
+		{UserID: alice.ID, Method: "PUT", Path: "/api/v1/user"},
+		{UserID: alice.ID, Method: "DELETE", Path: "/api/v1/user/3"},
+		{UserID: alice.ID, Method: "GET", Path: "/api/v1/menu/tree"},
+		{UserID: alice.ID, Method: "GET", Path: "/api/v1/audit/list"},
+		// 统计周期之外的记录和其他用户的记录不计入
+		{UserID: alice.ID, Method: "GET", Path: "/api/v1/old"},
+		{UserID: bob.ID, Method: "GET", Path: "/api/v1/user/list"},
+	}
+	for i := range logs {
+		logs[i].CreatedAt = now.Add(-time.Duration(len(logs)-i) * time.Minute)
+	}
+	logs[10].CreatedAt = now.Add(-48 * time.Hour)
+	for i := range logs {
+		if err := global.DB.Create(&logs[i]).Error; err != nil {
+			t.Fatalf("failed to create audit log: %v", err)
+		}
+	}
+
+	summary, err := (&UserService{}).GetUserActivitySummary(alice.ID, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("GetUserActivitySummary() error = %v", err)
+	}
+	if summary.TotalRequests != 10 {
+		t.Errorf("TotalRequests = %d, want 10", summary.TotalRequests)
+	}
+	wantMethods := map[string]int64{"GET": 7, "POST": 1, "PUT": 1, "DELETE": 1}
+	if !reflect.DeepEqual(summary.MethodBreakdown, wantMethods) {
+		t.Errorf("MethodBreakdown = %v, want %v", summary.MethodBreakdown, wantMethods)
+	}
+	// 按访问次数倒序，次数相同时按路径排序，最多5个
+	wantPaths := []string{"/api/v1/user/list", "/api/v1/role/list", "/api/v1/user", "/api/v1/audit/list", "/api/v1/menu/tree"}
+	if !reflect.DeepEqual(summary.MostVisitedPaths, wantPaths) {
+		t.Errorf("MostVisitedPaths = %v, want %v", summary.MostVisitedPaths, wantPaths)
+	}
+	if summary.LastActivity == nil || !summary.LastActivity.Equal(logs[9].CreatedAt) {
+		t.Errorf("LastActivity = %v, want %v", summary.LastActivity, logs[9].CreatedAt)
+	}
+
+	// 周期内没有记录的用户
+	summary, err = (&UserService{}).GetUserActivitySummary(bob.ID, time.Second)
+	if err != nil {
+		t.Fatalf("GetUserActivitySummary() error = %v", err)
+	}
+	if summary.TotalRequests != 0 || len(summary.MethodBreakdown) != 0 || len(summary.MostVisitedPaths) != 0 || summary.LastActivity != nil {
+		t.Errorf("summary without activity = %+v, want empty", summary)
+	}
+
+	if _, err := (&UserService{}).GetUserActivitySummary(bob.ID+1, time.Hour); err == nil || err.Error() != "user not found" {
+		t.Errorf("GetUserActivitySummary() for a missing user error = %v", err)
+	}
+}
+
+func TestGetUsersByMenuAccess_TwoRoles(t *testing.T) {
+	setupTestEnv(t)
+	editor := createTestRole(t, "editor")
+	viewer := createTestRole(t, "viewer")
+	guest := createTestRole(t, "guest")
+	alice := createTestUser(t, "alice", "Password123!", editor.ID)
+	bob := createTestUser(t, "bob", "Password123!", viewer.ID)
+	carol := createTestUser(t, "carol", "Password123!", editor.ID)
+	createTestUser(t, "dave", "Password123!", guest.ID)
+
+	reports := createTestMenu(t, "/reports", "Reports", "views/reports/index")
+	settings := createTestMenu(t, "/settings", "Settings", "views/settings/index")
+	for _, role := range []*system.SysRole{editor, viewer} {
+		if err := global.DB.Model(role).Association("Menus").Append(reports); err != nil {
+			t.Fatalf("failed to assign menu: %v", err)
+		}
+	}
+	if err := global.DB.Model(guest).Association("Menus").Append(settings); err != nil {
+		t.Fatalf("failed to assign menu: %v", err)
+	}
+
+	users, err := (&UserService{}).GetUsersByMenuAccess(reports.ID)
+	if err != nil {
+		t.Fatalf("GetUsersByMenuAccess() error = %v", err)
+	}
+	var got []string
+	for _, user := range users {
+		got = append(got, user.Username+":"+user.Role.RoleKey)
+	}
+	want := []string{alice.Username + ":editor", bob.Username + ":viewer", carol.Username + ":editor"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetUsersByMenuAccess() = %v, want %v", got, want)
+	}
+
+	if _, err := (&UserService{}).GetUsersByMenuAccess(settings.ID + 1); err == nil || err.Error() != "menu not found" {
+		t.Errorf("GetUsersByMenuAccess() for a missing menu error = %v", err)
+	}
+}
+
+func TestGetUsersByLastLoginRange_RecentAndDormant(t *testing.T) {
+	setupTestEnv(t)
+	role := createTestRole(t, "editor")
+	now := time.Now().Truncate(time.Second)
+
+	var dormant []string
+	for i := 0; i < 10; i++ {
+		user := createTestUser(t, fmt.Sprintf("user%02d", i), "Password123!", role.ID)
+		// 偶数用户最近一周内登录过，奇数用户60天以上未登录（越靠后越久）
+		lastLogin := now.Add(-time.Duration(i+1) * time.Hour)
+		if i%2 == 1 {
+			lastLogin = now.AddDate(0, 0, -60-i*5)
+			dormant = append([]string{user.Username}, dormant...)
+		}
+		setLastLogin(t, user, &lastLogin)
+		if i == 3 || i == 7 {
+			if err := global.DB.Model(user).Update("active", false).Error; err != nil {
+				t.Fatalf("failed to disable user: %v", err)
+			}
+		}
+	}
+	usernames := func(users []system.SysUser) []string {
+		names := make([]string, 0, len(users))
+		for _, user := range users {
+			names = append(names, user.Username)
+		}
+		return names
+	}
+	s := &UserService{}
+
+	users, err := s.GetUsersByLastLoginRange(now.AddDate(0, 0, -365), now.AddDate(0, 0, -30), nil)
+	if err != nil {
+		t.Fatalf("GetUsersByLastLoginRange() error = %v", err)
+	}
+	if got := usernames(users); !reflect.DeepEqual(got, dormant) {
+		t.Errorf("dormant users = %v, want %v", got, dormant)
+	}
+
+	active := true
+	users, err = s.GetUsersByLastLoginRange(now.AddDate(0, 0, -365), now.AddDate(0, 0, -30), &active)
+	if err != nil {
+		t.Fatalf("GetUsersByLastLoginRange() error = %v", err)
+	}
+	if got, want := usernames(users), []string{"user09", "user05", "user01"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("active dormant users = %v, want %v", got, want)
+	}
+
+	users, err = s.GetUsersByLastLoginRange(now.AddDate(0, 0, -7), now, nil)
+	if err != nil {
+		t.Fatalf("GetUsersByLastLoginRange() error = %v", err)
+	}
+	if got, want := usernames(users), []string{"user08", "user06", "user04", "user02", "user00"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("recent users = %v, want %v", got, want)
+	}
+
+	if _, err := s.GetUsersByLastLoginRange(now, now.Add(-time.Hour), nil); err == nil {
+		t.Error("GetUsersByLastLoginRange() accepted an end time before the start time")
+	}
+}
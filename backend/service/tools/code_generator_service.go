@@ -2,12 +2,19 @@ package tools
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 	"text/template"
 
+	"k-admin-system/global"
+	"k-admin-system/model/system"
+
 	"gorm.io/gorm"
 )
 
@@ -30,11 +37,28 @@ type FieldConfig struct {
 	GormTag      string `json:"gorm_tag"`
 	Comment      string `json:"comment"`
 	TSType       string `json:"ts_type"`
+	SQLType      string `json:"sql_type,omitempty"` // column type for migration SQL, derived from FieldType when empty
 	Label        string `json:"label"`
 	FormType     string `json:"form_type"`
 	Searchable   bool   `json:"searchable"`
 	Nullable     bool   `json:"nullable"`
 	IsPrimaryKey bool   `json:"is_primary_key"`
+	// Enum columns only: allowed values and the generated Go type declaration
+	EnumValues []string `json:"enum_values,omitempty"`
+	EnumDecl   string   `json:"enum_decl,omitempty"`
+	// Foreign key columns named <name>_id only: the BelongsTo association field
+	// (e.g. Role SysRole) and its rendered declaration for the model template
+	AssociationName string `json:"association_name,omitempty"`
+	AssociationType string `json:"association_type,omitempty"`
+	AssociationDecl string `json:"association_decl,omitempty"`
+	// Migration template only: the column definition and a quoted ALTER TABLE statement
+	// that adds the column to a table created by an earlier version of the migration
+	ColumnDef    string `json:"-"`
+	AddColumnSQL string `json:"-"`
+	// Unit test template only: Go literals assigned when creating and when updating a test record,
+	// empty for field types the template cannot build a value for
+	TestValue       string `json:"-"`
+	TestUpdateValue string `json:"-"`
 }
 
 // GenerateConfig represents the configuration for code generation
@@ -52,13 +76,30 @@ type GenerateConfig struct {
 
 // GenerateOptions represents options for code generation
 type GenerateOptions struct {
-	GenerateModel         bool `json:"generate_model"`
-	GenerateService       bool `json:"generate_service"`
-	GenerateAPI           bool `json:"generate_api"`
-	GenerateRouter        bool `json:"generate_router"`
-	GenerateFrontendAPI   bool `json:"generate_frontend_api"`
-	GenerateFrontendTypes bool `json:"generate_frontend_types"`
-	GenerateFrontendPage  bool `json:"generate_frontend_page"`
+	GenerateModel         bool `json:"generate_model" form:"generate_model"`
+	GenerateService       bool `json:"generate_service" form:"generate_service"`
+	GenerateAPI           bool `json:"generate_api" form:"generate_api"`
+	GenerateRouter        bool `json:"generate_router" form:"generate_router"`
+	GenerateFrontendAPI   bool `json:"generate_frontend_api" form:"generate_frontend_api"`
+	GenerateFrontendTypes bool `json:"generate_frontend_types" form:"generate_frontend_types"`
+	GenerateFrontendPage  bool `json:"generate_frontend_page" form:"generate_frontend_page"`
+	GenerateTests         bool `json:"generate_tests" form:"generate_tests"`
+	GenerateMigrationSQL  bool `json:"generate_migration_sql" form:"generate_migration_sql"`
+}
+
+// AllGenerateOptions returns options with every generation flag enabled
+func AllGenerateOptions() GenerateOptions {
+	return GenerateOptions{
+		GenerateModel:         true,
+		GenerateService:       true,
+		GenerateAPI:           true,
+		GenerateRouter:        true,
+		GenerateFrontendAPI:   true,
+		GenerateFrontendTypes: true,
+		GenerateFrontendPage:  true,
+		GenerateTests:         true,
+		GenerateMigrationSQL:  true,
+	}
 }
 
 // TableMetadata represents metadata extracted from a database table
@@ -77,10 +118,16 @@ type CodeGenColumnInfo struct {
 	Default  string `json:"default"`
 	Extra    string `json:"extra"`
 	Comment  string `json:"comment"`
+	// ReferencedTable is the table referenced by a foreign key on this column, if any
+	ReferencedTable string `json:"referenced_table,omitempty"`
 }
 
 // GetTableMetadata extracts metadata from a database table
 func (s *CodeGeneratorService) GetTableMetadata(tableName string) (*TableMetadata, error) {
+	if s.db.Dialector.Name() == "sqlite" {
+		return s.getSQLiteTableMetadata(tableName)
+	}
+
 	var columns []CodeGenColumnInfo
 
 	query := `
@@ -116,6 +163,31 @@ func (s *CodeGeneratorService) GetTableMetadata(tableName string) (*TableMetadat
 	`
 	s.db.Raw(commentQuery, tableName).Scan(&tableComment)
 
+	// Get foreign key references
+	var foreignKeys []struct {
+		ColumnName      string
+		ReferencedTable string
+	}
+	fkQuery := `
+		SELECT
+			COLUMN_NAME as column_name,
+			REFERENCED_TABLE_NAME as referenced_table
+		FROM INFORMATION_SCHEMA.KEY_COLUMN_USAGE
+		WHERE TABLE_SCHEMA = DATABASE()
+		AND TABLE_NAME = ?
+		AND REFERENCED_TABLE_NAME IS NOT NULL
+	`
+	if err := s.db.Raw(fkQuery, tableName).Scan(&foreignKeys).Error; err != nil {
+		return nil, fmt.Errorf("failed to get foreign keys: %w", err)
+	}
+	for _, fk := range foreignKeys {
+		for i := range columns {
+			if columns[i].Name == fk.ColumnName {
+				columns[i].ReferencedTable = fk.ReferencedTable
+			}
+		}
+	}
+
 	return &TableMetadata{
 		TableName:    tableName,
 		TableComment: tableComment,
@@ -123,16 +195,170 @@ func (s *CodeGeneratorService) GetTableMetadata(tableName string) (*TableMetadat
 	}, nil
 }
 
+// getSQLiteTableMetadata extracts table metadata with PRAGMA statements.
+// SQLite has no column or table comments, so those are left empty
+func (s *CodeGeneratorService) getSQLiteTableMetadata(tableName string) (*TableMetadata, error) {
+	// PRAGMA does not accept bind parameters, so the name is restricted to identifier characters
+	if !goIdentifierPattern.MatchString(tableName) {
+		return nil, fmt.Errorf("invalid table name: %s", tableName)
+	}
+
+	var sqliteColumns []struct {
+		Name      string  `gorm:"column:name"`
+		Type      string  `gorm:"column:type"`
+		NotNull   int     `gorm:"column:notnull"`
+		DfltValue *string `gorm:"column:dflt_value"`
+		PK        int     `gorm:"column:pk"`
+	}
+	if err := s.db.Raw(fmt.Sprintf("PRAGMA table_info(%s)", tableName)).Scan(&sqliteColumns).Error; err != nil {
+		return nil, fmt.Errorf("failed to get table metadata: %w", err)
+	}
+	if len(sqliteColumns) == 0 {
+		return nil, fmt.Errorf("table %s not found", tableName)
+	}
+
+	var foreignKeys []struct {
+		Table string `gorm:"column:table"`
+		From  string `gorm:"column:from"`
+	}
+	if err := s.db.Raw(fmt.Sprintf("PRAGMA foreign_key_list(%s)", tableName)).Scan(&foreignKeys).Error; err != nil {
+		return nil, fmt.Errorf("failed to get foreign keys: %w", err)
+	}
+	referencedTables := make(map[string]string, len(foreignKeys))
+	for _, fk := range foreignKeys {
+		referencedTables[fk.From] = fk.Table
+	}
+
+	columns := make([]CodeGenColumnInfo, 0, len(sqliteColumns))
+	for _, col := range sqliteColumns {
+		column := CodeGenColumnInfo{
+			Name:            col.Name,
+			Type:            strings.ToLower(col.Type),
+			Nullable:        col.NotNull == 0 && col.PK == 0,
+			ReferencedTable: referencedTables[col.Name],
+		}
+		if col.PK > 0 {
+			column.Key = "PRI"
+		}
+		if col.DfltValue != nil {
+			column.Default = *col.DfltValue
+		}
+		columns = append(columns, column)
+	}
+
+	return &TableMetadata{
+		TableName: tableName,
+		Columns:   columns,
+	}, nil
+}
+
+// ListUserTables lists the tables that can be used for code generation.
+// System tables (sys_* and casbin_rule) and migration bookkeeping tables are excluded.
+func (s *CodeGeneratorService) ListUserTables() ([]string, error) {
+	tables, err := s.db.Migrator().GetTables()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+
+	userTables := make([]string, 0, len(tables))
+	for _, table := range tables {
+		if isSystemTable(table) {
+			continue
+		}
+		userTables = append(userTables, table)
+	}
+	sort.Strings(userTables)
+
+	return userTables, nil
+}
+
+// ValidationError describes an invalid field in a GenerateConfig
+type ValidationError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error implements the error interface
+func (e ValidationError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+var (
+	// goIdentifierPattern matches exported or unexported Go identifiers
+	goIdentifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	// goPackagePattern matches conventional lower-case Go package names
+	goPackagePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+	// sqlTypePattern is the allowlist of column types accepted in migration SQL
+	sqlTypePattern = regexp.MustCompile(`(?i)^(?:` +
+		`(?:tinyint|smallint|mediumint|int|integer|bigint)(?:\(\d+\))?(?: unsigned)?` +
+		`|(?:decimal|numeric)(?:\(\d+(?:, ?\d+)?\))?(?: unsigned)?` +
+		`|float|double|real|bool|boolean|date|year|json` +
+		`|(?:char|varchar|binary|varbinary)\(\d+\)` +
+		`|(?:tiny|medium|long)?(?:text|blob)` +
+		`|(?:datetime|timestamp|time)(?:\([0-6]\))?` +
+		`|(?:enum|set)\('(?:[^'\\]|'')*'(?:, ?'(?:[^'\\]|'')*')*\)` +
+		`)$`)
+)
+
+// ValidateConfig checks a GenerateConfig before any code is generated or written.
+// Struct and package names end up in file paths, so they are restricted to
+// identifier characters. It returns every problem found, or nil if the config is valid.
+func (s *CodeGeneratorService) ValidateConfig(config GenerateConfig) []ValidationError {
+	var errs []ValidationError
+
+	if config.TableName == "" {
+		errs = append(errs, ValidationError{Field: "table_name", Message: "is required"})
+	} else if !goIdentifierPattern.MatchString(config.TableName) {
+		errs = append(errs, ValidationError{Field: "table_name", Message: "may only contain letters, digits and underscores"})
+	}
+
+	if config.StructName == "" {
+		errs = append(errs, ValidationError{Field: "struct_name", Message: "is required"})
+	} else if !goIdentifierPattern.MatchString(config.StructName) {
+		errs = append(errs, ValidationError{Field: "struct_name", Message: "must be a valid Go identifier"})
+	}
+
+	if config.PackageName == "" {
+		errs = append(errs, ValidationError{Field: "package_name", Message: "is required"})
+	} else if !goPackagePattern.MatchString(config.PackageName) {
+		errs = append(errs, ValidationError{Field: "package_name", Message: "must be a lower-case Go package name"})
+	}
+
+	seen := make(map[string]bool, len(config.Fields))
+	for i, field := range config.Fields {
+		prefix := fmt.Sprintf("fields[%d]", i)
+		if field.ColumnName == "" {
+			errs = append(errs, ValidationError{Field: prefix + ".column_name", Message: "is required"})
+		} else if !goIdentifierPattern.MatchString(field.ColumnName) {
+			errs = append(errs, ValidationError{Field: prefix + ".column_name", Message: "may only contain letters, digits and underscores"})
+		}
+		if field.SQLType != "" && !sqlTypePattern.MatchString(field.SQLType) {
+			errs = append(errs, ValidationError{Field: prefix + ".sql_type", Message: "is not a supported column type"})
+		}
+		if field.FieldName == "" {
+			errs = append(errs, ValidationError{Field: prefix + ".field_name", Message: "is required"})
+		} else if !goIdentifierPattern.MatchString(field.FieldName) {
+			errs = append(errs, ValidationError{Field: prefix + ".field_name", Message: "must be a valid Go identifier"})
+		} else if seen[field.FieldName] {
+			errs = append(errs, ValidationError{Field: prefix + ".field_name", Message: "is duplicated"})
+		}
+		seen[field.FieldName] = true
+	}
+
+	return errs
+}
+
 // GenerateCode generates code based on the configuration
 func (s *CodeGeneratorService) GenerateCode(config GenerateConfig) (map[string]string, error) {
 	files := make(map[string]string)
 
 	// Add helper fields to config
 	config.RouterPath = strings.ToLower(strings.ReplaceAll(config.StructName, "_", "-"))
+	applyEnumTypes(&config)
 
 	// Generate backend files
 	if config.Options.GenerateModel {
-		content, err := s.generateFromTemplate("backend/model.tpl", config)
+		content, err := s.generateFromTemplate("backend/model.tpl", modelConfig(config))
 		if err != nil {
 			return nil, err
 		}
@@ -145,6 +371,15 @@ func (s *CodeGeneratorService) GenerateCode(config GenerateConfig) (map[string]s
 			return nil, err
 		}
 		files[fmt.Sprintf("backend/service/%s/%s_service.go", config.PackageName, strings.ToLower(config.StructName))] = content
+
+		// Generate test scaffold for the service
+		if config.Options.GenerateTests {
+			testContent, err := s.GenerateUnitTestFile(config)
+			if err != nil {
+				return nil, err
+			}
+			files[fmt.Sprintf("backend/service/%s/%s_service_test.go", config.PackageName, strings.ToLower(config.StructName))] = testContent
+		}
 	}
 
 	if config.Options.GenerateAPI {
@@ -163,6 +398,19 @@ func (s *CodeGeneratorService) GenerateCode(config GenerateConfig) (map[string]s
 		files[fmt.Sprintf("backend/router/%s/%s.go", config.PackageName, strings.ToLower(config.StructName))] = content
 	}
 
+	if config.Options.GenerateMigrationSQL {
+		migration, err := migrationConfig(config)
+		if err != nil {
+			return nil, err
+		}
+		content, err := s.generateFromTemplate("backend/migration.tpl", migration)
+		if err != nil {
+			return nil, err
+		}
+		// One file per table: regenerating overwrites it instead of adding another migration
+		files[fmt.Sprintf("backend/migrations/create_%s.sql", config.TableName)] = content
+	}
+
 	// Generate frontend files
 	if config.Options.GenerateFrontendTypes {
 		content, err := s.generateFromTemplate("frontend/types.tpl", config)
@@ -196,16 +444,98 @@ func (s *CodeGeneratorService) GenerateCode(config GenerateConfig) (map[string]s
 		files[fmt.Sprintf("%s/views/%s/components/%sModal.tsx", config.FrontendPath, strings.ToLower(config.StructName), config.StructName)] = modalContent
 	}
 
+	// Format generated Go files
+	for path, content := range files {
+		if !strings.HasSuffix(path, ".go") {
+			continue
+		}
+		formatted, err := s.FormatGoCode(content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to format %s: %w", path, err)
+		}
+		files[path] = formatted
+	}
+
 	return files, nil
 }
 
+// FormatGoCode formats Go source by running the local gofmt binary on a temp file.
+// If gofmt is not in PATH the source is returned unchanged and a warning is logged
+func (s *CodeGeneratorService) FormatGoCode(src string) (string, error) {
+	gofmtPath, err := exec.LookPath("gofmt")
+	if err != nil {
+		global.Logger.Warn("gofmt not found in PATH, generated Go code is left unformatted")
+		return src, nil
+	}
+
+	tempFile, err := os.CreateTemp("", "kadmin-gen-*.go")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tempFile.Name())
+
+	if _, err := tempFile.WriteString(src); err != nil {
+		tempFile.Close()
+		return "", fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := tempFile.Close(); err != nil {
+		return "", fmt.Errorf("failed to close temp file: %w", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(gofmtPath, tempFile.Name())
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("gofmt failed: %s", strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.String(), nil
+}
+
+// GenerateFromExistingTable generates code for an existing table using its metadata.
+// Fields are derived from the table columns; empty names and paths in config fall back to defaults
+func (s *CodeGeneratorService) GenerateFromExistingTable(metadata *TableMetadata, config GenerateConfig) (map[string]string, error) {
+	config.TableName = metadata.TableName
+	config.TableComment = metadata.TableComment
+
+	if config.StructName == "" {
+		config.StructName = toCamelCase(metadata.TableName)
+	}
+	if config.PackageName == "" {
+		config.PackageName = "system"
+	}
+	if config.ModulePath == "" {
+		config.ModulePath = "k-admin-system"
+	}
+	if config.FrontendPath == "" {
+		config.FrontendPath = "frontend/src"
+	}
+
+	config.Fields = make([]FieldConfig, 0, len(metadata.Columns))
+	for _, col := range metadata.Columns {
+		config.Fields = append(config.Fields, ConvertColumnToField(col))
+	}
+
+	return s.GenerateCode(config)
+}
+
+// GenerateUnitTestFile generates a *_test.go scaffold for the generated service,
+// with a test database helper, one test per CRUD operation and an ID uniqueness property test
+func (s *CodeGeneratorService) GenerateUnitTestFile(config GenerateConfig) (string, error) {
+	return s.generateFromTemplate("backend/service_test.tpl", unitTestConfig(config))
+}
+
 // PreviewCode generates code without writing to files
 func (s *CodeGeneratorService) PreviewCode(config GenerateConfig) (map[string]string, error) {
 	return s.GenerateCode(config)
 }
 
-// WriteGeneratedCode writes generated code to disk
-func (s *CodeGeneratorService) WriteGeneratedCode(files map[string]string) error {
+// GenerationRecord is a code generation history entry
+type GenerationRecord = system.SysCodeGenHistory
+
+// WriteGeneratedCode writes generated code to disk and records the generation in the history
+func (s *CodeGeneratorService) WriteGeneratedCode(config GenerateConfig, files map[string]string, generatedBy string) error {
 	for path, content := range files {
 		// Create directory if it doesn't exist
 		dir := filepath.Dir(path)
@@ -219,6 +549,44 @@ func (s *CodeGeneratorService) WriteGeneratedCode(files map[string]string) error
 		}
 	}
 
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	record := GenerationRecord{
+		GenTableName:   config.TableName,
+		StructName:     config.StructName,
+		PackageName:    config.PackageName,
+		GeneratedBy:    generatedBy,
+		FilesGenerated: paths,
+	}
+	if err := s.db.Create(&record).Error; err != nil {
+		return fmt.Errorf("failed to record generation history: %w", err)
+	}
+
+	return nil
+}
+
+// GetGenerationHistory returns all code generation records, newest first
+func (s *CodeGeneratorService) GetGenerationHistory() ([]GenerationRecord, error) {
+	var records []GenerationRecord
+	if err := s.db.Order("created_at DESC, id DESC").Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to get generation history: %w", err)
+	}
+	return records, nil
+}
+
+// DeleteGenerationRecord removes a history record; the generated files are left on disk
+func (s *CodeGeneratorService) DeleteGenerationRecord(id uint) error {
+	result := s.db.Delete(&GenerationRecord{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete generation record: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("generation record not found")
+	}
 	return nil
 }
 
@@ -260,12 +628,18 @@ func (s *CodeGeneratorService) generateFromTemplate(templatePath string, config
 	type TemplateData struct {
 		GenerateConfig
 		LowerStructName string
+		NeedsTimeImport bool
 	}
 
 	data := TemplateData{
 		GenerateConfig:  config,
 		LowerStructName: strings.ToLower(config.StructName[:1]) + config.StructName[1:],
 	}
+	for _, field := range config.Fields {
+		if field.FieldType == "time.Time" {
+			data.NeedsTimeImport = true
+		}
+	}
 
 	// Read template file
 	templateFile := filepath.Join("backend/resource/template", templatePath)
@@ -302,8 +676,20 @@ func ConvertColumnToField(col CodeGenColumnInfo) FieldConfig {
 	// Map database type to Go type
 	field.FieldType = mapDBTypeToGoType(col.Type)
 	field.TSType = mapDBTypeToTSType(col.Type)
+	field.SQLType = col.Type
 	field.FormType = mapDBTypeToFormType(col.Type)
 	field.Label = toLabel(col.Name)
+	if isEnumType(col.Type) {
+		field.EnumValues = parseEnumValues(col.Type)
+	}
+
+	// Foreign key columns named <name>_id get a BelongsTo association (role_id -> Role SysRole)
+	if col.ReferencedTable != "" && strings.HasSuffix(col.Name, "_id") {
+		field.AssociationName = toCamelCase(strings.TrimSuffix(col.Name, "_id"))
+		field.AssociationType = toCamelCase(singularize(col.ReferencedTable))
+		field.AssociationDecl = fmt.Sprintf("%s %s `gorm:\"foreignKey:%s\" json:\"%s,omitempty\"`",
+			field.AssociationName, field.AssociationType, field.FieldName, strings.TrimSuffix(col.Name, "_id"))
+	}
 
 	// Build Gorm tag
 	gormTags := []string{fmt.Sprintf("column:%s", col.Name)}
@@ -321,6 +707,217 @@ func ConvertColumnToField(col CodeGenColumnInfo) FieldConfig {
 	return field
 }
 
+// applyEnumTypes gives every enum field a typed string named after the struct and field
+// (e.g. UserStatus) and renders its const block into EnumDecl for the model template
+func applyEnumTypes(config *GenerateConfig) {
+	// Copy fields so the caller's slice is not modified
+	config.Fields = append([]FieldConfig(nil), config.Fields...)
+	for i := range config.Fields {
+		field := &config.Fields[i]
+		if len(field.EnumValues) == 0 {
+			continue
+		}
+		field.FieldType = config.StructName + field.FieldName
+		field.EnumDecl = buildEnumDecl(field.FieldType, field.EnumValues)
+	}
+}
+
+// migrationBaseColumns are created by the migration template itself (common.BaseModel)
+var migrationBaseColumns = map[string]bool{
+	"id":         true,
+	"created_at": true,
+	"updated_at": true,
+	"deleted_at": true,
+}
+
+// modelConfig prepares a copy of config for the model template: base model and primary key
+// columns are dropped (common.BaseModel provides them) and comments are flattened to one line
+func modelConfig(config GenerateConfig) GenerateConfig {
+	fields := make([]FieldConfig, 0, len(config.Fields))
+	for _, field := range config.Fields {
+		if field.IsPrimaryKey || migrationBaseColumns[field.ColumnName] {
+			continue
+		}
+		field.Comment = strings.ReplaceAll(field.Comment, "\n", " ")
+		fields = append(fields, field)
+	}
+	config.Fields = fields
+	config.TableComment = strings.ReplaceAll(config.TableComment, "\n", " ")
+	return config
+}
+
+// migrationConfig prepares a copy of config for the migration template: base model and
+// primary key columns are dropped, SQL types are filled in from the Go type when missing
+// and each column definition is rendered with its comment escaped as a MySQL string.
+// Table and column names must be plain identifiers and SQL types must be on the allowlist,
+// since all of them are written into the SQL verbatim
+func migrationConfig(config GenerateConfig) (GenerateConfig, error) {
+	if !goIdentifierPattern.MatchString(config.TableName) {
+		return config, fmt.Errorf("invalid table name %q", config.TableName)
+	}
+
+	fields := make([]FieldConfig, 0, len(config.Fields))
+	for _, field := range config.Fields {
+		if field.IsPrimaryKey || migrationBaseColumns[field.ColumnName] {
+			continue
+		}
+		if !goIdentifierPattern.MatchString(field.ColumnName) {
+			return config, fmt.Errorf("invalid column name %q", field.ColumnName)
+		}
+		if field.SQLType == "" {
+			field.SQLType = mapGoTypeToSQLType(field.FieldType, len(field.EnumValues) > 0)
+		}
+		if !sqlTypePattern.MatchString(field.SQLType) {
+			return config, fmt.Errorf("unsupported SQL type %q for column %s", field.SQLType, field.ColumnName)
+		}
+
+		field.ColumnDef = fmt.Sprintf("`%s` %s", field.ColumnName, field.SQLType)
+		if !field.Nullable {
+			field.ColumnDef += " NOT NULL"
+		}
+		if field.Comment != "" {
+			field.ColumnDef += " COMMENT " + quoteMySQLString(field.Comment)
+		}
+		field.AddColumnSQL = quoteMySQLString(fmt.Sprintf("ALTER TABLE `%s` ADD COLUMN %s", config.TableName, field.ColumnDef))
+		fields = append(fields, field)
+	}
+	config.Fields = fields
+	config.TableComment = strings.ReplaceAll(config.TableComment, "\n", " ")
+	return config, nil
+}
+
+// unitTestConfig prepares a copy of config for the unit test template: fields are those of the
+// model and each gets a pair of distinct Go literals the generated tests create and update records with
+func unitTestConfig(config GenerateConfig) GenerateConfig {
+	config = modelConfig(config)
+	for i := range config.Fields {
+		field := &config.Fields[i]
+		field.TestValue, field.TestUpdateValue = testValues(config.PackageName, *field)
+	}
+	return config
+}
+
+// testValues returns two distinct Go literals of the field's type, or empty strings
+// when the type is not one the code generator maps columns to
+func testValues(packageName string, field FieldConfig) (string, string) {
+	if len(field.EnumValues) > 0 {
+		first, last := field.EnumValues[0], field.EnumValues[len(field.EnumValues)-1]
+		// Typed enums (see applyEnumTypes) are declared in the model package
+		if field.FieldType != "string" {
+			prefix := packageName + "." + field.FieldType
+			return prefix + enumConstSuffix(first), prefix + enumConstSuffix(last)
+		}
+		return fmt.Sprintf("%q", first), fmt.Sprintf("%q", last)
+	}
+
+	switch field.FieldType {
+	case "string":
+		return fmt.Sprintf("%q", "test "+field.ColumnName), fmt.Sprintf("%q", "updated "+field.ColumnName)
+	case "int", "uint":
+		return "1", "2"
+	case "float64":
+		return "1.5", "2.5"
+	case "bool":
+		return "false", "true"
+	case "time.Time":
+		return "time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)", "time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)"
+	}
+	return "", ""
+}
+
+// quoteMySQLString quotes s as a MySQL string literal, escaping backslashes and single quotes
+func quoteMySQLString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// buildEnumDecl renders a typed string declaration with one constant per enum value
+func buildEnumDecl(typeName string, values []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "type %s string\n\nconst (\n", typeName)
+	for _, value := range values {
+		fmt.Fprintf(&b, "\t%s%s %s = %q\n", typeName, enumConstSuffix(value), typeName, value)
+	}
+	b.WriteString(")\n")
+	return b.String()
+}
+
+// enumConstSuffix converts an enum value into an exported identifier suffix, e.g. "in-progress" → "InProgress"
+func enumConstSuffix(value string) string {
+	normalized := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, strings.ToLower(value))
+
+	suffix := toCamelCase(normalized)
+	if suffix == "" {
+		return "Empty"
+	}
+	return suffix
+}
+
+// isEnumType reports whether the column type is a MySQL ENUM(...)
+func isEnumType(dbType string) bool {
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(dbType)), "enum(")
+}
+
+// parseEnumValues extracts the quoted values from ENUM('a','b',...), handling ” escapes
+func parseEnumValues(dbType string) []string {
+	dbType = strings.TrimSpace(dbType)
+	start := strings.Index(dbType, "(")
+	end := strings.LastIndex(dbType, ")")
+	if start < 0 || end <= start {
+		return nil
+	}
+	body := dbType[start+1 : end]
+
+	var values []string
+	var current strings.Builder
+	inQuote := false
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		switch {
+		case c == '\'' && inQuote && i+1 < len(body) && body[i+1] == '\'':
+			current.WriteByte('\'')
+			i++
+		case c == '\'':
+			if inQuote {
+				values = append(values, current.String())
+				current.Reset()
+			}
+			inQuote = !inQuote
+		case inQuote:
+			current.WriteByte(c)
+		}
+	}
+	return values
+}
+
+// isSystemTable reports whether a table belongs to the framework itself
+func isSystemTable(tableName string) bool {
+	name := strings.ToLower(tableName)
+	return strings.HasPrefix(name, "sys_") ||
+		name == "casbin_rule" ||
+		strings.Contains(name, "migration")
+}
+
+// singularize turns a plural table name into the singular model name (sys_roles -> sys_role)
+func singularize(tableName string) string {
+	switch {
+	case strings.HasSuffix(tableName, "ies"):
+		return strings.TrimSuffix(tableName, "ies") + "y"
+	case strings.HasSuffix(tableName, "sses"), strings.HasSuffix(tableName, "xes"),
+		strings.HasSuffix(tableName, "ches"), strings.HasSuffix(tableName, "shes"):
+		return strings.TrimSuffix(tableName, "es")
+	case strings.HasSuffix(tableName, "s") && !strings.HasSuffix(tableName, "ss"):
+		return strings.TrimSuffix(tableName, "s")
+	default:
+		return tableName
+	}
+}
+
 // Helper functions
 func toCamelCase(s string) string {
 	parts := strings.Split(s, "_")
@@ -341,6 +938,12 @@ func toLabel(s string) string {
 func mapDBTypeToGoType(dbType string) string {
 	dbType = strings.ToLower(dbType)
 
+	// Enum values may contain "int" etc., so check enums first.
+	// The typed name is assigned in applyEnumTypes once the struct name is known
+	if isEnumType(dbType) {
+		return "string"
+	}
+
 	// Check for boolean first (before int check)
 	if strings.Contains(dbType, "bool") || strings.Contains(dbType, "tinyint(1)") {
 		return "bool"
@@ -367,7 +970,44 @@ func mapDBTypeToGoType(dbType string) string {
 	return "string"
 }
 
+// mapGoTypeToSQLType maps a generated Go field type back to a MySQL column type
+func mapGoTypeToSQLType(goType string, isEnum bool) string {
+	if isEnum {
+		return "varchar(50)"
+	}
+
+	switch goType {
+	case "bool":
+		return "tinyint(1)"
+	case "int", "int64":
+		return "bigint"
+	case "uint", "uint64":
+		return "bigint unsigned"
+	case "int32":
+		return "int"
+	case "float32", "float64":
+		return "double"
+	case "time.Time", "*time.Time":
+		return "datetime(3)"
+	default:
+		return "varchar(255)"
+	}
+}
+
 func mapDBTypeToTSType(dbType string) string {
+	// Enum columns become a union of string literals, e.g. "active" | "inactive"
+	if isEnumType(dbType) {
+		values := parseEnumValues(dbType)
+		literals := make([]string, 0, len(values))
+		for _, value := range values {
+			literals = append(literals, fmt.Sprintf("%q", value))
+		}
+		if len(literals) > 0 {
+			return strings.Join(literals, " | ")
+		}
+		return "string"
+	}
+
 	dbType = strings.ToLower(dbType)
 
 	// Check for boolean first (before int check)
@@ -382,6 +1022,10 @@ func mapDBTypeToTSType(dbType string) string {
 }
 
 func mapDBTypeToFormType(dbType string) string {
+	if isEnumType(dbType) {
+		return "select"
+	}
+
 	dbType = strings.ToLower(dbType)
 
 	// Check for boolean first (before int check)
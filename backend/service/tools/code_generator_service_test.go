@@ -0,0 +1,410 @@
+package tools
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k-admin-system/global"
+
+	"go.uber.org/zap"
+)
+
+// generateModel 以仓库根目录为工作目录渲染模型模板（模板路径相对于仓库根目录）
+func generateModel(t *testing.T, config GenerateConfig) string {
+	t.Helper()
+	t.Chdir("../../..")
+
+	config.Options = GenerateOptions{GenerateModel: true}
+	files, err := (&CodeGeneratorService{}).GenerateCode(config)
+	if err != nil {
+		t.Fatalf("GenerateCode() error = %v", err)
+	}
+	content, ok := files["backend/model/demo/order.go"]
+	if !ok {
+		t.Fatalf("GenerateCode() files = %v, want backend/model/demo/order.go", files)
+	}
+	return content
+}
+
+// parseGenerated 解析生成的Go代码，语法错误时测试失败
+func parseGenerated(t *testing.T, content string) *ast.File {
+	t.Helper()
+	file, err := parser.ParseFile(token.NewFileSet(), "order.go", content, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("generated model does not parse: %v\n%s", err, content)
+	}
+	return file
+}
+
+// declaredNames 返回文件中声明的类型名和常量名
+func declaredNames(file *ast.File) map[string]bool {
+	names := map[string]bool{}
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			switch s := spec.(type) {
+			case *ast.TypeSpec:
+				names[s.Name.Name] = true
+			case *ast.ValueSpec:
+				for _, name := range s.Names {
+					names[name.Name] = true
+				}
+			}
+		}
+	}
+	return names
+}
+
+func TestGenerateModel_EmitsEnumDecl(t *testing.T) {
+	content := generateModel(t, GenerateConfig{
+		TableName:   "orders",
+		StructName:  "Order",
+		PackageName: "demo",
+		ModulePath:  "k-admin-system",
+		Fields: []FieldConfig{
+			ConvertColumnToField(CodeGenColumnInfo{Name: "id", Type: "bigint unsigned", Key: "PRI"}),
+			ConvertColumnToField(CodeGenColumnInfo{Name: "status", Type: "enum('pending','in-progress','done')"}),
+			ConvertColumnToField(CodeGenColumnInfo{Name: "paid_at", Type: "datetime", Nullable: true}),
+		},
+	})
+
+	names := declaredNames(parseGenerated(t, content))
+	for _, name := range []string{"Order", "OrderStatus", "OrderStatusPending", "OrderStatusInProgress", "OrderStatusDone"} {
+		if !names[name] {
+			t.Errorf("generated model does not declare %s:\n%s", name, content)
+		}
+	}
+	if !strings.Contains(content, "Status OrderStatus `") {
+		t.Errorf("generated model does not use the enum type for Status:\n%s", content)
+	}
+	if !strings.Contains(content, `"time"`) {
+		t.Errorf("generated model with a datetime field does not import time:\n%s", content)
+	}
+}
+
+func TestGenerateModel_EmitsAssociationDecl(t *testing.T) {
+	content := generateModel(t, GenerateConfig{
+		TableName:   "orders",
+		StructName:  "Order",
+		PackageName: "demo",
+		ModulePath:  "k-admin-system",
+		Fields: []FieldConfig{
+			ConvertColumnToField(CodeGenColumnInfo{Name: "id", Type: "bigint unsigned", Key: "PRI"}),
+			ConvertColumnToField(CodeGenColumnInfo{Name: "customer_id", Type: "bigint unsigned", ReferencedTable: "customers"}),
+		},
+	})
+
+	file := parseGenerated(t, content)
+	var fields map[string]string
+	ast.Inspect(file, func(n ast.Node) bool {
+		spec, ok := n.(*ast.TypeSpec)
+		if !ok || spec.Name.Name != "Order" {
+			return true
+		}
+		fields = map[string]string{}
+		for _, field := range spec.Type.(*ast.StructType).Fields.List {
+			if ident, ok := field.Type.(*ast.Ident); ok && len(field.Names) == 1 {
+				fields[field.Names[0].Name] = ident.Name
+			}
+		}
+		return false
+	})
+
+	if fields["CustomerId"] != "uint" {
+		t.Errorf("generated model CustomerId type = %q, want uint:\n%s", fields["CustomerId"], content)
+	}
+	if fields["Customer"] != "Customer" {
+		t.Errorf("generated model Customer association type = %q, want Customer:\n%s", fields["Customer"], content)
+	}
+	if !strings.Contains(content, "`gorm:\"foreignKey:CustomerId\" json:\"customer,omitempty\"`") {
+		t.Errorf("generated model association tag is missing:\n%s", content)
+	}
+}
+
+// generateMigration 渲染迁移模板，返回生成的文件路径和内容
+func generateMigration(t *testing.T, config GenerateConfig) (string, string) {
+	t.Helper()
+	t.Chdir("../../..")
+
+	config.Options = GenerateOptions{GenerateMigrationSQL: true}
+	files, err := (&CodeGeneratorService{}).GenerateCode(config)
+	if err != nil {
+		t.Fatalf("GenerateCode() error = %v", err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("GenerateCode() files = %v, want one migration", files)
+	}
+	for path, content := range files {
+		return path, content
+	}
+	return "", ""
+}
+
+func TestGenerateMigration_IsRerunnable(t *testing.T) {
+	config := GenerateConfig{
+		TableName:   "orders",
+		StructName:  "Order",
+		PackageName: "demo",
+		Fields: []FieldConfig{
+			{ColumnName: "id", FieldName: "ID", FieldType: "uint", IsPrimaryKey: true},
+			{ColumnName: "title", FieldName: "Title", FieldType: "string", Comment: `it's C:\temp`},
+			{ColumnName: "amount", FieldName: "Amount", FieldType: "float64", SQLType: "decimal(10,2)", Nullable: true},
+		},
+	}
+
+	path, content := generateMigration(t, config)
+	if path != "backend/migrations/create_orders.sql" {
+		t.Errorf("migration path = %q, want a stable name without a timestamp", path)
+	}
+
+	// 列定义直接写入建表语句，注释中的反斜杠和单引号被转义
+	if !strings.Contains(content, "  `title` varchar(255) NOT NULL COMMENT 'it''s C:\\\\temp',\n") {
+		t.Errorf("migration does not define the escaped title column in CREATE TABLE:\n%s", content)
+	}
+
+	// 每个 ALTER TABLE 都只在列不存在时通过预处理语句执行
+	if got := strings.Count(content, "ALTER TABLE"); got != 2 {
+		t.Errorf("migration has %d ALTER TABLE statements, want 2:\n%s", got, content)
+	}
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(line, "ALTER TABLE") {
+			t.Errorf("migration has an unguarded statement %q", line)
+		}
+	}
+	if got := strings.Count(content, "COLUMN_NAME = 'amount') = 0"); got != 1 {
+		t.Errorf("migration does not check for an existing amount column:\n%s", content)
+	}
+	if !strings.Contains(content, "'ALTER TABLE `orders` ADD COLUMN `title` varchar(255) NOT NULL COMMENT ''it''''s C:\\\\\\\\temp'''") {
+		t.Errorf("guarded ALTER TABLE for title is not quoted correctly:\n%s", content)
+	}
+}
+
+func TestGenerateMigration_RejectsUnsafeIdentifiers(t *testing.T) {
+	base := GenerateConfig{TableName: "orders", StructName: "Order", PackageName: "demo"}
+	tests := []struct {
+		name  string
+		field FieldConfig
+		want  string
+	}{
+		{"column name", FieldConfig{ColumnName: "title`; DROP TABLE users; --", FieldName: "Title", FieldType: "string"}, "fields[0].column_name"},
+		{"sql type", FieldConfig{ColumnName: "title", FieldName: "Title", FieldType: "string", SQLType: "text; DROP TABLE users"}, "fields[0].sql_type"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := base
+			config.Fields = []FieldConfig{tt.field}
+
+			errs := (&CodeGeneratorService{}).ValidateConfig(config)
+			if len(errs) != 1 || errs[0].Field != tt.want {
+				t.Errorf("ValidateConfig() = %v, want one error for %s", errs, tt.want)
+			}
+
+			config.Options = GenerateOptions{GenerateMigrationSQL: true}
+			if _, err := (&CodeGeneratorService{}).GenerateCode(config); err == nil {
+				t.Error("GenerateCode() rendered a migration for an unsafe config")
+			}
+		})
+	}
+}
+
+func TestSQLTypePattern(t *testing.T) {
+	for _, sqlType := range []string{"bigint unsigned", "int(11)", "varchar(255)", "decimal(10, 2)", "datetime(3)", "longtext", "enum('a','it''s')", "tinyint(1)"} {
+		if !sqlTypePattern.MatchString(sqlType) {
+			t.Errorf("sqlTypePattern rejects %q", sqlType)
+		}
+	}
+	for _, sqlType := range []string{"", "varchar", "text DEFAULT 'x'", "int) ; DROP TABLE t; --", "enum('a\\')"} {
+		if sqlTypePattern.MatchString(sqlType) {
+			t.Errorf("sqlTypePattern accepts %q", sqlType)
+		}
+	}
+}
+
+func TestListUserTables_HidesSystemTables(t *testing.T) {
+	db := setupTestDB(t,
+		`CREATE TABLE sys_users (id INTEGER PRIMARY KEY)`,
+		`CREATE TABLE sys_roles (id INTEGER PRIMARY KEY)`,
+		`CREATE TABLE casbin_rule (id INTEGER PRIMARY KEY)`,
+		`CREATE TABLE schema_migrations (version INTEGER)`,
+		`CREATE TABLE products (id INTEGER PRIMARY KEY, name VARCHAR(100))`,
+		`CREATE TABLE categories (id INTEGER PRIMARY KEY, name VARCHAR(100))`,
+	)
+
+	tables, err := NewCodeGeneratorService(db).ListUserTables()
+	if err != nil {
+		t.Fatalf("ListUserTables() error = %v", err)
+	}
+	if want := []string{"categories", "products"}; !reflect.DeepEqual(tables, want) {
+		t.Errorf("ListUserTables() = %v, want %v", tables, want)
+	}
+}
+
+func TestFormatGoCode(t *testing.T) {
+	if _, err := exec.LookPath("gofmt"); err != nil {
+		t.Skip("gofmt not found in PATH")
+	}
+	s := &CodeGeneratorService{}
+
+	src := "package demo\n\nimport \"fmt\"\n\nfunc Hello(name string) string {\n        if name == \"\" {\n  return \"hello\"\n   }\n\treturn fmt.Sprintf(\"hello %s\", name)\n}\n"
+	want := "package demo\n\nimport \"fmt\"\n\nfunc Hello(name string) string {\n\tif name == \"\" {\n\t\treturn \"hello\"\n\t}\n\treturn fmt.Sprintf(\"hello %s\", name)\n}\n"
+	got, err := s.FormatGoCode(src)
+	if err != nil {
+		t.Fatalf("FormatGoCode() error = %v", err)
+	}
+	if got != want {
+		t.Errorf("FormatGoCode() =\n%s\nwant\n%s", got, want)
+	}
+
+	if _, err := s.FormatGoCode("package demo\n\nfunc Broken( {\n"); err == nil {
+		t.Error("FormatGoCode() accepted invalid Go source")
+	}
+}
+
+func TestFormatGoCode_WithoutGofmtReturnsSource(t *testing.T) {
+	prevLogger := global.Logger
+	global.Logger = zap.NewNop()
+	t.Cleanup(func() { global.Logger = prevLogger })
+	t.Setenv("PATH", t.TempDir())
+
+	src := "package demo\nfunc  Hello() {}\n"
+	got, err := (&CodeGeneratorService{}).FormatGoCode(src)
+	if err != nil || got != src {
+		t.Errorf("FormatGoCode() without gofmt = %q, %v; want the source unchanged", got, err)
+	}
+}
+
+// generatedServiceStub 生成的单元测试所依赖的服务实现（仓库中没有服务模板，测试中以此代替）
+const generatedServiceStub = `package demo
+
+import (
+	"gentest/global"
+	"gentest/model/demo"
+)
+
+type ProductService struct{}
+
+func (s *ProductService) CreateProduct(product *demo.Product) error {
+	return global.DB.Create(product).Error
+}
+
+func (s *ProductService) GetProductByID(id uint) (*demo.Product, error) {
+	var product demo.Product
+	if err := global.DB.First(&product, id).Error; err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+func (s *ProductService) GetProductList(page, pageSize int) ([]demo.Product, int64, error) {
+	var list []demo.Product
+	var total int64
+	if err := global.DB.Model(&demo.Product{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := global.DB.Offset((page - 1) * pageSize).Limit(pageSize).Find(&list).Error
+	return list, total, err
+}
+
+func (s *ProductService) UpdateProduct(product *demo.Product) error {
+	return global.DB.Save(product).Error
+}
+
+func (s *ProductService) DeleteProduct(id uint) error {
+	return global.DB.Delete(&demo.Product{}, id).Error
+}
+`
+
+// TestGenerateUnitTestFile_BuildsAndPasses 在临时模块中编译并运行生成的服务单元测试
+func TestGenerateUnitTestFile_BuildsAndPasses(t *testing.T) {
+	if testing.Short() {
+		t.Skip("compiles a temporary module")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go toolchain not in PATH")
+	}
+
+	backendDir, err := filepath.Abs("../..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir("../../..")
+
+	columns := []CodeGenColumnInfo{
+		{Name: "id", Type: "bigint unsigned", Key: "PRI"},
+		{Name: "name", Type: "varchar(100)"},
+		{Name: "price", Type: "decimal(10,2)"},
+		{Name: "stock", Type: "int"},
+		{Name: "on_sale", Type: "tinyint(1)"},
+		{Name: "released_at", Type: "datetime", Nullable: true},
+		{Name: "status", Type: "enum('draft','published')"},
+	}
+	config := GenerateConfig{TableName: "products", StructName: "Product", PackageName: "demo", ModulePath: "gentest"}
+	for _, column := range columns {
+		config.Fields = append(config.Fields, ConvertColumnToField(column))
+	}
+	applyEnumTypes(&config)
+
+	service := &CodeGeneratorService{}
+	model, err := service.generateFromTemplate("backend/model.tpl", modelConfig(config))
+	if err != nil {
+		t.Fatalf("failed to generate model: %v", err)
+	}
+	testFile, err := service.GenerateUnitTestFile(config)
+	if err != nil {
+		t.Fatalf("GenerateUnitTestFile() error = %v", err)
+	}
+	if strings.Contains(testFile, "TODO") {
+		t.Errorf("generated test still contains TODO placeholders:\n%s", testFile)
+	}
+
+	goMod, err := os.ReadFile(filepath.Join(backendDir, "go.mod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	goSum, err := os.ReadFile(filepath.Join(backendDir, "go.sum"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	baseModel, err := os.ReadFile(filepath.Join(backendDir, "model/common/base.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	moduleDir := t.TempDir()
+	files := map[string]string{
+		"go.mod":                               strings.Replace(string(goMod), "module k-admin-system", "module gentest", 1),
+		"go.sum":                               string(goSum),
+		"global/global.go":                     "package global\n\nimport \"gorm.io/gorm\"\n\nvar DB *gorm.DB\n",
+		"model/common/base.go":                 string(baseModel),
+		"model/demo/product.go":                model,
+		"service/demo/product_service.go":      generatedServiceStub,
+		"service/demo/product_service_test.go": testFile,
+	}
+	for name, content := range files {
+		path := filepath.Join(moduleDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cmd := exec.Command(goBin, "test", "./service/demo/")
+	cmd.Dir = moduleDir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("generated tests failed: %v\n%s\n%s", err, output, testFile)
+	}
+}
@@ -1,12 +1,21 @@
 package tools
 
 import (
+	"bufio"
+	"encoding/hex"
 	"errors"
 	"fmt"
-	"regexp"
+	"io"
+	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"k-admin-system/global"
+	"k-admin-system/model/system"
+	"k-admin-system/utils"
+
+	"gorm.io/gorm"
 )
 
 // DBInspectorService 数据库检查器服务
@@ -23,8 +32,30 @@ type ColumnInfo struct {
 	Comment  string `json:"comment"`
 }
 
-// GetTables 获取所有表名
+// ForeignKeyInfo 外键信息
+type ForeignKeyInfo struct {
+	ColumnName       string `json:"columnName"`
+	ReferencedTable  string `json:"referencedTable"`
+	ReferencedColumn string `json:"referencedColumn"`
+}
+
+// IndexInfo 索引信息
+type IndexInfo struct {
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"`
+	Unique  bool     `json:"unique"`
+	Type    string   `json:"type"`
+}
+
+// systemTablePrefix 系统表前缀，系统表不通过数据库检查器暴露
+const systemTablePrefix = "sys_"
+
+// GetTables 获取所有表名（不含系统表）
 func (s *DBInspectorService) GetTables() ([]string, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return nil, err
+	}
+
 	var tables []string
 
 	// 检测数据库类型
@@ -52,14 +83,26 @@ func (s *DBInspectorService) GetTables() ([]string, error) {
 		}
 	}
 
-	return tables, nil
+	// 隐藏系统表：其中保存会话令牌哈希、两步验证密钥、API密钥等敏感数据，只能通过对应的管理接口访问
+	visible := make([]string, 0, len(tables))
+	for _, table := range tables {
+		if !strings.HasPrefix(table, systemTablePrefix) {
+			visible = append(visible, table)
+		}
+	}
+
+	return visible, nil
 }
 
 // GetTableSchema 获取表结构
 func (s *DBInspectorService) GetTableSchema(tableName string) ([]CodeGenColumnInfo, error) {
-	// 验证表名（防止SQL注入）
-	if !isValidTableName(tableName) {
-		return nil, errors.New("invalid table name")
+	if err := utils.DBMustInit(); err != nil {
+		return nil, err
+	}
+
+	// 验证表名（白名单校验，防止SQL注入）
+	if err := s.validateTable(tableName); err != nil {
+		return nil, err
 	}
 
 	var columns []CodeGenColumnInfo
@@ -135,34 +178,592 @@ func (s *DBInspectorService) GetTableSchema(tableName string) ([]CodeGenColumnIn
 	return columns, nil
 }
 
+// GetForeignKeys 获取表的外键约束
+func (s *DBInspectorService) GetForeignKeys(tableName string) ([]ForeignKeyInfo, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return nil, err
+	}
+
+	// 验证表名（白名单校验，防止SQL注入）
+	if err := s.validateTable(tableName); err != nil {
+		return nil, err
+	}
+
+	foreignKeys := []ForeignKeyInfo{}
+
+	// 检测数据库类型
+	dbType := global.DB.Dialector.Name()
+
+	if dbType == "sqlite" {
+		// SQLite: 使用 PRAGMA foreign_key_list
+		type sqliteForeignKey struct {
+			Table string `gorm:"column:table"`
+			From  string `gorm:"column:from"`
+			To    string `gorm:"column:to"`
+		}
+
+		var sqliteForeignKeys []sqliteForeignKey
+		query := fmt.Sprintf("PRAGMA foreign_key_list(%s)", tableName)
+		if err := global.DB.Raw(query).Scan(&sqliteForeignKeys).Error; err != nil {
+			return nil, fmt.Errorf("failed to get foreign keys: %w", err)
+		}
+
+		for _, fk := range sqliteForeignKeys {
+			foreignKeys = append(foreignKeys, ForeignKeyInfo{
+				ColumnName:       fk.From,
+				ReferencedTable:  fk.Table,
+				ReferencedColumn: fk.To,
+			})
+		}
+	} else {
+		// MySQL: 使用 information_schema
+		var dbName string
+		if err := global.DB.Raw("SELECT DATABASE()").Scan(&dbName).Error; err != nil {
+			return nil, fmt.Errorf("failed to get database name: %w", err)
+		}
+
+		query := `SELECT 
+		            column_name AS column_name,
+		            referenced_table_name AS referenced_table,
+		            referenced_column_name AS referenced_column
+		          FROM information_schema.key_column_usage
+		          WHERE table_schema = ? AND table_name = ? AND referenced_table_name IS NOT NULL
+		          ORDER BY ordinal_position`
+
+		if err := global.DB.Raw(query, dbName, tableName).Scan(&foreignKeys).Error; err != nil {
+			return nil, fmt.Errorf("failed to get foreign keys: %w", err)
+		}
+	}
+
+	return foreignKeys, nil
+}
+
+// GetTableIndexes 获取表的索引，列按索引中的顺序排列
+func (s *DBInspectorService) GetTableIndexes(tableName string) ([]IndexInfo, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return nil, err
+	}
+
+	// 验证表名（白名单校验，防止SQL注入）
+	if err := s.validateTable(tableName); err != nil {
+		return nil, err
+	}
+
+	indexes := []IndexInfo{}
+
+	// 检测数据库类型
+	dbType := global.DB.Dialector.Name()
+
+	if dbType == "sqlite" {
+		// SQLite: 使用 PRAGMA index_list + PRAGMA index_info
+		type sqliteIndex struct {
+			Name   string `gorm:"column:name"`
+			Unique bool   `gorm:"column:unique"`
+		}
+		type sqliteIndexColumn struct {
+			Name string `gorm:"column:name"`
+		}
+
+		var sqliteIndexes []sqliteIndex
+		query := fmt.Sprintf("PRAGMA index_list(%s)", tableName)
+		if err := global.DB.Raw(query).Scan(&sqliteIndexes).Error; err != nil {
+			return nil, fmt.Errorf("failed to get indexes: %w", err)
+		}
+
+		for _, idx := range sqliteIndexes {
+			var indexColumns []sqliteIndexColumn
+			query := fmt.Sprintf("PRAGMA index_info('%s')", strings.ReplaceAll(idx.Name, "'", "''"))
+			if err := global.DB.Raw(query).Scan(&indexColumns).Error; err != nil {
+				return nil, fmt.Errorf("failed to get columns of index %s: %w", idx.Name, err)
+			}
+
+			columns := make([]string, 0, len(indexColumns))
+			for _, col := range indexColumns {
+				columns = append(columns, col.Name)
+			}
+			indexes = append(indexes, IndexInfo{
+				Name:    idx.Name,
+				Columns: columns,
+				Unique:  idx.Unique,
+				Type:    "BTREE",
+			})
+		}
+	} else {
+		// MySQL: 使用 information_schema.statistics，每行对应索引中的一列
+		type mysqlIndexColumn struct {
+			IndexName  string `gorm:"column:index_name"`
+			ColumnName string `gorm:"column:column_name"`
+			NonUnique  bool   `gorm:"column:non_unique"`
+			IndexType  string `gorm:"column:index_type"`
+		}
+
+		var dbName string
+		if err := global.DB.Raw("SELECT DATABASE()").Scan(&dbName).Error; err != nil {
+			return nil, fmt.Errorf("failed to get database name: %w", err)
+		}
+
+		query := `SELECT 
+		            index_name AS index_name,
+		            column_name AS column_name,
+		            non_unique AS non_unique,
+		            index_type AS index_type
+		          FROM information_schema.statistics
+		          WHERE table_schema = ? AND table_name = ?
+		          ORDER BY index_name, seq_in_index`
+
+		var rows []mysqlIndexColumn
+		if err := global.DB.Raw(query, dbName, tableName).Scan(&rows).Error; err != nil {
+			return nil, fmt.Errorf("failed to get indexes: %w", err)
+		}
+
+		// 按索引名聚合列
+		positions := make(map[string]int)
+		for _, row := range rows {
+			pos, ok := positions[row.IndexName]
+			if !ok {
+				pos = len(indexes)
+				positions[row.IndexName] = pos
+				indexes = append(indexes, IndexInfo{
+					Name:    row.IndexName,
+					Columns: []string{},
+					Unique:  !row.NonUnique,
+					Type:    row.IndexType,
+				})
+			}
+			indexes[pos].Columns = append(indexes[pos].Columns, row.ColumnName)
+		}
+	}
+
+	return indexes, nil
+}
+
+// GenerateERDiagram 生成指定表的 Mermaid erDiagram 语法
+// 实体包含每一列（类型、PK/FK/UK 标记），关系由外键生成：被引用表 ||--o{ 引用表
+func (s *DBInspectorService) GenerateERDiagram(tables []string) (string, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return "", err
+	}
+
+	if len(tables) == 0 {
+		return "", errors.New("at least one table is required")
+	}
+
+	var entities, relationships strings.Builder
+	for _, table := range tables {
+		columns, err := s.GetTableSchema(table)
+		if err != nil {
+			return "", fmt.Errorf("failed to get schema of %s: %w", table, err)
+		}
+		foreignKeys, err := s.GetForeignKeys(table)
+		if err != nil {
+			return "", fmt.Errorf("failed to get foreign keys of %s: %w", table, err)
+		}
+
+		fkColumns := make(map[string]bool, len(foreignKeys))
+		for _, fk := range foreignKeys {
+			fkColumns[fk.ColumnName] = true
+			fmt.Fprintf(&relationships, "    %s ||--o{ %s : \"%s\"\n", fk.ReferencedTable, table, fk.ColumnName)
+		}
+
+		fmt.Fprintf(&entities, "    %s {\n", table)
+		for _, col := range columns {
+			var keys []string
+			switch col.Key {
+			case "PRI":
+				keys = append(keys, "PK")
+			case "UNI":
+				keys = append(keys, "UK")
+			}
+			if fkColumns[col.Name] {
+				keys = append(keys, "FK")
+			}
+
+			fmt.Fprintf(&entities, "        %s %s", mermaidType(col.Type), col.Name)
+			if len(keys) > 0 {
+				fmt.Fprintf(&entities, " %s", strings.Join(keys, ","))
+			}
+			entities.WriteString("\n")
+		}
+		entities.WriteString("    }\n")
+	}
+
+	return "erDiagram\n" + entities.String() + relationships.String(), nil
+}
+
+// mermaidType 将数据库列类型转换为 Mermaid 可接受的类型名（去掉长度和修饰符，如 varchar(50) -> varchar）
+func mermaidType(dbType string) string {
+	typeName := strings.ToLower(strings.TrimSpace(dbType))
+	if i := strings.IndexAny(typeName, "( "); i >= 0 {
+		typeName = typeName[:i]
+	}
+	if typeName == "" {
+		return "unknown"
+	}
+	return typeName
+}
+
 // GetTableData 获取表数据（支持分页）
-func (s *DBInspectorService) GetTableData(tableName string, page, pageSize int) ([]map[string]interface{}, int64, error) {
-	// 验证表名
-	if !isValidTableName(tableName) {
-		return nil, 0, errors.New("invalid table name")
+// roleID 为请求用户的角色ID，角色配置了该表的行级过滤（SysRole.DBInspectorFilter）时只返回满足条件的行；
+// 传 0 表示不过滤（如内部备份）
+func (s *DBInspectorService) GetTableData(tableName string, page, pageSize int, roleID uint) ([]map[string]interface{}, int64, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return nil, 0, err
+	}
+
+	query, err := s.filteredTable(tableName, roleID)
+	if err != nil {
+		return nil, 0, err
 	}
 
 	var total int64
 	var data []map[string]interface{}
 
 	// 获取总数
-	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM `%s`", tableName)
-	if err := global.DB.Raw(countQuery).Scan(&total).Error; err != nil {
+	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, fmt.Errorf("failed to count records: %w", err)
 	}
 
 	// 分页查询
 	offset := (page - 1) * pageSize
-	dataQuery := fmt.Sprintf("SELECT * FROM `%s` LIMIT ? OFFSET ?", tableName)
-	if err := global.DB.Raw(dataQuery, pageSize, offset).Scan(&data).Error; err != nil {
+	if err := query.Limit(pageSize).Offset(offset).Find(&data).Error; err != nil {
 		return nil, 0, fmt.Errorf("failed to query table data: %w", err)
 	}
 
 	return data, total, nil
 }
 
+// filteredTable 返回指定表的查询，并应用角色对该表配置的行级过滤条件
+// 表名必须通过 validateTable 校验；条件中的列名必须存在于表结构中，值以参数绑定，不拼接进SQL。
+// roleID 为 0 时不过滤。返回的查询可重复使用（如先 Count 再 Find）
+func (s *DBInspectorService) filteredTable(tableName string, roleID uint) (*gorm.DB, error) {
+	scope, err := s.rowFilterScope(tableName, roleID)
+	if err != nil {
+		return nil, err
+	}
+	return global.DB.Table(tableName).Scopes(scope).Session(&gorm.Session{}), nil
+}
+
+// rowFilterScope 校验表名并返回应用角色行级过滤条件的查询作用域
+// 过滤条件在返回前查询和校验完毕，作用域本身不访问数据库，可用于事务内的查询
+func (s *DBInspectorService) rowFilterScope(tableName string, roleID uint) (func(*gorm.DB) *gorm.DB, error) {
+	// 验证表名（白名单校验，防止SQL注入）
+	if err := s.validateTable(tableName); err != nil {
+		return nil, err
+	}
+
+	conditions, err := roleRowFilter(roleID, tableName)
+	if err != nil {
+		return nil, err
+	}
+	clauses := make([]string, 0, len(conditions))
+	for _, cond := range conditions {
+		column, err := s.resolveColumn(tableName, cond.Column)
+		if err != nil {
+			return nil, fmt.Errorf("invalid row filter on column %q: %w", cond.Column, err)
+		}
+		clauses = append(clauses, fmt.Sprintf("`%s` %s", column, cond.Operator))
+	}
+
+	return func(db *gorm.DB) *gorm.DB {
+		for i, cond := range conditions {
+			if cond.Value == nil {
+				db = db.Where(clauses[i])
+				continue
+			}
+			db = db.Where(clauses[i]+" ?", cond.Value)
+		}
+		return db
+	}, nil
+}
+
+// roleRowFilter 返回角色对指定表配置的行级过滤条件，roleID 为 0 或未配置时返回空
+func roleRowFilter(roleID uint, tableName string) ([]system.RowCondition, error) {
+	if roleID == 0 {
+		return nil, nil
+	}
+	filters, err := roleRowFilters(roleID)
+	if err != nil {
+		return nil, err
+	}
+	return filters[tableName], nil
+}
+
+// roleRowFilters 返回角色配置的全部行级过滤条件
+func roleRowFilters(roleID uint) (map[string][]system.RowCondition, error) {
+	var role system.SysRole
+	if err := global.DB.Select("id", "db_inspector_filter").First(&role, roleID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("role not found")
+		}
+		return nil, fmt.Errorf("failed to query role: %w", err)
+	}
+	return role.DBInspectorFilters()
+}
+
+// ensureUnfilteredRole 拒绝配置了行级过滤的角色执行任意SQL，否则过滤条件可以被直接绕过
+func ensureUnfilteredRole(roleID uint) error {
+	if roleID == 0 {
+		return nil
+	}
+	filters, err := roleRowFilters(roleID)
+	if err != nil {
+		return err
+	}
+	if len(filters) > 0 {
+		return errors.New("SQL execution is not available to roles with DB inspector row filters")
+	}
+	return nil
+}
+
+// maxSampleRows 随机抽样返回的最大行数
+const maxSampleRows = 100
+
+// GetSampleRows 随机抽取表中最多 n 行数据，用于了解陌生表的数据形态
+// 表名必须存在于 GetTables 返回的列表中，n 超过 maxSampleRows 时按上限处理；只抽取角色行级过滤允许的行
+func (s *DBInspectorService) GetSampleRows(tableName string, n int, roleID uint) ([]map[string]interface{}, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return nil, err
+	}
+
+	if n < 1 {
+		return nil, errors.New("sample size must be at least 1")
+	}
+	if n > maxSampleRows {
+		n = maxSampleRows
+	}
+
+	// 只允许查询已存在的表，并应用角色的行级过滤
+	query, err := s.filteredTable(tableName, roleID)
+	if err != nil {
+		return nil, err
+	}
+
+	randomFunc := "RAND()"
+	if global.DB.Dialector.Name() == "sqlite" {
+		randomFunc = "RANDOM()"
+	}
+
+	var rows []map[string]interface{}
+	if err := query.Order(randomFunc).Limit(n).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to query sample rows: %w", err)
+	}
+
+	return rows, nil
+}
+
+// CountNullValues 统计表中指定列为NULL的行数，用于数据质量检查
+// 列名必须存在于 GetTableSchema 返回的列列表中，查询使用表结构中的列名而非用户输入，防止SQL注入；
+// 只统计角色行级过滤允许的行
+func (s *DBInspectorService) CountNullValues(tableName, columnName string, roleID uint) (int64, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return 0, err
+	}
+
+	query, err := s.filteredTable(tableName, roleID)
+	if err != nil {
+		return 0, err
+	}
+	column, err := s.resolveColumn(tableName, columnName)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	if err := query.Where(fmt.Sprintf("`%s` IS NULL", column)).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count null values: %w", err)
+	}
+
+	return count, nil
+}
+
+// maxDistinctValues 单列去重值查询返回的最大数量
+const maxDistinctValues = 200
+
+// GetDistinctValues 查询列的去重值（按值排序），用于筛选条件的自动补全
+// 列名必须存在于 GetTableSchema 返回的列列表中，limit 超过 maxDistinctValues 时按上限处理；只统计角色行级过滤允许的行
+func (s *DBInspectorService) GetDistinctValues(tableName, columnName string, limit int, roleID uint) ([]interface{}, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return nil, err
+	}
+
+	if limit < 1 {
+		return nil, errors.New("limit must be at least 1")
+	}
+	if limit > maxDistinctValues {
+		limit = maxDistinctValues
+	}
+
+	query, err := s.filteredTable(tableName, roleID)
+	if err != nil {
+		return nil, err
+	}
+	column, err := s.resolveColumn(tableName, columnName)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []map[string]interface{}
+	if err := query.Select(fmt.Sprintf("DISTINCT `%s`", column)).Order(fmt.Sprintf("`%s`", column)).
+		Limit(limit).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to query distinct values: %w", err)
+	}
+
+	values := make([]interface{}, 0, len(rows))
+	for _, row := range rows {
+		values = append(values, row[column])
+	}
+
+	return values, nil
+}
+
+// backupBatchSize 备份表数据时每输出多少行刷新一次缓冲
+const backupBatchSize = 500
+
+// BackupTableToSQL 将单表导出为SQL脚本（类似 mysqldump）
+// 先输出由表结构生成的 CREATE TABLE 语句（仅包含列类型、非空和主键约束），再按主键顺序流式读取数据并逐行输出 INSERT 语句；
+// roleID 对应的角色配置了行级过滤时只导出满足条件的行
+func (s *DBInspectorService) BackupTableToSQL(tableName string, w io.Writer, roleID uint) error {
+	if err := utils.DBMustInit(); err != nil {
+		return err
+	}
+
+	columns, err := s.GetTableSchema(tableName)
+	if err != nil {
+		return err
+	}
+
+	dbType := global.DB.Dialector.Name()
+	bw := bufio.NewWriter(w)
+
+	// 表结构
+	fmt.Fprintf(bw, "-- Table structure for `%s`\n", tableName)
+	fmt.Fprintf(bw, "CREATE TABLE IF NOT EXISTS `%s` (\n", tableName)
+	var primaryKeys []string
+	for i, col := range columns {
+		fmt.Fprintf(bw, "  `%s` %s", col.Name, col.Type)
+		if !col.Nullable {
+			bw.WriteString(" NOT NULL")
+		}
+		if strings.Contains(strings.ToLower(col.Extra), "auto_increment") {
+			bw.WriteString(" AUTO_INCREMENT")
+		}
+		if col.Key == "PRI" {
+			primaryKeys = append(primaryKeys, "`"+col.Name+"`")
+		}
+		if i < len(columns)-1 || len(primaryKeys) > 0 {
+			bw.WriteString(",")
+		}
+		bw.WriteString("\n")
+	}
+	if len(primaryKeys) > 0 {
+		fmt.Fprintf(bw, "  PRIMARY KEY (%s)\n", strings.Join(primaryKeys, ", "))
+	}
+	bw.WriteString(");\n\n")
+
+	// 表数据
+	columnNames := make([]string, len(columns))
+	for i, col := range columns {
+		columnNames[i] = "`" + col.Name + "`"
+	}
+	insertPrefix := fmt.Sprintf("INSERT INTO `%s` (%s) VALUES (", tableName, strings.Join(columnNames, ", "))
+
+	// 按主键排序后以游标方式逐行读取，避免 LIMIT/OFFSET 分页在无序结果上重复或遗漏行；
+	// 没有主键的表按全部列排序，保证输出顺序稳定
+	orderColumns := primaryKeys
+	if len(orderColumns) == 0 {
+		orderColumns = columnNames
+	}
+	query, err := s.filteredTable(tableName, roleID)
+	if err != nil {
+		return err
+	}
+	rows, err := query.Order(strings.Join(orderColumns, ", ")).Rows()
+	if err != nil {
+		return fmt.Errorf("failed to query table data: %w", err)
+	}
+	defer rows.Close()
+
+	fmt.Fprintf(bw, "-- Data for `%s`\n", tableName)
+	for written := 1; rows.Next(); written++ {
+		row := make(map[string]interface{}, len(columns))
+		if err := global.DB.ScanRows(rows, &row); err != nil {
+			return fmt.Errorf("failed to read table data: %w", err)
+		}
+
+		values := make([]string, len(columns))
+		for i, col := range columns {
+			values[i] = quoteSQLValue(row[col.Name], dbType)
+		}
+		bw.WriteString(insertPrefix)
+		bw.WriteString(strings.Join(values, ", "))
+		bw.WriteString(");\n")
+
+		if written%backupBatchSize == 0 {
+			if err := bw.Flush(); err != nil {
+				return fmt.Errorf("failed to write backup: %w", err)
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read table data: %w", err)
+	}
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write backup: %w", err)
+	}
+	return nil
+}
+
+// quoteSQLValue 将查询结果中的值转换为SQL字面量
+// 字符串中的单引号成对转义，MySQL 还需转义反斜杠；非UTF-8的二进制数据输出为十六进制字面量
+func quoteSQLValue(value interface{}, dbType string) string {
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case bool:
+		if v {
+			return "1"
+		}
+		return "0"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprintf("%d", v)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case time.Time:
+		return quoteSQLString(v.Format("2006-01-02 15:04:05.999999"), dbType)
+	case []byte:
+		if !utf8.Valid(v) {
+			return "X'" + hex.EncodeToString(v) + "'"
+		}
+		return quoteSQLString(string(v), dbType)
+	case string:
+		return quoteSQLString(v, dbType)
+	default:
+		return quoteSQLString(fmt.Sprint(v), dbType)
+	}
+}
+
+// quoteSQLString 对字符串加引号并转义
+func quoteSQLString(s, dbType string) string {
+	if dbType != "sqlite" {
+		s = strings.ReplaceAll(s, `\`, `\\`)
+	}
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
 // ExecuteSQL 执行SQL语句
-func (s *DBInspectorService) ExecuteSQL(sql string, readOnly bool) (interface{}, error) {
+// roleID 为请求用户的角色ID，配置了行级过滤的角色不允许执行任意SQL
+func (s *DBInspectorService) ExecuteSQL(sql string, readOnly bool, roleID uint) (interface{}, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return nil, err
+	}
+
+	if err := ensureUnfilteredRole(roleID); err != nil {
+		return nil, err
+	}
+
 	// 验证SQL
 	if err := s.ValidateSQL(sql, readOnly); err != nil {
 		return nil, err
@@ -192,17 +793,67 @@ func (s *DBInspectorService) ExecuteSQL(sql string, readOnly bool) (interface{},
 	}
 }
 
+// RunExplain 返回SQL语句的执行计划
+// verbose 为 true 时使用 EXPLAIN ANALYZE（仅MySQL支持），该模式会真正执行语句，因此只允许只读语句
+// 配置了行级过滤的角色不允许使用（EXPLAIN ANALYZE 会真正执行语句）
+func (s *DBInspectorService) RunExplain(sql string, verbose bool, roleID uint) ([]map[string]interface{}, error) {
+	if err := utils.DBMustInit(); err != nil {
+		return nil, err
+	}
+
+	if err := ensureUnfilteredRole(roleID); err != nil {
+		return nil, err
+	}
+
+	if err := s.ValidateSQL(sql, verbose); err != nil {
+		return nil, err
+	}
+
+	prefix := "EXPLAIN "
+	if verbose {
+		if global.DB.Dialector.Name() != "mysql" {
+			return nil, errors.New("EXPLAIN ANALYZE is only supported on MySQL")
+		}
+		prefix = "EXPLAIN ANALYZE "
+	}
+
+	var rows []map[string]interface{}
+	if err := global.DB.Raw(prefix + strings.TrimSpace(sql)).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to explain query: %w", err)
+	}
+
+	return rows, nil
+}
+
 // CreateRecord 创建记录
-func (s *DBInspectorService) CreateRecord(tableName string, data map[string]interface{}) error {
-	// 验证表名
-	if !isValidTableName(tableName) {
-		return errors.New("invalid table name")
+// 对该表配置了行级过滤的角色不能新增记录，否则可以写入过滤条件之外的行
+func (s *DBInspectorService) CreateRecord(tableName string, data map[string]interface{}, roleID uint) error {
+	if err := utils.DBMustInit(); err != nil {
+		return err
+	}
+
+	// 验证表名（白名单校验，防止SQL注入）
+	if err := s.validateTable(tableName); err != nil {
+		return err
+	}
+
+	conditions, err := roleRowFilter(roleID, tableName)
+	if err != nil {
+		return err
+	}
+	if len(conditions) > 0 {
+		return errors.New("creating records is not available to roles with a row filter on this table")
 	}
 
 	if len(data) == 0 {
 		return errors.New("no data provided")
 	}
 
+	// 验证列名（必须存在于表结构中）
+	if err := s.validateColumns(tableName, data); err != nil {
+		return err
+	}
+
 	// 构建INSERT语句
 	var columns []string
 	var placeholders []string
@@ -227,52 +878,67 @@ func (s *DBInspectorService) CreateRecord(tableName string, data map[string]inte
 }
 
 // UpdateRecord 更新记录
-func (s *DBInspectorService) UpdateRecord(tableName string, id interface{}, data map[string]interface{}) error {
-	// 验证表名
-	if !isValidTableName(tableName) {
-		return errors.New("invalid table name")
+// 只能更新角色行级过滤允许的行，更新后的记录仍须满足过滤条件，否则回滚
+func (s *DBInspectorService) UpdateRecord(tableName string, id interface{}, data map[string]interface{}, roleID uint) error {
+	if err := utils.DBMustInit(); err != nil {
+		return err
+	}
+
+	// 验证表名（白名单校验，防止SQL注入）
+	if err := s.validateTable(tableName); err != nil {
+		return err
 	}
 
 	if len(data) == 0 {
 		return errors.New("no data provided")
 	}
 
-	// 构建UPDATE语句
-	var setClauses []string
-	var values []interface{}
-
-	for col, val := range data {
-		setClauses = append(setClauses, fmt.Sprintf("`%s` = ?", col))
-		values = append(values, val)
+	// 验证列名（必须存在于表结构中）
+	if err := s.validateColumns(tableName, data); err != nil {
+		return err
 	}
-	values = append(values, id)
 
-	query := fmt.Sprintf("UPDATE `%s` SET %s WHERE id = ?",
-		tableName,
-		strings.Join(setClauses, ", "))
-
-	result := global.DB.Exec(query, values...)
-	if result.Error != nil {
-		return fmt.Errorf("failed to update record: %w", result.Error)
+	scope, err := s.rowFilterScope(tableName, roleID)
+	if err != nil {
+		return err
 	}
 
-	if result.RowsAffected == 0 {
-		return errors.New("record not found")
-	}
+	return global.DB.Transaction(func(tx *gorm.DB) error {
+		query := tx.Table(tableName).Scopes(scope).Session(&gorm.Session{})
 
-	return nil
+		result := query.Where("id = ?", id).Updates(data)
+		if result.Error != nil {
+			return fmt.Errorf("failed to update record: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("record not found")
+		}
+
+		// 更新后的记录必须仍在过滤范围内
+		var count int64
+		if err := query.Where("id = ?", id).Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to verify updated record: %w", err)
+		}
+		if count == 0 {
+			return errors.New("update would move the record outside the role's row filter")
+		}
+		return nil
+	})
 }
 
 // DeleteRecord 删除记录
-func (s *DBInspectorService) DeleteRecord(tableName string, id interface{}) error {
-	// 验证表名
-	if !isValidTableName(tableName) {
-		return errors.New("invalid table name")
+// 只能删除角色行级过滤允许的行
+func (s *DBInspectorService) DeleteRecord(tableName string, id interface{}, roleID uint) error {
+	if err := utils.DBMustInit(); err != nil {
+		return err
 	}
 
-	query := fmt.Sprintf("DELETE FROM `%s` WHERE id = ?", tableName)
+	query, err := s.filteredTable(tableName, roleID)
+	if err != nil {
+		return err
+	}
 
-	result := global.DB.Exec(query, id)
+	result := query.Where("id = ?", id).Delete(map[string]interface{}{})
 	if result.Error != nil {
 		return fmt.Errorf("failed to delete record: %w", result.Error)
 	}
@@ -321,9 +987,61 @@ func (s *DBInspectorService) ValidateSQL(sql string, readOnly bool) error {
 	return nil
 }
 
-// isValidTableName 验证表名是否合法
-func isValidTableName(tableName string) bool {
-	// 只允许字母、数字、下划线
-	matched, _ := regexp.MatchString(`^[a-zA-Z0-9_]+$`, tableName)
-	return matched
+// validateTable 校验表名：必须是合法标识符，且存在于 GetTables 返回的列表中
+func (s *DBInspectorService) validateTable(tableName string) error {
+	if err := utils.SanitizeSQL(tableName); err != nil {
+		return fmt.Errorf("invalid table name: %w", err)
+	}
+
+	tables, err := s.GetTables()
+	if err != nil {
+		return err
+	}
+	for _, table := range tables {
+		if table == tableName {
+			return nil
+		}
+	}
+	return errors.New("table not found")
+}
+
+// validateColumns 校验记录数据中的所有列名：必须是合法标识符，且存在于表结构中
+func (s *DBInspectorService) validateColumns(tableName string, data map[string]interface{}) error {
+	columns, err := s.GetTableSchema(tableName)
+	if err != nil {
+		return err
+	}
+	known := make(map[string]bool, len(columns))
+	for _, col := range columns {
+		known[col.Name] = true
+	}
+
+	for col := range data {
+		if err := utils.SanitizeSQL(col); err != nil {
+			return fmt.Errorf("invalid column name %q: %w", col, err)
+		}
+		if !known[col] {
+			return fmt.Errorf("column %q not found", col)
+		}
+	}
+	return nil
+}
+
+// resolveColumn 校验列名：必须是合法标识符，且存在于 GetTableSchema 返回的列列表中
+// 返回表结构中的列名，调用方拼接SQL时使用该返回值而非用户输入
+func (s *DBInspectorService) resolveColumn(tableName, columnName string) (string, error) {
+	if err := utils.SanitizeSQL(columnName); err != nil {
+		return "", fmt.Errorf("invalid column name: %w", err)
+	}
+
+	columns, err := s.GetTableSchema(tableName)
+	if err != nil {
+		return "", err
+	}
+	for _, col := range columns {
+		if col.Name == columnName {
+			return col.Name, nil
+		}
+	}
+	return "", errors.New("column not found")
 }
@@ -0,0 +1,357 @@
+package tools
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k-admin-system/global"
+)
+
+func TestGetTables_HidesSystemTables(t *testing.T) {
+	setupTestDB(t,
+		"CREATE TABLE products (id INTEGER PRIMARY KEY, name TEXT)",
+		"CREATE TABLE sys_user_sessions (id INTEGER PRIMARY KEY, session_id TEXT)",
+	)
+
+	s := &DBInspectorService{}
+	tables, err := s.GetTables()
+	if err != nil {
+		t.Fatalf("GetTables() error = %v", err)
+	}
+	if len(tables) != 1 || tables[0] != "products" {
+		t.Errorf("GetTables() = %v, want [products]", tables)
+	}
+
+	if _, _, err := s.GetTableData("sys_user_sessions", 1, 10, 0); err == nil {
+		t.Error("GetTableData(sys_user_sessions) succeeded, want error")
+	}
+}
+
+func TestBackupTableToSQL_ReimportsAllRowsInOrder(t *testing.T) {
+	// 行数超过一个刷新批次，且以乱序写入
+	statements := []string{"CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT, note TEXT)"}
+	const rowCount = backupBatchSize*2 + 37
+	for i := rowCount; i >= 1; i-- {
+		note := "'it''s'"
+		if i%3 == 0 {
+			note = "NULL"
+		}
+		statements = append(statements, fmt.Sprintf("INSERT INTO items (id, name, note) VALUES (%d, 'item %d', %s)", i, i, note))
+	}
+	setupTestDB(t, statements...)
+
+	var buf bytes.Buffer
+	if err := (&DBInspectorService{}).BackupTableToSQL("items", &buf, 0); err != nil {
+		t.Fatalf("BackupTableToSQL() error = %v", err)
+	}
+
+	// 按主键升序输出
+	dump := buf.String()
+	if first, second := strings.Index(dump, "VALUES (1, "), strings.Index(dump, "VALUES (2, "); first < 0 || second < first {
+		t.Error("BackupTableToSQL() did not write rows in primary key order")
+	}
+
+	// 导入到空库后数据一致
+	restored := setupTestDB(t)
+	for _, stmt := range strings.Split(dump, ";\n") {
+		if strings.TrimSpace(stmt) == "" {
+			continue
+		}
+		if err := restored.Exec(stmt).Error; err != nil {
+			t.Fatalf("failed to re-import %q: %v", stmt, err)
+		}
+	}
+
+	var count, nulls int64
+	restored.Table("items").Count(&count)
+	restored.Table("items").Where("note IS NULL").Count(&nulls)
+	if count != rowCount {
+		t.Errorf("re-imported %d rows, want %d", count, rowCount)
+	}
+	if nulls != rowCount/3 {
+		t.Errorf("re-imported %d NULL notes, want %d", nulls, rowCount/3)
+	}
+	var note string
+	restored.Table("items").Where("id = ?", 1).Pluck("note", &note)
+	if note != "it's" {
+		t.Errorf("re-imported note = %q, want %q", note, "it's")
+	}
+}
+
+func TestCountNullValues(t *testing.T) {
+	setupTestDB(t,
+		"CREATE TABLE contacts (id INTEGER PRIMARY KEY, email TEXT, phone TEXT)",
+		"INSERT INTO contacts (id, email, phone) VALUES (1, NULL, '1'), (2, 'a@b.c', NULL), (3, NULL, NULL), (4, 'd@e.f', '4')",
+	)
+	s := &DBInspectorService{}
+
+	for column, want := range map[string]int64{"email": 2, "phone": 2, "id": 0} {
+		got, err := s.CountNullValues("contacts", column, 0)
+		if err != nil {
+			t.Fatalf("CountNullValues(%s) error = %v", column, err)
+		}
+		if got != want {
+			t.Errorf("CountNullValues(%s) = %d, want %d", column, got, want)
+		}
+	}
+
+	// 列名必须存在于表结构中
+	if _, err := s.CountNullValues("contacts", "email` IS NULL OR 1=1 --", 0); err == nil {
+		t.Error("CountNullValues() accepted a column that is not in the schema")
+	}
+	if _, err := s.CountNullValues("missing", "email", 0); err == nil {
+		t.Error("CountNullValues() accepted a table that does not exist")
+	}
+}
+
+func TestGetDistinctValues(t *testing.T) {
+	statements := []string{"CREATE TABLE tags (id INTEGER PRIMARY KEY, name TEXT)"}
+	for i := 0; i < 30; i++ {
+		// 10 个不同的值，每个重复 3 次
+		statements = append(statements, fmt.Sprintf("INSERT INTO tags (id, name) VALUES (%d, 'tag%d')", i+1, i%10))
+	}
+	setupTestDB(t, statements...)
+	s := &DBInspectorService{}
+
+	for _, limit := range []int{10, 50} {
+		values, err := s.GetDistinctValues("tags", "name", limit, 0)
+		if err != nil {
+			t.Fatalf("GetDistinctValues(limit %d) error = %v", limit, err)
+		}
+		if len(values) != 10 {
+			t.Fatalf("GetDistinctValues(limit %d) returned %d values, want 10", limit, len(values))
+		}
+		for i, value := range values {
+			if want := fmt.Sprintf("tag%d", i); value != want {
+				t.Errorf("values[%d] = %v, want %s", i, value, want)
+			}
+		}
+	}
+
+	values, err := s.GetDistinctValues("tags", "name", 3, 0)
+	if err != nil || len(values) != 3 {
+		t.Errorf("GetDistinctValues(limit 3) = %v, %v, want 3 values", values, err)
+	}
+	if _, err := s.GetDistinctValues("tags", "name", 0, 0); err == nil {
+		t.Error("GetDistinctValues() accepted a limit of 0")
+	}
+	if _, err := s.GetDistinctValues("tags", "missing", 10, 0); err == nil {
+		t.Error("GetDistinctValues() accepted a column that is not in the schema")
+	}
+}
+
+func TestGetDistinctValues_CapsLimit(t *testing.T) {
+	statements := []string{"CREATE TABLE numbers (id INTEGER PRIMARY KEY)"}
+	for i := 1; i <= maxDistinctValues+50; i++ {
+		statements = append(statements, fmt.Sprintf("INSERT INTO numbers (id) VALUES (%d)", i))
+	}
+	setupTestDB(t, statements...)
+
+	values, err := (&DBInspectorService{}).GetDistinctValues("numbers", "id", 1000, 0)
+	if err != nil {
+		t.Fatalf("GetDistinctValues() error = %v", err)
+	}
+	if len(values) != maxDistinctValues {
+		t.Errorf("GetDistinctValues(limit 1000) returned %d values, want %d", len(values), maxDistinctValues)
+	}
+}
+
+func TestGetForeignKeys(t *testing.T) {
+	setupTestDB(t,
+		"CREATE TABLE customers (id INTEGER PRIMARY KEY, name TEXT)",
+		"CREATE TABLE products (id INTEGER PRIMARY KEY, name TEXT)",
+		`CREATE TABLE orders (
+			id INTEGER PRIMARY KEY,
+			customer_id INTEGER REFERENCES customers(id),
+			product_id INTEGER,
+			note TEXT,
+			FOREIGN KEY (product_id) REFERENCES products(id)
+		)`,
+	)
+	s := &DBInspectorService{}
+
+	foreignKeys, err := s.GetForeignKeys("orders")
+	if err != nil {
+		t.Fatalf("GetForeignKeys() error = %v", err)
+	}
+	got := make(map[string]ForeignKeyInfo, len(foreignKeys))
+	for _, fk := range foreignKeys {
+		got[fk.ColumnName] = fk
+	}
+	want := map[string]ForeignKeyInfo{
+		"customer_id": {ColumnName: "customer_id", ReferencedTable: "customers", ReferencedColumn: "id"},
+		"product_id":  {ColumnName: "product_id", ReferencedTable: "products", ReferencedColumn: "id"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("GetForeignKeys() = %+v, want %+v", foreignKeys, want)
+	}
+	for column, fk := range want {
+		if got[column] != fk {
+			t.Errorf("foreign key on %s = %+v, want %+v", column, got[column], fk)
+		}
+	}
+
+	// 无外键的表返回空列表
+	foreignKeys, err = s.GetForeignKeys("customers")
+	if err != nil {
+		t.Fatalf("GetForeignKeys() error = %v", err)
+	}
+	if foreignKeys == nil || len(foreignKeys) != 0 {
+		t.Errorf("GetForeignKeys(customers) = %#v, want an empty list", foreignKeys)
+	}
+	if _, err := s.GetForeignKeys("missing"); err == nil {
+		t.Error("GetForeignKeys() accepted a table that does not exist")
+	}
+}
+
+func TestGetTableIndexes(t *testing.T) {
+	setupTestDB(t,
+		"CREATE TABLE accounts (id INTEGER PRIMARY KEY, email TEXT, first_name TEXT, last_name TEXT)",
+		"CREATE UNIQUE INDEX idx_accounts_email ON accounts (email)",
+		"CREATE INDEX idx_accounts_name ON accounts (last_name, first_name)",
+	)
+
+	indexes, err := (&DBInspectorService{}).GetTableIndexes("accounts")
+	if err != nil {
+		t.Fatalf("GetTableIndexes() error = %v", err)
+	}
+	got := make(map[string]IndexInfo, len(indexes))
+	for _, idx := range indexes {
+		got[idx.Name] = idx
+	}
+	want := []IndexInfo{
+		{Name: "idx_accounts_email", Columns: []string{"email"}, Unique: true, Type: "BTREE"},
+		{Name: "idx_accounts_name", Columns: []string{"last_name", "first_name"}, Unique: false, Type: "BTREE"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("GetTableIndexes() = %+v, want %+v", indexes, want)
+	}
+	for _, idx := range want {
+		if !reflect.DeepEqual(got[idx.Name], idx) {
+			t.Errorf("index %s = %+v, want %+v", idx.Name, got[idx.Name], idx)
+		}
+	}
+}
+
+func TestGenerateERDiagram(t *testing.T) {
+	setupTestDB(t,
+		"CREATE TABLE customers (id INTEGER PRIMARY KEY, name VARCHAR(100))",
+		"CREATE TABLE orders (id INTEGER PRIMARY KEY, customer_id INTEGER REFERENCES customers(id), total DECIMAL(10,2))",
+	)
+	s := &DBInspectorService{}
+
+	diagram, err := s.GenerateERDiagram([]string{"customers", "orders"})
+	if err != nil {
+		t.Fatalf("GenerateERDiagram() error = %v", err)
+	}
+	want := `erDiagram
+    customers {
+        integer id PK
+        varchar name
+    }
+    orders {
+        integer id PK
+        integer customer_id FK
+        decimal total
+    }
+    customers ||--o{ orders : "customer_id"
+`
+	if diagram != want {
+		t.Errorf("GenerateERDiagram() =\n%s\nwant\n%s", diagram, want)
+	}
+
+	if _, err := s.GenerateERDiagram(nil); err == nil {
+		t.Error("GenerateERDiagram() accepted an empty table list")
+	}
+	if _, err := s.GenerateERDiagram([]string{"customers", "missing"}); err == nil {
+		t.Error("GenerateERDiagram() accepted a table that does not exist")
+	}
+}
+
+func TestGetSampleRows(t *testing.T) {
+	statements := []string{"CREATE TABLE events (id INTEGER PRIMARY KEY, name TEXT, score INTEGER)"}
+	for i := 1; i <= maxSampleRows+20; i++ {
+		statements = append(statements, fmt.Sprintf("INSERT INTO events (id, name, score) VALUES (%d, 'event%d', %d)", i, i, i*10))
+	}
+	setupTestDB(t, statements...)
+	s := &DBInspectorService{}
+
+	for _, tt := range []struct{ n, want int }{{5, 5}, {maxSampleRows, maxSampleRows}, {1000, maxSampleRows}} {
+		rows, err := s.GetSampleRows("events", tt.n, 0)
+		if err != nil {
+			t.Fatalf("GetSampleRows(%d) error = %v", tt.n, err)
+		}
+		if len(rows) != tt.want {
+			t.Fatalf("GetSampleRows(%d) returned %d rows, want %d", tt.n, len(rows), tt.want)
+		}
+
+		// 每行包含表的全部列，且为表中真实存在的不重复行
+		seen := make(map[int64]bool, len(rows))
+		for _, row := range rows {
+			if len(row) != 3 {
+				t.Fatalf("row %v has %d columns, want id, name and score", row, len(row))
+			}
+			id, ok := row["id"].(int64)
+			if !ok || id < 1 || id > maxSampleRows+20 || seen[id] {
+				t.Fatalf("row has an unexpected id: %v", row)
+			}
+			seen[id] = true
+			if row["name"] != fmt.Sprintf("event%d", id) || row["score"] != id*10 {
+				t.Errorf("row %v does not match the stored values", row)
+			}
+		}
+	}
+
+	if _, err := s.GetSampleRows("events", 0, 0); err == nil {
+		t.Error("GetSampleRows() accepted a sample size of 0")
+	}
+	if _, err := s.GetSampleRows("missing", 5, 0); err == nil {
+		t.Error("GetSampleRows() accepted a table that does not exist")
+	}
+}
+
+func TestRunExplain_SQLite(t *testing.T) {
+	setupTestDB(t,
+		"CREATE TABLE test_users (id INTEGER PRIMARY KEY, name TEXT)",
+		"INSERT INTO test_users (id, name) VALUES (1, 'x'), (2, 'y')",
+	)
+	s := &DBInspectorService{}
+
+	rows, err := s.RunExplain("SELECT * FROM test_users WHERE name = 'x'", false, 0)
+	if err != nil {
+		t.Fatalf("RunExplain() error = %v", err)
+	}
+	if len(rows) == 0 {
+		t.Fatal("RunExplain() returned no plan rows")
+	}
+	// SQLite 的 EXPLAIN 返回字节码，每行包含 opcode 列
+	var opcodes []string
+	for _, row := range rows {
+		opcode, ok := row["opcode"].(string)
+		if !ok {
+			t.Fatalf("plan row %v has no opcode column", row)
+		}
+		opcodes = append(opcodes, opcode)
+	}
+	if plan := strings.Join(opcodes, ","); !strings.Contains(plan, "OpenRead") {
+		t.Errorf("plan %s does not read the table", plan)
+	}
+
+	// EXPLAIN 不执行语句，数据保持不变
+	if _, err := s.RunExplain("DELETE FROM test_users", false, 0); err != nil {
+		t.Fatalf("RunExplain(DELETE) error = %v", err)
+	}
+	var count int64
+	if err := global.DB.Table("test_users").Count(&count).Error; err != nil || count != 2 {
+		t.Errorf("test_users has %d rows after explaining a DELETE (err %v), want 2", count, err)
+	}
+	if _, err := s.RunExplain("DROP TABLE test_users", false, 0); err == nil {
+		t.Error("RunExplain() accepted a DROP statement")
+	}
+	if _, err := s.RunExplain("SELECT * FROM test_users", true, 0); err == nil {
+		t.Error("RunExplain() ran EXPLAIN ANALYZE on SQLite")
+	}
+}
@@ -0,0 +1,44 @@
+package tools
+
+import (
+	"testing"
+
+	"k-admin-system/global"
+
+	"github.com/glebarez/sqlite"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// setupTestDB 使用内存SQLite初始化 global.DB，执行 statements 建表和写入数据，测试结束后恢复原值
+func setupTestDB(t *testing.T, statements ...string) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	// 内存数据库只存在于单个连接中
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get sql.DB: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	for _, stmt := range statements {
+		if err := db.Exec(stmt).Error; err != nil {
+			t.Fatalf("failed to execute %q: %v", stmt, err)
+		}
+	}
+
+	prevDB, prevLogger := global.DB, global.Logger
+	global.DB = db
+	global.Logger = zap.NewNop()
+	t.Cleanup(func() {
+		_ = sqlDB.Close()
+		global.DB, global.Logger = prevDB, prevLogger
+	})
+
+	return db
+}
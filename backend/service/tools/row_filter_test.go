@@ -0,0 +1,144 @@
+package tools
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"k-admin-system/global"
+)
+
+// setupRowFilterTestDB 创建 people 表和配置了 age > 18 行级过滤的角色（ID 为 1）
+func setupRowFilterTestDB(t *testing.T) {
+	t.Helper()
+	setupTestDB(t,
+		"CREATE TABLE sys_roles (id INTEGER PRIMARY KEY, db_inspector_filter TEXT, deleted_at DATETIME)",
+		`INSERT INTO sys_roles (id, db_inspector_filter) VALUES (1, '{"people": "age > 18"}'), (2, '')`,
+		"CREATE TABLE people (id INTEGER PRIMARY KEY, name TEXT, age INTEGER, email TEXT)",
+		"INSERT INTO people (id, name, age, email) VALUES (1, 'kid', 10, NULL), (2, 'adult', 30, NULL), (3, 'senior', 70, 'a@b.c')",
+	)
+}
+
+func TestGetTableData_AppliesRowFilter(t *testing.T) {
+	setupRowFilterTestDB(t)
+	s := &DBInspectorService{}
+
+	rows, total, err := s.GetTableData("people", 1, 10, 1)
+	if err != nil {
+		t.Fatalf("GetTableData() error = %v", err)
+	}
+	if total != 2 || len(rows) != 2 {
+		t.Fatalf("GetTableData() returned %d rows (total %d), want 2", len(rows), total)
+	}
+	for _, row := range rows {
+		if row["name"] == "kid" {
+			t.Errorf("GetTableData() returned filtered row %v", row)
+		}
+	}
+
+	if _, total, err := s.GetTableData("people", 1, 10, 2); err != nil || total != 3 {
+		t.Errorf("GetTableData() without filter total = %d, err = %v, want 3", total, err)
+	}
+}
+
+func TestRowFilter_AppliesToOtherReadPaths(t *testing.T) {
+	setupRowFilterTestDB(t)
+	s := &DBInspectorService{}
+
+	sample, err := s.GetSampleRows("people", 10, 1)
+	if err != nil {
+		t.Fatalf("GetSampleRows() error = %v", err)
+	}
+	if len(sample) != 2 {
+		t.Errorf("GetSampleRows() returned %d rows, want 2", len(sample))
+	}
+
+	nulls, err := s.CountNullValues("people", "email", 1)
+	if err != nil {
+		t.Fatalf("CountNullValues() error = %v", err)
+	}
+	if nulls != 1 {
+		t.Errorf("CountNullValues() = %d, want 1", nulls)
+	}
+
+	values, err := s.GetDistinctValues("people", "name", 10, 1)
+	if err != nil {
+		t.Fatalf("GetDistinctValues() error = %v", err)
+	}
+	if len(values) != 2 {
+		t.Errorf("GetDistinctValues() = %v, want 2 values", values)
+	}
+
+	var buf bytes.Buffer
+	if err := s.BackupTableToSQL("people", &buf, 1); err != nil {
+		t.Fatalf("BackupTableToSQL() error = %v", err)
+	}
+	if strings.Contains(buf.String(), "'kid'") {
+		t.Error("BackupTableToSQL() exported a filtered row")
+	}
+
+	if _, err := s.ExecuteSQL("SELECT * FROM people", true, 1); err == nil {
+		t.Error("ExecuteSQL() for a filtered role succeeded, want error")
+	}
+	if _, err := s.RunExplain("SELECT * FROM people", false, 1); err == nil {
+		t.Error("RunExplain() for a filtered role succeeded, want error")
+	}
+	if _, err := s.ExecuteSQL("SELECT * FROM people", true, 2); err != nil {
+		t.Errorf("ExecuteSQL() for an unfiltered role error = %v", err)
+	}
+}
+
+// personAge 返回 people 表中指定记录的年龄，记录不存在时返回 -1
+func personAge(t *testing.T, id int) int {
+	t.Helper()
+	var ages []int
+	if err := global.DB.Table("people").Where("id = ?", id).Pluck("age", &ages).Error; err != nil {
+		t.Fatalf("failed to query person: %v", err)
+	}
+	if len(ages) == 0 {
+		return -1
+	}
+	return ages[0]
+}
+
+func TestRowFilter_AppliesToWrites(t *testing.T) {
+	setupRowFilterTestDB(t)
+	s := &DBInspectorService{}
+
+	// 被过滤的行（kid, age 10）既不能更新也不能删除
+	if err := s.UpdateRecord("people", 1, map[string]interface{}{"age": 40}, 1); err == nil {
+		t.Error("UpdateRecord() updated a row excluded by the role's filter")
+	}
+	if err := s.DeleteRecord("people", 1, 1); err == nil {
+		t.Error("DeleteRecord() deleted a row excluded by the role's filter")
+	}
+	if age := personAge(t, 1); age != 10 {
+		t.Errorf("excluded row age = %d after filtered writes, want 10", age)
+	}
+
+	// 可见的行可以更新，但不能更新到过滤范围之外
+	if err := s.UpdateRecord("people", 2, map[string]interface{}{"age": 31}, 1); err != nil {
+		t.Errorf("UpdateRecord() on a visible row error = %v", err)
+	}
+	if err := s.UpdateRecord("people", 2, map[string]interface{}{"age": 5}, 1); err == nil {
+		t.Error("UpdateRecord() moved a row outside the role's filter")
+	}
+	if age := personAge(t, 2); age != 31 {
+		t.Errorf("visible row age = %d, want 31", age)
+	}
+
+	// 配置了过滤的角色不能新增记录，未配置过滤的角色不受影响
+	if err := s.CreateRecord("people", map[string]interface{}{"name": "new", "age": 5}, 1); err == nil {
+		t.Error("CreateRecord() allowed a filtered role to insert")
+	}
+	if err := s.CreateRecord("people", map[string]interface{}{"id": 4, "name": "new", "age": 5}, 2); err != nil {
+		t.Errorf("CreateRecord() for an unfiltered role error = %v", err)
+	}
+
+	if err := s.DeleteRecord("people", 3, 1); err != nil {
+		t.Errorf("DeleteRecord() on a visible row error = %v", err)
+	}
+	if age := personAge(t, 3); age != -1 {
+		t.Error("DeleteRecord() did not delete the visible row")
+	}
+}
@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+const (
+	apiKeyPrefix   = "ka_"
+	apiKeyByteSize = 32
+)
+
+// GenerateAPIKey 生成随机API密钥
+// 返回原始密钥（仅在创建时展示给调用方一次）及其SHA-256哈希（用于存储和校验）
+func GenerateAPIKey() (key, hashedKey string, err error) {
+	buf := make([]byte, apiKeyByteSize)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+
+	key = apiKeyPrefix + hex.EncodeToString(buf)
+	return key, HashAPIKey(key), nil
+}
+
+// HashAPIKey 计算API密钥的SHA-256哈希（十六进制）
+// API密钥本身是高熵随机值，无需使用bcrypt等慢哈希，按哈希值直接查询即可
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
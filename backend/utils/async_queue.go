@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"sync"
+
+	"k-admin-system/global"
+
+	"go.uber.org/zap"
+)
+
+// AsyncQueue 有界异步任务队列
+// 固定数量的后台协程消费缓冲通道中的任务，首次提交时启动；队列已满时丢弃新任务并记录警告，
+// 避免高并发下为每个请求创建协程导致协程和数据库连接数无限增长
+type AsyncQueue struct {
+	name    string
+	workers int
+	tasks   chan func()
+	once    sync.Once
+}
+
+// NewAsyncQueue 创建异步任务队列，size 为缓冲区大小，workers 为消费协程数
+func NewAsyncQueue(name string, size, workers int) *AsyncQueue {
+	if workers < 1 {
+		workers = 1
+	}
+	return &AsyncQueue{
+		name:    name,
+		workers: workers,
+		tasks:   make(chan func(), size),
+	}
+}
+
+// Submit 提交任务，返回任务是否入队；队列已满时丢弃任务
+func (q *AsyncQueue) Submit(task func()) bool {
+	q.once.Do(q.start)
+
+	select {
+	case q.tasks <- task:
+		return true
+	default:
+		if global.Logger != nil {
+			global.Logger.Warn("Async queue is full, dropping task", zap.String("queue", q.name))
+		}
+		return false
+	}
+}
+
+// start 启动消费协程
+func (q *AsyncQueue) start() {
+	for i := 0; i < q.workers; i++ {
+		go func() {
+			for task := range q.tasks {
+				q.run(task)
+			}
+		}()
+	}
+}
+
+// run 执行单个任务，任务 panic 不影响消费协程继续工作
+func (q *AsyncQueue) run(task func()) {
+	defer func() {
+		if r := recover(); r != nil && global.Logger != nil {
+			global.Logger.Error("Async task panicked", zap.String("queue", q.name), zap.Any("panic", r))
+		}
+	}()
+	task()
+}
@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestAsyncQueue_RunsTasks(t *testing.T) {
+	q := NewAsyncQueue("test", 10, 2)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	done := 0
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		if !q.Submit(func() {
+			defer wg.Done()
+			mu.Lock()
+			done++
+			mu.Unlock()
+		}) {
+			t.Fatal("Submit() on a queue with free space returned false")
+		}
+	}
+	wg.Wait()
+
+	if done != 5 {
+		t.Errorf("ran %d tasks, want 5", done)
+	}
+}
+
+func TestAsyncQueue_DropsWhenFull(t *testing.T) {
+	q := NewAsyncQueue("test", 1, 1)
+
+	// 阻塞唯一的消费协程，再填满缓冲区
+	release := make(chan struct{})
+	started := make(chan struct{})
+	q.Submit(func() {
+		close(started)
+		<-release
+	})
+	<-started
+	if !q.Submit(func() {}) {
+		t.Fatal("Submit() into the empty buffer returned false")
+	}
+
+	if q.Submit(func() {}) {
+		t.Error("Submit() on a full queue returned true, want task to be dropped")
+	}
+	close(release)
+}
+
+func TestAsyncQueue_RecoversFromPanic(t *testing.T) {
+	q := NewAsyncQueue("test", 2, 1)
+	q.Submit(func() { panic("boom") })
+
+	done := make(chan struct{})
+	q.Submit(func() { close(done) })
+	<-done
+}
@@ -0,0 +1,19 @@
+package utils
+
+import (
+	"errors"
+
+	"k-admin-system/global"
+)
+
+// ErrDatabaseNotInitialized 数据库连接尚未初始化
+var ErrDatabaseNotInitialized = errors.New("database is not initialized")
+
+// DBMustInit 检查 global.DB 是否已初始化，未初始化时返回 ErrDatabaseNotInitialized
+// 服务方法在访问数据库前调用，避免在 nil 连接上调用方法导致 panic
+func DBMustInit() error {
+	if global.DB == nil {
+		return ErrDatabaseNotInitialized
+	}
+	return nil
+}
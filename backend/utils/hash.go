@@ -1,11 +1,41 @@
 package utils
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"k-admin-system/config"
+	"k-admin-system/global"
+
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
 )
 
-// HashPassword 使用bcrypt加密密码
+const (
+	argon2idPrefix  = "$argon2id$"
+	argon2SaltLen   = 16
+	argon2KeyLen    = 32
+	defaultHashAlgo = "bcrypt"
+)
+
+// HashPassword 加密密码
+// 根据 security.password_hash_algorithm 配置使用 bcrypt（默认）或 argon2id
 func HashPassword(password string) (string, error) {
+	algorithm := defaultHashAlgo
+	var params config.Argon2Config
+	if global.Config != nil {
+		algorithm = global.Config.Security.PasswordHashAlgorithm
+		params = global.Config.Security.Argon2Params
+	}
+
+	if algorithm == "argon2id" {
+		return hashArgon2id(password, params)
+	}
+
 	hashedBytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
 		return "", err
@@ -14,7 +44,72 @@ func HashPassword(password string) (string, error) {
 }
 
 // CheckPassword 验证密码是否匹配
+// 根据哈希前缀（$argon2id$ 或 $2a$/$2b$/$2y$）自动选择校验算法，切换算法后旧密码仍可校验
 func CheckPassword(hashedPassword, password string) bool {
+	if strings.HasPrefix(hashedPassword, argon2idPrefix) {
+		ok, err := verifyArgon2id(hashedPassword, password)
+		return err == nil && ok
+	}
+
 	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
 	return err == nil
 }
+
+// hashArgon2id 使用argon2id加密密码，输出PHC字符串格式：
+// $argon2id$v=19$m=<memory>,t=<time>,p=<threads>$<salt>$<hash>
+func hashArgon2id(password string, params config.Argon2Config) (string, error) {
+	if params.Time == 0 || params.Memory == 0 || params.Threads == 0 {
+		return "", errors.New("invalid argon2 parameters")
+	}
+
+	salt := make([]byte, argon2SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, params.Time, params.Memory, params.Threads, argon2KeyLen)
+
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2idPrefix,
+		argon2.Version,
+		params.Memory,
+		params.Time,
+		params.Threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// verifyArgon2id 使用哈希中记录的参数重新计算并比较密码
+func verifyArgon2id(encodedHash, password string) (bool, error) {
+	parts := strings.Split(encodedHash, "$")
+	if len(parts) != 6 {
+		return false, errors.New("invalid argon2id hash format")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return false, err
+	}
+	if version != argon2.Version {
+		return false, errors.New("incompatible argon2 version")
+	}
+
+	var memory, time uint32
+	var threads uint8
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &time, &threads); err != nil {
+		return false, err
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false, err
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return false, err
+	}
+
+	computed := argon2.IDKey([]byte(password), salt, time, memory, threads, uint32(len(key)))
+	return subtle.ConstantTimeCompare(key, computed) == 1, nil
+}
@@ -0,0 +1,110 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"k-admin-system/config"
+	"k-admin-system/global"
+)
+
+// benchmarkArgon2Params 与配置默认值一致的argon2id参数，与 bcrypt.DefaultCost 的耗时量级相当
+var benchmarkArgon2Params = config.Argon2Config{Time: 1, Memory: 64 * 1024, Threads: 4}
+
+// setHashAlgorithm 设置密码哈希算法，测试结束后恢复原配置
+func setHashAlgorithm(tb testing.TB, algorithm string) {
+	tb.Helper()
+	prevConfig := global.Config
+	global.Config = &config.Config{Security: config.SecurityConfig{
+		PasswordHashAlgorithm: algorithm,
+		Argon2Params:          benchmarkArgon2Params,
+	}}
+	tb.Cleanup(func() {
+		global.Config = prevConfig
+	})
+}
+
+func TestHashPassword_Algorithms(t *testing.T) {
+	tests := []struct {
+		algorithm string
+		prefix    string
+	}{
+		{"bcrypt", "$2a$"},
+		{"argon2id", argon2idPrefix},
+	}
+	for _, tt := range tests {
+		t.Run(tt.algorithm, func(t *testing.T) {
+			setHashAlgorithm(t, tt.algorithm)
+
+			hashed, err := HashPassword("Password123!")
+			if err != nil {
+				t.Fatalf("HashPassword() error = %v", err)
+			}
+			if !strings.HasPrefix(hashed, tt.prefix) {
+				t.Errorf("HashPassword() = %q, want prefix %q", hashed, tt.prefix)
+			}
+			if !CheckPassword(hashed, "Password123!") {
+				t.Error("CheckPassword() = false for the correct password")
+			}
+			if CheckPassword(hashed, "wrong") {
+				t.Error("CheckPassword() = true for a wrong password")
+			}
+		})
+	}
+}
+
+func TestCheckPassword_AfterAlgorithmSwitch(t *testing.T) {
+	setHashAlgorithm(t, "bcrypt")
+	bcryptHash, err := HashPassword("Password123!")
+	if err != nil {
+		t.Fatalf("HashPassword() error = %v", err)
+	}
+
+	// 切换算法后旧的 bcrypt 哈希仍按前缀校验
+	setHashAlgorithm(t, "argon2id")
+	if !CheckPassword(bcryptHash, "Password123!") {
+		t.Error("CheckPassword() = false for a bcrypt hash after switching to argon2id")
+	}
+}
+
+func BenchmarkHashPassword_Bcrypt(b *testing.B) {
+	setHashAlgorithm(b, "bcrypt")
+	for i := 0; i < b.N; i++ {
+		if _, err := HashPassword("Password123!"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkHashPassword_Argon2id(b *testing.B) {
+	setHashAlgorithm(b, "argon2id")
+	for i := 0; i < b.N; i++ {
+		if _, err := HashPassword("Password123!"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCheckPassword_Bcrypt(b *testing.B) {
+	benchmarkCheckPassword(b, "bcrypt")
+}
+
+func BenchmarkCheckPassword_Argon2id(b *testing.B) {
+	benchmarkCheckPassword(b, "argon2id")
+}
+
+// benchmarkCheckPassword 测量指定算法的密码校验耗时
+func benchmarkCheckPassword(b *testing.B, algorithm string) {
+	setHashAlgorithm(b, algorithm)
+	hashed, err := HashPassword("Password123!")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if !CheckPassword(hashed, "Password123!") {
+			b.Fatal("CheckPassword() = false")
+		}
+	}
+}
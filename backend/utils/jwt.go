@@ -2,6 +2,9 @@ package utils
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"time"
@@ -14,9 +17,11 @@ import (
 
 // JWTClaims JWT声明结构
 type JWTClaims struct {
-	UserID   uint   `json:"userId"`
-	Username string `json:"username"`
-	RoleID   uint   `json:"roleId"`
+	UserID      uint   `json:"userId"`
+	Username    string `json:"username"`
+	RoleID      uint   `json:"roleId"`
+	Fingerprint string `json:"fingerprint,omitempty"` // 签发时的客户端设备指纹
+	SessionID   string `json:"sid,omitempty"`         // 登录会话ID，刷新得到的访问令牌沿用同一会话ID
 	jwt.RegisteredClaims
 }
 
@@ -26,14 +31,37 @@ var (
 	ErrTokenBlacklisted = errors.New("token is blacklisted")
 )
 
-// GenerateToken 生成访问令牌和刷新令牌
-func GenerateToken(userID uint, username string, roleID uint) (accessToken, refreshToken string, err error) {
+// ComputeFingerprint 根据 User-Agent 和 Accept-Language 请求头计算设备指纹（SHA256十六进制）
+func ComputeFingerprint(userAgent, acceptLanguage string) string {
+	sum := sha256.Sum256([]byte(userAgent + "\n" + acceptLanguage))
+	return hex.EncodeToString(sum[:])
+}
+
+// GenerateSessionID 生成随机的登录会话ID
+func GenerateSessionID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// HashToken 计算令牌的SHA-256哈希（十六进制），数据库和Redis中只保存令牌哈希
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// GenerateToken 生成访问令牌和刷新令牌，fingerprint 为客户端设备指纹，sessionID 为登录会话ID
+func GenerateToken(userID uint, username string, roleID uint, fingerprint, sessionID string) (accessToken, refreshToken string, err error) {
 	// 生成访问令牌
 	accessExpiration := time.Duration(global.Config.JWT.AccessExpiration) * time.Minute
 	accessClaims := JWTClaims{
-		UserID:   userID,
-		Username: username,
-		RoleID:   roleID,
+		UserID:      userID,
+		Username:    username,
+		RoleID:      roleID,
+		Fingerprint: fingerprint,
+		SessionID:   sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(accessExpiration)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -50,9 +78,11 @@ func GenerateToken(userID uint, username string, roleID uint) (accessToken, refr
 	// 生成刷新令牌
 	refreshExpiration := time.Duration(global.Config.JWT.RefreshExpiration) * 24 * time.Hour
 	refreshClaims := JWTClaims{
-		UserID:   userID,
-		Username: username,
-		RoleID:   roleID,
+		UserID:      userID,
+		Username:    username,
+		RoleID:      roleID,
+		Fingerprint: fingerprint,
+		SessionID:   sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(refreshExpiration)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -69,7 +99,13 @@ func GenerateToken(userID uint, username string, roleID uint) (accessToken, refr
 	return accessToken, refreshToken, nil
 }
 
+// clockSkew 校验过期时间和生效时间时允许的时钟偏差
+func clockSkew() time.Duration {
+	return time.Duration(global.Config.JWT.ClockSkewSeconds) * time.Second
+}
+
 // ParseToken 解析令牌
+// 过期时间和生效时间的校验允许 jwt.clock_skew_seconds 的时钟偏差，以兼容分布式服务间的时钟漂移
 func ParseToken(tokenString string) (*JWTClaims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
 		// 验证签名方法
@@ -77,7 +113,7 @@ func ParseToken(tokenString string) (*JWTClaims, error) {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
 		return []byte(global.Config.JWT.Secret), nil
-	})
+	}, jwt.WithLeeway(clockSkew()))
 
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
@@ -87,8 +123,8 @@ func ParseToken(tokenString string) (*JWTClaims, error) {
 	}
 
 	if claims, ok := token.Claims.(*JWTClaims); ok && token.Valid {
-		// 检查令牌是否在黑名单中
-		if IsTokenBlacklisted(tokenString) {
+		// 检查令牌或其所属会话是否已被撤销
+		if IsTokenBlacklisted(tokenString) || IsSessionRevoked(claims.SessionID) {
 			return nil, ErrTokenBlacklisted
 		}
 		return claims, nil
@@ -108,9 +144,11 @@ func RefreshToken(refreshTokenString string) (newAccessToken string, err error)
 	// 生成新的访问令牌
 	accessExpiration := time.Duration(global.Config.JWT.AccessExpiration) * time.Minute
 	newClaims := JWTClaims{
-		UserID:   claims.UserID,
-		Username: claims.Username,
-		RoleID:   claims.RoleID,
+		UserID:      claims.UserID,
+		Username:    claims.Username,
+		RoleID:      claims.RoleID,
+		Fingerprint: claims.Fingerprint,
+		SessionID:   claims.SessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(accessExpiration)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -127,7 +165,7 @@ func RefreshToken(refreshTokenString string) (newAccessToken string, err error)
 	return newAccessToken, nil
 }
 
-// AddTokenToBlacklist 将令牌添加到黑名单
+// AddTokenToBlacklist 将令牌添加到黑名单，并删除使用该令牌的登录会话
 func AddTokenToBlacklist(tokenString string) error {
 	if global.RedisClient == nil {
 		return errors.New("redis client is not initialized")
@@ -135,12 +173,20 @@ func AddTokenToBlacklist(tokenString string) error {
 
 	// 解析令牌获取过期时间
 	claims, err := ParseToken(tokenString)
-	if err != nil && !errors.Is(err, ErrTokenBlacklisted) {
+	if errors.Is(err, ErrTokenBlacklisted) {
+		// 已在黑名单中
+		return deleteSessionByToken(tokenString)
+	}
+	if err != nil {
 		return err
 	}
 
-	// 计算令牌剩余有效时间
-	expiration := time.Until(claims.ExpiresAt.Time)
+	if err := deleteSessionByToken(tokenString); err != nil {
+		return err
+	}
+
+	// 计算令牌剩余有效时间（含时钟偏差容忍窗口，期间令牌仍可通过校验）
+	expiration := time.Until(claims.ExpiresAt.Time) + clockSkew()
 	if expiration <= 0 {
 		// 令牌已过期，无需加入黑名单
 		return nil
@@ -148,7 +194,7 @@ func AddTokenToBlacklist(tokenString string) error {
 
 	// 将令牌添加到Redis黑名单，设置过期时间
 	ctx := context.Background()
-	key := fmt.Sprintf("blacklist:%s", tokenString)
+	key := fmt.Sprintf("blacklist:%s", HashToken(tokenString))
 	err = global.RedisClient.Set(ctx, key, "1", expiration).Err()
 	if err != nil {
 		return fmt.Errorf("failed to add token to blacklist: %w", err)
@@ -157,6 +203,21 @@ func AddTokenToBlacklist(tokenString string) error {
 	return nil
 }
 
+// deleteSessionByToken 删除使用该令牌的登录会话
+// model/system 依赖 utils，这里按表名删除 sys_user_sessions 以避免循环引用
+func deleteSessionByToken(tokenString string) error {
+	if global.DB == nil {
+		return nil
+	}
+	hash := HashToken(tokenString)
+	if err := global.DB.Table("sys_user_sessions").
+		Where("access_token_hash = ? OR refresh_token_hash = ?", hash, hash).
+		Delete(nil).Error; err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	return nil
+}
+
 // IsTokenBlacklisted 检查令牌是否在黑名单中
 func IsTokenBlacklisted(tokenString string) bool {
 	if global.RedisClient == nil {
@@ -164,7 +225,7 @@ func IsTokenBlacklisted(tokenString string) bool {
 	}
 
 	ctx := context.Background()
-	key := fmt.Sprintf("blacklist:%s", tokenString)
+	key := fmt.Sprintf("blacklist:%s", HashToken(tokenString))
 	result, err := global.RedisClient.Get(ctx, key).Result()
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
@@ -177,3 +238,44 @@ func IsTokenBlacklisted(tokenString string) bool {
 
 	return result == "1"
 }
+
+// RevokeSession 撤销登录会话：该会话签发的所有令牌（包括刷新得到的访问令牌）均失效
+// ttl 为会话剩余有效期，过期后会话令牌自然失效，撤销记录随之清除
+func RevokeSession(sessionID string, ttl time.Duration) error {
+	if global.RedisClient == nil {
+		return errors.New("redis client not initialized")
+	}
+	if sessionID == "" {
+		return nil
+	}
+
+	ttl += clockSkew()
+	if ttl <= 0 {
+		return nil
+	}
+
+	key := fmt.Sprintf("session_revoked:%s", sessionID)
+	if err := global.RedisClient.Set(context.Background(), key, "1", ttl).Err(); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	return nil
+}
+
+// IsSessionRevoked 检查登录会话是否已被撤销，sessionID 为空（旧版令牌）时视为未撤销
+func IsSessionRevoked(sessionID string) bool {
+	if sessionID == "" || global.RedisClient == nil {
+		return false
+	}
+
+	key := fmt.Sprintf("session_revoked:%s", sessionID)
+	result, err := global.RedisClient.Get(context.Background(), key).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return false
+		}
+		// 其他错误，为安全起见视为已撤销
+		return true
+	}
+
+	return result == "1"
+}
@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"k-admin-system/config"
+	"k-admin-system/global"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/redis/go-redis/v9"
+)
+
+// setupJWTTest 设置JWT配置和miniredis，测试结束后恢复原值
+func setupJWTTest(t *testing.T, clockSkewSeconds int) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	prevConfig, prevRedis := global.Config, global.RedisClient
+	global.Config = &config.Config{JWT: config.JWTConfig{Secret: "test-secret", ClockSkewSeconds: clockSkewSeconds}}
+	global.RedisClient = redisClient
+	t.Cleanup(func() {
+		_ = redisClient.Close()
+		global.Config, global.RedisClient = prevConfig, prevRedis
+	})
+}
+
+// signExpiredToken 签发一个已过期 expiredFor 的令牌
+func signExpiredToken(t *testing.T, expiredFor time.Duration) string {
+	t.Helper()
+	now := time.Now()
+	claims := JWTClaims{
+		UserID:   1,
+		Username: "alice",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(-expiredFor)),
+			IssuedAt:  jwt.NewNumericDate(now.Add(-expiredFor - time.Second)),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(global.Config.JWT.Secret))
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return token
+}
+
+func TestParseToken_ClockSkewLeeway(t *testing.T) {
+	setupJWTTest(t, 30)
+
+	if _, err := ParseToken(signExpiredToken(t, 2*time.Second)); err != nil {
+		t.Errorf("ParseToken() of a token expired 2s ago = %v, want accepted within 30s leeway", err)
+	}
+	if _, err := ParseToken(signExpiredToken(t, 31*time.Second)); !errors.Is(err, ErrTokenExpired) {
+		t.Errorf("ParseToken() of a token expired 31s ago = %v, want ErrTokenExpired", err)
+	}
+}
+
+func TestParseToken_ZeroClockSkewDisablesLeeway(t *testing.T) {
+	setupJWTTest(t, 0)
+
+	if _, err := ParseToken(signExpiredToken(t, 2*time.Second)); !errors.Is(err, ErrTokenExpired) {
+		t.Errorf("ParseToken() with clock_skew_seconds 0 = %v, want ErrTokenExpired", err)
+	}
+}
+
+func TestParseToken_LeewayDoesNotBypassBlacklist(t *testing.T) {
+	setupJWTTest(t, 30)
+
+	token := signExpiredToken(t, 2*time.Second)
+	if err := AddTokenToBlacklist(token); err != nil {
+		t.Fatalf("AddTokenToBlacklist() error = %v", err)
+	}
+	if _, err := ParseToken(token); !errors.Is(err, ErrTokenBlacklisted) {
+		t.Errorf("ParseToken() of a blacklisted token in the leeway window = %v, want ErrTokenBlacklisted", err)
+	}
+}
@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"strings"
+)
+
+// maskPlaceholder 脱敏后替换原内容的占位符
+const maskPlaceholder = "***"
+
+// MaskEmail 邮箱脱敏，仅保留本地部分首字符和域名，例如 user@example.com → u***@example.com
+// 多个 @ 时以最后一个 @ 作为域名分隔符；不含 @ 时按普通字符串处理，仅保留首字符
+func MaskEmail(email string) string {
+	if email == "" {
+		return ""
+	}
+
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return firstRune(email) + maskPlaceholder
+	}
+
+	local, domain := email[:at], email[at:]
+	if local == "" {
+		return maskPlaceholder + domain
+	}
+	return firstRune(local) + maskPlaceholder + domain
+}
+
+// MaskPhone 手机号脱敏，保留前3位和后4位，例如 +12125551234 → +12***1234
+// 长度不足时仅保留后2位
+func MaskPhone(phone string) string {
+	runes := []rune(phone)
+	switch {
+	case len(runes) == 0:
+		return ""
+	case len(runes) <= 4:
+		return maskPlaceholder
+	case len(runes) <= 7:
+		return maskPlaceholder + string(runes[len(runes)-2:])
+	default:
+		return string(runes[:3]) + maskPlaceholder + string(runes[len(runes)-4:])
+	}
+}
+
+// firstRune 返回字符串的首个字符
+func firstRune(s string) string {
+	for _, r := range s {
+		return string(r)
+	}
+	return ""
+}
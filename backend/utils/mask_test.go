@@ -0,0 +1,43 @@
+package utils
+
+import "testing"
+
+func TestMaskEmail(t *testing.T) {
+	tests := []struct {
+		name  string
+		email string
+		want  string
+	}{
+		{"regular address", "user@example.com", "u***@example.com"},
+		{"single-character local part", "a@example.com", "a***@example.com"},
+		{"empty local part", "@example.com", "***@example.com"},
+		{"multiple @", "first@second@example.com", "f***@example.com"},
+		{"no @", "username", "u***"},
+		{"multi-byte local part", "张三@example.com", "张***@example.com"},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MaskEmail(tt.email); got != tt.want {
+				t.Errorf("MaskEmail(%q) = %q, want %q", tt.email, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaskPhone(t *testing.T) {
+	tests := []struct {
+		phone string
+		want  string
+	}{
+		{"+12125551234", "+12***1234"},
+		{"1234567", "***67"},
+		{"1234", "***"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := MaskPhone(tt.phone); got != tt.want {
+			t.Errorf("MaskPhone(%q) = %q, want %q", tt.phone, got, tt.want)
+		}
+	}
+}
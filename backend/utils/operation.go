@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"context"
+	"time"
+
+	"k-admin-system/global"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// operationErrorMaxLen 操作日志错误信息的最大长度，与 sys_operation_logs.error_msg 字段一致
+const operationErrorMaxLen = 512
+
+// operationQueue 操作日志的异步写入队列，队列满时丢弃日志并记录警告
+var operationQueue = NewAsyncQueue("operation_log", 4096, 4)
+
+// RecordOperation 经有界队列异步写入一条操作日志
+// ctx 为 *gin.Context 时从请求中读取方法和路径，操作者从 "userId" 上下文键读取（由JWT中间件设置）
+// model/system 依赖 utils，这里按表名写入 sys_operation_logs 以避免循环引用
+func RecordOperation(ctx context.Context, module, action string, duration time.Duration, err error) {
+	if global.DB == nil {
+		return
+	}
+
+	now := time.Now()
+	record := map[string]interface{}{
+		"created_at": now,
+		"updated_at": now,
+		"user_id":    uint(0),
+		"method":     "",
+		"path":       "",
+		"module":     module,
+		"action":     action,
+		"duration":   duration.Milliseconds(),
+		"success":    err == nil,
+		"error_msg":  "",
+	}
+	if userID, ok := ctx.Value("userId").(uint); ok {
+		record["user_id"] = userID
+	}
+	if c, ok := ctx.(*gin.Context); ok && c.Request != nil {
+		record["method"] = c.Request.Method
+		record["path"] = c.Request.URL.Path
+	}
+	if err != nil {
+		record["error_msg"] = TruncateRunes(err.Error(), operationErrorMaxLen)
+	}
+
+	db := global.DB
+	operationQueue.Submit(func() {
+		if err := db.Table("sys_operation_logs").Create(record).Error; err != nil && global.Logger != nil {
+			global.Logger.Warn("Failed to write operation log", zap.Error(err))
+		}
+	})
+}
+
+// TruncateRunes 截断字符串到最多 maxLen 个字符，不会截断多字节字符，用于写入有长度限制的数据库字段
+func TruncateRunes(s string, maxLen int) string {
+	if len(s) <= maxLen {
+		return s
+	}
+	runes := []rune(s)
+	if len(runes) <= maxLen {
+		return s
+	}
+	return string(runes[:maxLen])
+}
@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"k-admin-system/global"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestTruncateRunes(t *testing.T) {
+	tests := []struct {
+		in   string
+		max  int
+		want string
+	}{
+		{"short", 10, "short"},
+		{"abcdef", 3, "abc"},
+		{"操作日志错误", 4, "操作日志"},
+		{"a操作", 2, "a操"},
+	}
+	for _, tt := range tests {
+		if got := TruncateRunes(tt.in, tt.max); got != tt.want {
+			t.Errorf("TruncateRunes(%q, %d) = %q, want %q", tt.in, tt.max, got, tt.want)
+		}
+	}
+}
+
+func TestRecordOperation_TruncatesErrorByRune(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.Exec(`CREATE TABLE sys_operation_logs (id INTEGER PRIMARY KEY, created_at DATETIME, updated_at DATETIME,
+		user_id INTEGER, method TEXT, path TEXT, module TEXT, action TEXT, duration INTEGER, success BOOLEAN, error_msg TEXT)`).Error; err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	prevDB := global.DB
+	global.DB = db
+	t.Cleanup(func() { global.DB = prevDB })
+
+	// 多字节字符跨越截断边界
+	msg := strings.Repeat("a", operationErrorMaxLen-1) + "错误"
+	RecordOperation(context.Background(), "user", "create", time.Millisecond, errors.New(msg))
+
+	var stored string
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) && stored == "" {
+		db.Raw("SELECT error_msg FROM sys_operation_logs").Scan(&stored)
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if !utf8.ValidString(stored) {
+		t.Fatalf("stored error message is not valid UTF-8: %q", stored[len(stored)-4:])
+	}
+	if n := utf8.RuneCountInString(stored); n != operationErrorMaxLen {
+		t.Errorf("stored error message has %d characters, want %d", n, operationErrorMaxLen)
+	}
+}
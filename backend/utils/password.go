@@ -0,0 +1,131 @@
+package utils
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"unicode"
+)
+
+const (
+	passwordLowerChars  = "abcdefghijklmnopqrstuvwxyz"
+	passwordUpperChars  = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	passwordDigitChars  = "0123456789"
+	passwordSymbolChars = "!@#$%^&*()-_=+[]{}<>?"
+)
+
+// PasswordOptions 随机密码生成选项
+type PasswordOptions struct {
+	MinUpper  int // 最少大写字母数量
+	MinDigit  int // 最少数字数量
+	MinSymbol int // 最少特殊字符数量
+}
+
+// GeneratePassword 使用crypto/rand生成满足约束条件的随机密码
+// 先按约束放入指定数量的大写字母、数字和特殊字符，其余位置从全部字符集中随机选取，最后整体打乱顺序
+func GeneratePassword(length int, opts PasswordOptions) (string, error) {
+	if length <= 0 {
+		return "", errors.New("password length must be greater than 0")
+	}
+	if opts.MinUpper < 0 || opts.MinDigit < 0 || opts.MinSymbol < 0 {
+		return "", errors.New("password options must not be negative")
+	}
+	if opts.MinUpper+opts.MinDigit+opts.MinSymbol > length {
+		return "", errors.New("password length is too short for the required character counts")
+	}
+
+	password := make([]byte, 0, length)
+
+	// 按约束放入必需字符
+	required := []struct {
+		chars string
+		count int
+	}{
+		{passwordUpperChars, opts.MinUpper},
+		{passwordDigitChars, opts.MinDigit},
+		{passwordSymbolChars, opts.MinSymbol},
+	}
+	for _, r := range required {
+		for i := 0; i < r.count; i++ {
+			c, err := randomChar(r.chars)
+			if err != nil {
+				return "", err
+			}
+			password = append(password, c)
+		}
+	}
+
+	// 剩余位置从全部字符集中随机选取
+	allChars := passwordLowerChars + passwordUpperChars + passwordDigitChars + passwordSymbolChars
+	for len(password) < length {
+		c, err := randomChar(allChars)
+		if err != nil {
+			return "", err
+		}
+		password = append(password, c)
+	}
+
+	// Fisher-Yates 洗牌，避免必需字符总是出现在开头
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", err
+		}
+		password[i], password[j.Int64()] = password[j.Int64()], password[i]
+	}
+
+	return string(password), nil
+}
+
+// randomChar 从字符集中随机选取一个字符
+func randomChar(chars string) (byte, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(chars))))
+	if err != nil {
+		return 0, err
+	}
+	return chars[n.Int64()], nil
+}
+
+// StrengthScore 密码强度评分，取值 0-4
+type StrengthScore int
+
+// PasswordStrength 评估密码强度，返回评分和改进建议
+// 长度不少于8位、包含大写字母、包含数字、包含特殊字符各加1分
+func PasswordStrength(password string) (StrengthScore, []string) {
+	var hasUpper, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			hasSymbol = true
+		}
+	}
+
+	var score StrengthScore
+	hints := []string{}
+	if len([]rune(password)) >= 8 {
+		score++
+	} else {
+		hints = append(hints, "Use at least 8 characters")
+	}
+	if hasUpper {
+		score++
+	} else {
+		hints = append(hints, "Add uppercase letters")
+	}
+	if hasDigit {
+		score++
+	} else {
+		hints = append(hints, "Add digits")
+	}
+	if hasSymbol {
+		score++
+	} else {
+		hints = append(hints, "Add symbols")
+	}
+
+	return score, hints
+}
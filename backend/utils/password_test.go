@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+)
+
+// countChars 统计密码中属于字符集的字符数量
+func countChars(password, chars string) int {
+	n := 0
+	for _, c := range password {
+		if strings.ContainsRune(chars, c) {
+			n++
+		}
+	}
+	return n
+}
+
+func TestGeneratePassword_MeetsConstraints(t *testing.T) {
+	allChars := passwordLowerChars + passwordUpperChars + passwordDigitChars + passwordSymbolChars
+	property := func(length, upper, digit, symbol uint8) bool {
+		opts := PasswordOptions{MinUpper: int(upper % 8), MinDigit: int(digit % 8), MinSymbol: int(symbol % 8)}
+		n := opts.MinUpper + opts.MinDigit + opts.MinSymbol + int(length%32)
+		if n == 0 {
+			n = 1
+		}
+
+		password, err := GeneratePassword(n, opts)
+		if err != nil {
+			t.Logf("GeneratePassword(%d, %+v) error = %v", n, opts, err)
+			return false
+		}
+		if len(password) != n || countChars(password, allChars) != n {
+			return false
+		}
+		return countChars(password, passwordUpperChars) >= opts.MinUpper &&
+			countChars(password, passwordDigitChars) >= opts.MinDigit &&
+			countChars(password, passwordSymbolChars) >= opts.MinSymbol
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGeneratePassword_RejectsUnsatisfiableOptions(t *testing.T) {
+	property := func(upper, digit, symbol uint8) bool {
+		opts := PasswordOptions{MinUpper: int(upper%8) + 1, MinDigit: int(digit % 8), MinSymbol: int(symbol % 8)}
+		_, err := GeneratePassword(opts.MinUpper+opts.MinDigit+opts.MinSymbol-1, opts)
+		return err != nil
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+
+	if _, err := GeneratePassword(8, PasswordOptions{MinDigit: -1}); err == nil {
+		t.Error("GeneratePassword() accepted a negative minimum")
+	}
+}
+
+func TestPasswordStrength(t *testing.T) {
+	tests := []struct {
+		password  string
+		wantScore StrengthScore
+		wantHints []string
+	}{
+		{"", 0, []string{"Use at least 8 characters", "Add uppercase letters", "Add digits", "Add symbols"}},
+		{"abc", 0, []string{"Use at least 8 characters", "Add uppercase letters", "Add digits", "Add symbols"}},
+		{"abcdefgh", 1, []string{"Add uppercase letters", "Add digits", "Add symbols"}},
+		{"Abc", 1, []string{"Use at least 8 characters", "Add digits", "Add symbols"}},
+		{"Abcdefgh", 2, []string{"Add digits", "Add symbols"}},
+		{"abcdefg1", 2, []string{"Add uppercase letters", "Add symbols"}},
+		{"Abcdefg1", 3, []string{"Add symbols"}},
+		{"A1!", 3, []string{"Use at least 8 characters"}},
+		{"Abcdef1!", 4, []string{}},
+		{"密码密码密码密码", 1, []string{"Add uppercase letters", "Add digits", "Add symbols"}},
+	}
+	for _, tt := range tests {
+		score, hints := PasswordStrength(tt.password)
+		if score != tt.wantScore || !reflect.DeepEqual(hints, tt.wantHints) {
+			t.Errorf("PasswordStrength(%q) = %d, %q; want %d, %q", tt.password, score, hints, tt.wantScore, tt.wantHints)
+		}
+	}
+}
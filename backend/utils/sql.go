@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"errors"
+	"regexp"
+)
+
+// maxSQLIdentifierLength SQL标识符的最大长度（与MySQL表名、列名的长度上限一致）
+const maxSQLIdentifierLength = 64
+
+// sqlIdentifierPattern 允许的SQL标识符：仅字母、数字和下划线
+var sqlIdentifierPattern = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// SanitizeSQL 校验将拼接进SQL语句的标识符（表名、列名）
+// 只允许字母、数字和下划线，包含引号、分号、空白、注释符等任何其他字符时返回错误
+func SanitizeSQL(input string) error {
+	if input == "" {
+		return errors.New("identifier is empty")
+	}
+	if len(input) > maxSQLIdentifierLength {
+		return errors.New("identifier is too long")
+	}
+	if !sqlIdentifierPattern.MatchString(input) {
+		return errors.New("identifier may only contain letters, digits and underscores")
+	}
+	return nil
+}
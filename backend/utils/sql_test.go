@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"testing/quick"
+)
+
+// sqlMetacharacters 可用于构造SQL注入的字符
+var sqlMetacharacters = []string{"'", "\"", "`", ";", "-", "/", "*", "#", "(", ")", "=", ",", ".", " ", "\t", "\n", "\\", "%", "\x00"}
+
+func TestSanitizeSQL_RejectsMetacharacters(t *testing.T) {
+	property := func(prefix, suffix string, index uint8) bool {
+		meta := sqlMetacharacters[int(index)%len(sqlMetacharacters)]
+		return SanitizeSQL(prefix+meta+suffix) != nil
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSanitizeSQL_AcceptsIdentifiers(t *testing.T) {
+	const alphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_"
+	property := func(indexes []uint8) bool {
+		if len(indexes) == 0 || len(indexes) > maxSQLIdentifierLength {
+			return true
+		}
+		var b strings.Builder
+		for _, index := range indexes {
+			b.WriteByte(alphabet[int(index)%len(alphabet)])
+		}
+		return SanitizeSQL(b.String()) == nil
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSanitizeSQL(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantErr bool
+	}{
+		{"sys_users", false},
+		{"Table1", false},
+		{"", true},
+		{strings.Repeat("a", maxSQLIdentifierLength), false},
+		{strings.Repeat("a", maxSQLIdentifierLength+1), true},
+		{"users; DROP TABLE users", true},
+		{"users`--", true},
+		{"name' OR '1'='1", true},
+		{"列名", true},
+	}
+	for _, tt := range tests {
+		if err := SanitizeSQL(tt.input); (err != nil) != tt.wantErr {
+			t.Errorf("SanitizeSQL(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+		}
+	}
+}
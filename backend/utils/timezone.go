@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"time"
+)
+
+// ConvertToTimezone 将时间转换到指定时区（IANA名称，如 "America/New_York"）
+// tz 为空或无效时原样返回
+func ConvertToTimezone(t time.Time, tz string) time.Time {
+	if tz == "" {
+		return t
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return t
+	}
+	return t.In(loc)
+}
+
+// IsValidTimezone 判断是否为有效的IANA时区名称
+func IsValidTimezone(tz string) bool {
+	if tz == "" {
+		return false
+	}
+	_, err := time.LoadLocation(tz)
+	return err == nil
+}
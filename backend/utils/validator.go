@@ -0,0 +1,20 @@
+package utils
+
+import (
+	"regexp"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// e164Regexp E.164 国际电话号码格式：+ 开头，国家码首位非0，总位数不超过15位
+var e164Regexp = regexp.MustCompile(`^\+[1-9]\d{1,14}$`)
+
+// IsValidE164 判断电话号码是否符合 E.164 格式
+func IsValidE164(phone string) bool {
+	return e164Regexp.MatchString(phone)
+}
+
+// ValidateE164 gin 参数校验器，用于 binding:"e164" 标签
+func ValidateE164(fl validator.FieldLevel) bool {
+	return IsValidE164(fl.Field().String())
+}
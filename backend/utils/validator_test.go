@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+)
+
+func TestIsValidE164(t *testing.T) {
+	tests := []struct {
+		phone string
+		want  bool
+	}{
+		{"+12125551234", true},
+		{"+8613800138000", true},
+		{"+123456789012345", true},
+		{"12345", false},
+		{"+1234567890123456", false},
+		{"+0123456789", false},
+		{"+1", false},
+		{"+1 212 555 1234", false},
+		{"+1-212-555-1234", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := IsValidE164(tt.phone); got != tt.want {
+			t.Errorf("IsValidE164(%q) = %v, want %v", tt.phone, got, tt.want)
+		}
+	}
+}
+
+func TestValidateE164_BindingTag(t *testing.T) {
+	v := validator.New()
+	if err := v.RegisterValidation("e164", ValidateE164); err != nil {
+		t.Fatalf("RegisterValidation() error = %v", err)
+	}
+	type request struct {
+		Phone string `validate:"omitempty,e164"`
+	}
+
+	for phone, wantErr := range map[string]bool{"+12125551234": false, "12345": true, "": false} {
+		if err := v.Struct(request{Phone: phone}); (err != nil) != wantErr {
+			t.Errorf("validate %q error = %v, wantErr %v", phone, err, wantErr)
+		}
+	}
+}